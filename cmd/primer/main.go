@@ -1,27 +1,38 @@
 package main
 
 import (
+	"bufio"
 	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/joho/godotenv"
 	"github.com/kbrakke/illustrated-primer/internal/ai"
 	"github.com/kbrakke/illustrated-primer/internal/db"
+	"github.com/kbrakke/illustrated-primer/internal/illustration"
+	"github.com/kbrakke/illustrated-primer/internal/models"
+	"github.com/kbrakke/illustrated-primer/internal/narration"
 	"github.com/kbrakke/illustrated-primer/internal/seed"
 	"github.com/kbrakke/illustrated-primer/internal/tui"
+	"github.com/kbrakke/illustrated-primer/internal/voice"
+	"golang.org/x/term"
 )
 
 func main() {
 	// Parse command line flags
 	seedFlag := flag.Bool("seed", false, "Load seed data from the seed/ directory")
 	debugFlag := flag.Bool("debug", false, "Enable debug logging")
+	setPINFlag := flag.String("set-pin", "", "Make the user with this ID a guardian and set their PIN, read from stdin")
 	flag.Parse()
 
 	// Load environment variables
@@ -61,12 +72,6 @@ func main() {
 	logger.Info("log file created", "path", logFilePath)
 
 	// Get configuration from environment
-	openaiAPIKey := os.Getenv("OPENAI_API_KEY")
-	if openaiAPIKey == "" {
-		fmt.Fprintln(os.Stderr, "OPENAI_API_KEY must be set in environment")
-		os.Exit(1)
-	}
-
 	databaseURL := os.Getenv("DATABASE_URL")
 	if databaseURL == "" {
 		databaseURL = "postgres://localhost:5432/primer?sslmode=disable"
@@ -107,6 +112,23 @@ func main() {
 		}
 	}
 
+	// Set a guardian's PIN and exit
+	if *setPINFlag != "" {
+		if err := setPIN(ctx, database, *setPINFlag, os.Stdin); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to set PIN: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println("PIN set.")
+		return
+	}
+
+	// Everything from here on needs the model
+	openaiAPIKey := os.Getenv("OPENAI_API_KEY")
+	if openaiAPIKey == "" {
+		fmt.Fprintln(os.Stderr, "OPENAI_API_KEY must be set in environment")
+		os.Exit(1)
+	}
+
 	// Initialize AI client
 	logger.Info("initializing OpenAI client")
 	aiModel := os.Getenv("OPENAI_MODEL")
@@ -135,11 +157,236 @@ func main() {
 		clientOpts = append(clientOpts, ai.WithOrgID(orgID))
 	}
 
+	if model := os.Getenv("OPENAI_MODERATION_MODEL"); model != "" {
+		clientOpts = append(clientOpts, ai.WithModerationModel(model))
+	}
+
 	aiClient := ai.NewClient(openaiAPIKey, clientOpts...)
 
+	// Load key binding overrides
+	var tuiOpts []tui.Option
+	if keymapPath := configPath("PRIMER_KEYMAP", "keys.json"); keymapPath != "" {
+		keys, err := tui.LoadKeyMap(keymapPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to load key bindings: %v\n", err)
+			logger.Error("failed to load key bindings", "path", keymapPath, "error", err)
+			os.Exit(1)
+		}
+		logger.Info("loaded key bindings", "path", keymapPath)
+		tuiOpts = append(tuiOpts, tui.WithKeyMap(keys))
+	}
+
+	// Load custom themes
+	if themesDir := configPath("PRIMER_THEMES_DIR", "themes"); themesDir != "" {
+		themes, err := tui.LoadThemes(themesDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to load themes: %v\n", err)
+			logger.Error("failed to load themes", "dir", themesDir, "error", err)
+			os.Exit(1)
+		}
+		logger.Info("loaded themes", "dir", themesDir, "count", len(themes))
+		tuiOpts = append(tuiOpts, tui.WithThemes(themes))
+	}
+
+	// Load prompt templates and the variants new stories are assigned to
+	promptsDir := configPath("PRIMER_PROMPTS_DIR", "prompts")
+	prompts, err := ai.LoadRegistry(promptsDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load prompt templates: %v\n", err)
+		logger.Error("failed to load prompt templates", "dir", promptsDir, "error", err)
+		os.Exit(1)
+	}
+	weights, err := ai.ParseTemplateWeights(os.Getenv("PRIMER_PROMPT_WEIGHTS"))
+	if err == nil {
+		err = prompts.SetWeights(weights)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid prompt template weights: %v\n", err)
+		os.Exit(1)
+	}
+	logger.Info("loaded prompt templates", "dir", promptsDir, "variants", len(weights))
+	tuiOpts = append(tuiOpts, tui.WithPromptTemplates(prompts))
+
+	// Prompts and completions are checked by the local rules, and optionally
+	// the OpenAI moderation endpoint
+	guard, err := moderationGuard(aiClient)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid moderation settings: %v\n", err)
+		os.Exit(1)
+	}
+	if guard != nil {
+		logger.Info("moderation enabled", "moderators", len(guard.Moderators), "default_action", guard.Policy.Default)
+		tuiOpts = append(tuiOpts, tui.WithModeration(guard))
+	} else {
+		logger.Warn("moderation disabled")
+	}
+
+	// Markdown rendering can be turned off for a plain text fallback
+	if os.Getenv("PRIMER_MARKDOWN") == "false" {
+		tuiOpts = append(tuiOpts, tui.WithMarkdown(false))
+	}
+
+	// Pages come with suggested next actions unless turned off
+	if os.Getenv("PRIMER_CHOICES") == "false" {
+		tuiOpts = append(tuiOpts, tui.WithChoices(false))
+	}
+
+	// Pages above the child's reading level are rewritten more simply when
+	// turned on
+	if os.Getenv("PRIMER_SIMPLIFY") == "true" {
+		tuiOpts = append(tuiOpts, tui.WithSimplify(true))
+	}
+
+	// The parent dashboard is unlocked with a PIN; without one it stays off
+	if pin := os.Getenv("PRIMER_PARENT_PIN"); pin != "" {
+		tuiOpts = append(tuiOpts, tui.WithParentPIN(pin))
+	}
+	if dir := os.Getenv("PRIMER_REPORT_DIR"); dir != "" {
+		tuiOpts = append(tuiOpts, tui.WithReportDir(dir))
+	}
+
+	// Illustrations are drawn with the detected terminal graphics protocol
+	// unless one is chosen explicitly
+	if name := os.Getenv("PRIMER_GRAPHICS"); name != "" && name != "auto" {
+		protocol, err := tui.ParseGraphicsProtocol(name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid PRIMER_GRAPHICS: %v\n", err)
+			os.Exit(1)
+		}
+		logger.Info("using graphics protocol", "protocol", protocol)
+		tuiOpts = append(tuiOpts, tui.WithGraphics(protocol))
+	}
+
+	// Illustrations are generated for new pages when a generator is configured
+	assetDir := os.Getenv("PRIMER_ASSET_DIR")
+	if assetDir == "" {
+		assetDir = "assets"
+	}
+
+	var generator illustration.ImageGenerator
+	switch name := os.Getenv("PRIMER_IMAGE_GENERATOR"); name {
+	case "", "off":
+	case "openai":
+		imageOpts := []illustration.OpenAIOption{illustration.WithLogger(logger)}
+		if model := os.Getenv("OPENAI_IMAGE_MODEL"); model != "" {
+			imageOpts = append(imageOpts, illustration.WithModel(model))
+		}
+		if orgID := os.Getenv("OPENAI_ORG_ID"); orgID != "" {
+			imageOpts = append(imageOpts, illustration.WithOrgID(orgID))
+		}
+		generator = illustration.NewOpenAIGenerator(openaiAPIKey, imageOpts...)
+	case "placeholder":
+		generator = illustration.NewPlaceholderGenerator()
+	default:
+		fmt.Fprintf(os.Stderr, "Invalid PRIMER_IMAGE_GENERATOR %q (want openai, placeholder or off)\n", name)
+		os.Exit(1)
+	}
+	if generator != nil {
+		logger.Info("illustrations enabled", "generator", os.Getenv("PRIMER_IMAGE_GENERATOR"), "asset_dir", assetDir)
+		tuiOpts = append(tuiOpts, tui.WithIllustrator(illustration.NewIllustrator(generator, assetDir, logger)))
+	}
+
+	// Pages are narrated when a speech engine is configured
+	var speaker narration.Speaker
+	switch name := os.Getenv("PRIMER_NARRATION"); name {
+	case "", "off":
+	case "openai":
+		speechOpts := []narration.OpenAIOption{narration.WithLogger(logger)}
+		if model := os.Getenv("OPENAI_TTS_MODEL"); model != "" {
+			speechOpts = append(speechOpts, narration.WithModel(model))
+		}
+		if voice := os.Getenv("OPENAI_TTS_VOICE"); voice != "" {
+			speechOpts = append(speechOpts, narration.WithVoice(voice))
+		}
+		if orgID := os.Getenv("OPENAI_ORG_ID"); orgID != "" {
+			speechOpts = append(speechOpts, narration.WithOrgID(orgID))
+		}
+		speaker = narration.NewOpenAISpeaker(openaiAPIKey, speechOpts...)
+	case "espeak":
+		speaker = narration.NewEspeakSpeaker(os.Getenv("PRIMER_ESPEAK_VOICE"))
+	case "piper":
+		model := os.Getenv("PRIMER_PIPER_MODEL")
+		if model == "" {
+			fmt.Fprintln(os.Stderr, "PRIMER_PIPER_MODEL must be set to a piper voice model for PRIMER_NARRATION=piper")
+			os.Exit(1)
+		}
+		speaker = narration.NewPiperSpeaker(model)
+	default:
+		fmt.Fprintf(os.Stderr, "Invalid PRIMER_NARRATION %q (want openai, espeak, piper or off)\n", name)
+		os.Exit(1)
+	}
+	if speaker != nil {
+		logger.Info("narration enabled", "engine", os.Getenv("PRIMER_NARRATION"), "asset_dir", assetDir)
+		tuiOpts = append(tuiOpts, tui.WithNarrator(narration.NewNarrator(speaker, assetDir, logger)))
+	}
+
+	// Pages are read aloud with an external audio player
+	if command := os.Getenv("PRIMER_PLAYER"); command != "" {
+		player, err := narration.ParsePlayer(command)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid PRIMER_PLAYER: %v\n", err)
+			os.Exit(1)
+		}
+		tuiOpts = append(tuiOpts, tui.WithPlayer(player))
+	} else if player, err := narration.DetectPlayer(exec.LookPath); err == nil {
+		logger.Info("using audio player", "player", player.Name)
+		tuiOpts = append(tuiOpts, tui.WithPlayer(player))
+	} else {
+		logger.Warn("read-aloud unavailable", "error", err)
+	}
+
+	// Spoken prompts are recorded with a capture command and transcribed
+	var transcriber voice.Transcriber
+	switch name := os.Getenv("PRIMER_TRANSCRIBER"); name {
+	case "", "off":
+	case "openai":
+		transcribeOpts := []voice.OpenAIOption{voice.WithLogger(logger)}
+		if model := os.Getenv("OPENAI_TRANSCRIBE_MODEL"); model != "" {
+			transcribeOpts = append(transcribeOpts, voice.WithModel(model))
+		}
+		if language := os.Getenv("PRIMER_VOICE_LANGUAGE"); language != "" {
+			transcribeOpts = append(transcribeOpts, voice.WithLanguage(language))
+		}
+		if orgID := os.Getenv("OPENAI_ORG_ID"); orgID != "" {
+			transcribeOpts = append(transcribeOpts, voice.WithOrgID(orgID))
+		}
+		transcriber = voice.NewOpenAITranscriber(openaiAPIKey, transcribeOpts...)
+	case "whisper":
+		model := os.Getenv("PRIMER_WHISPER_MODEL")
+		if model == "" {
+			fmt.Fprintln(os.Stderr, "PRIMER_WHISPER_MODEL must be set to a whisper.cpp model for PRIMER_TRANSCRIBER=whisper")
+			os.Exit(1)
+		}
+		whisper := voice.NewWhisperCppTranscriber(model)
+		whisper.Language = os.Getenv("PRIMER_VOICE_LANGUAGE")
+		transcriber = whisper
+	default:
+		fmt.Fprintf(os.Stderr, "Invalid PRIMER_TRANSCRIBER %q (want openai, whisper or off)\n", name)
+		os.Exit(1)
+	}
+	if transcriber != nil {
+		var recorder *voice.Recorder
+		var err error
+		if command := os.Getenv("PRIMER_CAPTURE"); command != "" {
+			recorder, err = voice.ParseRecorder(command)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Invalid PRIMER_CAPTURE: %v\n", err)
+				os.Exit(1)
+			}
+		} else {
+			recorder, err = voice.DetectRecorder(exec.LookPath)
+		}
+		if err != nil {
+			logger.Warn("voice input unavailable", "error", err)
+		} else {
+			logger.Info("voice input enabled", "transcriber", os.Getenv("PRIMER_TRANSCRIBER"), "capture", recorder.Name)
+			tuiOpts = append(tuiOpts, tui.WithVoiceInput(recorder, transcriber))
+		}
+	}
+
 	// Create and run the TUI
 	logger.Info("starting TUI")
-	model := tui.New(database, aiClient, logger)
+	model := tui.New(database, aiClient, logger, tuiOpts...)
 
 	program := tea.NewProgram(model, tea.WithAltScreen())
 	if _, err := program.Run(); err != nil {
@@ -151,6 +398,79 @@ func main() {
 	logger.Info("application exited successfully")
 }
 
+// moderationGuard builds the moderation guard from PRIMER_MODERATION, a
+// comma-separated list of "rules" and "openai", or "off". It returns nil when
+// moderation is off.
+func moderationGuard(client *ai.OpenAIClient) (*ai.Guard, error) {
+	setting := os.Getenv("PRIMER_MODERATION")
+	if setting == "" {
+		setting = "rules"
+	}
+	if setting == "off" {
+		return nil, nil
+	}
+
+	guard := &ai.Guard{Rewriter: client}
+	for _, name := range strings.Split(setting, ",") {
+		switch strings.TrimSpace(name) {
+		case "rules":
+			rules := ai.DefaultRules()
+			if path := configPath("PRIMER_MODERATION_RULES", "moderation.json"); path != "" {
+				extra, err := ai.LoadRules(path)
+				if err != nil {
+					return nil, err
+				}
+				rules = append(rules, extra...)
+			}
+			moderator, err := ai.NewRuleModerator(rules)
+			if err != nil {
+				return nil, err
+			}
+			guard.Moderators = append(guard.Moderators, moderator)
+		case "openai":
+			guard.Moderators = append(guard.Moderators, client)
+		default:
+			return nil, fmt.Errorf("unknown moderator %q in PRIMER_MODERATION (want rules, openai or off)", name)
+		}
+	}
+
+	guard.Policy.Default = ai.ActionBlock
+	if name := os.Getenv("PRIMER_MODERATION_ACTION"); name != "" {
+		action, err := ai.ParseAction(name)
+		if err != nil {
+			return nil, err
+		}
+		guard.Policy.Default = action
+	}
+
+	categories, err := ai.ParseCategoryActions(os.Getenv("PRIMER_MODERATION_CATEGORIES"))
+	if err != nil {
+		return nil, err
+	}
+	guard.Policy.Categories = categories
+	return guard, nil
+}
+
+// configPath returns the user configuration file or directory to load, if any.
+// The environment variable takes precedence over the default primer/<name> in
+// the user's config directory, which is only used when it exists.
+func configPath(envVar, name string) string {
+	if path := os.Getenv(envVar); path != "" {
+		return path
+	}
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+
+	path := filepath.Join(configDir, "primer", name)
+	if _, err := os.Stat(path); err != nil {
+		return ""
+	}
+	return path
+}
+
 // maskDatabaseURL masks sensitive parts of a database URL for logging.
 func maskDatabaseURL(url string) string {
 	// Simple masking - in production you'd want to parse the URL properly
@@ -159,3 +479,36 @@ func maskDatabaseURL(url string) string {
 	}
 	return url
 }
+
+// setPIN makes a user a guardian with the PIN read from f: typed without
+// echo on a terminal, otherwise the first line of input.
+func setPIN(ctx context.Context, database *db.Database, userID string, f *os.File) error {
+	user, err := database.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("New PIN for %s (at least %d digits): ", user.DisplayName(), models.MinPINLength)
+	pin, err := readPIN(f)
+	if err != nil {
+		return fmt.Errorf("read PIN: %w", err)
+	}
+	if err := user.SetPIN(strings.TrimSpace(pin)); err != nil {
+		return err
+	}
+	user.Role = models.RoleGuardian
+	return database.UpdateUser(ctx, user)
+}
+
+// readPIN reads a PIN from f, without echoing it if f is a terminal.
+func readPIN(f *os.File) (string, error) {
+	if term.IsTerminal(int(f.Fd())) {
+		pin, err := term.ReadPassword(int(f.Fd()))
+		fmt.Println()
+		return string(pin), err
+	}
+	pin, err := bufio.NewReader(f).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	return pin, nil
+}
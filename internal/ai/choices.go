@@ -0,0 +1,173 @@
+package ai
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// MinChoices and MaxChoices bound the next actions suggested with a page.
+	MinChoices = 2
+	MaxChoices = 4
+)
+
+// textOptions is the "text" parameter of the Responses API.
+type textOptions struct {
+	Format textFormat `json:"format"`
+}
+
+// textFormat asks for output matching a JSON schema.
+type textFormat struct {
+	Type   string          `json:"type"`
+	Name   string          `json:"name"`
+	Strict bool            `json:"strict"`
+	Schema json.RawMessage `json:"schema"`
+}
+
+// storyPageSchema is the JSON schema for a StoryPage. The text comes first so
+// it can be shown while the rest is streaming.
+var storyPageSchema = json.RawMessage(`{
+	"type": "object",
+	"properties": {
+		"text": {
+			"type": "string",
+			"description": "The next page of the story, in markdown."
+		},
+		"choices": {
+			"type": "array",
+			"description": "2 to 4 short things the child could choose to do next, each written as the child would say it.",
+			"items": {"type": "string"},
+			"minItems": 2,
+			"maxItems": 4
+		},
+		"objectives": {
+			"type": "array",
+			"description": "The learning goals from the instructions that this page worked on, named exactly as given. Empty if there are none.",
+			"items": {"type": "string"}
+		}
+	},
+	"required": ["text", "choices", "objectives"],
+	"additionalProperties": false
+}`)
+
+// Format is a structured output a request can ask for instead of free text.
+type Format struct {
+	name   string
+	schema json.RawMessage
+}
+
+// StoryPageFormat asks for a StoryPage instead of free text. The response
+// text is then JSON, to be read with ParseStoryPage (or PartialPageText
+// while streaming).
+var StoryPageFormat = &Format{name: "story_page", schema: storyPageSchema}
+
+// textOptions returns the "text" parameter asking for the format, or nil
+// for free text.
+func (f *Format) textOptions() *textOptions {
+	if f == nil {
+		return nil
+	}
+	return &textOptions{Format: textFormat{
+		Type:   "json_schema",
+		Name:   f.name,
+		Strict: true,
+		Schema: f.schema,
+	}}
+}
+
+// StoryPage is a page of the story with suggested next actions, and the
+// titles of the learning objectives it worked on.
+type StoryPage struct {
+	Text       string   `json:"text"`
+	Choices    []string `json:"choices"`
+	Objectives []string `json:"objectives"`
+}
+
+// ErrEmptyPage is returned by ParseStoryPage for a page without text.
+var ErrEmptyPage = errors.New("story page has no text")
+
+// ParseStoryPage parses and validates a structured response. Choices are
+// trimmed, with blanks and repeats dropped and at most MaxChoices kept; if
+// fewer than MinChoices are left the page has none, and the child writes
+// their own next prompt.
+func ParseStoryPage(raw string) (StoryPage, error) {
+	var page StoryPage
+	if err := json.Unmarshal([]byte(raw), &page); err != nil {
+		return StoryPage{}, fmt.Errorf("parse story page: %w", err)
+	}
+
+	page.Text = strings.TrimSpace(page.Text)
+	if page.Text == "" {
+		return StoryPage{}, ErrEmptyPage
+	}
+
+	var choices []string
+	seen := make(map[string]bool)
+	for _, choice := range page.Choices {
+		choice = strings.TrimSpace(choice)
+		if choice == "" || seen[strings.ToLower(choice)] {
+			continue
+		}
+		seen[strings.ToLower(choice)] = true
+		choices = append(choices, choice)
+	}
+	if len(choices) > MaxChoices {
+		choices = choices[:MaxChoices]
+	}
+	if len(choices) < MinChoices {
+		choices = nil
+	}
+	page.Choices = choices
+	return page, nil
+}
+
+// PartialPageText returns as much of a structured page's text as has
+// arrived in a partial response. Text that isn't JSON is returned as it is.
+func PartialPageText(raw string) string {
+	if !strings.HasPrefix(strings.TrimSpace(raw), "{") {
+		return raw
+	}
+
+	key := strings.Index(raw, `"text"`)
+	if key < 0 {
+		return ""
+	}
+	rest := strings.TrimLeft(raw[key+len(`"text"`):], " \t\r\n")
+	if !strings.HasPrefix(rest, ":") {
+		return ""
+	}
+	rest = strings.TrimLeft(rest[1:], " \t\r\n")
+	if !strings.HasPrefix(rest, `"`) {
+		return ""
+	}
+	rest = rest[1:]
+
+	// Find the end of the string, or of its last complete character.
+	end := len(rest)
+	for i := 0; i < len(rest); i++ {
+		if rest[i] == '"' {
+			end = i
+			break
+		}
+		if rest[i] != '\\' {
+			continue
+		}
+		size := 2 // an escape like \n
+		if i+1 < len(rest) && rest[i+1] == 'u' {
+			size = 6 // \uXXXX
+		}
+		if i+size > len(rest) {
+			end = i
+			break
+		}
+		i += size - 1
+	}
+
+	var text string
+	if err := json.Unmarshal([]byte(`"`+rest[:end]+`"`), &text); err != nil {
+		return ""
+	}
+	return text
+}
@@ -0,0 +1,101 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestParseStoryPage(t *testing.T) {
+	tests := []struct {
+		raw     string
+		text    string
+		choices []string
+		err     bool
+	}{
+		{
+			raw:     `{"text":"The fox found a door.","choices":["Open the door","Knock first"]}`,
+			text:    "The fox found a door.",
+			choices: []string{"Open the door", "Knock first"},
+		},
+		{
+			raw:     `{"text":" Hi ","choices":[" a ","A","","b","c","d","e"]}`,
+			text:    "Hi",
+			choices: []string{"a", "b", "c", "d"},
+		},
+		{
+			raw:  `{"text":"Only one way on.","choices":["Go on",""]}`,
+			text: "Only one way on.",
+		},
+		{raw: `{"text":"  ","choices":["a","b"]}`, err: true},
+		{raw: `Once upon a time`, err: true},
+	}
+	for _, tt := range tests {
+		page, err := ParseStoryPage(tt.raw)
+		if (err != nil) != tt.err {
+			t.Errorf("ParseStoryPage(%s) error = %v", tt.raw, err)
+			continue
+		}
+		if page.Text != tt.text || strings.Join(page.Choices, "|") != strings.Join(tt.choices, "|") {
+			t.Errorf("ParseStoryPage(%s) = %+v", tt.raw, page)
+		}
+	}
+	if _, err := ParseStoryPage(`{"text":""}`); !errors.Is(err, ErrEmptyPage) {
+		t.Errorf("error = %v, want ErrEmptyPage", err)
+	}
+}
+
+func TestPartialPageText(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{``, ``},
+		{`{"te`, ``},
+		{`{"text": "`, ``},
+		{`{"text": "Once upon`, `Once upon`},
+		{`{"text":"Line one\n`, "Line one\n"},
+		{`{"text":"Line one\`, `Line one`},
+		{`{"text":"caf\u00e`, `caf`},
+		{`{"text":"café \"au lait\"","cho`, `café "au lait"`},
+		{`Plain text reply`, `Plain text reply`},
+	}
+	for _, tt := range tests {
+		if got := PartialPageText(tt.raw); got != tt.want {
+			t.Errorf("PartialPageText(%q) = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestStoryPageFormat(t *testing.T) {
+	var sent map[string]json.RawMessage
+	client := &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		sent = nil
+		json.NewDecoder(req.Body).Decode(&sent)
+		body := `{"output":[{"type":"message","content":[{"type":"output_text","text":"ok"}]}]}`
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body))}, nil
+	})}
+	c := NewClient("key", WithHTTPClient(client))
+
+	if _, _, err := c.GenerateResponse(context.Background(), "hi", nil, RequestOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := sent["text"]; ok {
+		t.Error("a plain request should not set a text format")
+	}
+
+	if _, _, err := c.GenerateResponse(context.Background(), "hi", nil, RequestOptions{Format: StoryPageFormat}); err != nil {
+		t.Fatal(err)
+	}
+	var text textOptions
+	if err := json.Unmarshal(sent["text"], &text); err != nil {
+		t.Fatalf("text = %s: %v", sent["text"], err)
+	}
+	if text.Format.Type != "json_schema" || text.Format.Name != "story_page" || !text.Format.Strict || !json.Valid(text.Format.Schema) {
+		t.Errorf("text format = %+v", text.Format)
+	}
+}
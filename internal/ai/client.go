@@ -31,10 +31,48 @@ const (
 	responsesAPIURL = "https://api.openai.com/v1/responses"
 )
 
-// Client is an interface for AI operations.
+// Client is an interface for AI operations. Both calls report the tokens
+// the request used: GenerateResponse returns them, and a Stream has them once
+// its chunks are done.
 type Client interface {
-	GenerateResponse(ctx context.Context, message string, history []string) (string, error)
-	GenerateResponseStream(ctx context.Context, message string, history []string) (<-chan string, error)
+	GenerateResponse(ctx context.Context, message string, history []string, opts RequestOptions) (string, Tokens, error)
+	GenerateResponseStream(ctx context.Context, message string, history []string, opts RequestOptions) (*Stream, error)
+}
+
+// RequestOptions shape a single request. The zero value asks for free text
+// with SystemPrompt().
+type RequestOptions struct {
+	System string  // system prompt, such as one from RenderSystemPrompt; SystemPrompt() if empty
+	Format *Format // structured output to ask for, such as StoryPageFormat; nil for free text
+}
+
+// systemPrompt returns the system prompt to use for a request.
+func (o RequestOptions) systemPrompt() string {
+	if o.System != "" {
+		return o.System
+	}
+	return SystemPrompt()
+}
+
+// Stream is a response arriving in pieces.
+type Stream struct {
+	Chunks <-chan string // the text as it arrives, closed at the end
+	tokens *Tokens       // set before Chunks is closed
+}
+
+// NewStream returns a Stream of chunks with the tokens reported at the end,
+// for clients other than OpenAIClient.
+func NewStream(chunks <-chan string, tokens *Tokens) *Stream {
+	return &Stream{Chunks: chunks, tokens: tokens}
+}
+
+// Tokens returns the tokens the response used. They are only complete once
+// Chunks has been closed.
+func (s *Stream) Tokens() Tokens {
+	if s.tokens == nil {
+		return Tokens{}
+	}
+	return *s.tokens
 }
 
 // OpenAIClient implements the Client interface using OpenAI's GPT-5 Responses API.
@@ -45,6 +83,8 @@ type OpenAIClient struct {
 	maxTokens  int
 	httpClient *http.Client
 	logger     *slog.Logger
+
+	moderationModel string
 }
 
 // ClientOption is a function that configures an OpenAIClient.
@@ -64,6 +104,13 @@ func WithMaxTokens(tokens int) ClientOption {
 	}
 }
 
+// WithModerationModel sets the model used by Moderate.
+func WithModerationModel(model string) ClientOption {
+	return func(c *OpenAIClient) {
+		c.moderationModel = model
+	}
+}
+
 // WithOrgID sets the organization ID for project-scoped keys.
 func WithOrgID(orgID string) ClientOption {
 	return func(c *OpenAIClient) {
@@ -93,6 +140,8 @@ func NewClient(apiKey string, opts ...ClientOption) *OpenAIClient {
 		maxTokens:  DefaultMaxTokens,
 		httpClient: http.DefaultClient,
 		logger:     slog.Default(),
+
+		moderationModel: DefaultModerationModel,
 	}
 
 	for _, opt := range opts {
@@ -114,16 +163,17 @@ type responsesRequest struct {
 	Input    json.RawMessage `json:"input"`
 	Stream   bool            `json:"stream,omitempty"`
 	MaxTokens int            `json:"max_output_tokens,omitempty"`
+	Text     *textOptions    `json:"text,omitempty"`
 }
 
 // buildInput creates the input array for the Responses API.
-func (c *OpenAIClient) buildInput(message string, history []string) json.RawMessage {
+func (c *OpenAIClient) buildInput(opts RequestOptions, message string, history []string) json.RawMessage {
 	var messages []map[string]string
 
 	// Add system message
 	messages = append(messages, map[string]string{
 		"role":    "system",
-		"content": SystemPrompt(),
+		"content": opts.systemPrompt(),
 	})
 
 	// Add conversation history (alternating user/assistant)
@@ -149,21 +199,22 @@ func (c *OpenAIClient) buildInput(message string, history []string) json.RawMess
 }
 
 // GenerateResponse sends a message and returns the complete response.
-func (c *OpenAIClient) GenerateResponse(ctx context.Context, message string, history []string) (string, error) {
+func (c *OpenAIClient) GenerateResponse(ctx context.Context, message string, history []string, opts RequestOptions) (string, Tokens, error) {
 	reqBody := responsesRequest{
 		Model:     c.model,
-		Input:     c.buildInput(message, history),
+		Input:     c.buildInput(opts, message, history),
 		MaxTokens: c.maxTokens,
+		Text:      opts.Format.textOptions(),
 	}
 
 	bodyBytes, err := json.Marshal(reqBody)
 	if err != nil {
-		return "", fmt.Errorf("marshal request: %w", err)
+		return "", Tokens{}, fmt.Errorf("marshal request: %w", err)
 	}
 
 	req, err := http.NewRequestWithContext(ctx, "POST", responsesAPIURL, bytes.NewReader(bodyBytes))
 	if err != nil {
-		return "", fmt.Errorf("create request: %w", err)
+		return "", Tokens{}, fmt.Errorf("create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
@@ -179,13 +230,13 @@ func (c *OpenAIClient) GenerateResponse(ctx context.Context, message string, his
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("send request: %w", err)
+		return "", Tokens{}, fmt.Errorf("send request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+		return "", Tokens{}, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
 	}
 
 	var result struct {
@@ -204,7 +255,7 @@ func (c *OpenAIClient) GenerateResponse(ctx context.Context, message string, his
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return "", fmt.Errorf("decode response: %w", err)
+		return "", Tokens{}, fmt.Errorf("decode response: %w", err)
 	}
 
 	c.logger.Info("received response from OpenAI",
@@ -212,6 +263,7 @@ func (c *OpenAIClient) GenerateResponse(ctx context.Context, message string, his
 		"input_tokens", result.Usage.InputTokens,
 		"output_tokens", result.Usage.OutputTokens,
 	)
+	tokens := Tokens{Input: int64(result.Usage.InputTokens), Output: int64(result.Usage.OutputTokens)}
 
 	// Extract text from response
 	for _, output := range result.Output {
@@ -219,23 +271,24 @@ func (c *OpenAIClient) GenerateResponse(ctx context.Context, message string, his
 			for _, content := range output.Content {
 				// OpenAI Responses API uses "output_text" as the content type
 				if content.Type == "output_text" || content.Type == "text" {
-					return content.Text, nil
+					return content.Text, tokens, nil
 				}
 			}
 		}
 	}
 
 	c.logger.Warn("empty response from OpenAI")
-	return "", nil
+	return "", tokens, nil
 }
 
 // GenerateResponseStream sends a message and returns a channel for streaming the response.
-func (c *OpenAIClient) GenerateResponseStream(ctx context.Context, message string, history []string) (<-chan string, error) {
+func (c *OpenAIClient) GenerateResponseStream(ctx context.Context, message string, history []string, opts RequestOptions) (*Stream, error) {
 	reqBody := responsesRequest{
 		Model:     c.model,
-		Input:     c.buildInput(message, history),
+		Input:     c.buildInput(opts, message, history),
 		Stream:    true,
 		MaxTokens: c.maxTokens,
+		Text:      opts.Format.textOptions(),
 	}
 
 	bodyBytes, err := json.Marshal(reqBody)
@@ -272,6 +325,7 @@ func (c *OpenAIClient) GenerateResponseStream(ctx context.Context, message strin
 	}
 
 	ch := make(chan string, 100)
+	tokens := &Tokens{}
 
 	go func() {
 		defer close(ch)
@@ -298,14 +352,25 @@ func (c *OpenAIClient) GenerateResponseStream(ctx context.Context, message strin
 
 				// Parse the event
 				var event struct {
-					Type  string `json:"type"`
-					Delta string `json:"delta"`
+					Type     string `json:"type"`
+					Delta    string `json:"delta"`
+					Response struct {
+						Usage struct {
+							InputTokens  int64 `json:"input_tokens"`
+							OutputTokens int64 `json:"output_tokens"`
+						} `json:"usage"`
+					} `json:"response"`
 				}
 				if err := json.Unmarshal([]byte(data), &event); err != nil {
 					c.logger.Debug("failed to parse event", "data", data)
 					continue
 				}
 
+				// The final event reports the tokens used
+				if event.Type == "response.completed" {
+					*tokens = Tokens{Input: event.Response.Usage.InputTokens, Output: event.Response.Usage.OutputTokens}
+				}
+
 				// Send text deltas to the channel
 				if event.Type == "response.output_text.delta" && event.Delta != "" {
 					select {
@@ -323,5 +388,5 @@ func (c *OpenAIClient) GenerateResponseStream(ctx context.Context, message strin
 		}
 	}()
 
-	return ch, nil
+	return NewStream(ch, tokens), nil
 }
@@ -156,14 +156,14 @@ func TestStreamingClient(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	ch, err := client.GenerateResponseStream(ctx, "Say 'hello' and nothing else.", nil)
+	stream, err := client.GenerateResponseStream(ctx, "Say 'hello' and nothing else.", nil, ai.RequestOptions{})
 	if err != nil {
 		t.Fatalf("GenerateResponseStream failed: %v", err)
 	}
 
 	var fullResponse strings.Builder
 	chunkCount := 0
-	for chunk := range ch {
+	for chunk := range stream.Chunks {
 		chunkCount++
 		fullResponse.WriteString(chunk)
 		t.Logf("CHUNK %d: %q", chunkCount, chunk)
@@ -193,7 +193,7 @@ func TestNonStreamingClient(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	response, err := client.GenerateResponse(ctx, "Say 'hello' and nothing else.", nil)
+	response, _, err := client.GenerateResponse(ctx, "Say 'hello' and nothing else.", nil, ai.RequestOptions{})
 	if err != nil {
 		t.Fatalf("GenerateResponse failed: %v", err)
 	}
@@ -222,7 +222,7 @@ func TestCompareStreamingAndNonStreaming(t *testing.T) {
 
 	// Test non-streaming first
 	t.Log("Testing non-streaming...")
-	nonStreamResp, err := client.GenerateResponse(ctx, "What is 2+2? Answer with just the number.", nil)
+	nonStreamResp, _, err := client.GenerateResponse(ctx, "What is 2+2? Answer with just the number.", nil, ai.RequestOptions{})
 	if err != nil {
 		t.Fatalf("Non-streaming failed: %v", err)
 	}
@@ -230,13 +230,13 @@ func TestCompareStreamingAndNonStreaming(t *testing.T) {
 
 	// Test streaming
 	t.Log("Testing streaming...")
-	ch, err := client.GenerateResponseStream(ctx, "What is 2+2? Answer with just the number.", nil)
+	stream, err := client.GenerateResponseStream(ctx, "What is 2+2? Answer with just the number.", nil, ai.RequestOptions{})
 	if err != nil {
 		t.Fatalf("Streaming failed: %v", err)
 	}
 
 	var streamResp strings.Builder
-	for chunk := range ch {
+	for chunk := range stream.Chunks {
 		streamResp.WriteString(chunk)
 	}
 	t.Logf("Streaming response: %q", streamResp.String())
@@ -30,7 +30,7 @@ func TestOpenAIClient_RealAPI(t *testing.T) {
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 
-		response, err := client.GenerateResponse(ctx, "Say hello in one word.", nil)
+		response, _, err := client.GenerateResponse(ctx, "Say hello in one word.", nil, ai.RequestOptions{})
 		if err != nil {
 			t.Fatalf("GenerateResponse failed: %v", err)
 		}
@@ -51,7 +51,7 @@ func TestOpenAIClient_RealAPI(t *testing.T) {
 			"2+2 equals 4.",
 		}
 
-		response, err := client.GenerateResponse(ctx, "What did I just ask you?", history)
+		response, _, err := client.GenerateResponse(ctx, "What did I just ask you?", history, ai.RequestOptions{})
 		if err != nil {
 			t.Fatalf("GenerateResponse failed: %v", err)
 		}
@@ -67,13 +67,13 @@ func TestOpenAIClient_RealAPI(t *testing.T) {
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 
-		ch, err := client.GenerateResponseStream(ctx, "Count from 1 to 3.", nil)
+		stream, err := client.GenerateResponseStream(ctx, "Count from 1 to 3.", nil, ai.RequestOptions{})
 		if err != nil {
 			t.Fatalf("GenerateResponseStream failed: %v", err)
 		}
 
 		var fullResponse string
-		for chunk := range ch {
+		for chunk := range stream.Chunks {
 			fullResponse += chunk
 		}
 
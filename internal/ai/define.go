@@ -0,0 +1,45 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrEmptyDefinition is returned by DefineWord when the model says nothing.
+var ErrEmptyDefinition = errors.New("definition is empty")
+
+// RenderDefinePrompt renders the system prompt for explaining words to a child.
+func RenderDefinePrompt(pc PromptContext) (string, error) {
+	var b bytes.Buffer
+	if err := systemTemplate.ExecuteTemplate(&b, "define", pc); err != nil {
+		return "", fmt.Errorf("render define prompt: %w", err)
+	}
+	return b.String(), nil
+}
+
+// DefineWord asks the model to explain a word to the child in pc. The
+// sentence it appeared in, if known, picks the right meaning.
+func DefineWord(ctx context.Context, client Client, pc PromptContext, word, sentence string) (string, Tokens, error) {
+	system, err := RenderDefinePrompt(pc)
+	if err != nil {
+		return "", Tokens{}, err
+	}
+
+	message := fmt.Sprintf("What does %q mean?", word)
+	if sentence != "" {
+		message += fmt.Sprintf(" It is used in this sentence: %q", sentence)
+	}
+
+	text, tokens, err := client.GenerateResponse(ctx, message, nil, RequestOptions{System: system})
+	if err != nil {
+		return "", tokens, fmt.Errorf("define word: %w", err)
+	}
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "", tokens, ErrEmptyDefinition
+	}
+	return text, tokens, nil
+}
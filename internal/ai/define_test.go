@@ -0,0 +1,55 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/kbrakke/illustrated-primer/internal/models"
+)
+
+func TestRenderDefinePrompt(t *testing.T) {
+	tests := []struct {
+		name string
+		pc   PromptContext
+	}{
+		{"define_toddler", PromptContext{Name: "Nell", Age: 3, Band: models.AgeBandToddler}},
+		{"define_reader", PromptContext{Name: "Nell", Age: 9, Band: models.AgeBandReader, Language: "es"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderDefinePrompt(tt.pc)
+			if err != nil {
+				t.Fatalf("RenderDefinePrompt() error = %v", err)
+			}
+			checkGolden(t, tt.name, got)
+		})
+	}
+}
+
+func TestDefineWord(t *testing.T) {
+	client := &recordingClient{response: "  Enormous means very, very big.\n"}
+	text, _, err := DefineWord(context.Background(), client, PromptContext{Name: "Nell", Band: models.AgeBandToddler},
+		"enormous", "An enormous caterpillar.")
+	if err != nil {
+		t.Fatalf("DefineWord() error = %v", err)
+	}
+	if text != "Enormous means very, very big." {
+		t.Errorf("definition = %q", text)
+	}
+	if !strings.Contains(client.message, `"enormous"`) || !strings.Contains(client.message, "An enormous caterpillar.") {
+		t.Errorf("message = %q, want the word and its sentence", client.message)
+	}
+	if system := client.opts.System; !strings.Contains(system, "toddler") {
+		t.Errorf("system prompt not pitched at a toddler:\n%s", system)
+	}
+	if client.opts.Format != nil {
+		t.Error("definitions are free text")
+	}
+
+	client.response = " "
+	if _, _, err := DefineWord(context.Background(), client, PromptContext{}, "enormous", ""); !errors.Is(err, ErrEmptyDefinition) {
+		t.Errorf("DefineWord() error = %v, want ErrEmptyDefinition", err)
+	}
+}
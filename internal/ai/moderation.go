@@ -0,0 +1,303 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+const (
+	// DefaultModerationModel is the OpenAI moderation model.
+	DefaultModerationModel = "omni-moderation-latest"
+
+	moderationsAPIURL = "https://api.openai.com/v1/moderations"
+)
+
+// Action is what happens to text that a Moderator objects to.
+type Action string
+
+const (
+	// ActionAllow lets the text through unrecorded.
+	ActionAllow Action = "allow"
+	// ActionFlag lets the text through and records it for a parent to review.
+	ActionFlag Action = "flag"
+	// ActionRewrite replaces the offending text and records it.
+	ActionRewrite Action = "rewrite"
+	// ActionBlock stops the text from being sent, shown or saved, and records it.
+	ActionBlock Action = "block"
+)
+
+// severity orders actions so the strictest one found wins.
+var severity = map[Action]int{ActionAllow: 0, ActionFlag: 1, ActionRewrite: 2, ActionBlock: 3}
+
+// ParseAction returns the Action with the given name.
+func ParseAction(name string) (Action, error) {
+	action := Action(strings.ToLower(strings.TrimSpace(name)))
+	if _, ok := severity[action]; !ok {
+		return "", fmt.Errorf("unknown moderation action %q (want allow, flag, rewrite or block)", name)
+	}
+	return action, nil
+}
+
+// Source says whose text is being moderated.
+type Source string
+
+const (
+	SourcePrompt     Source = "prompt"     // written by the child
+	SourceCompletion Source = "completion" // written by the model
+)
+
+// Finding is one problem a Moderator found in a text.
+type Finding struct {
+	Category string
+	// Match is the offending text, or "" if the moderator can't locate it
+	// (as with the OpenAI endpoint, which only classifies the whole text).
+	Match string
+	// Replacement is used for Match when the text is rewritten.
+	Replacement string
+	// Action overrides the policy for this finding when set.
+	Action Action
+}
+
+// Moderator checks text for content that isn't suitable for children.
+type Moderator interface {
+	Moderate(ctx context.Context, text string) ([]Finding, error)
+}
+
+// moderationRequest is the request body for the moderations API.
+type moderationRequest struct {
+	Model string `json:"model"`
+	Input string `json:"input"`
+}
+
+// Moderate classifies text with the OpenAI moderation endpoint, returning a
+// finding for each flagged category.
+func (c *OpenAIClient) Moderate(ctx context.Context, text string) ([]Finding, error) {
+	bodyBytes, err := json.Marshal(moderationRequest{Model: c.moderationModel, Input: text})
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", moderationsAPIURL, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	if c.orgID != "" {
+		req.Header.Set("OpenAI-Organization", c.orgID)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var result struct {
+		Results []struct {
+			Flagged    bool            `json:"flagged"`
+			Categories map[string]bool `json:"categories"`
+		} `json:"results"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	var findings []Finding
+	for _, r := range result.Results {
+		if !r.Flagged {
+			continue
+		}
+		for category, flagged := range r.Categories {
+			if flagged {
+				findings = append(findings, Finding{Category: category})
+			}
+		}
+	}
+	sort.Slice(findings, func(i, j int) bool { return findings[i].Category < findings[j].Category })
+
+	c.logger.Debug("moderated text", "flagged", len(findings) > 0, "categories", len(findings))
+	return findings, nil
+}
+
+// Policy chooses the action for each category of finding.
+type Policy struct {
+	Default    Action            // for categories not listed; ActionBlock if empty
+	Categories map[string]Action // keyed by finding category
+}
+
+// ActionFor returns the action to take for a finding.
+func (p Policy) ActionFor(f Finding) Action {
+	if f.Action != "" {
+		return f.Action
+	}
+	if action, ok := p.Categories[f.Category]; ok {
+		return action
+	}
+	if p.Default != "" {
+		return p.Default
+	}
+	return ActionBlock
+}
+
+// ParseCategoryActions parses per-category actions written as
+// "violence=block,profanity=rewrite".
+func ParseCategoryActions(spec string) (map[string]Action, error) {
+	actions := make(map[string]Action)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		category, name, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid moderation action %q (want category=action)", entry)
+		}
+		action, err := ParseAction(name)
+		if err != nil {
+			return nil, err
+		}
+		actions[strings.TrimSpace(category)] = action
+	}
+	return actions, nil
+}
+
+// Verdict is the outcome of checking a text.
+type Verdict struct {
+	Action   Action    // the strictest action over all findings
+	Text     string    // the text to use, rewritten when Action is ActionRewrite
+	Findings []Finding // everything the moderators found, including after a rewrite
+}
+
+// Categories returns the distinct categories of the verdict's findings.
+func (v Verdict) Categories() []string {
+	var categories []string
+	seen := make(map[string]bool)
+	for _, f := range v.Findings {
+		if !seen[f.Category] {
+			seen[f.Category] = true
+			categories = append(categories, f.Category)
+		}
+	}
+	return categories
+}
+
+// Guard runs text past a set of moderators and applies a policy to what they find.
+type Guard struct {
+	Moderators []Moderator
+	Policy     Policy
+	// Rewriter rewrites texts whose problems can't be located by a rule.
+	// Without one, such texts are blocked instead of rewritten.
+	Rewriter Client
+}
+
+// Check moderates text from the given source. A moderator error is returned
+// rather than letting unchecked text through.
+func (g *Guard) Check(ctx context.Context, source Source, text string) (Verdict, error) {
+	findings, err := g.findings(ctx, text)
+	if err != nil {
+		return Verdict{}, err
+	}
+
+	verdict := Verdict{Action: g.strictest(findings), Text: text, Findings: findings}
+	if verdict.Action != ActionRewrite {
+		return verdict, nil
+	}
+
+	rewritten, err := g.rewrite(ctx, source, text, findings)
+	if err != nil {
+		return Verdict{}, err
+	}
+	if rewritten == "" {
+		verdict.Action = ActionBlock
+		return verdict, nil
+	}
+
+	// The rewrite has to pass on its own.
+	again, err := g.findings(ctx, rewritten)
+	if err != nil {
+		return Verdict{}, err
+	}
+	verdict.Findings = append(verdict.Findings, again...)
+	if severity[g.strictest(again)] >= severity[ActionRewrite] {
+		verdict.Action = ActionBlock
+		return verdict, nil
+	}
+	verdict.Text = rewritten
+	return verdict, nil
+}
+
+func (g *Guard) findings(ctx context.Context, text string) ([]Finding, error) {
+	var findings []Finding
+	for _, m := range g.Moderators {
+		found, err := m.Moderate(ctx, text)
+		if err != nil {
+			return nil, fmt.Errorf("moderate text: %w", err)
+		}
+		findings = append(findings, found...)
+	}
+	return findings, nil
+}
+
+func (g *Guard) strictest(findings []Finding) Action {
+	action := ActionAllow
+	for _, f := range findings {
+		if a := g.Policy.ActionFor(f); severity[a] > severity[action] {
+			action = a
+		}
+	}
+	return action
+}
+
+// rewrite replaces located matches with their replacements, and has the
+// Rewriter rework the text when a problem couldn't be located. It returns ""
+// if the text can't be rewritten.
+func (g *Guard) rewrite(ctx context.Context, source Source, text string, findings []Finding) (string, error) {
+	needsRewriter := false
+	for _, f := range findings {
+		if g.Policy.ActionFor(f) != ActionRewrite {
+			continue
+		}
+		if f.Match == "" {
+			needsRewriter = true
+			continue
+		}
+		text = strings.ReplaceAll(text, f.Match, f.Replacement)
+	}
+	if !needsRewriter {
+		return text, nil
+	}
+	if g.Rewriter == nil {
+		return "", nil
+	}
+
+	rewritten, _, err := g.Rewriter.GenerateResponse(ctx, rewriteInstructions(source)+"\n\n"+text, nil, RequestOptions{})
+	if err != nil {
+		return "", fmt.Errorf("rewrite text: %w", err)
+	}
+	return strings.TrimSpace(rewritten), nil
+}
+
+// rewriteInstructions asks the model for a child-safe version of a text.
+func rewriteInstructions(source Source) string {
+	if source == SourcePrompt {
+		return "A child wrote the message below, but part of it isn't suitable for a children's story. " +
+			"Rewrite it so it is, keeping as much of the child's idea as you can. " +
+			"Reply with only the rewritten message."
+	}
+	return "The story passage below isn't suitable for young children. " +
+		"Rewrite it so it is, keeping the plot and the lesson where you can. " +
+		"Reply with only the rewritten passage."
+}
@@ -0,0 +1,202 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestOpenAIModerate(t *testing.T) {
+	var sent moderationRequest
+	client := &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if req.URL.String() != moderationsAPIURL {
+			t.Errorf("unexpected URL %s", req.URL)
+		}
+		json.NewDecoder(req.Body).Decode(&sent)
+		body := `{"results":[{"flagged":true,"categories":{"violence":true,"sexual":false,"harassment":true}}]}`
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body))}, nil
+	})}
+
+	c := NewClient("key", WithHTTPClient(client))
+	findings, err := c.Moderate(context.Background(), "some text")
+	if err != nil {
+		t.Fatalf("Moderate() error = %v", err)
+	}
+	if sent.Model != DefaultModerationModel || sent.Input != "some text" {
+		t.Errorf("unexpected request %+v", sent)
+	}
+	if len(findings) != 2 || findings[0].Category != "harassment" || findings[1].Category != "violence" {
+		t.Errorf("findings = %+v", findings)
+	}
+}
+
+func TestRuleModerator(t *testing.T) {
+	m, err := NewRuleModerator(DefaultRules())
+	if err != nil {
+		t.Fatalf("NewRuleModerator() error = %v", err)
+	}
+
+	tests := []struct {
+		text       string
+		categories []string
+	}{
+		{"Tell me a story about a brave fox", nil},
+		{"my email is sam@example.com", []string{"personal-information"}},
+		{"call me on 555-123-4567 ok", []string{"personal-information"}},
+		{"that SHIT dragon", []string{"profanity"}},
+		{"the shitake mushroom", nil}, // whole words only
+		{"sometimes I want to  die", []string{"self-harm"}},
+		{"there were 3 pigs and 1 wolf", nil},
+	}
+	for _, tt := range tests {
+		findings, _ := m.Moderate(context.Background(), tt.text)
+		var got []string
+		for _, f := range findings {
+			got = append(got, f.Category)
+		}
+		if strings.Join(got, ",") != strings.Join(tt.categories, ",") {
+			t.Errorf("Moderate(%q) categories = %v, want %v", tt.text, got, tt.categories)
+		}
+	}
+
+	if _, err := NewRuleModerator([]Rule{{Category: "x", Pattern: "("}}); err == nil {
+		t.Error("expected an error for an invalid pattern")
+	}
+	if _, err := NewRuleModerator([]Rule{{Category: "x"}}); err == nil {
+		t.Error("expected an error for a rule without words or pattern")
+	}
+}
+
+// stubModerator flags every text containing one of its words.
+type stubModerator struct {
+	words []string
+	err   error
+}
+
+func (s stubModerator) Moderate(_ context.Context, text string) ([]Finding, error) {
+	var findings []Finding
+	for _, w := range s.words {
+		if strings.Contains(text, w) {
+			findings = append(findings, Finding{Category: "violence"})
+		}
+	}
+	return findings, s.err
+}
+
+type stubRewriter struct {
+	response string
+	calls    int
+}
+
+func (r *stubRewriter) GenerateResponse(context.Context, string, []string, RequestOptions) (string, Tokens, error) {
+	r.calls++
+	return r.response, Tokens{}, nil
+}
+
+func (r *stubRewriter) GenerateResponseStream(context.Context, string, []string, RequestOptions) (*Stream, error) {
+	return nil, errors.New("not implemented")
+}
+
+func TestGuard(t *testing.T) {
+	rules, err := NewRuleModerator(DefaultRules())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	t.Run("allows clean text", func(t *testing.T) {
+		g := &Guard{Moderators: []Moderator{rules}}
+		v, err := g.Check(ctx, SourcePrompt, "a story about owls")
+		if err != nil || v.Action != ActionAllow || v.Text != "a story about owls" {
+			t.Errorf("Check() = %+v, %v", v, err)
+		}
+	})
+
+	t.Run("rewrites located matches", func(t *testing.T) {
+		g := &Guard{Moderators: []Moderator{rules}}
+		v, _ := g.Check(ctx, SourcePrompt, "write to me at sam@example.com")
+		if v.Action != ActionRewrite || v.Text != "write to me at [private]" {
+			t.Errorf("Check() = %+v", v)
+		}
+	})
+
+	t.Run("flags without changing the text", func(t *testing.T) {
+		g := &Guard{Moderators: []Moderator{rules}}
+		v, _ := g.Check(ctx, SourcePrompt, "I want to die, said the tired dragon")
+		if v.Action != ActionFlag || v.Text != "I want to die, said the tired dragon" {
+			t.Errorf("Check() = %+v", v)
+		}
+	})
+
+	t.Run("policy chooses actions per category", func(t *testing.T) {
+		g := &Guard{
+			Moderators: []Moderator{stubModerator{words: []string{"sword"}}},
+			Policy:     Policy{Default: ActionBlock, Categories: map[string]Action{"violence": ActionFlag}},
+		}
+		if v, _ := g.Check(ctx, SourceCompletion, "a sword fight"); v.Action != ActionFlag {
+			t.Errorf("Action = %s, want flag", v.Action)
+		}
+	})
+
+	t.Run("rewrites unlocated findings with the rewriter", func(t *testing.T) {
+		rewriter := &stubRewriter{response: " The knights played a friendly game. "}
+		g := &Guard{
+			Moderators: []Moderator{stubModerator{words: []string{"sword"}}},
+			Policy:     Policy{Default: ActionRewrite},
+			Rewriter:   rewriter,
+		}
+		v, _ := g.Check(ctx, SourceCompletion, "a sword fight")
+		if v.Action != ActionRewrite || v.Text != "The knights played a friendly game." || rewriter.calls != 1 {
+			t.Errorf("Check() = %+v after %d calls", v, rewriter.calls)
+		}
+	})
+
+	t.Run("blocks a rewrite that still fails", func(t *testing.T) {
+		g := &Guard{
+			Moderators: []Moderator{stubModerator{words: []string{"sword"}}},
+			Policy:     Policy{Default: ActionRewrite},
+			Rewriter:   &stubRewriter{response: "a bigger sword fight"},
+		}
+		if v, _ := g.Check(ctx, SourceCompletion, "a sword fight"); v.Action != ActionBlock {
+			t.Errorf("Action = %s, want block", v.Action)
+		}
+	})
+
+	t.Run("blocks when it can't rewrite", func(t *testing.T) {
+		g := &Guard{
+			Moderators: []Moderator{stubModerator{words: []string{"sword"}}},
+			Policy:     Policy{Default: ActionRewrite},
+		}
+		if v, _ := g.Check(ctx, SourceCompletion, "a sword fight"); v.Action != ActionBlock {
+			t.Errorf("Action = %s, want block", v.Action)
+		}
+	})
+
+	t.Run("fails closed", func(t *testing.T) {
+		g := &Guard{Moderators: []Moderator{stubModerator{err: errors.New("offline")}}}
+		if _, err := g.Check(ctx, SourcePrompt, "hello"); err == nil {
+			t.Error("expected the moderator's error")
+		}
+	})
+}
+
+func TestParseCategoryActions(t *testing.T) {
+	actions, err := ParseCategoryActions("violence=flag, profanity = rewrite")
+	if err != nil {
+		t.Fatalf("ParseCategoryActions() error = %v", err)
+	}
+	if actions["violence"] != ActionFlag || actions["profanity"] != ActionRewrite {
+		t.Errorf("actions = %v", actions)
+	}
+	if _, err := ParseCategoryActions("violence=explode"); err == nil {
+		t.Error("expected an error for an unknown action")
+	}
+}
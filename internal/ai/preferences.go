@@ -0,0 +1,122 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kbrakke/illustrated-primer/internal/models"
+)
+
+// Finding categories for a child's content preferences.
+const (
+	CategoryBannedTopic = "banned-topic"
+	CategorySensitivity = "sensitivity"
+)
+
+// relatedWords are other words for common sensitivities, so that "death"
+// also catches a character who "died".
+var relatedWords = map[string][]string{
+	"death":       {"dead", "die", "dies", "died", "dying", "killed"},
+	"blood":       {"bleed", "bleeding", "bloody"},
+	"loud noises": {"bang", "boom", "crash", "explosion", "thunder"},
+}
+
+// PreferenceRules returns rules that find a child's banned topics and
+// sensitivities in a text. Each topic is matched as a phrase in its singular
+// and plural forms, along with related words for a few common
+// sensitivities. Every match blocks.
+func PreferenceRules(prefs models.ContentPreferences) []Rule {
+	var rules []Rule
+	if words := topicWords(prefs.Banned); len(words) > 0 {
+		rules = append(rules, Rule{Category: CategoryBannedTopic, Words: words, Action: ActionBlock})
+	}
+	if words := topicWords(prefs.Sensitivities); len(words) > 0 {
+		rules = append(rules, Rule{Category: CategorySensitivity, Words: words, Action: ActionBlock})
+	}
+	return rules
+}
+
+// NewPreferenceGuard returns a guard that blocks text breaking a child's
+// content preferences, or nil if the preferences don't keep anything out.
+func NewPreferenceGuard(prefs models.ContentPreferences) (*Guard, error) {
+	if !prefs.Restricted() {
+		return nil, nil
+	}
+	moderator, err := NewRuleModerator(PreferenceRules(prefs))
+	if err != nil {
+		return nil, fmt.Errorf("compile content preferences: %w", err)
+	}
+	return &Guard{Moderators: []Moderator{moderator}}, nil
+}
+
+// topicWords returns every form of the topics to match, without repeats.
+func topicWords(topics []string) []string {
+	var words []string
+	seen := make(map[string]bool)
+	for _, topic := range topics {
+		for _, word := range topicForms(topic) {
+			if word != "" && !seen[word] {
+				seen[word] = true
+				words = append(words, word)
+			}
+		}
+	}
+	return words
+}
+
+// topicForms returns a topic with its last word in the singular and plural,
+// and any related words.
+func topicForms(topic string) []string {
+	topic = strings.ToLower(strings.Join(strings.Fields(topic), " "))
+	head, last := "", topic
+	if i := strings.LastIndex(topic, " "); i >= 0 {
+		head, last = topic[:i+1], topic[i+1:]
+	}
+
+	forms := []string{topic}
+	switch {
+	case strings.HasSuffix(last, "ies"):
+		forms = append(forms, head+strings.TrimSuffix(last, "ies")+"y")
+	case strings.HasSuffix(last, "es"):
+		forms = append(forms, head+strings.TrimSuffix(last, "s"), head+strings.TrimSuffix(last, "es"))
+	case strings.HasSuffix(last, "ss"), strings.HasSuffix(last, "x"), strings.HasSuffix(last, "ch"), strings.HasSuffix(last, "sh"):
+		forms = append(forms, topic+"es")
+	case strings.HasSuffix(last, "s"):
+		forms = append(forms, head+strings.TrimSuffix(last, "s"))
+	case strings.HasSuffix(last, "y") && !strings.HasSuffix(last, "ay") && !strings.HasSuffix(last, "ey") && !strings.HasSuffix(last, "oy"):
+		forms = append(forms, head+strings.TrimSuffix(last, "y")+"ies")
+	default:
+		forms = append(forms, topic+"s")
+	}
+
+	for _, form := range forms {
+		forms = append(forms, relatedWords[form]...)
+	}
+	return forms
+}
+
+// RegeneratePage asks the model again for the page it wrote in reply to
+// prompt, after a preference guard found words in it that the child's
+// grown-ups want kept out. opts should be the same as for the first attempt.
+func RegeneratePage(ctx context.Context, client Client, prompt string, history []string, verdict Verdict, opts RequestOptions) (string, Tokens, error) {
+	var found []string
+	seen := make(map[string]bool)
+	for _, f := range verdict.Findings {
+		word := strings.ToLower(f.Match)
+		if word != "" && !seen[word] {
+			seen[word] = true
+			found = append(found, fmt.Sprintf("%q", word))
+		}
+	}
+
+	message := prompt
+	if len(found) > 0 {
+		message += fmt.Sprintf("\n\n(Write this page without %s, or anything like them. The child's grown-ups asked for them to be left out.)", joinList(found))
+	}
+	text, tokens, err := client.GenerateResponse(ctx, message, history, opts)
+	if err != nil {
+		return "", tokens, fmt.Errorf("regenerate page: %w", err)
+	}
+	return strings.TrimSpace(text), tokens, nil
+}
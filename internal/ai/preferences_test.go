@@ -0,0 +1,87 @@
+package ai
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kbrakke/illustrated-primer/internal/models"
+)
+
+func TestTopicForms(t *testing.T) {
+	tests := []struct {
+		topic string
+		want  []string
+	}{
+		{"Scary  Monsters", []string{"scary monsters", "scary monster"}},
+		{"spider", []string{"spider", "spiders"}},
+		{"ponies", []string{"ponies", "pony"}},
+		{"witches", []string{"witches", "witche", "witch"}},
+		{"fox", []string{"fox", "foxes"}},
+		{"death", []string{"death", "deaths", "dead", "die", "dies", "died", "dying", "killed"}},
+		{"loud noise", []string{"loud noise", "loud noises", "bang", "boom", "crash", "explosion", "thunder"}},
+	}
+	for _, tt := range tests {
+		if got := topicForms(tt.topic); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("topicForms(%q) = %q, want %q", tt.topic, got, tt.want)
+		}
+	}
+}
+
+func TestPreferenceGuard(t *testing.T) {
+	if guard, err := NewPreferenceGuard(models.ContentPreferences{StoryThemes: []string{"dinosaurs"}}); guard != nil || err != nil {
+		t.Errorf("story themes alone shouldn't need a guard, got %v, %v", guard, err)
+	}
+
+	guard, err := NewPreferenceGuard(models.ContentPreferences{
+		Banned:        []string{"scary monsters"},
+		Sensitivities: []string{"death"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		text       string
+		action     Action
+		categories []string
+	}{
+		{"The dinosaur danced all night.", ActionAllow, nil},
+		{"A Scary\nMonster peeked out.", ActionBlock, []string{CategoryBannedTopic}},
+		{"The old fish died, and a scary monster wept.", ActionBlock, []string{CategoryBannedTopic, CategorySensitivity}},
+		{"They studied diet and dyes.", ActionAllow, nil},
+	}
+	for _, tt := range tests {
+		verdict, err := guard.Check(context.Background(), SourceCompletion, tt.text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if verdict.Action != tt.action || !reflect.DeepEqual(verdict.Categories(), tt.categories) {
+			t.Errorf("Check(%q) = %s %v, want %s %v", tt.text, verdict.Action, verdict.Categories(), tt.action, tt.categories)
+		}
+	}
+}
+
+func TestRegeneratePage(t *testing.T) {
+	client := &recordingClient{response: " The dinosaur waved goodbye. \n"}
+	verdict := Verdict{Action: ActionBlock, Findings: []Finding{
+		{Category: CategorySensitivity, Match: "Died"},
+		{Category: CategorySensitivity, Match: "died"},
+		{Category: CategoryBannedTopic, Match: "monster"},
+	}}
+	opts := RequestOptions{System: "Be gentle.", Format: StoryPageFormat}
+	text, _, err := RegeneratePage(context.Background(), client, "What happens next?", nil, verdict, opts)
+	if err != nil {
+		t.Fatalf("RegeneratePage() error = %v", err)
+	}
+	if text != "The dinosaur waved goodbye." {
+		t.Errorf("text = %q", text)
+	}
+	if !strings.HasPrefix(client.message, "What happens next?") || !strings.Contains(client.message, `without "died" and "monster"`) {
+		t.Errorf("message = %q, want the prompt and the words to leave out", client.message)
+	}
+	if client.opts != opts {
+		t.Errorf("options = %+v, want those of the first attempt", client.opts)
+	}
+}
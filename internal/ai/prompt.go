@@ -1,7 +1,135 @@
 package ai
 
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"math"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/kbrakke/illustrated-primer/internal/models"
+)
+
 // SystemPrompt returns the system prompt for the educational storytelling AI.
 // This prompt is designed for children aged 2-8 and emphasizes warm, educational content.
+// It is used when nothing is known about the child; see RenderSystemPrompt.
 func SystemPrompt() string {
 	return `You are a lovely and warm teacher who is able to expertly weave education into a story. You are also able to answer questions about the story. You primarily focus on children between the ages of 2 and 8 and will modify your tone and language to be appropriate for that age group. You allow for tangents in the story to help the child learn and grow, but ultimately try and steer them back to the main goal of the story. If the child asks completely unrelated questions you will answer as best you can, while trying to steer it back on topic. Be open and friendly, but also firm when needed.`
 }
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+var systemTemplate = template.Must(template.New("").Funcs(template.FuncMap{
+	"list":         joinList,
+	"languageName": languageName,
+	"capitalize":   capitalize,
+	"grade":        grade,
+}).ParseFS(templateFS, "templates/*.tmpl"))
+
+// PromptContext is what the system prompt is rendered from: the child's
+// profile and the story being told.
+type PromptContext struct {
+	Name         string         // the child's name, or "the child"
+	Age          int            // in years, 0 if unknown
+	Band         models.AgeBand // empty for the general 2-8 audience
+	ReadingLevel string
+	Interests    []string
+	Language     string // ISO 639-1 code
+	StoryTitle   string
+	Premise      string
+	Objectives   []string // titles of the story's learning objectives
+	Review       []string // words the child is due to practise
+	WrapUp       bool     // the child's screen time is nearly up
+
+	// The child's content preferences, set by their grown-ups
+	StoryThemes   []string // to bring in often
+	Avoid         []string // never to include
+	Sensitivities []string // such as loud noises or death
+}
+
+// NewPromptContext builds a PromptContext from a child's profile and story.
+// Either may be nil.
+func NewPromptContext(user *models.User, story *models.Story, now time.Time) PromptContext {
+	pc := PromptContext{Name: "the child"}
+	if user != nil {
+		if user.Name != nil && *user.Name != "" {
+			pc.Name = *user.Name
+		}
+		pc.Age, _ = user.Age(now)
+		pc.Band = user.Band(now)
+		if user.ReadingLevel != nil {
+			pc.ReadingLevel = *user.ReadingLevel
+		}
+		pc.Interests = user.Interests
+		if user.Language != nil {
+			pc.Language = *user.Language
+		}
+		pc.StoryThemes, pc.Avoid, pc.Sensitivities = user.StoryThemes, user.BannedTopics, user.Sensitivities
+	}
+	if story != nil {
+		pc.StoryTitle = story.Title
+		pc.Premise = story.Summary
+	}
+	return pc
+}
+
+// RenderSystemPrompt renders the system prompt for a child and story. The
+// age band sets vocabulary, sentence length and page length; the other
+// fields are added when known.
+func RenderSystemPrompt(pc PromptContext) (string, error) {
+	var b bytes.Buffer
+	if err := systemTemplate.ExecuteTemplate(&b, "system", pc); err != nil {
+		return "", fmt.Errorf("render system prompt: %w", err)
+	}
+	return b.String(), nil
+}
+
+// joinList joins items as English prose: "a", "a and b", "a, b and c".
+func joinList(items []string) string {
+	switch len(items) {
+	case 0:
+		return ""
+	case 1:
+		return items[0]
+	default:
+		return strings.Join(items[:len(items)-1], ", ") + " and " + items[len(items)-1]
+	}
+}
+
+// grade formats a reading grade level, rounded to a whole grade.
+func grade(level float64) string {
+	return fmt.Sprintf("%.0f", math.Max(level, 1))
+}
+
+// capitalize upper-cases the first letter, for names at the start of a sentence.
+func capitalize(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	return string(unicode.ToUpper(r)) + s[size:]
+}
+
+// languageNames covers the languages the models write well; other codes
+// are passed through as they are.
+var languageNames = map[string]string{
+	"de": "German",
+	"en": "English",
+	"es": "Spanish",
+	"fr": "French",
+	"it": "Italian",
+	"ja": "Japanese",
+	"ko": "Korean",
+	"nl": "Dutch",
+	"pt": "Portuguese",
+	"zh": "Chinese",
+}
+
+func languageName(code string) string {
+	if name, ok := languageNames[strings.ToLower(code)]; ok {
+		return name
+	}
+	return code
+}
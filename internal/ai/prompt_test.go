@@ -0,0 +1,163 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kbrakke/illustrated-primer/internal/models"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files")
+
+// checkGolden compares got with testdata/golden/<name>.golden, rewriting the
+// file instead when the test is run with -update.
+func checkGolden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", "golden", name+".golden")
+	if *update {
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read golden file (run with -update to create it): %v", err)
+	}
+	if got != string(want) {
+		t.Errorf("output differs from %s (run with -update to accept)\ngot:\n%s\nwant:\n%s", path, got, want)
+	}
+}
+
+func TestRenderSystemPrompt(t *testing.T) {
+	tests := []struct {
+		name string
+		pc   PromptContext
+	}{
+		{"general", PromptContext{Name: "the child"}},
+		{"toddler", PromptContext{
+			Name: "Nell", Age: 3, Band: models.AgeBandToddler,
+			ReadingLevel: "pre-reader", Interests: []string{"ducks"},
+		}},
+		{"preschool", PromptContext{
+			Name: "Nell", Age: 5, Band: models.AgeBandPreschool,
+			ReadingLevel: "pre-reader", Interests: []string{"ducks", "trains"},
+			StoryTitle: "The Lost Duckling",
+			Objectives: []string{"Count to 10", "Primary colors"},
+		}},
+		{"early-reader", PromptContext{
+			Name: "Nell", Age: 6, Band: models.AgeBandEarlyReader,
+			ReadingLevel: "beginning", Interests: []string{"ducks", "trains", "the moon"},
+			Language: "es", StoryTitle: "The Lost Duckling",
+			Premise: "A duckling follows a train to find its family.",
+		}},
+		{"reader", PromptContext{
+			Name: "the child", Band: models.AgeBandReader,
+			ReadingLevel: "fluent",
+			StoryTitle:   "The Lost Duckling",
+			Premise:      "A duckling follows a train to find its family.",
+		}},
+		{"review", PromptContext{
+			Name: "Nell", Age: 6, Band: models.AgeBandEarlyReader,
+			StoryTitle: "The Lost Duckling",
+			Review:     []string{"enormous", "iridescent"},
+		}},
+		{"wrap-up", PromptContext{
+			Name: "Nell", Age: 5, Band: models.AgeBandPreschool,
+			StoryTitle: "The Lost Duckling",
+			WrapUp:     true,
+		}},
+		{"preferences", PromptContext{
+			Name: "Nell", Age: 5, Band: models.AgeBandPreschool,
+			StoryTitle:    "The Lost Duckling",
+			StoryThemes:   []string{"dinosaurs"},
+			Avoid:         []string{"scary monsters", "spiders"},
+			Sensitivities: []string{"loud noises", "death"},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderSystemPrompt(tt.pc)
+			if err != nil {
+				t.Fatalf("RenderSystemPrompt() error = %v", err)
+			}
+			checkGolden(t, "system_"+tt.name, got)
+		})
+	}
+}
+
+func TestRenderSystemPromptDefault(t *testing.T) {
+	got, err := RenderSystemPrompt(NewPromptContext(nil, nil, time.Now()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != SystemPrompt() {
+		t.Errorf("without a profile the prompt should match SystemPrompt()\ngot:  %s\nwant: %s", got, SystemPrompt())
+	}
+}
+
+func TestNewPromptContext(t *testing.T) {
+	name, birthdate, language := "Nell", "2019-06-01", "fr"
+	user := &models.User{Name: &name, Birthdate: &birthdate, Language: &language, Interests: []string{"owls"}}
+	story := &models.Story{Title: "Owl Night", Summary: "An owl learns to fly."}
+
+	pc := NewPromptContext(user, story, time.Date(2025, 5, 31, 0, 0, 0, 0, time.UTC))
+	if pc.Age != 5 || pc.Band != models.AgeBandPreschool {
+		t.Errorf("Age, Band = %d, %q; want 5, preschool", pc.Age, pc.Band)
+	}
+	if pc.Premise != "An owl learns to fly." || pc.Language != "fr" {
+		t.Errorf("unexpected context %+v", pc)
+	}
+
+	user.SetContentPreferences(models.ContentPreferences{StoryThemes: []string{"stars"}, Banned: []string{"owls hunting"}})
+	if pc := NewPromptContext(user, nil, time.Now()); len(pc.StoryThemes) != 1 || len(pc.Avoid) != 1 {
+		t.Errorf("content preferences should carry over, got %+v", pc)
+	}
+
+	band := string(models.AgeBandReader)
+	user.AgeBand = &band
+	if pc := NewPromptContext(user, nil, time.Now()); pc.Band != models.AgeBandReader {
+		t.Errorf("explicit age band should win, got %q", pc.Band)
+	}
+}
+
+func TestJoinList(t *testing.T) {
+	got := []string{
+		joinList([]string{"a"}),
+		joinList([]string{"a", "b"}),
+		joinList([]string{"a", "b", "c"}),
+	}
+	if strings.Join(got, "|") != "a|a and b|a, b and c" {
+		t.Errorf("joinList = %q", got)
+	}
+}
+
+func TestRequestOptionsSystem(t *testing.T) {
+	var systems []string
+	client := &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		var body struct {
+			Input []map[string]string `json:"input"`
+		}
+		json.NewDecoder(req.Body).Decode(&body)
+		systems = append(systems, body.Input[0]["content"])
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(`{"output":[]}`))}, nil
+	})}
+	c := NewClient("key", WithHTTPClient(client))
+
+	c.GenerateResponse(context.Background(), "hi", nil, RequestOptions{})
+	c.GenerateResponse(context.Background(), "hi", nil, RequestOptions{System: "Be brief."})
+
+	if len(systems) != 2 || systems[0] != SystemPrompt() || systems[1] != "Be brief." {
+		t.Errorf("system prompts = %q", systems)
+	}
+}
@@ -0,0 +1,174 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/kbrakke/illustrated-primer/internal/models"
+)
+
+const (
+	// QuizQuestions is how many questions a quiz asks for.
+	QuizQuestions = 3
+	// QuizPages is how many of a story's latest pages a quiz is about.
+	QuizPages = 3
+)
+
+// QuestionKind is how a question's options are shown.
+type QuestionKind string
+
+const (
+	// QuestionText options are words, for children who can read them.
+	QuestionText QuestionKind = "text"
+	// QuestionPicture options are single emoji, for children who can't read yet.
+	QuestionPicture QuestionKind = "picture"
+)
+
+// quizSchema is the JSON schema for a Quiz.
+var quizSchema = json.RawMessage(`{
+	"type": "object",
+	"properties": {
+		"questions": {
+			"type": "array",
+			"items": {
+				"type": "object",
+				"properties": {
+					"question": {
+						"type": "string",
+						"description": "The question, short enough to read aloud."
+					},
+					"kind": {
+						"type": "string",
+						"enum": ["text", "picture"],
+						"description": "picture when every option is a single emoji."
+					},
+					"options": {
+						"type": "array",
+						"items": {"type": "string"},
+						"minItems": 2,
+						"maxItems": 4
+					},
+					"answer": {
+						"type": "integer",
+						"description": "The index of the right option, counting from 0."
+					}
+				},
+				"required": ["question", "kind", "options", "answer"],
+				"additionalProperties": false
+			}
+		}
+	},
+	"required": ["questions"],
+	"additionalProperties": false
+}`)
+
+// QuizFormat asks for a Quiz, to be read with ParseQuiz.
+var QuizFormat = &Format{name: "quiz", schema: quizSchema}
+
+// Quiz is a set of multiple-choice comprehension questions about a story.
+type Quiz struct {
+	Questions []QuizQuestion `json:"questions"`
+}
+
+// QuizQuestion is a multiple-choice question with one right option.
+type QuizQuestion struct {
+	Question string       `json:"question"`
+	Kind     QuestionKind `json:"kind"`
+	Options  []string     `json:"options"`
+	Answer   int          `json:"answer"` // index in Options
+}
+
+// ErrEmptyQuiz is returned by ParseQuiz when no question is usable.
+var ErrEmptyQuiz = errors.New("quiz has no valid questions")
+
+// ParseQuiz parses and validates a structured quiz. Questions without text,
+// with fewer than two or more than four options, with blank or repeated
+// options, or whose answer isn't one of the options are dropped.
+func ParseQuiz(raw string) (Quiz, error) {
+	var quiz Quiz
+	if err := json.Unmarshal([]byte(raw), &quiz); err != nil {
+		return Quiz{}, fmt.Errorf("parse quiz: %w", err)
+	}
+
+	var questions []QuizQuestion
+	for _, q := range quiz.Questions {
+		if q, ok := validQuestion(q); ok {
+			questions = append(questions, q)
+		}
+	}
+	if len(questions) == 0 {
+		return Quiz{}, ErrEmptyQuiz
+	}
+	quiz.Questions = questions
+	return quiz, nil
+}
+
+// validQuestion trims a question and reports whether it can be asked.
+func validQuestion(q QuizQuestion) (QuizQuestion, bool) {
+	q.Question = strings.TrimSpace(q.Question)
+	if q.Question == "" || len(q.Options) < 2 || len(q.Options) > 4 {
+		return q, false
+	}
+	if q.Answer < 0 || q.Answer >= len(q.Options) {
+		return q, false
+	}
+
+	options := make([]string, len(q.Options))
+	seen := make(map[string]bool)
+	for i, option := range q.Options {
+		option = strings.TrimSpace(option)
+		if option == "" || seen[strings.ToLower(option)] {
+			return q, false
+		}
+		seen[strings.ToLower(option)] = true
+		options[i] = option
+	}
+	q.Options = options
+
+	if q.Kind != QuestionPicture {
+		q.Kind = QuestionText
+	}
+	return q, true
+}
+
+// RenderQuizPrompt renders the system prompt for writing a quiz for a child.
+func RenderQuizPrompt(pc PromptContext) (string, error) {
+	var b bytes.Buffer
+	if err := systemTemplate.ExecuteTemplate(&b, "quiz", pc); err != nil {
+		return "", fmt.Errorf("render quiz prompt: %w", err)
+	}
+	return b.String(), nil
+}
+
+// GenerateQuiz asks the model for comprehension questions about the last
+// QuizPages pages of a story, pitched at the child in pc.
+func GenerateQuiz(ctx context.Context, client Client, pc PromptContext, pages []models.Page) (Quiz, Tokens, error) {
+	if len(pages) == 0 {
+		return Quiz{}, Tokens{}, ErrEmptyQuiz
+	}
+	if len(pages) > QuizPages {
+		pages = pages[len(pages)-QuizPages:]
+	}
+
+	system, err := RenderQuizPrompt(pc)
+	if err != nil {
+		return Quiz{}, Tokens{}, err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Write %d questions about these pages of the story.", QuizQuestions)
+	for _, page := range pages {
+		fmt.Fprintf(&b, "\n\nPage %d:\n%s", page.PageNum, page.Completion)
+	}
+
+	raw, tokens, err := client.GenerateResponse(ctx, b.String(), nil, RequestOptions{System: system, Format: QuizFormat})
+	if err != nil {
+		return Quiz{}, Tokens{}, fmt.Errorf("generate quiz: %w", err)
+	}
+	quiz, err := ParseQuiz(raw)
+	return quiz, tokens, err
+}
@@ -0,0 +1,120 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/kbrakke/illustrated-primer/internal/models"
+)
+
+func TestParseQuiz(t *testing.T) {
+	raw := `{"questions":[
+		{"question":" Who found the door? ","kind":"text","options":[" The fox ","The owl","The bear"],"answer":0},
+		{"question":"Which animal?","kind":"picture","options":["🦊","🦉"],"answer":1},
+		{"question":"","kind":"text","options":["a","b"],"answer":0},
+		{"question":"Out of range","kind":"text","options":["a","b"],"answer":2},
+		{"question":"Repeated","kind":"text","options":["a","A"],"answer":0},
+		{"question":"Too few","kind":"text","options":["a"],"answer":0},
+		{"question":"Odd kind","kind":"drawing","options":["a","b"],"answer":1}
+	]}`
+
+	quiz, err := ParseQuiz(raw)
+	if err != nil {
+		t.Fatalf("ParseQuiz() error = %v", err)
+	}
+	if len(quiz.Questions) != 3 {
+		t.Fatalf("got %d questions, want 3: %+v", len(quiz.Questions), quiz.Questions)
+	}
+	if q := quiz.Questions[0]; q.Question != "Who found the door?" || q.Options[0] != "The fox" {
+		t.Errorf("question not trimmed: %+v", q)
+	}
+	if quiz.Questions[1].Kind != QuestionPicture {
+		t.Errorf("kind = %q, want picture", quiz.Questions[1].Kind)
+	}
+	if quiz.Questions[2].Kind != QuestionText {
+		t.Errorf("unknown kind = %q, want text", quiz.Questions[2].Kind)
+	}
+
+	for _, raw := range []string{`{"questions":[]}`, `{"questions":[{"question":"","options":[],"answer":0}]}`} {
+		if _, err := ParseQuiz(raw); !errors.Is(err, ErrEmptyQuiz) {
+			t.Errorf("ParseQuiz(%s) error = %v, want ErrEmptyQuiz", raw, err)
+		}
+	}
+	if _, err := ParseQuiz("Here is a quiz!"); err == nil {
+		t.Error("ParseQuiz of free text should fail")
+	}
+}
+
+func TestRenderQuizPrompt(t *testing.T) {
+	tests := []struct {
+		name string
+		pc   PromptContext
+	}{
+		{"quiz_toddler", PromptContext{
+			Name: "Nell", Age: 3, Band: models.AgeBandToddler, ReadingLevel: "pre-reader",
+			StoryTitle: "The Lost Duckling",
+		}},
+		{"quiz_reader", PromptContext{
+			Name: "Nell", Age: 9, Band: models.AgeBandReader, ReadingLevel: "fluent",
+			Language: "fr", StoryTitle: "The Lost Duckling",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderQuizPrompt(tt.pc)
+			if err != nil {
+				t.Fatalf("RenderQuizPrompt() error = %v", err)
+			}
+			checkGolden(t, tt.name, got)
+		})
+	}
+}
+
+// recordingClient answers every request with response and records the last one.
+type recordingClient struct {
+	response string
+	opts     RequestOptions
+	message  string
+}
+
+func (c *recordingClient) GenerateResponse(ctx context.Context, message string, history []string, opts RequestOptions) (string, Tokens, error) {
+	c.opts, c.message = opts, message
+	return c.response, Tokens{}, nil
+}
+
+func (c *recordingClient) GenerateResponseStream(ctx context.Context, message string, history []string, opts RequestOptions) (*Stream, error) {
+	return nil, errors.New("not used")
+}
+
+func TestGenerateQuiz(t *testing.T) {
+	client := &recordingClient{response: `{"questions":[{"question":"Who found the door?","kind":"text","options":["The fox","The owl"],"answer":0}]}`}
+	var pages []models.Page
+	for i := 1; i <= 5; i++ {
+		page := models.NewPage("story", int64(i), "prompt", "completion "+string(rune('A'+i-1)))
+		pages = append(pages, *page)
+	}
+
+	quiz, _, err := GenerateQuiz(context.Background(), client, PromptContext{Name: "Nell", Band: models.AgeBandToddler}, pages)
+	if err != nil {
+		t.Fatalf("GenerateQuiz() error = %v", err)
+	}
+	if len(quiz.Questions) != 1 {
+		t.Errorf("got %d questions, want 1", len(quiz.Questions))
+	}
+
+	if client.opts.Format != QuizFormat {
+		t.Errorf("quiz requested with format %+v, want the quiz schema", client.opts.Format)
+	}
+	if system := client.opts.System; !strings.Contains(system, "picture questions") {
+		t.Errorf("system prompt not pitched at a toddler:\n%s", system)
+	}
+	if strings.Contains(client.message, "completion B") || !strings.Contains(client.message, "Page 5:\ncompletion E") {
+		t.Errorf("quiz should cover the last %d pages, got:\n%s", QuizPages, client.message)
+	}
+
+	if _, _, err := GenerateQuiz(context.Background(), client, PromptContext{}, nil); !errors.Is(err, ErrEmptyQuiz) {
+		t.Errorf("GenerateQuiz without pages error = %v, want ErrEmptyQuiz", err)
+	}
+}
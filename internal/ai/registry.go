@@ -0,0 +1,242 @@
+package ai
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// DefaultTemplate is the built-in system prompt template, as rendered by
+// RenderSystemPrompt.
+const DefaultTemplate = "default@1"
+
+// ErrUnknownTemplate is returned when a prompt template isn't in the registry.
+var ErrUnknownTemplate = errors.New("unknown prompt template")
+
+// PromptTemplate is one version of a named system prompt template.
+type PromptTemplate struct {
+	Name    string
+	Version int
+
+	tmpl  *template.Template
+	entry string // the template to execute
+}
+
+// ID returns the template's reference, "name@version", as pinned by stories
+// and recorded on pages.
+func (t *PromptTemplate) ID() string {
+	return fmt.Sprintf("%s@%d", t.Name, t.Version)
+}
+
+// Render renders the template for a child and story.
+func (t *PromptTemplate) Render(pc PromptContext) (string, error) {
+	var b bytes.Buffer
+	if err := t.tmpl.ExecuteTemplate(&b, t.entry, pc); err != nil {
+		return "", fmt.Errorf("render prompt template %s: %w", t.ID(), err)
+	}
+	return b.String(), nil
+}
+
+// Registry holds versioned system prompt templates and chooses between
+// variants for experiments.
+type Registry struct {
+	templates map[string][]*PromptTemplate // by name, oldest version first
+	variants  []variant
+	intn      func(n int) int
+}
+
+// variant is a template reference with its share of new stories.
+type variant struct {
+	ref    string
+	weight int
+}
+
+// NewRegistry returns a registry holding only the built-in template.
+func NewRegistry() *Registry {
+	builtin := &PromptTemplate{Name: "default", Version: 1, tmpl: systemTemplate, entry: "system"}
+	return &Registry{
+		templates: map[string][]*PromptTemplate{builtin.Name: {builtin}},
+		intn:      rand.IntN,
+	}
+}
+
+// LoadRegistry returns a registry holding the built-in template and every
+// "name@version.tmpl" file in dir. A missing directory is not an error.
+func LoadRegistry(dir string) (*Registry, error) {
+	r := NewRegistry()
+
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read prompt templates: %w", err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".tmpl" {
+			continue
+		}
+		name, version, err := ParseTemplateRef(strings.TrimSuffix(entry.Name(), ".tmpl"))
+		if err != nil || version == 0 {
+			return nil, fmt.Errorf("prompt template %s: file name must be name@version.tmpl", entry.Name())
+		}
+
+		text, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("read prompt template: %w", err)
+		}
+		if err := r.Add(name, version, string(text)); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// Add parses and registers a template version. The text is the whole
+// prompt; it can use the built-in "audience" and "reading-level" templates
+// and functions, and redefine them for itself.
+func (r *Registry) Add(name string, version int, text string) error {
+	if name == "" || strings.Contains(name, "@") || version < 1 {
+		return fmt.Errorf("invalid prompt template %s@%d", name, version)
+	}
+	for _, t := range r.templates[name] {
+		if t.Version == version {
+			return fmt.Errorf("prompt template %s@%d is already registered", name, version)
+		}
+	}
+
+	t := &PromptTemplate{Name: name, Version: version}
+	t.entry = t.ID()
+	base, err := systemTemplate.Clone()
+	if err != nil {
+		return fmt.Errorf("clone built-in templates: %w", err)
+	}
+	if t.tmpl, err = base.New(t.entry).Parse(text); err != nil {
+		return fmt.Errorf("parse prompt template %s: %w", t.ID(), err)
+	}
+
+	versions := append(r.templates[name], t)
+	sort.Slice(versions, func(i, j int) bool { return versions[i].Version < versions[j].Version })
+	r.templates[name] = versions
+	return nil
+}
+
+// Lookup returns the template for a reference: "name@version" for that
+// version, "name" for the latest one, or "" for DefaultTemplate.
+func (r *Registry) Lookup(ref string) (*PromptTemplate, error) {
+	if ref == "" {
+		ref = DefaultTemplate
+	}
+	name, version, err := ParseTemplateRef(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := r.templates[name]
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("%w %q", ErrUnknownTemplate, ref)
+	}
+	if version == 0 {
+		return versions[len(versions)-1], nil
+	}
+	for _, t := range versions {
+		if t.Version == version {
+			return t, nil
+		}
+	}
+	return nil, fmt.Errorf("%w %q", ErrUnknownTemplate, ref)
+}
+
+// SetWeights sets the variants new stories are assigned to, keyed by
+// template reference, with each one chosen in proportion to its weight.
+func (r *Registry) SetWeights(weights map[string]int) error {
+	var variants []variant
+	for ref, weight := range weights {
+		if weight < 0 {
+			return fmt.Errorf("prompt template %s has a negative weight", ref)
+		}
+		if _, err := r.Lookup(ref); err != nil {
+			return err
+		}
+		if weight > 0 {
+			variants = append(variants, variant{ref: ref, weight: weight})
+		}
+	}
+	sort.Slice(variants, func(i, j int) bool { return variants[i].ref < variants[j].ref })
+	r.variants = variants
+	return nil
+}
+
+// Choose picks the template for a new story: a weighted random variant, or
+// DefaultTemplate when no weights are set.
+func (r *Registry) Choose() *PromptTemplate {
+	ref := DefaultTemplate
+	if len(r.variants) > 0 {
+		total := 0
+		for _, v := range r.variants {
+			total += v.weight
+		}
+		n := r.intn(total)
+		for _, v := range r.variants {
+			if n < v.weight {
+				ref = v.ref
+				break
+			}
+			n -= v.weight
+		}
+	}
+
+	t, err := r.Lookup(ref)
+	if err != nil {
+		// Variants are checked by SetWeights, so only the default can get here.
+		t, _ = r.Lookup(DefaultTemplate)
+	}
+	return t
+}
+
+// ParseTemplateRef splits a template reference into its name and version.
+// The version is 0 when the reference names only the template.
+func ParseTemplateRef(ref string) (name string, version int, err error) {
+	name, v, pinned := strings.Cut(strings.TrimSpace(ref), "@")
+	if name == "" {
+		return "", 0, fmt.Errorf("invalid prompt template %q", ref)
+	}
+	if !pinned {
+		return name, 0, nil
+	}
+	version, err = strconv.Atoi(v)
+	if err != nil || version < 1 {
+		return "", 0, fmt.Errorf("invalid prompt template version %q (want name@version)", ref)
+	}
+	return name, version, nil
+}
+
+// ParseTemplateWeights parses variant weights written as
+// "default@1=50,playful@2=50".
+func ParseTemplateWeights(spec string) (map[string]int, error) {
+	weights := make(map[string]int)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		ref, w, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid prompt template weight %q (want template=weight)", entry)
+		}
+		weight, err := strconv.Atoi(strings.TrimSpace(w))
+		if err != nil {
+			return nil, fmt.Errorf("invalid prompt template weight %q: %w", entry, err)
+		}
+		weights[strings.TrimSpace(ref)] = weight
+	}
+	return weights, nil
+}
@@ -0,0 +1,133 @@
+package ai
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kbrakke/illustrated-primer/internal/models"
+)
+
+func writeTemplates(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, text := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(text), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestLoadRegistry(t *testing.T) {
+	dir := writeTemplates(t, map[string]string{
+		"playful@1.tmpl": "Be playful with {{.Name}}.",
+		"playful@2.tmpl": "Be very playful with {{.Name}}. {{template \"audience\" .}}",
+		"README.md":      "not a template",
+	})
+
+	r, err := LoadRegistry(dir)
+	if err != nil {
+		t.Fatalf("LoadRegistry() error = %v", err)
+	}
+
+	tests := []struct {
+		ref  string
+		want string
+	}{
+		{"", DefaultTemplate},
+		{"default", DefaultTemplate},
+		{"playful@1", "playful@1"},
+		{"playful", "playful@2"},
+	}
+	for _, tt := range tests {
+		got, err := r.Lookup(tt.ref)
+		if err != nil || got.ID() != tt.want {
+			t.Errorf("Lookup(%q) = %v, %v; want %s", tt.ref, got, err, tt.want)
+		}
+	}
+	for _, ref := range []string{"playful@3", "serious"} {
+		if _, err := r.Lookup(ref); !errors.Is(err, ErrUnknownTemplate) {
+			t.Errorf("Lookup(%q) error = %v, want ErrUnknownTemplate", ref, err)
+		}
+	}
+
+	// Templates can use the built-in audience blocks.
+	tmpl, _ := r.Lookup("playful")
+	got, err := tmpl.Render(PromptContext{Name: "Sam", Band: models.AgeBandToddler})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if !strings.HasPrefix(got, "Be very playful with Sam.") || !strings.Contains(got, "who is a toddler") {
+		t.Errorf("Render() = %q", got)
+	}
+
+	if r, err := LoadRegistry(filepath.Join(dir, "missing")); err != nil || r == nil {
+		t.Errorf("LoadRegistry(missing) = %v, %v; want the built-in registry", r, err)
+	}
+}
+
+func TestLoadRegistryErrors(t *testing.T) {
+	for name, files := range map[string]map[string]string{
+		"unversioned name": {"playful.tmpl": "hi"},
+		"bad version":      {"playful@x.tmpl": "hi"},
+		"parse error":      {"playful@1.tmpl": "{{.Name"},
+		"built-in version": {"default@1.tmpl": "hi"},
+	} {
+		if _, err := LoadRegistry(writeTemplates(t, files)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestRegistryDefaultMatchesRenderSystemPrompt(t *testing.T) {
+	pc := PromptContext{Name: "Sam", Age: 5, Band: models.AgeBandPreschool}
+	want, _ := RenderSystemPrompt(pc)
+
+	tmpl, _ := NewRegistry().Lookup(DefaultTemplate)
+	if got, err := tmpl.Render(pc); err != nil || got != want {
+		t.Errorf("Render() = %q, %v; want %q", got, err, want)
+	}
+}
+
+func TestRegistryChoose(t *testing.T) {
+	r := NewRegistry()
+	if got := r.Choose().ID(); got != DefaultTemplate {
+		t.Errorf("Choose() without weights = %s", got)
+	}
+
+	if err := r.Add("playful", 1, "Be playful."); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.SetWeights(map[string]int{"default@1": 1, "playful": 3, "off@1": 0}); err == nil {
+		t.Error("expected an error for a weight on an unknown template")
+	}
+	if err := r.SetWeights(map[string]int{"default@1": 1, "playful": 3}); err != nil {
+		t.Fatalf("SetWeights() error = %v", err)
+	}
+
+	// Variants are ordered by reference: default@1 takes 0, playful 1-3.
+	for n, want := range []string{"default@1", "playful@1", "playful@1", "playful@1"} {
+		r.intn = func(int) int { return n }
+		if got := r.Choose().ID(); got != want {
+			t.Errorf("Choose() with %d = %s, want %s", n, got, want)
+		}
+	}
+}
+
+func TestParseTemplateWeights(t *testing.T) {
+	weights, err := ParseTemplateWeights("default@1=50, playful@2 = 50")
+	if err != nil {
+		t.Fatalf("ParseTemplateWeights() error = %v", err)
+	}
+	if weights["default@1"] != 50 || weights["playful@2"] != 50 {
+		t.Errorf("weights = %v", weights)
+	}
+	for _, spec := range []string{"playful", "playful=lots"} {
+		if _, err := ParseTemplateWeights(spec); err == nil {
+			t.Errorf("ParseTemplateWeights(%q): expected an error", spec)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Rule is a local moderation rule: a list of words or a regular expression.
+type Rule struct {
+	Category string   `json:"category"`
+	Words    []string `json:"words,omitempty"`   // matched as whole words, ignoring case
+	Pattern  string   `json:"pattern,omitempty"` // a Go regular expression
+	// Action overrides the policy for this rule's category when set.
+	Action Action `json:"action,omitempty"`
+	// Replacement is used for matches when the text is rewritten.
+	Replacement string `json:"replacement,omitempty"`
+}
+
+// DefaultRules returns the built-in rules: personal details a child
+// shouldn't share, and words that don't belong in a children's story.
+func DefaultRules() []Rule {
+	return []Rule{
+		{
+			Category:    "personal-information",
+			Pattern:     `[\w.+-]+@[\w-]+\.[\w.-]+`,
+			Action:      ActionRewrite,
+			Replacement: "[private]",
+		},
+		{
+			Category:    "personal-information",
+			Pattern:     `\+?\d[\d ().-]{7,}\d`,
+			Action:      ActionRewrite,
+			Replacement: "[private]",
+		},
+		{
+			Category:    "profanity",
+			Words:       []string{"fuck", "fucking", "shit", "bitch", "bastard", "asshole", "cunt"},
+			Action:      ActionRewrite,
+			Replacement: "***",
+		},
+		{
+			Category: "self-harm",
+			Words:    []string{"kill myself", "hurt myself", "want to die"},
+			Action:   ActionFlag,
+		},
+	}
+}
+
+// LoadRules reads moderation rules from a JSON file containing an array of rules.
+func LoadRules(path string) ([]Rule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read moderation rules: %w", err)
+	}
+
+	var rules []Rule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("parse moderation rules %s: %w", path, err)
+	}
+	return rules, nil
+}
+
+// RuleModerator implements Moderator with local rules, without any API calls.
+type RuleModerator struct {
+	rules    []Rule
+	patterns []*regexp.Regexp
+}
+
+// NewRuleModerator compiles rules into a moderator.
+func NewRuleModerator(rules []Rule) (*RuleModerator, error) {
+	m := &RuleModerator{rules: rules}
+	for i, rule := range rules {
+		if rule.Category == "" {
+			return nil, fmt.Errorf("moderation rule %d has no category", i+1)
+		}
+		if rule.Action != "" {
+			if _, err := ParseAction(string(rule.Action)); err != nil {
+				return nil, fmt.Errorf("moderation rule %d: %w", i+1, err)
+			}
+		}
+
+		expr := rule.Pattern
+		if len(rule.Words) > 0 {
+			if expr != "" {
+				return nil, fmt.Errorf("moderation rule %d has both words and a pattern", i+1)
+			}
+			quoted := make([]string, len(rule.Words))
+			for j, word := range rule.Words {
+				quoted[j] = strings.ReplaceAll(regexp.QuoteMeta(word), " ", `\s+`)
+			}
+			expr = `(?i)\b(?:` + strings.Join(quoted, "|") + `)\b`
+		}
+		if expr == "" {
+			return nil, fmt.Errorf("moderation rule %d has no words or pattern", i+1)
+		}
+
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("moderation rule %d: %w", i+1, err)
+		}
+		m.patterns = append(m.patterns, re)
+	}
+	return m, nil
+}
+
+// Moderate returns a finding for each match of each rule.
+func (m *RuleModerator) Moderate(_ context.Context, text string) ([]Finding, error) {
+	var findings []Finding
+	for i, re := range m.patterns {
+		rule := m.rules[i]
+		for _, match := range re.FindAllString(text, -1) {
+			findings = append(findings, Finding{
+				Category:    rule.Category,
+				Match:       match,
+				Replacement: rule.Replacement,
+				Action:      rule.Action,
+			})
+		}
+	}
+	return findings, nil
+}
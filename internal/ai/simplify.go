@@ -0,0 +1,49 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrEmptySimplification is returned by SimplifyPage when the model says
+// nothing.
+var ErrEmptySimplification = errors.New("simplified page is empty")
+
+// simplifyData is what the simplify prompt is rendered from: the child and
+// story, and the grade level to write at.
+type simplifyData struct {
+	PromptContext
+	Grade float64
+}
+
+// RenderSimplifyPrompt renders the system prompt for rewriting a page at the
+// given reading grade level.
+func RenderSimplifyPrompt(pc PromptContext, grade float64) (string, error) {
+	var b bytes.Buffer
+	if err := systemTemplate.ExecuteTemplate(&b, "simplify", simplifyData{PromptContext: pc, Grade: grade}); err != nil {
+		return "", fmt.Errorf("render simplify prompt: %w", err)
+	}
+	return b.String(), nil
+}
+
+// SimplifyPage asks the model to rewrite a page of the story in pc so that
+// it reads at the given grade level.
+func SimplifyPage(ctx context.Context, client Client, pc PromptContext, text string, grade float64) (string, Tokens, error) {
+	system, err := RenderSimplifyPrompt(pc, grade)
+	if err != nil {
+		return "", Tokens{}, err
+	}
+
+	text, tokens, err := client.GenerateResponse(ctx, "Rewrite this page:\n\n"+text, nil, RequestOptions{System: system})
+	if err != nil {
+		return "", tokens, fmt.Errorf("simplify page: %w", err)
+	}
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "", tokens, ErrEmptySimplification
+	}
+	return text, tokens, nil
+}
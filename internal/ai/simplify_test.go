@@ -0,0 +1,54 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/kbrakke/illustrated-primer/internal/models"
+)
+
+func TestRenderSimplifyPrompt(t *testing.T) {
+	tests := []struct {
+		name  string
+		pc    PromptContext
+		grade float64
+	}{
+		{"simplify_toddler", PromptContext{Name: "Nell", Age: 3, Band: models.AgeBandToddler}, 1},
+		{"simplify_reader", PromptContext{Name: "Nell", Age: 9, Band: models.AgeBandReader, Language: "es",
+			Review: []string{"enormous"}}, 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderSimplifyPrompt(tt.pc, tt.grade)
+			if err != nil {
+				t.Fatalf("RenderSimplifyPrompt() error = %v", err)
+			}
+			checkGolden(t, tt.name, got)
+		})
+	}
+}
+
+func TestSimplifyPage(t *testing.T) {
+	client := &recordingClient{response: "\nThe fox was big.\n"}
+	text, _, err := SimplifyPage(context.Background(), client, PromptContext{Name: "Nell", Band: models.AgeBandPreschool},
+		"The fox was of prodigious proportions.", 2)
+	if err != nil {
+		t.Fatalf("SimplifyPage() error = %v", err)
+	}
+	if text != "The fox was big." {
+		t.Errorf("simplified = %q", text)
+	}
+	if !strings.Contains(client.message, "The fox was of prodigious proportions.") {
+		t.Errorf("message = %q, want the page", client.message)
+	}
+	if system := client.opts.System; !strings.Contains(system, "grade level of about 2") {
+		t.Errorf("system prompt missing the target grade:\n%s", system)
+	}
+
+	client.response = ""
+	if _, _, err := SimplifyPage(context.Background(), client, PromptContext{}, "text", 2); !errors.Is(err, ErrEmptySimplification) {
+		t.Errorf("SimplifyPage() error = %v, want ErrEmptySimplification", err)
+	}
+}
@@ -0,0 +1,48 @@
+package ai
+
+import (
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// charsPerToken is the rough number of characters per token for English
+// text with OpenAI tokenizers.
+const charsPerToken = 4
+
+// EstimateTokens returns an approximate token count for text. It is meant for
+// live feedback while typing, not for billing.
+func EstimateTokens(text string) int {
+	if strings.TrimSpace(text) == "" {
+		return 0
+	}
+	return (utf8.RuneCountInString(text) + charsPerToken - 1) / charsPerToken
+}
+
+// Tokens are the input and output tokens the API reports for a request.
+type Tokens struct {
+	Input  int64
+	Output int64
+}
+
+// Usage adds up the Tokens of several requests, such as a page and its
+// rewrites, for keeping track of spend. It is safe for concurrent use.
+type Usage struct {
+	mu    sync.Mutex
+	total Tokens
+}
+
+// Add adds the tokens of one request.
+func (u *Usage) Add(t Tokens) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.total.Input += t.Input
+	u.total.Output += t.Output
+}
+
+// Tokens returns the tokens added so far.
+func (u *Usage) Tokens() Tokens {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.total
+}
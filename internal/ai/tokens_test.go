@@ -0,0 +1,69 @@
+package ai
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestEstimateTokens(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected int
+	}{
+		{name: "empty", text: "", expected: 0},
+		{name: "whitespace", text: "   \n", expected: 0},
+		{name: "short word", text: "hi", expected: 1},
+		{name: "exact multiple", text: "abcdefgh", expected: 2},
+		{name: "multibyte runes", text: "ñandú", expected: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EstimateTokens(tt.text); got != tt.expected {
+				t.Errorf("EstimateTokens(%q) = %d, want %d", tt.text, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestUsage(t *testing.T) {
+	responses := []string{
+		`{"output":[{"type":"message","content":[{"type":"output_text","text":"ok"}]}],"usage":{"input_tokens":120,"output_tokens":30}}`,
+		"data: {\"type\":\"response.output_text.delta\",\"delta\":\"ok\"}\n\n" +
+			"data: {\"type\":\"response.completed\",\"response\":{\"usage\":{\"input_tokens\":200,\"output_tokens\":50}}}\n\n",
+	}
+	client := &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		body := responses[0]
+		responses = responses[1:]
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body))}, nil
+	})}
+	c := NewClient("key", WithHTTPClient(client))
+
+	_, tokens, err := c.GenerateResponse(context.Background(), "hi", nil, RequestOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tokens != (Tokens{Input: 120, Output: 30}) {
+		t.Errorf("GenerateResponse tokens = %+v, want 120, 30", tokens)
+	}
+	stream, err := c.GenerateResponseStream(context.Background(), "hi", nil, RequestOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range stream.Chunks {
+	}
+	if got := stream.Tokens(); got != (Tokens{Input: 200, Output: 50}) {
+		t.Errorf("stream tokens = %+v, want 200, 50", got)
+	}
+
+	usage := &Usage{}
+	usage.Add(tokens)
+	usage.Add(stream.Tokens())
+	if got := usage.Tokens(); got != (Tokens{Input: 320, Output: 80}) {
+		t.Errorf("Tokens() = %+v, want 320, 80", got)
+	}
+}
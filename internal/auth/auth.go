@@ -0,0 +1,340 @@
+// Package auth signs families in with one-time email links and keeps them
+// signed in with sessions, using the accounts, sessions and
+// verification_tokens tables.
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/kbrakke/illustrated-primer/internal/db"
+	"github.com/kbrakke/illustrated-primer/internal/models"
+)
+
+const (
+	// DefaultSessionTTL is how long a session lasts without being used.
+	DefaultSessionTTL = 30 * 24 * time.Hour
+
+	// DefaultSessionRefresh is how often a session in use has its expiry
+	// pushed back.
+	DefaultSessionRefresh = 24 * time.Hour
+
+	// DefaultLinkTTL is how long a sign-in link works.
+	DefaultLinkTTL = 15 * time.Minute
+
+	// DefaultLinkURL is where sign-in links point.
+	DefaultLinkURL = "http://localhost:8080/auth/verify"
+)
+
+// ErrInvalidLink is returned for a sign-in link that doesn't exist or has
+// already been used.
+var ErrInvalidLink = errors.New("invalid sign-in link")
+
+// ErrLinkExpired is returned for a sign-in link used after it expired.
+var ErrLinkExpired = errors.New("sign-in link expired")
+
+// ErrInvalidSession is returned for a session token that doesn't exist.
+var ErrInvalidSession = errors.New("invalid session")
+
+// ErrSessionExpired is returned for a session token used after it expired.
+var ErrSessionExpired = errors.New("session expired")
+
+// ErrUnknownAccount is returned when no user is linked to a provider account.
+var ErrUnknownAccount = errors.New("unknown account")
+
+// Store is the storage auth needs. *db.Database implements it.
+type Store interface {
+	GetUserByEmail(ctx context.Context, email string) (*models.User, error)
+	UpdateUser(ctx context.Context, user *models.User) error
+	GetAccountByProvider(ctx context.Context, provider, providerAccountID string) (*models.Account, error)
+	CreateAccount(ctx context.Context, account *models.Account) error
+	CreateSession(ctx context.Context, session *models.Session) error
+	GetSessionByToken(ctx context.Context, sessionToken string) (*models.Session, error)
+	ExtendSession(ctx context.Context, sessionToken string, expires time.Time) error
+	DeleteSession(ctx context.Context, sessionToken string) error
+	CreateVerificationToken(ctx context.Context, token *models.VerificationToken) error
+	UseVerificationToken(ctx context.Context, identifier, token string) (*models.VerificationToken, error)
+	DeleteExpired(ctx context.Context, now time.Time) (sessions, tokens int64, err error)
+}
+
+var _ Store = (*db.Database)(nil)
+
+// Authenticator signs users in and checks their sessions.
+type Authenticator struct {
+	store          Store
+	mailer         Mailer
+	sessionTTL     time.Duration
+	sessionRefresh time.Duration
+	linkTTL        time.Duration
+	linkURL        string
+	now            func() time.Time
+	logger         *slog.Logger
+}
+
+// Option is a function that configures an Authenticator.
+type Option func(*Authenticator)
+
+// WithSessionTTL sets how long a session lasts without being used.
+func WithSessionTTL(ttl time.Duration) Option {
+	return func(a *Authenticator) {
+		a.sessionTTL = ttl
+	}
+}
+
+// WithSessionRefresh sets how often a session in use has its expiry pushed
+// back.
+func WithSessionRefresh(refresh time.Duration) Option {
+	return func(a *Authenticator) {
+		a.sessionRefresh = refresh
+	}
+}
+
+// WithLinkTTL sets how long a sign-in link works.
+func WithLinkTTL(ttl time.Duration) Option {
+	return func(a *Authenticator) {
+		a.linkTTL = ttl
+	}
+}
+
+// WithLinkURL sets where sign-in links point. The email and token are added
+// as query parameters.
+func WithLinkURL(linkURL string) Option {
+	return func(a *Authenticator) {
+		a.linkURL = linkURL
+	}
+}
+
+// WithClock sets the source of the current time.
+func WithClock(now func() time.Time) Option {
+	return func(a *Authenticator) {
+		a.now = now
+	}
+}
+
+// WithLogger sets a custom logger.
+func WithLogger(logger *slog.Logger) Option {
+	return func(a *Authenticator) {
+		a.logger = logger
+	}
+}
+
+// New creates an Authenticator that stores sessions in store and sends
+// sign-in links with mailer.
+func New(store Store, mailer Mailer, opts ...Option) *Authenticator {
+	a := &Authenticator{
+		store:          store,
+		mailer:         mailer,
+		sessionTTL:     DefaultSessionTTL,
+		sessionRefresh: DefaultSessionRefresh,
+		linkTTL:        DefaultLinkTTL,
+		linkURL:        DefaultLinkURL,
+		now:            time.Now,
+		logger:         slog.Default(),
+	}
+
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	return a
+}
+
+// SendSignInLink emails a one-time sign-in link to a user. An address with
+// no user gets no email but no error either, so the result doesn't reveal
+// who has an account.
+func (a *Authenticator) SendSignInLink(ctx context.Context, email string) error {
+	email = strings.TrimSpace(email)
+	if _, err := a.store.GetUserByEmail(ctx, email); err != nil {
+		if errors.Is(err, db.ErrUserNotFound) {
+			a.logger.Info("sign-in link requested for unknown email")
+			return nil
+		}
+		return fmt.Errorf("look up user: %w", err)
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return err
+	}
+	expires := a.now().Add(a.linkTTL)
+	if err := a.store.CreateVerificationToken(ctx, &models.VerificationToken{
+		Identifier: email,
+		Token:      hashToken(token),
+		Expires:    expires.Unix(),
+	}); err != nil {
+		return err
+	}
+
+	link, err := a.signInLink(email, token)
+	if err != nil {
+		return err
+	}
+	msg := Message{
+		To:      email,
+		Subject: "Sign in to the Illustrated Primer",
+		Body: fmt.Sprintf("Use this link to sign in to the Illustrated Primer. It works once and expires in %d minutes.\n\n%s\n\nIf you didn't ask to sign in, you can ignore this email.\n",
+			int(a.linkTTL.Minutes()), link),
+	}
+	if err := a.mailer.Send(ctx, msg); err != nil {
+		return fmt.Errorf("send sign-in link: %w", err)
+	}
+	return nil
+}
+
+// signInLink adds the email and token to the link URL.
+func (a *Authenticator) signInLink(email, token string) (string, error) {
+	u, err := url.Parse(a.linkURL)
+	if err != nil {
+		return "", fmt.Errorf("parse link URL: %w", err)
+	}
+	query := u.Query()
+	query.Set("email", email)
+	query.Set("token", token)
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// VerifySignInLink uses up the token from a sign-in link and starts a
+// session for its user, marking their email as verified. It returns the
+// session token to hand to the client.
+func (a *Authenticator) VerifySignInLink(ctx context.Context, email, token string) (string, *models.Session, error) {
+	email = strings.TrimSpace(email)
+	used, err := a.store.UseVerificationToken(ctx, email, hashToken(token))
+	if err != nil {
+		if errors.Is(err, db.ErrVerificationTokenNotFound) {
+			return "", nil, ErrInvalidLink
+		}
+		return "", nil, err
+	}
+	now := a.now()
+	if used.Expired(now) {
+		return "", nil, ErrLinkExpired
+	}
+
+	user, err := a.store.GetUserByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, db.ErrUserNotFound) {
+			return "", nil, ErrInvalidLink
+		}
+		return "", nil, fmt.Errorf("look up user: %w", err)
+	}
+	if user.EmailVerified == nil {
+		verified := now.Unix()
+		user.EmailVerified = &verified
+		if err := a.store.UpdateUser(ctx, user); err != nil {
+			return "", nil, err
+		}
+	}
+
+	return a.CreateSession(ctx, user.ID)
+}
+
+// LinkAccount links a user to a provider account, so they can sign in with
+// it.
+func (a *Authenticator) LinkAccount(ctx context.Context, account *models.Account) error {
+	return a.store.CreateAccount(ctx, account)
+}
+
+// SignInWithAccount starts a session for the user linked to a provider
+// account.
+func (a *Authenticator) SignInWithAccount(ctx context.Context, provider, providerAccountID string) (string, *models.Session, error) {
+	account, err := a.store.GetAccountByProvider(ctx, provider, providerAccountID)
+	if err != nil {
+		if errors.Is(err, db.ErrAccountNotFound) {
+			return "", nil, ErrUnknownAccount
+		}
+		return "", nil, err
+	}
+	return a.CreateSession(ctx, account.UserID)
+}
+
+// CreateSession starts a session for a user. It returns the session token to
+// hand to the client; only its hash is stored.
+func (a *Authenticator) CreateSession(ctx context.Context, userID string) (string, *models.Session, error) {
+	token, err := newToken()
+	if err != nil {
+		return "", nil, err
+	}
+	session := models.NewSession(userID, hashToken(token), a.now().Add(a.sessionTTL))
+	if err := a.store.CreateSession(ctx, session); err != nil {
+		return "", nil, err
+	}
+	return token, session, nil
+}
+
+// ValidateSession returns the session for a token. A session in use has its
+// expiry pushed back at most once per refresh interval; an expired one is
+// deleted.
+func (a *Authenticator) ValidateSession(ctx context.Context, token string) (*models.Session, error) {
+	hash := hashToken(token)
+	session, err := a.store.GetSessionByToken(ctx, hash)
+	if err != nil {
+		if errors.Is(err, db.ErrSessionNotFound) {
+			return nil, ErrInvalidSession
+		}
+		return nil, err
+	}
+
+	now := a.now()
+	if session.Expired(now) {
+		if err := a.store.DeleteSession(ctx, hash); err != nil && !errors.Is(err, db.ErrSessionNotFound) {
+			a.logger.Error("failed to delete expired session", "error", err)
+		}
+		return nil, ErrSessionExpired
+	}
+
+	// The expiry was last set sessionTTL before it falls.
+	lastSet := time.Unix(session.Expires, 0).Add(-a.sessionTTL)
+	if now.Sub(lastSet) >= a.sessionRefresh {
+		expires := now.Add(a.sessionTTL)
+		if err := a.store.ExtendSession(ctx, hash, expires); err != nil {
+			return nil, err
+		}
+		session.Expires = expires.Unix()
+	}
+	return session, nil
+}
+
+// SignOut ends the session for a token.
+func (a *Authenticator) SignOut(ctx context.Context, token string) error {
+	if err := a.store.DeleteSession(ctx, hashToken(token)); err != nil && !errors.Is(err, db.ErrSessionNotFound) {
+		return err
+	}
+	return nil
+}
+
+// DeleteExpired removes expired sessions and sign-in links.
+func (a *Authenticator) DeleteExpired(ctx context.Context) error {
+	sessions, tokens, err := a.store.DeleteExpired(ctx, a.now())
+	if err != nil {
+		return err
+	}
+	a.logger.Debug("deleted expired sign-ins", "sessions", sessions, "links", tokens)
+	return nil
+}
+
+// newToken returns 32 random bytes, URL-safe encoded.
+func newToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken returns the hex SHA-256 of a token. Tokens are random and long,
+// so a fast hash is enough to keep a database leak from handing out working
+// sessions or links.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,326 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kbrakke/illustrated-primer/internal/db"
+	"github.com/kbrakke/illustrated-primer/internal/models"
+)
+
+// memoryStore is an in-memory Store.
+type memoryStore struct {
+	users    map[string]*models.User // by email
+	accounts map[string]*models.Account
+	sessions map[string]*models.Session // by token hash
+	tokens   map[string]*models.VerificationToken
+}
+
+func newMemoryStore(users ...*models.User) *memoryStore {
+	s := &memoryStore{
+		users:    make(map[string]*models.User),
+		accounts: make(map[string]*models.Account),
+		sessions: make(map[string]*models.Session),
+		tokens:   make(map[string]*models.VerificationToken),
+	}
+	for _, user := range users {
+		s.users[*user.Email] = user
+	}
+	return s
+}
+
+func (s *memoryStore) GetUserByEmail(_ context.Context, email string) (*models.User, error) {
+	user, ok := s.users[email]
+	if !ok {
+		return nil, db.ErrUserNotFound
+	}
+	copied := *user
+	return &copied, nil
+}
+
+func (s *memoryStore) UpdateUser(_ context.Context, user *models.User) error {
+	s.users[*user.Email] = user
+	return nil
+}
+
+func (s *memoryStore) GetAccountByProvider(_ context.Context, provider, providerAccountID string) (*models.Account, error) {
+	account, ok := s.accounts[provider+"/"+providerAccountID]
+	if !ok {
+		return nil, db.ErrAccountNotFound
+	}
+	return account, nil
+}
+
+func (s *memoryStore) CreateAccount(_ context.Context, account *models.Account) error {
+	s.accounts[account.Provider+"/"+account.ProviderAccountID] = account
+	return nil
+}
+
+func (s *memoryStore) CreateSession(_ context.Context, session *models.Session) error {
+	copied := *session
+	s.sessions[session.SessionToken] = &copied
+	return nil
+}
+
+func (s *memoryStore) GetSessionByToken(_ context.Context, sessionToken string) (*models.Session, error) {
+	session, ok := s.sessions[sessionToken]
+	if !ok {
+		return nil, db.ErrSessionNotFound
+	}
+	copied := *session
+	return &copied, nil
+}
+
+func (s *memoryStore) ExtendSession(_ context.Context, sessionToken string, expires time.Time) error {
+	session, ok := s.sessions[sessionToken]
+	if !ok {
+		return db.ErrSessionNotFound
+	}
+	session.Expires = expires.Unix()
+	return nil
+}
+
+func (s *memoryStore) DeleteSession(_ context.Context, sessionToken string) error {
+	if _, ok := s.sessions[sessionToken]; !ok {
+		return db.ErrSessionNotFound
+	}
+	delete(s.sessions, sessionToken)
+	return nil
+}
+
+func (s *memoryStore) CreateVerificationToken(_ context.Context, token *models.VerificationToken) error {
+	s.tokens[token.Identifier+"/"+token.Token] = token
+	return nil
+}
+
+func (s *memoryStore) UseVerificationToken(_ context.Context, identifier, token string) (*models.VerificationToken, error) {
+	used, ok := s.tokens[identifier+"/"+token]
+	if !ok {
+		return nil, db.ErrVerificationTokenNotFound
+	}
+	delete(s.tokens, identifier+"/"+token)
+	return used, nil
+}
+
+func (s *memoryStore) DeleteExpired(_ context.Context, now time.Time) (int64, int64, error) {
+	var sessions, tokens int64
+	for key, session := range s.sessions {
+		if session.Expired(now) {
+			delete(s.sessions, key)
+			sessions++
+		}
+	}
+	for key, token := range s.tokens {
+		if token.Expired(now) {
+			delete(s.tokens, key)
+			tokens++
+		}
+	}
+	return sessions, tokens, nil
+}
+
+// outbox is a Mailer that keeps what it sends.
+type outbox []Message
+
+func (o *outbox) Send(_ context.Context, msg Message) error {
+	*o = append(*o, msg)
+	return nil
+}
+
+// clock is a settable time source.
+type clock struct{ now time.Time }
+
+func (c *clock) Now() time.Time { return c.now }
+
+func newTestAuthenticator(t *testing.T) (*Authenticator, *memoryStore, *outbox, *clock) {
+	t.Helper()
+	store := newMemoryStore(models.NewUser("Miranda", "miranda@example.com"))
+	mail := &outbox{}
+	c := &clock{now: time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)}
+	a := New(store, mail,
+		WithClock(c.Now),
+		WithLinkURL("https://primer.example/verify"),
+		WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+	)
+	return a, store, mail, c
+}
+
+// linkToken returns the email and token from the sign-in link in a message.
+func linkToken(t *testing.T, msg Message) (string, string) {
+	t.Helper()
+	for _, field := range strings.Fields(msg.Body) {
+		if strings.HasPrefix(field, "https://primer.example/verify?") {
+			u, err := url.Parse(field)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return u.Query().Get("email"), u.Query().Get("token")
+		}
+	}
+	t.Fatalf("no sign-in link in:\n%s", msg.Body)
+	return "", ""
+}
+
+func TestSignInLink(t *testing.T) {
+	a, store, mail, _ := newTestAuthenticator(t)
+	ctx := context.Background()
+
+	if err := a.SendSignInLink(ctx, " miranda@example.com "); err != nil {
+		t.Fatalf("SendSignInLink() error = %v", err)
+	}
+	if len(*mail) != 1 || (*mail)[0].To != "miranda@example.com" {
+		t.Fatalf("sent %+v, want one email to miranda", *mail)
+	}
+	email, token := linkToken(t, (*mail)[0])
+	for key := range store.tokens {
+		if strings.Contains(key, token) {
+			t.Error("the link token should only be stored hashed")
+		}
+	}
+
+	sessionToken, session, err := a.VerifySignInLink(ctx, email, token)
+	if err != nil {
+		t.Fatalf("VerifySignInLink() error = %v", err)
+	}
+	if sessionToken == "" || session.UserID != store.users[email].ID {
+		t.Errorf("session = %+v, want one for miranda", session)
+	}
+	if store.users[email].EmailVerified == nil {
+		t.Error("signing in with a link should verify the email")
+	}
+	if _, ok := store.sessions[sessionToken]; ok {
+		t.Error("the session token should only be stored hashed")
+	}
+
+	if _, _, err := a.VerifySignInLink(ctx, email, token); !errors.Is(err, ErrInvalidLink) {
+		t.Errorf("reusing a link: got %v, want ErrInvalidLink", err)
+	}
+}
+
+func TestSignInLinkExpired(t *testing.T) {
+	a, _, mail, c := newTestAuthenticator(t)
+	ctx := context.Background()
+
+	if err := a.SendSignInLink(ctx, "miranda@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	c.now = c.now.Add(DefaultLinkTTL)
+	email, token := linkToken(t, (*mail)[0])
+	if _, _, err := a.VerifySignInLink(ctx, email, token); !errors.Is(err, ErrLinkExpired) {
+		t.Errorf("got %v, want ErrLinkExpired", err)
+	}
+}
+
+func TestSignInLinkUnknownEmail(t *testing.T) {
+	a, store, mail, _ := newTestAuthenticator(t)
+	if err := a.SendSignInLink(context.Background(), "stranger@example.com"); err != nil {
+		t.Fatalf("an unknown email shouldn't be an error, got %v", err)
+	}
+	if len(*mail) != 0 || len(store.tokens) != 0 {
+		t.Error("an unknown email shouldn't get a link")
+	}
+}
+
+func TestValidateSession(t *testing.T) {
+	a, store, _, c := newTestAuthenticator(t)
+	ctx := context.Background()
+
+	token, created, err := a.CreateSession(ctx, "user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.ValidateSession(ctx, "not-a-token"); !errors.Is(err, ErrInvalidSession) {
+		t.Errorf("unknown token: got %v, want ErrInvalidSession", err)
+	}
+
+	// Within the refresh interval the expiry stays put.
+	c.now = c.now.Add(time.Hour)
+	session, err := a.ValidateSession(ctx, token)
+	if err != nil {
+		t.Fatalf("ValidateSession() error = %v", err)
+	}
+	if session.Expires != created.Expires {
+		t.Error("a session shouldn't be extended within the refresh interval")
+	}
+
+	// After it, the session is pushed back a full TTL.
+	c.now = c.now.Add(DefaultSessionRefresh)
+	session, err = a.ValidateSession(ctx, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := c.now.Add(DefaultSessionTTL).Unix(); session.Expires != want {
+		t.Errorf("Expires = %d, want %d", session.Expires, want)
+	}
+
+	c.now = c.now.Add(DefaultSessionTTL)
+	if _, err := a.ValidateSession(ctx, token); !errors.Is(err, ErrSessionExpired) {
+		t.Errorf("expired session: got %v, want ErrSessionExpired", err)
+	}
+	if len(store.sessions) != 0 {
+		t.Error("an expired session should be deleted")
+	}
+}
+
+func TestSignOut(t *testing.T) {
+	a, _, _, _ := newTestAuthenticator(t)
+	ctx := context.Background()
+
+	token, _, err := a.CreateSession(ctx, "user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := a.SignOut(ctx, token); err != nil {
+		t.Fatalf("SignOut() error = %v", err)
+	}
+	if _, err := a.ValidateSession(ctx, token); !errors.Is(err, ErrInvalidSession) {
+		t.Errorf("after signing out: got %v, want ErrInvalidSession", err)
+	}
+	if err := a.SignOut(ctx, token); err != nil {
+		t.Errorf("signing out twice should be fine, got %v", err)
+	}
+}
+
+func TestSignInWithAccount(t *testing.T) {
+	a, _, _, _ := newTestAuthenticator(t)
+	ctx := context.Background()
+
+	if _, _, err := a.SignInWithAccount(ctx, "google", "123"); !errors.Is(err, ErrUnknownAccount) {
+		t.Errorf("unlinked account: got %v, want ErrUnknownAccount", err)
+	}
+	if err := a.LinkAccount(ctx, models.NewAccount("user", "oauth", "google", "123")); err != nil {
+		t.Fatal(err)
+	}
+	_, session, err := a.SignInWithAccount(ctx, "google", "123")
+	if err != nil {
+		t.Fatalf("SignInWithAccount() error = %v", err)
+	}
+	if session.UserID != "user" {
+		t.Errorf("UserID = %q, want the linked user", session.UserID)
+	}
+}
+
+func TestDeleteExpired(t *testing.T) {
+	a, store, _, c := newTestAuthenticator(t)
+	ctx := context.Background()
+
+	if _, _, err := a.CreateSession(ctx, "user"); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.SendSignInLink(ctx, "miranda@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	c.now = c.now.Add(DefaultLinkTTL)
+	if err := a.DeleteExpired(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(store.tokens) != 0 || len(store.sessions) != 1 {
+		t.Errorf("tokens %d, sessions %d: want only the expired link removed", len(store.tokens), len(store.sessions))
+	}
+}
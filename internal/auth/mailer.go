@@ -0,0 +1,65 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Message is an email to send.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers sign-in emails.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// FileMailer implements Mailer by writing each message to a file, for
+// running locally without a mail server. Messages hold working sign-in
+// links, so they are only readable by their owner.
+type FileMailer struct {
+	dir string
+	now func() time.Time
+}
+
+// NewFileMailer creates a mailer that writes messages to dir.
+func NewFileMailer(dir string) *FileMailer {
+	return &FileMailer{dir: dir, now: time.Now}
+}
+
+// Send writes the message to a .eml file named after when it was sent and
+// who it is for.
+func (f *FileMailer) Send(ctx context.Context, msg Message) error {
+	if err := os.MkdirAll(f.dir, 0o700); err != nil {
+		return fmt.Errorf("create mail directory: %w", err)
+	}
+
+	now := f.now()
+	name := fmt.Sprintf("%s-%s.eml", now.Format("20060102_150405.000000000"), fileSafe(msg.To))
+	content := fmt.Sprintf("To: %s\r\nSubject: %s\r\nDate: %s\r\nContent-Type: text/plain; charset=utf-8\r\n\r\n%s",
+		msg.To, msg.Subject, now.Format(time.RFC1123Z), msg.Body)
+
+	path := filepath.Join(f.dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		return fmt.Errorf("write message: %w", err)
+	}
+	return nil
+}
+
+// fileSafe replaces characters that don't belong in a file name.
+func fileSafe(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '@', r == '.', r == '-', r == '_':
+			return r
+		}
+		return '_'
+	}, s)
+}
@@ -0,0 +1,45 @@
+package auth
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFileMailer(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mail")
+	mailer := NewFileMailer(dir)
+	msg := Message{To: "miranda@example.com", Subject: "Sign in", Body: "https://primer.example/verify?token=abc\n"}
+	if err := mailer.Send(context.Background(), msg); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*-miranda@example.com.eml"))
+	if err != nil || len(files) != 1 {
+		t.Fatalf("files = %v, %v, want one message", files, err)
+	}
+	info, err := os.Stat(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("mode = %v, want only the owner to read sign-in links", info.Mode().Perm())
+	}
+	data, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"To: miranda@example.com\r\n", "Subject: Sign in\r\n", "\r\n\r\nhttps://primer.example/verify?token=abc\n"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("message missing %q:\n%s", want, data)
+		}
+	}
+}
+
+func TestFileSafe(t *testing.T) {
+	if got := fileSafe("../a b/c@example.com"); got != ".._a_b_c@example.com" {
+		t.Errorf("fileSafe() = %q", got)
+	}
+}
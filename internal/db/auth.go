@@ -0,0 +1,266 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/kbrakke/illustrated-primer/internal/models"
+)
+
+// ErrAccountNotFound is returned when no account is linked to a provider account.
+var ErrAccountNotFound = errors.New("account not found")
+
+// ErrSessionNotFound is returned when a session is not found.
+var ErrSessionNotFound = errors.New("session not found")
+
+// ErrVerificationTokenNotFound is returned when a verification token doesn't
+// exist or has already been used.
+var ErrVerificationTokenNotFound = errors.New("verification token not found")
+
+// CreateAccount links a user to a provider account.
+func (db *Database) CreateAccount(ctx context.Context, account *models.Account) error {
+	query := `
+		INSERT INTO accounts (id, user_id, type, provider, provider_account_id, refresh_token, access_token,
+			expires_at, token_type, scope, id_token, session_state, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+	`
+	_, err := db.pool.Exec(ctx, query,
+		account.ID,
+		account.UserID,
+		account.Type,
+		account.Provider,
+		account.ProviderAccountID,
+		account.RefreshToken,
+		account.AccessToken,
+		account.ExpiresAt,
+		account.TokenType,
+		account.Scope,
+		account.IDToken,
+		account.SessionState,
+		account.CreatedAt,
+		account.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("insert account: %w", err)
+	}
+	return nil
+}
+
+// GetAccountByProvider retrieves the account linked to a provider account.
+func (db *Database) GetAccountByProvider(ctx context.Context, provider, providerAccountID string) (*models.Account, error) {
+	query := `
+		SELECT id, user_id, type, provider, provider_account_id, refresh_token, access_token,
+			expires_at, token_type, scope, id_token, session_state, created_at, updated_at
+		FROM accounts
+		WHERE provider = $1 AND provider_account_id = $2
+	`
+
+	account, err := scanAccount(db.pool.QueryRow(ctx, query, provider, providerAccountID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrAccountNotFound
+		}
+		return nil, fmt.Errorf("query account by provider: %w", err)
+	}
+	return account, nil
+}
+
+// ListAccountsByUser retrieves the accounts linked to a user, oldest first.
+func (db *Database) ListAccountsByUser(ctx context.Context, userID string) ([]models.Account, error) {
+	query := `
+		SELECT id, user_id, type, provider, provider_account_id, refresh_token, access_token,
+			expires_at, token_type, scope, id_token, session_state, created_at, updated_at
+		FROM accounts
+		WHERE user_id = $1
+		ORDER BY created_at
+	`
+	rows, err := db.pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("query accounts: %w", err)
+	}
+	defer rows.Close()
+
+	var accounts []models.Account
+	for rows.Next() {
+		account, err := scanAccount(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan account: %w", err)
+		}
+		accounts = append(accounts, *account)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate accounts: %w", err)
+	}
+
+	return accounts, nil
+}
+
+// scanAccount scans the columns selected by the account queries.
+func scanAccount(row pgx.Row) (*models.Account, error) {
+	var account models.Account
+	err := row.Scan(
+		&account.ID,
+		&account.UserID,
+		&account.Type,
+		&account.Provider,
+		&account.ProviderAccountID,
+		&account.RefreshToken,
+		&account.AccessToken,
+		&account.ExpiresAt,
+		&account.TokenType,
+		&account.Scope,
+		&account.IDToken,
+		&account.SessionState,
+		&account.CreatedAt,
+		&account.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+// DeleteAccount unlinks a provider account.
+func (db *Database) DeleteAccount(ctx context.Context, provider, providerAccountID string) error {
+	query := `DELETE FROM accounts WHERE provider = $1 AND provider_account_id = $2`
+	result, err := db.pool.Exec(ctx, query, provider, providerAccountID)
+	if err != nil {
+		return fmt.Errorf("delete account: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrAccountNotFound
+	}
+	return nil
+}
+
+// CreateSession stores a new session.
+func (db *Database) CreateSession(ctx context.Context, session *models.Session) error {
+	query := `
+		INSERT INTO sessions (id, session_token, user_id, expires, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	_, err := db.pool.Exec(ctx, query,
+		session.ID,
+		session.SessionToken,
+		session.UserID,
+		session.Expires,
+		session.CreatedAt,
+		session.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("insert session: %w", err)
+	}
+	return nil
+}
+
+// GetSessionByToken retrieves a session by its token hash, expired or not.
+func (db *Database) GetSessionByToken(ctx context.Context, sessionToken string) (*models.Session, error) {
+	query := `
+		SELECT id, session_token, user_id, expires, created_at, updated_at
+		FROM sessions
+		WHERE session_token = $1
+	`
+
+	var session models.Session
+	err := db.pool.QueryRow(ctx, query, sessionToken).Scan(
+		&session.ID,
+		&session.SessionToken,
+		&session.UserID,
+		&session.Expires,
+		&session.CreatedAt,
+		&session.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrSessionNotFound
+		}
+		return nil, fmt.Errorf("query session by token: %w", err)
+	}
+
+	return &session, nil
+}
+
+// ExtendSession moves a session's expiry.
+func (db *Database) ExtendSession(ctx context.Context, sessionToken string, expires time.Time) error {
+	query := `UPDATE sessions SET expires = $2, updated_at = $3 WHERE session_token = $1`
+	result, err := db.pool.Exec(ctx, query, sessionToken, expires.Unix(), time.Now().Unix())
+	if err != nil {
+		return fmt.Errorf("extend session: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrSessionNotFound
+	}
+	return nil
+}
+
+// DeleteSession deletes a session by its token hash.
+func (db *Database) DeleteSession(ctx context.Context, sessionToken string) error {
+	query := `DELETE FROM sessions WHERE session_token = $1`
+	result, err := db.pool.Exec(ctx, query, sessionToken)
+	if err != nil {
+		return fmt.Errorf("delete session: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrSessionNotFound
+	}
+	return nil
+}
+
+// DeleteUserSessions signs a user out everywhere.
+func (db *Database) DeleteUserSessions(ctx context.Context, userID string) error {
+	if _, err := db.pool.Exec(ctx, `DELETE FROM sessions WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("delete user sessions: %w", err)
+	}
+	return nil
+}
+
+// CreateVerificationToken stores a one-time sign-in token.
+func (db *Database) CreateVerificationToken(ctx context.Context, token *models.VerificationToken) error {
+	query := `INSERT INTO verification_tokens (identifier, token, expires) VALUES ($1, $2, $3)`
+	if _, err := db.pool.Exec(ctx, query, token.Identifier, token.Token, token.Expires); err != nil {
+		return fmt.Errorf("insert verification token: %w", err)
+	}
+	return nil
+}
+
+// UseVerificationToken deletes a verification token and returns it, so each
+// token works at most once. Expired tokens are returned too; the caller
+// checks the expiry.
+func (db *Database) UseVerificationToken(ctx context.Context, identifier, token string) (*models.VerificationToken, error) {
+	query := `
+		DELETE FROM verification_tokens
+		WHERE identifier = $1 AND token = $2
+		RETURNING identifier, token, expires
+	`
+
+	var used models.VerificationToken
+	err := db.pool.QueryRow(ctx, query, identifier, token).Scan(&used.Identifier, &used.Token, &used.Expires)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrVerificationTokenNotFound
+		}
+		return nil, fmt.Errorf("use verification token: %w", err)
+	}
+
+	return &used, nil
+}
+
+// DeleteExpired removes sessions and verification tokens that have expired
+// by now, and returns how many of each were removed.
+func (db *Database) DeleteExpired(ctx context.Context, now time.Time) (sessions, tokens int64, err error) {
+	result, err := db.pool.Exec(ctx, `DELETE FROM sessions WHERE expires <= $1`, now.Unix())
+	if err != nil {
+		return 0, 0, fmt.Errorf("delete expired sessions: %w", err)
+	}
+	sessions = result.RowsAffected()
+
+	result, err = db.pool.Exec(ctx, `DELETE FROM verification_tokens WHERE expires <= $1`, now.Unix())
+	if err != nil {
+		return sessions, 0, fmt.Errorf("delete expired verification tokens: %w", err)
+	}
+	return sessions, result.RowsAffected(), nil
+}
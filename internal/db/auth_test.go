@@ -0,0 +1,132 @@
+//go:build integration
+
+package db_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kbrakke/illustrated-primer/internal/db"
+	"github.com/kbrakke/illustrated-primer/internal/models"
+	"github.com/kbrakke/illustrated-primer/testutil"
+)
+
+func TestAuthTables(t *testing.T) {
+	testDB := testutil.NewTestDatabase(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	user := models.NewUser("Auth User", "auth@example.com")
+	if err := testDB.Database.CreateUser(ctx, user); err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+
+	t.Run("Accounts", func(t *testing.T) {
+		account := models.NewAccount(user.ID, "oauth", "google", "g-123")
+		if err := testDB.Database.CreateAccount(ctx, account); err != nil {
+			t.Fatalf("CreateAccount failed: %v", err)
+		}
+		if err := testDB.Database.CreateAccount(ctx, models.NewAccount(user.ID, "oauth", "google", "g-123")); err == nil {
+			t.Error("a provider account should only be linked once")
+		}
+
+		got, err := testDB.Database.GetAccountByProvider(ctx, "google", "g-123")
+		if err != nil {
+			t.Fatalf("GetAccountByProvider failed: %v", err)
+		}
+		if got.UserID != user.ID {
+			t.Errorf("UserID = %q, want %q", got.UserID, user.ID)
+		}
+		accounts, err := testDB.Database.ListAccountsByUser(ctx, user.ID)
+		if err != nil || len(accounts) != 1 {
+			t.Fatalf("ListAccountsByUser = %v, %v, want one account", accounts, err)
+		}
+
+		if err := testDB.Database.DeleteAccount(ctx, "google", "g-123"); err != nil {
+			t.Fatalf("DeleteAccount failed: %v", err)
+		}
+		if _, err := testDB.Database.GetAccountByProvider(ctx, "google", "g-123"); !errors.Is(err, db.ErrAccountNotFound) {
+			t.Errorf("deleted account: got %v, want ErrAccountNotFound", err)
+		}
+	})
+
+	t.Run("Sessions", func(t *testing.T) {
+		session := models.NewSession(user.ID, "hash-1", now.Add(time.Hour))
+		if err := testDB.Database.CreateSession(ctx, session); err != nil {
+			t.Fatalf("CreateSession failed: %v", err)
+		}
+		later := now.Add(48 * time.Hour)
+		if err := testDB.Database.ExtendSession(ctx, "hash-1", later); err != nil {
+			t.Fatalf("ExtendSession failed: %v", err)
+		}
+		got, err := testDB.Database.GetSessionByToken(ctx, "hash-1")
+		if err != nil {
+			t.Fatalf("GetSessionByToken failed: %v", err)
+		}
+		if got.UserID != user.ID || got.Expires != later.Unix() {
+			t.Errorf("session = %+v, want the extended session", got)
+		}
+
+		if err := testDB.Database.DeleteSession(ctx, "hash-1"); err != nil {
+			t.Fatalf("DeleteSession failed: %v", err)
+		}
+		if _, err := testDB.Database.GetSessionByToken(ctx, "hash-1"); !errors.Is(err, db.ErrSessionNotFound) {
+			t.Errorf("deleted session: got %v, want ErrSessionNotFound", err)
+		}
+
+		for _, hash := range []string{"hash-2", "hash-3"} {
+			if err := testDB.Database.CreateSession(ctx, models.NewSession(user.ID, hash, now.Add(time.Hour))); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := testDB.Database.DeleteUserSessions(ctx, user.ID); err != nil {
+			t.Fatalf("DeleteUserSessions failed: %v", err)
+		}
+		if _, err := testDB.Database.GetSessionByToken(ctx, "hash-2"); !errors.Is(err, db.ErrSessionNotFound) {
+			t.Error("signing out everywhere should delete every session")
+		}
+	})
+
+	t.Run("VerificationTokens", func(t *testing.T) {
+		token := &models.VerificationToken{Identifier: "auth@example.com", Token: "link-hash", Expires: now.Add(time.Hour).Unix()}
+		if err := testDB.Database.CreateVerificationToken(ctx, token); err != nil {
+			t.Fatalf("CreateVerificationToken failed: %v", err)
+		}
+		used, err := testDB.Database.UseVerificationToken(ctx, "auth@example.com", "link-hash")
+		if err != nil {
+			t.Fatalf("UseVerificationToken failed: %v", err)
+		}
+		if used.Expires != token.Expires {
+			t.Errorf("Expires = %d, want %d", used.Expires, token.Expires)
+		}
+		if _, err := testDB.Database.UseVerificationToken(ctx, "auth@example.com", "link-hash"); !errors.Is(err, db.ErrVerificationTokenNotFound) {
+			t.Errorf("reused token: got %v, want ErrVerificationTokenNotFound", err)
+		}
+	})
+
+	t.Run("DeleteExpired", func(t *testing.T) {
+		if err := testDB.Database.CreateSession(ctx, models.NewSession(user.ID, "old", now.Add(-time.Minute))); err != nil {
+			t.Fatal(err)
+		}
+		if err := testDB.Database.CreateSession(ctx, models.NewSession(user.ID, "current", now.Add(time.Hour))); err != nil {
+			t.Fatal(err)
+		}
+		old := &models.VerificationToken{Identifier: "auth@example.com", Token: "old", Expires: now.Add(-time.Minute).Unix()}
+		if err := testDB.Database.CreateVerificationToken(ctx, old); err != nil {
+			t.Fatal(err)
+		}
+
+		sessions, tokens, err := testDB.Database.DeleteExpired(ctx, now)
+		if err != nil {
+			t.Fatalf("DeleteExpired failed: %v", err)
+		}
+		if sessions != 1 || tokens != 1 {
+			t.Errorf("deleted %d sessions and %d tokens, want 1 and 1", sessions, tokens)
+		}
+		if _, err := testDB.Database.GetSessionByToken(ctx, "current"); err != nil {
+			t.Errorf("a current session shouldn't be deleted: %v", err)
+		}
+	})
+}
@@ -0,0 +1,212 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/kbrakke/illustrated-primer/internal/models"
+)
+
+// CreateSubject inserts a new curriculum subject.
+func (db *Database) CreateSubject(ctx context.Context, subject *models.Subject) error {
+	query := `
+		INSERT INTO subjects (id, name, description, created_at)
+		VALUES ($1, $2, $3, $4)
+	`
+	_, err := db.pool.Exec(ctx, query, subject.ID, subject.Name, subject.Description, subject.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("insert subject: %w", err)
+	}
+	return nil
+}
+
+// ListSubjects retrieves all curriculum subjects ordered by name.
+func (db *Database) ListSubjects(ctx context.Context) ([]models.Subject, error) {
+	query := `
+		SELECT id, name, description, created_at
+		FROM subjects
+		ORDER BY name
+	`
+	rows, err := db.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("query subjects: %w", err)
+	}
+	defer rows.Close()
+
+	var subjects []models.Subject
+	for rows.Next() {
+		var subject models.Subject
+		if err := rows.Scan(&subject.ID, &subject.Name, &subject.Description, &subject.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan subject: %w", err)
+		}
+		subjects = append(subjects, subject)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate subjects: %w", err)
+	}
+	return subjects, nil
+}
+
+// CreateObjective inserts a new learning objective.
+func (db *Database) CreateObjective(ctx context.Context, objective *models.Objective) error {
+	query := `
+		INSERT INTO objectives (id, subject_id, title, description, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	_, err := db.pool.Exec(ctx, query,
+		objective.ID,
+		objective.SubjectID,
+		objective.Title,
+		objective.Description,
+		objective.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("insert objective: %w", err)
+	}
+	return nil
+}
+
+// ListObjectives retrieves all learning objectives, grouped by subject name
+// and ordered by title within each subject.
+func (db *Database) ListObjectives(ctx context.Context) ([]models.Objective, error) {
+	query := `
+		SELECT o.id, o.subject_id, o.title, o.description, o.created_at
+		FROM objectives o
+		JOIN subjects s ON s.id = o.subject_id
+		ORDER BY s.name, o.title
+	`
+	return db.queryObjectives(ctx, query)
+}
+
+// SetStoryObjectives replaces the objectives a story teaches.
+func (db *Database) SetStoryObjectives(ctx context.Context, storyID string, objectiveIDs []string) error {
+	return db.setObjectives(ctx, "story_objectives", "story_id", storyID, objectiveIDs)
+}
+
+// ListStoryObjectives retrieves the objectives a story teaches, ordered by title.
+func (db *Database) ListStoryObjectives(ctx context.Context, storyID string) ([]models.Objective, error) {
+	query := `
+		SELECT o.id, o.subject_id, o.title, o.description, o.created_at
+		FROM story_objectives so
+		JOIN objectives o ON o.id = so.objective_id
+		WHERE so.story_id = $1
+		ORDER BY o.title
+	`
+	return db.queryObjectives(ctx, query, storyID)
+}
+
+// SetPageObjectives replaces the objectives a page worked on.
+func (db *Database) SetPageObjectives(ctx context.Context, pageID string, objectiveIDs []string) error {
+	return db.setObjectives(ctx, "page_objectives", "page_id", pageID, objectiveIDs)
+}
+
+// ListPageObjectives retrieves the objectives a page worked on, ordered by title.
+func (db *Database) ListPageObjectives(ctx context.Context, pageID string) ([]models.Objective, error) {
+	query := `
+		SELECT o.id, o.subject_id, o.title, o.description, o.created_at
+		FROM page_objectives po
+		JOIN objectives o ON o.id = po.objective_id
+		WHERE po.page_id = $1
+		ORDER BY o.title
+	`
+	return db.queryObjectives(ctx, query, pageID)
+}
+
+// ListObjectiveProgress retrieves each of a story's objectives with the
+// number of its pages that worked on it, ordered by title.
+func (db *Database) ListObjectiveProgress(ctx context.Context, storyID string) ([]models.ObjectiveProgress, error) {
+	query := `
+		SELECT o.id, o.subject_id, o.title, o.description, o.created_at, COUNT(p.id)
+		FROM story_objectives so
+		JOIN objectives o ON o.id = so.objective_id
+		LEFT JOIN page_objectives po ON po.objective_id = o.id
+		LEFT JOIN pages p ON p.id = po.page_id AND p.story_id = so.story_id
+		WHERE so.story_id = $1
+		GROUP BY o.id
+		ORDER BY o.title
+	`
+	rows, err := db.pool.Query(ctx, query, storyID)
+	if err != nil {
+		return nil, fmt.Errorf("query objective progress: %w", err)
+	}
+	defer rows.Close()
+
+	var progress []models.ObjectiveProgress
+	for rows.Next() {
+		var p models.ObjectiveProgress
+		err := rows.Scan(
+			&p.ID,
+			&p.SubjectID,
+			&p.Title,
+			&p.Description,
+			&p.CreatedAt,
+			&p.Pages,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan objective progress: %w", err)
+		}
+		progress = append(progress, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate objective progress: %w", err)
+	}
+	return progress, nil
+}
+
+// queryObjectives runs a query selecting objective columns.
+func (db *Database) queryObjectives(ctx context.Context, query string, args ...any) ([]models.Objective, error) {
+	rows, err := db.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query objectives: %w", err)
+	}
+	defer rows.Close()
+
+	var objectives []models.Objective
+	for rows.Next() {
+		var objective models.Objective
+		err := rows.Scan(
+			&objective.ID,
+			&objective.SubjectID,
+			&objective.Title,
+			&objective.Description,
+			&objective.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan objective: %w", err)
+		}
+		objectives = append(objectives, objective)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate objectives: %w", err)
+	}
+	return objectives, nil
+}
+
+// setObjectives replaces the rows linking owner to objectives in a link table,
+// in a single transaction.
+func (db *Database) setObjectives(ctx context.Context, table, ownerColumn, ownerID string, objectiveIDs []string) error {
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, fmt.Sprintf(`DELETE FROM %s WHERE %s = $1`, table, ownerColumn), ownerID); err != nil {
+		return fmt.Errorf("clear %s: %w", table, err)
+	}
+
+	insert := fmt.Sprintf(`INSERT INTO %s (%s, objective_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`, table, ownerColumn)
+	batch := &pgx.Batch{}
+	for _, id := range objectiveIDs {
+		batch.Queue(insert, ownerID, id)
+	}
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("insert %s: %w", table, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+//go:build integration
+
+package db_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kbrakke/illustrated-primer/internal/models"
+	"github.com/kbrakke/illustrated-primer/testutil"
+)
+
+func TestCurriculum(t *testing.T) {
+	testDB := testutil.NewTestDatabase(t)
+	ctx := context.Background()
+
+	user := models.NewUser("Curriculum Test User", "curriculum-test@example.com")
+	if err := testDB.Database.CreateUser(ctx, user); err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+
+	math := models.NewSubject("Math", "Numbers and counting")
+	art := models.NewSubject("Art", "Colors and shapes")
+	for _, subject := range []*models.Subject{math, art} {
+		if err := testDB.Database.CreateSubject(ctx, subject); err != nil {
+			t.Fatalf("CreateSubject failed: %v", err)
+		}
+	}
+
+	counting := models.NewObjective(math.ID, "Count to 10", "")
+	colors := models.NewObjective(art.ID, "Primary colors", "")
+	shapes := models.NewObjective(art.ID, "Circles and squares", "")
+	for _, objective := range []*models.Objective{counting, colors, shapes} {
+		if err := testDB.Database.CreateObjective(ctx, objective); err != nil {
+			t.Fatalf("CreateObjective failed: %v", err)
+		}
+	}
+
+	story := models.NewStory(user.ID, "Curriculum Story", "")
+	if err := testDB.Database.CreateStory(ctx, story); err != nil {
+		t.Fatalf("failed to create story: %v", err)
+	}
+
+	t.Run("ListSubjects", func(t *testing.T) {
+		subjects, err := testDB.Database.ListSubjects(ctx)
+		if err != nil {
+			t.Fatalf("ListSubjects failed: %v", err)
+		}
+		if len(subjects) != 2 || subjects[0].Name != "Art" {
+			t.Errorf("subjects = %+v, want Art then Math", subjects)
+		}
+	})
+
+	t.Run("ListObjectives", func(t *testing.T) {
+		objectives, err := testDB.Database.ListObjectives(ctx)
+		if err != nil {
+			t.Fatalf("ListObjectives failed: %v", err)
+		}
+		want := []string{shapes.ID, colors.ID, counting.ID} // by subject, then title
+		if len(objectives) != len(want) {
+			t.Fatalf("got %d objectives, want %d", len(objectives), len(want))
+		}
+		for i, id := range want {
+			if objectives[i].ID != id {
+				t.Errorf("objectives[%d] = %q, want %q", i, objectives[i].Title, id)
+			}
+		}
+	})
+
+	t.Run("StoryObjectives", func(t *testing.T) {
+		if err := testDB.Database.SetStoryObjectives(ctx, story.ID, []string{counting.ID, shapes.ID}); err != nil {
+			t.Fatalf("SetStoryObjectives failed: %v", err)
+		}
+		if err := testDB.Database.SetStoryObjectives(ctx, story.ID, []string{counting.ID, colors.ID}); err != nil {
+			t.Fatalf("SetStoryObjectives failed: %v", err)
+		}
+
+		objectives, err := testDB.Database.ListStoryObjectives(ctx, story.ID)
+		if err != nil {
+			t.Fatalf("ListStoryObjectives failed: %v", err)
+		}
+		if len(objectives) != 2 || objectives[0].ID != counting.ID || objectives[1].ID != colors.ID {
+			t.Errorf("objectives = %+v, want the replaced set", objectives)
+		}
+	})
+
+	t.Run("PageObjectivesAndProgress", func(t *testing.T) {
+		for i, touched := range [][]string{{counting.ID}, {counting.ID, colors.ID}, nil} {
+			page := models.NewPage(story.ID, int64(i+1), "prompt", "completion")
+			if err := testDB.Database.CreatePage(ctx, page); err != nil {
+				t.Fatalf("CreatePage failed: %v", err)
+			}
+			if err := testDB.Database.SetPageObjectives(ctx, page.ID, touched); err != nil {
+				t.Fatalf("SetPageObjectives failed: %v", err)
+			}
+			objectives, err := testDB.Database.ListPageObjectives(ctx, page.ID)
+			if err != nil {
+				t.Fatalf("ListPageObjectives failed: %v", err)
+			}
+			if len(objectives) != len(touched) {
+				t.Errorf("page %d has %d objectives, want %d", i+1, len(objectives), len(touched))
+			}
+		}
+
+		progress, err := testDB.Database.ListObjectiveProgress(ctx, story.ID)
+		if err != nil {
+			t.Fatalf("ListObjectiveProgress failed: %v", err)
+		}
+		if len(progress) != 2 || progress[0].ID != counting.ID || progress[0].Pages != 2 || progress[1].Pages != 1 {
+			t.Errorf("progress = %+v", progress)
+		}
+	})
+
+	t.Run("DuplicateStoryCopiesObjectives", func(t *testing.T) {
+		copied, err := testDB.Database.DuplicateStory(ctx, story.ID, "Curriculum Story (copy)")
+		if err != nil {
+			t.Fatalf("DuplicateStory failed: %v", err)
+		}
+
+		progress, err := testDB.Database.ListObjectiveProgress(ctx, copied.ID)
+		if err != nil {
+			t.Fatalf("ListObjectiveProgress failed: %v", err)
+		}
+		if len(progress) != 2 || progress[0].Pages != 2 || progress[1].Pages != 1 {
+			t.Errorf("copied progress = %+v", progress)
+		}
+	})
+}
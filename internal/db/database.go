@@ -23,6 +23,42 @@ CREATE TABLE IF NOT EXISTS users (
     updated_at BIGINT NOT NULL DEFAULT (EXTRACT(EPOCH FROM NOW())::BIGINT)
 );
 
+CREATE TABLE IF NOT EXISTS accounts (
+    id TEXT PRIMARY KEY NOT NULL,
+    user_id TEXT NOT NULL,
+    type TEXT NOT NULL,
+    provider TEXT NOT NULL,
+    provider_account_id TEXT NOT NULL,
+    refresh_token TEXT,
+    access_token TEXT,
+    expires_at BIGINT,
+    token_type TEXT,
+    scope TEXT,
+    id_token TEXT,
+    session_state TEXT,
+    created_at BIGINT NOT NULL DEFAULT (EXTRACT(EPOCH FROM NOW())::BIGINT),
+    updated_at BIGINT NOT NULL DEFAULT (EXTRACT(EPOCH FROM NOW())::BIGINT),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    UNIQUE(provider, provider_account_id)
+);
+
+CREATE TABLE IF NOT EXISTS sessions (
+    id TEXT PRIMARY KEY NOT NULL,
+    session_token TEXT UNIQUE NOT NULL,
+    user_id TEXT NOT NULL,
+    expires BIGINT NOT NULL,
+    created_at BIGINT NOT NULL DEFAULT (EXTRACT(EPOCH FROM NOW())::BIGINT),
+    updated_at BIGINT NOT NULL DEFAULT (EXTRACT(EPOCH FROM NOW())::BIGINT),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS verification_tokens (
+    identifier TEXT NOT NULL,
+    token TEXT NOT NULL,
+    expires BIGINT NOT NULL,
+    UNIQUE(identifier, token)
+);
+
 CREATE TABLE IF NOT EXISTS stories (
     id TEXT PRIMARY KEY NOT NULL,
     user_id TEXT NOT NULL,
@@ -49,10 +85,209 @@ CREATE TABLE IF NOT EXISTS pages (
     UNIQUE(story_id, page_num)
 );
 
+CREATE INDEX IF NOT EXISTS idx_accounts_user_id ON accounts(user_id);
+CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions(user_id);
+CREATE INDEX IF NOT EXISTS idx_sessions_token ON sessions(session_token);
 CREATE INDEX IF NOT EXISTS idx_stories_user_id ON stories(user_id);
 CREATE INDEX IF NOT EXISTS idx_stories_user_created ON stories(user_id, created_at);
 CREATE INDEX IF NOT EXISTS idx_pages_story_id ON pages(story_id);
 CREATE INDEX IF NOT EXISTS idx_pages_story_page ON pages(story_id, page_num);
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS theme TEXT;
+
+ALTER TABLE stories ADD COLUMN IF NOT EXISTS art_style TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS moderation_flags (
+    id TEXT PRIMARY KEY NOT NULL,
+    user_id TEXT NOT NULL,
+    story_id TEXT,
+    source TEXT NOT NULL,
+    action TEXT NOT NULL,
+    categories TEXT[] NOT NULL DEFAULT '{}',
+    original_text TEXT NOT NULL,
+    final_text TEXT NOT NULL DEFAULT '',
+    reviewed_at BIGINT,
+    created_at BIGINT NOT NULL DEFAULT (EXTRACT(EPOCH FROM NOW())::BIGINT),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (story_id) REFERENCES stories(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_moderation_flags_user_created ON moderation_flags(user_id, created_at);
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS birthdate TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS age_band TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS reading_level TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS interests TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS language TEXT;
+
+ALTER TABLE stories ADD COLUMN IF NOT EXISTS prompt_template TEXT NOT NULL DEFAULT '';
+ALTER TABLE pages ADD COLUMN IF NOT EXISTS prompt_template TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE pages ADD COLUMN IF NOT EXISTS choices TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE TABLE IF NOT EXISTS subjects (
+    id TEXT PRIMARY KEY NOT NULL,
+    name TEXT NOT NULL UNIQUE,
+    description TEXT NOT NULL DEFAULT '',
+    created_at BIGINT NOT NULL DEFAULT (EXTRACT(EPOCH FROM NOW())::BIGINT)
+);
+
+CREATE TABLE IF NOT EXISTS objectives (
+    id TEXT PRIMARY KEY NOT NULL,
+    subject_id TEXT NOT NULL,
+    title TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_at BIGINT NOT NULL DEFAULT (EXTRACT(EPOCH FROM NOW())::BIGINT),
+    FOREIGN KEY (subject_id) REFERENCES subjects(id) ON DELETE CASCADE,
+    UNIQUE(subject_id, title)
+);
+
+-- The objectives a story was created to teach
+CREATE TABLE IF NOT EXISTS story_objectives (
+    story_id TEXT NOT NULL,
+    objective_id TEXT NOT NULL,
+    PRIMARY KEY (story_id, objective_id),
+    FOREIGN KEY (story_id) REFERENCES stories(id) ON DELETE CASCADE,
+    FOREIGN KEY (objective_id) REFERENCES objectives(id) ON DELETE CASCADE
+);
+
+-- The objectives each page worked on
+CREATE TABLE IF NOT EXISTS page_objectives (
+    page_id TEXT NOT NULL,
+    objective_id TEXT NOT NULL,
+    PRIMARY KEY (page_id, objective_id),
+    FOREIGN KEY (page_id) REFERENCES pages(id) ON DELETE CASCADE,
+    FOREIGN KEY (objective_id) REFERENCES objectives(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_objectives_subject_id ON objectives(subject_id);
+CREATE INDEX IF NOT EXISTS idx_page_objectives_objective_id ON page_objectives(objective_id);
+
+CREATE TABLE IF NOT EXISTS quiz_attempts (
+    id TEXT PRIMARY KEY NOT NULL,
+    user_id TEXT NOT NULL,
+    story_id TEXT,
+    score INTEGER NOT NULL DEFAULT 0,
+    total INTEGER NOT NULL DEFAULT 0,
+    created_at BIGINT NOT NULL DEFAULT (EXTRACT(EPOCH FROM NOW())::BIGINT),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (story_id) REFERENCES stories(id) ON DELETE SET NULL
+);
+
+-- The questions of each attempt as they were asked, and the options chosen
+CREATE TABLE IF NOT EXISTS quiz_answers (
+    attempt_id TEXT NOT NULL,
+    position INTEGER NOT NULL,
+    question TEXT NOT NULL,
+    kind TEXT NOT NULL DEFAULT 'text',
+    options TEXT[] NOT NULL DEFAULT '{}',
+    answer INTEGER NOT NULL,
+    chosen INTEGER NOT NULL,
+    PRIMARY KEY (attempt_id, position),
+    FOREIGN KEY (attempt_id) REFERENCES quiz_attempts(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_quiz_attempts_user_created ON quiz_attempts(user_id, created_at);
+
+-- The challenging words each child has met, and where they first saw them
+CREATE TABLE IF NOT EXISTS vocabulary (
+    user_id TEXT NOT NULL,
+    word TEXT NOT NULL,
+    first_story_id TEXT,
+    first_page_id TEXT,
+    times_seen INTEGER NOT NULL DEFAULT 1,
+    created_at BIGINT NOT NULL DEFAULT (EXTRACT(EPOCH FROM NOW())::BIGINT),
+    updated_at BIGINT NOT NULL DEFAULT (EXTRACT(EPOCH FROM NOW())::BIGINT),
+    PRIMARY KEY (user_id, word),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (first_story_id) REFERENCES stories(id) ON DELETE SET NULL,
+    FOREIGN KEY (first_page_id) REFERENCES pages(id) ON DELETE SET NULL
+);
+
+-- Definitions are generated once per word and age band
+CREATE TABLE IF NOT EXISTS definitions (
+    word TEXT NOT NULL,
+    age_band TEXT NOT NULL DEFAULT '',
+    definition TEXT NOT NULL,
+    created_at BIGINT NOT NULL DEFAULT (EXTRACT(EPOCH FROM NOW())::BIGINT),
+    PRIMARY KEY (word, age_band)
+);
+
+-- SM-2 review schedule of each word
+ALTER TABLE vocabulary ADD COLUMN IF NOT EXISTS ease DOUBLE PRECISION NOT NULL DEFAULT 2.5;
+ALTER TABLE vocabulary ADD COLUMN IF NOT EXISTS interval_days BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE vocabulary ADD COLUMN IF NOT EXISTS repetitions BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE vocabulary ADD COLUMN IF NOT EXISTS due_at BIGINT NOT NULL DEFAULT (EXTRACT(EPOCH FROM NOW())::BIGINT);
+ALTER TABLE vocabulary ADD COLUMN IF NOT EXISTS reviewed_at BIGINT;
+
+CREATE INDEX IF NOT EXISTS idx_vocabulary_user_due ON vocabulary(user_id, due_at);
+
+-- How well each word was remembered at each review
+CREATE TABLE IF NOT EXISTS word_reviews (
+    id TEXT PRIMARY KEY NOT NULL,
+    user_id TEXT NOT NULL,
+    word TEXT NOT NULL,
+    recall INTEGER NOT NULL,
+    interval_days BIGINT NOT NULL,
+    created_at BIGINT NOT NULL DEFAULT (EXTRACT(EPOCH FROM NOW())::BIGINT),
+    FOREIGN KEY (user_id, word) REFERENCES vocabulary(user_id, word) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_word_reviews_user_created ON word_reviews(user_id, created_at);
+
+-- How hard each page is to read; NULL for pages saved before scoring
+ALTER TABLE pages ADD COLUMN IF NOT EXISTS grade_level DOUBLE PRECISION;
+ALTER TABLE pages ADD COLUMN IF NOT EXISTS reading_ease DOUBLE PRECISION;
+ALTER TABLE pages ADD COLUMN IF NOT EXISTS words_per_sentence DOUBLE PRECISION;
+ALTER TABLE pages ADD COLUMN IF NOT EXISTS rare_word_ratio DOUBLE PRECISION;
+
+CREATE TABLE IF NOT EXISTS token_usage (
+    id TEXT PRIMARY KEY NOT NULL,
+    user_id TEXT NOT NULL,
+    story_id TEXT,
+    kind TEXT NOT NULL,
+    input_tokens BIGINT NOT NULL DEFAULT 0,
+    output_tokens BIGINT NOT NULL DEFAULT 0,
+    created_at BIGINT NOT NULL DEFAULT (EXTRACT(EPOCH FROM NOW())::BIGINT),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (story_id) REFERENCES stories(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_token_usage_user_created ON token_usage(user_id, created_at);
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS daily_minutes BIGINT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS allowed_from TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS allowed_until TEXT;
+
+CREATE TABLE IF NOT EXISTS screen_sessions (
+    id TEXT PRIMARY KEY NOT NULL,
+    user_id TEXT NOT NULL,
+    override BOOLEAN NOT NULL DEFAULT FALSE,
+    end_reason TEXT NOT NULL DEFAULT '',
+    started_at BIGINT NOT NULL,
+    last_seen_at BIGINT NOT NULL,
+    ended_at BIGINT,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_screen_sessions_user_started ON screen_sessions(user_id, started_at);
+
+CREATE TABLE IF NOT EXISTS households (
+    id TEXT PRIMARY KEY NOT NULL,
+    name TEXT NOT NULL,
+    created_at BIGINT NOT NULL DEFAULT (EXTRACT(EPOCH FROM NOW())::BIGINT),
+    updated_at BIGINT NOT NULL DEFAULT (EXTRACT(EPOCH FROM NOW())::BIGINT)
+);
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS household_id TEXT REFERENCES households(id) ON DELETE SET NULL;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role TEXT NOT NULL DEFAULT 'child';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS pin_hash TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_users_household_id ON users(household_id);
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS story_themes TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS banned_topics TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS sensitivities TEXT[] NOT NULL DEFAULT '{}';
 `
 
 // Database wraps a PostgreSQL connection pool and provides database operations.
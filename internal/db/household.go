@@ -0,0 +1,107 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/kbrakke/illustrated-primer/internal/models"
+)
+
+// ErrHouseholdNotFound is returned when a household is not found.
+var ErrHouseholdNotFound = errors.New("household not found")
+
+// CreateHousehold inserts a new household into the database.
+func (db *Database) CreateHousehold(ctx context.Context, household *models.Household) error {
+	query := `
+		INSERT INTO households (id, name, created_at, updated_at)
+		VALUES ($1, $2, $3, $4)
+	`
+	_, err := db.pool.Exec(ctx, query,
+		household.ID,
+		household.Name,
+		household.CreatedAt,
+		household.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("insert household: %w", err)
+	}
+	return nil
+}
+
+// GetHouseholdByID retrieves a household by its ID.
+func (db *Database) GetHouseholdByID(ctx context.Context, id string) (*models.Household, error) {
+	query := `SELECT id, name, created_at, updated_at FROM households WHERE id = $1`
+
+	var household models.Household
+	err := db.pool.QueryRow(ctx, query, id).Scan(
+		&household.ID,
+		&household.Name,
+		&household.CreatedAt,
+		&household.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrHouseholdNotFound
+		}
+		return nil, fmt.Errorf("query household by id: %w", err)
+	}
+
+	return &household, nil
+}
+
+// ListHouseholdMembers retrieves the guardians and children of a household,
+// guardians first.
+func (db *Database) ListHouseholdMembers(ctx context.Context, householdID string) ([]models.User, error) {
+	query := `
+		SELECT id, name, email, email_verified, image, theme, created_at, updated_at, birthdate, age_band, reading_level, interests, language,
+			daily_minutes, allowed_from, allowed_until, household_id, role, pin_hash, story_themes, banned_topics, sensitivities
+		FROM users
+		WHERE household_id = $1
+		ORDER BY role = 'guardian' DESC, created_at
+	`
+	rows, err := db.pool.Query(ctx, query, householdID)
+	if err != nil {
+		return nil, fmt.Errorf("query household members: %w", err)
+	}
+	defer rows.Close()
+
+	var users []models.User
+	for rows.Next() {
+		var user models.User
+		if err := rows.Scan(
+			&user.ID,
+			&user.Name,
+			&user.Email,
+			&user.EmailVerified,
+			&user.Image,
+			&user.Theme,
+			&user.CreatedAt,
+			&user.UpdatedAt,
+			&user.Birthdate,
+			&user.AgeBand,
+			&user.ReadingLevel,
+			&user.Interests,
+			&user.Language,
+			&user.DailyMinutes,
+			&user.AllowedFrom,
+			&user.AllowedUntil,
+			&user.HouseholdID,
+			&user.Role,
+			&user.PINHash,
+			&user.StoryThemes,
+			&user.BannedTopics,
+			&user.Sensitivities,
+		); err != nil {
+			return nil, fmt.Errorf("scan household member: %w", err)
+		}
+		users = append(users, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate household members: %w", err)
+	}
+
+	return users, nil
+}
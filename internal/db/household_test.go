@@ -0,0 +1,67 @@
+//go:build integration
+
+package db_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kbrakke/illustrated-primer/internal/db"
+	"github.com/kbrakke/illustrated-primer/internal/models"
+	"github.com/kbrakke/illustrated-primer/testutil"
+)
+
+func TestHouseholds(t *testing.T) {
+	testDB := testutil.NewTestDatabase(t)
+	ctx := context.Background()
+
+	household := models.NewHousehold("The Lindqvists")
+	if err := testDB.Database.CreateHousehold(ctx, household); err != nil {
+		t.Fatalf("CreateHousehold failed: %v", err)
+	}
+
+	t.Run("GetHouseholdByID", func(t *testing.T) {
+		got, err := testDB.Database.GetHouseholdByID(ctx, household.ID)
+		if err != nil {
+			t.Fatalf("GetHouseholdByID failed: %v", err)
+		}
+		if got.Name != household.Name {
+			t.Errorf("Name = %q, want %q", got.Name, household.Name)
+		}
+		if _, err := testDB.Database.GetHouseholdByID(ctx, "missing"); !errors.Is(err, db.ErrHouseholdNotFound) {
+			t.Errorf("missing household: got %v, want ErrHouseholdNotFound", err)
+		}
+	})
+
+	t.Run("ListHouseholdMembers", func(t *testing.T) {
+		child := models.NewUser("Nell", "nell@example.com")
+		child.HouseholdID = &household.ID
+		guardian := models.NewUser("Miranda", "miranda@example.com")
+		guardian.HouseholdID = &household.ID
+		guardian.Role = models.RoleGuardian
+		if err := guardian.SetPIN("2468"); err != nil {
+			t.Fatalf("SetPIN failed: %v", err)
+		}
+		outsider := models.NewUser("Harv", "harv@example.com")
+		for _, user := range []*models.User{child, guardian, outsider} {
+			if err := testDB.Database.CreateUser(ctx, user); err != nil {
+				t.Fatalf("failed to create user: %v", err)
+			}
+		}
+
+		members, err := testDB.Database.ListHouseholdMembers(ctx, household.ID)
+		if err != nil {
+			t.Fatalf("ListHouseholdMembers failed: %v", err)
+		}
+		if len(members) != 2 || members[0].ID != guardian.ID || members[1].ID != child.ID {
+			t.Fatalf("members = %+v, want the guardian then the child", members)
+		}
+		if !members[0].IsGuardian() || !members[0].CheckPIN("2468") {
+			t.Error("the guardian's role and PIN should round-trip")
+		}
+		if members[1].Role != models.RoleChild || members[1].HasPIN() {
+			t.Errorf("child role = %q, want a child without a PIN", members[1].Role)
+		}
+	})
+}
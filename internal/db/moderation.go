@@ -0,0 +1,96 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kbrakke/illustrated-primer/internal/models"
+)
+
+// ErrModerationFlagNotFound is returned when a moderation flag is not found.
+var ErrModerationFlagNotFound = errors.New("moderation flag not found")
+
+// CreateModerationFlag records a moderated prompt or completion.
+func (db *Database) CreateModerationFlag(ctx context.Context, flag *models.ModerationFlag) error {
+	query := `
+		INSERT INTO moderation_flags (id, user_id, story_id, source, action, categories, original_text, final_text, reviewed_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+	categories := flag.Categories
+	if categories == nil {
+		categories = []string{}
+	}
+	_, err := db.pool.Exec(ctx, query,
+		flag.ID,
+		flag.UserID,
+		flag.StoryID,
+		flag.Source,
+		flag.Action,
+		categories,
+		flag.OriginalText,
+		flag.FinalText,
+		flag.ReviewedAt,
+		flag.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("insert moderation flag: %w", err)
+	}
+	return nil
+}
+
+// ListModerationFlagsByUser retrieves a user's moderation flags, newest first.
+// Flags that have already been reviewed are skipped unless includeReviewed is set.
+func (db *Database) ListModerationFlagsByUser(ctx context.Context, userID string, includeReviewed bool) ([]models.ModerationFlag, error) {
+	query := `
+		SELECT id, user_id, story_id, source, action, categories, original_text, final_text, reviewed_at, created_at
+		FROM moderation_flags
+		WHERE user_id = $1 AND ($2 OR reviewed_at IS NULL)
+		ORDER BY created_at DESC
+	`
+	rows, err := db.pool.Query(ctx, query, userID, includeReviewed)
+	if err != nil {
+		return nil, fmt.Errorf("query moderation flags: %w", err)
+	}
+	defer rows.Close()
+
+	var flags []models.ModerationFlag
+	for rows.Next() {
+		var flag models.ModerationFlag
+		if err := rows.Scan(
+			&flag.ID,
+			&flag.UserID,
+			&flag.StoryID,
+			&flag.Source,
+			&flag.Action,
+			&flag.Categories,
+			&flag.OriginalText,
+			&flag.FinalText,
+			&flag.ReviewedAt,
+			&flag.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan moderation flag: %w", err)
+		}
+		flags = append(flags, flag)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate moderation flags: %w", err)
+	}
+
+	return flags, nil
+}
+
+// MarkModerationFlagReviewed records that a parent has seen a flag.
+func (db *Database) MarkModerationFlagReviewed(ctx context.Context, id string) error {
+	query := `UPDATE moderation_flags SET reviewed_at = $2 WHERE id = $1`
+	result, err := db.pool.Exec(ctx, query, id, time.Now().Unix())
+	if err != nil {
+		return fmt.Errorf("mark moderation flag reviewed: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrModerationFlagNotFound
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+//go:build integration
+
+package db_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kbrakke/illustrated-primer/internal/db"
+	"github.com/kbrakke/illustrated-primer/internal/models"
+	"github.com/kbrakke/illustrated-primer/testutil"
+)
+
+func TestModerationFlags(t *testing.T) {
+	testDB := testutil.NewTestDatabase(t)
+	ctx := context.Background()
+
+	user := models.NewUser("Flag Test User", "flag-test@example.com")
+	if err := testDB.Database.CreateUser(ctx, user); err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+
+	story := models.NewStory(user.ID, "Flag Test Story", "")
+	if err := testDB.Database.CreateStory(ctx, story); err != nil {
+		t.Fatalf("failed to create story: %v", err)
+	}
+
+	flag := models.NewModerationFlag(user.ID, "prompt", "rewrite", []string{"personal-information"},
+		"my email is kid@example.com", "my email is [private]")
+	flag.StoryID = &story.ID
+	if err := testDB.Database.CreateModerationFlag(ctx, flag); err != nil {
+		t.Fatalf("CreateModerationFlag failed: %v", err)
+	}
+
+	t.Run("ListModerationFlagsByUser", func(t *testing.T) {
+		flags, err := testDB.Database.ListModerationFlagsByUser(ctx, user.ID, false)
+		if err != nil {
+			t.Fatalf("ListModerationFlagsByUser failed: %v", err)
+		}
+		if len(flags) != 1 {
+			t.Fatalf("got %d flags, want 1", len(flags))
+		}
+		got := flags[0]
+		if got.Action != "rewrite" || got.FinalText != "my email is [private]" {
+			t.Errorf("unexpected flag %+v", got)
+		}
+		if len(got.Categories) != 1 || got.Categories[0] != "personal-information" {
+			t.Errorf("Categories = %v", got.Categories)
+		}
+	})
+
+	t.Run("MarkModerationFlagReviewed", func(t *testing.T) {
+		if err := testDB.Database.MarkModerationFlagReviewed(ctx, flag.ID); err != nil {
+			t.Fatalf("MarkModerationFlagReviewed failed: %v", err)
+		}
+
+		unreviewed, err := testDB.Database.ListModerationFlagsByUser(ctx, user.ID, false)
+		if err != nil {
+			t.Fatalf("ListModerationFlagsByUser failed: %v", err)
+		}
+		if len(unreviewed) != 0 {
+			t.Errorf("reviewed flag still listed: %+v", unreviewed)
+		}
+
+		all, err := testDB.Database.ListModerationFlagsByUser(ctx, user.ID, true)
+		if err != nil {
+			t.Fatalf("ListModerationFlagsByUser failed: %v", err)
+		}
+		if len(all) != 1 || all[0].ReviewedAt == nil {
+			t.Errorf("expected the reviewed flag, got %+v", all)
+		}
+	})
+
+	t.Run("MarkModerationFlagReviewed_NotFound", func(t *testing.T) {
+		err := testDB.Database.MarkModerationFlagReviewed(ctx, "nonexistent-id")
+		if err != db.ErrModerationFlagNotFound {
+			t.Errorf("expected ErrModerationFlagNotFound, got %v", err)
+		}
+	})
+
+	t.Run("story deletion keeps the flag", func(t *testing.T) {
+		if err := testDB.Database.DeleteStory(ctx, story.ID); err != nil {
+			t.Fatalf("DeleteStory failed: %v", err)
+		}
+		all, err := testDB.Database.ListModerationFlagsByUser(ctx, user.ID, true)
+		if err != nil {
+			t.Fatalf("ListModerationFlagsByUser failed: %v", err)
+		}
+		if len(all) != 1 || all[0].StoryID != nil {
+			t.Errorf("expected the flag to outlive its story, got %+v", all)
+		}
+	})
+}
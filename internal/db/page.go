@@ -16,9 +16,11 @@ var ErrPageNotFound = errors.New("page not found")
 // CreatePage inserts a new page into the database.
 func (db *Database) CreatePage(ctx context.Context, page *models.Page) error {
 	query := `
-		INSERT INTO pages (id, story_id, page_num, prompt, completion, summary, image_path, audio_path, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		INSERT INTO pages (id, story_id, page_num, prompt, completion, summary, image_path, audio_path, prompt_template, choices,
+			grade_level, reading_ease, words_per_sentence, rare_word_ratio, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
 	`
+	scores := readabilityColumnsOf(page)
 	_, err := db.pool.Exec(ctx, query,
 		page.ID,
 		page.StoryID,
@@ -28,6 +30,12 @@ func (db *Database) CreatePage(ctx context.Context, page *models.Page) error {
 		page.Summary,
 		page.ImagePath,
 		page.AudioPath,
+		page.PromptTemplate,
+		choices(page),
+		scores.gradeLevel,
+		scores.readingEase,
+		scores.wordsPerSentence,
+		scores.rareWordRatio,
 		page.CreatedAt,
 		page.UpdatedAt,
 	)
@@ -37,14 +45,55 @@ func (db *Database) CreatePage(ctx context.Context, page *models.Page) error {
 	return nil
 }
 
+// choices returns the page's choices for the NOT NULL choices column.
+func choices(page *models.Page) []string {
+	if page.Choices == nil {
+		return []string{}
+	}
+	return page.Choices
+}
+
+// readabilityColumns holds a page's nullable readability scores as stored.
+type readabilityColumns struct {
+	gradeLevel       *float64
+	readingEase      *float64
+	wordsPerSentence *float64
+	rareWordRatio    *float64
+}
+
+// readabilityColumnsOf returns the columns for the page's scores, all NULL
+// if it has none.
+func readabilityColumnsOf(page *models.Page) readabilityColumns {
+	r := page.Readability
+	if r == nil {
+		return readabilityColumns{}
+	}
+	return readabilityColumns{&r.GradeLevel, &r.ReadingEase, &r.WordsPerSentence, &r.RareWordRatio}
+}
+
+// readability returns the scores, or nil if the page was never scored.
+func (c readabilityColumns) readability() *models.Readability {
+	if c.gradeLevel == nil || c.readingEase == nil || c.wordsPerSentence == nil || c.rareWordRatio == nil {
+		return nil
+	}
+	return &models.Readability{
+		GradeLevel:       *c.gradeLevel,
+		ReadingEase:      *c.readingEase,
+		WordsPerSentence: *c.wordsPerSentence,
+		RareWordRatio:    *c.rareWordRatio,
+	}
+}
+
 // GetPageByID retrieves a page by its ID.
 func (db *Database) GetPageByID(ctx context.Context, id string) (*models.Page, error) {
 	query := `
-		SELECT id, story_id, page_num, prompt, completion, summary, image_path, audio_path, created_at, updated_at
+		SELECT id, story_id, page_num, prompt, completion, summary, image_path, audio_path, prompt_template, choices,
+			grade_level, reading_ease, words_per_sentence, rare_word_ratio, created_at, updated_at
 		FROM pages
 		WHERE id = $1
 	`
 	var page models.Page
+	var scores readabilityColumns
 	err := db.pool.QueryRow(ctx, query, id).Scan(
 		&page.ID,
 		&page.StoryID,
@@ -54,6 +103,12 @@ func (db *Database) GetPageByID(ctx context.Context, id string) (*models.Page, e
 		&page.Summary,
 		&page.ImagePath,
 		&page.AudioPath,
+		&page.PromptTemplate,
+		&page.Choices,
+		&scores.gradeLevel,
+		&scores.readingEase,
+		&scores.wordsPerSentence,
+		&scores.rareWordRatio,
 		&page.CreatedAt,
 		&page.UpdatedAt,
 	)
@@ -63,17 +118,20 @@ func (db *Database) GetPageByID(ctx context.Context, id string) (*models.Page, e
 		}
 		return nil, fmt.Errorf("query page by id: %w", err)
 	}
+	page.Readability = scores.readability()
 	return &page, nil
 }
 
 // GetPageByStoryAndNum retrieves a page by story ID and page number.
 func (db *Database) GetPageByStoryAndNum(ctx context.Context, storyID string, pageNum int64) (*models.Page, error) {
 	query := `
-		SELECT id, story_id, page_num, prompt, completion, summary, image_path, audio_path, created_at, updated_at
+		SELECT id, story_id, page_num, prompt, completion, summary, image_path, audio_path, prompt_template, choices,
+			grade_level, reading_ease, words_per_sentence, rare_word_ratio, created_at, updated_at
 		FROM pages
 		WHERE story_id = $1 AND page_num = $2
 	`
 	var page models.Page
+	var scores readabilityColumns
 	err := db.pool.QueryRow(ctx, query, storyID, pageNum).Scan(
 		&page.ID,
 		&page.StoryID,
@@ -83,6 +141,12 @@ func (db *Database) GetPageByStoryAndNum(ctx context.Context, storyID string, pa
 		&page.Summary,
 		&page.ImagePath,
 		&page.AudioPath,
+		&page.PromptTemplate,
+		&page.Choices,
+		&scores.gradeLevel,
+		&scores.readingEase,
+		&scores.wordsPerSentence,
+		&scores.rareWordRatio,
 		&page.CreatedAt,
 		&page.UpdatedAt,
 	)
@@ -92,13 +156,15 @@ func (db *Database) GetPageByStoryAndNum(ctx context.Context, storyID string, pa
 		}
 		return nil, fmt.Errorf("query page by story and num: %w", err)
 	}
+	page.Readability = scores.readability()
 	return &page, nil
 }
 
 // ListPagesByStory retrieves all pages for a story ordered by page number.
 func (db *Database) ListPagesByStory(ctx context.Context, storyID string) ([]models.Page, error) {
 	query := `
-		SELECT id, story_id, page_num, prompt, completion, summary, image_path, audio_path, created_at, updated_at
+		SELECT id, story_id, page_num, prompt, completion, summary, image_path, audio_path, prompt_template, choices,
+			grade_level, reading_ease, words_per_sentence, rare_word_ratio, created_at, updated_at
 		FROM pages
 		WHERE story_id = $1
 		ORDER BY page_num ASC
@@ -112,6 +178,7 @@ func (db *Database) ListPagesByStory(ctx context.Context, storyID string) ([]mod
 	var pages []models.Page
 	for rows.Next() {
 		var page models.Page
+		var scores readabilityColumns
 		if err := rows.Scan(
 			&page.ID,
 			&page.StoryID,
@@ -121,11 +188,18 @@ func (db *Database) ListPagesByStory(ctx context.Context, storyID string) ([]mod
 			&page.Summary,
 			&page.ImagePath,
 			&page.AudioPath,
+			&page.PromptTemplate,
+			&page.Choices,
+			&scores.gradeLevel,
+			&scores.readingEase,
+			&scores.wordsPerSentence,
+			&scores.rareWordRatio,
 			&page.CreatedAt,
 			&page.UpdatedAt,
 		); err != nil {
 			return nil, fmt.Errorf("scan page: %w", err)
 		}
+		page.Readability = scores.readability()
 		pages = append(pages, page)
 	}
 
@@ -173,6 +247,34 @@ func (db *Database) UpdatePage(ctx context.Context, page *models.Page) error {
 	return nil
 }
 
+// SetPageImage records the illustration file for a page. It only touches
+// image_path so it can run concurrently with other edits to the page.
+func (db *Database) SetPageImage(ctx context.Context, id, imagePath string) error {
+	query := `UPDATE pages SET image_path = $2, updated_at = $3 WHERE id = $1`
+	result, err := db.pool.Exec(ctx, query, id, imagePath, time.Now().Unix())
+	if err != nil {
+		return fmt.Errorf("set page image: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrPageNotFound
+	}
+	return nil
+}
+
+// SetPageAudio records the narration file for a page. It only touches
+// audio_path so it can run concurrently with other edits to the page.
+func (db *Database) SetPageAudio(ctx context.Context, id, audioPath string) error {
+	query := `UPDATE pages SET audio_path = $2, updated_at = $3 WHERE id = $1`
+	result, err := db.pool.Exec(ctx, query, id, audioPath, time.Now().Unix())
+	if err != nil {
+		return fmt.Errorf("set page audio: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrPageNotFound
+	}
+	return nil
+}
+
 // DeletePage deletes a page by its ID.
 func (db *Database) DeletePage(ctx context.Context, id string) error {
 	query := `DELETE FROM pages WHERE id = $1`
@@ -4,6 +4,7 @@ package db_test
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/kbrakke/illustrated-primer/internal/db"
@@ -36,6 +37,9 @@ func TestPageCRUD(t *testing.T) {
 
 	t.Run("GetPageByID", func(t *testing.T) {
 		page := models.NewPage(story.ID, 2, "Get page prompt", "Get page completion")
+		page.PromptTemplate = "default@1"
+		page.Choices = []string{"Open the door", "Knock first"}
+		page.Readability = &models.Readability{GradeLevel: 2.5, ReadingEase: 90, WordsPerSentence: 7, RareWordRatio: 0.1}
 		if err := testDB.Database.CreatePage(ctx, page); err != nil {
 			t.Fatalf("setup failed: %v", err)
 		}
@@ -51,6 +55,15 @@ func TestPageCRUD(t *testing.T) {
 		if retrieved.Prompt != page.Prompt {
 			t.Errorf("Prompt = %q, want %q", retrieved.Prompt, page.Prompt)
 		}
+		if retrieved.PromptTemplate != "default@1" {
+			t.Errorf("PromptTemplate = %q, want %q", retrieved.PromptTemplate, "default@1")
+		}
+		if strings.Join(retrieved.Choices, "|") != "Open the door|Knock first" {
+			t.Errorf("Choices = %v, want the saved choices", retrieved.Choices)
+		}
+		if retrieved.Readability == nil || *retrieved.Readability != *page.Readability {
+			t.Errorf("Readability = %+v, want %+v", retrieved.Readability, page.Readability)
+		}
 	})
 
 	t.Run("GetPageByID_NotFound", func(t *testing.T) {
@@ -76,6 +89,9 @@ func TestPageCRUD(t *testing.T) {
 		if err != nil {
 			t.Fatalf("GetPageByStoryAndNum failed: %v", err)
 		}
+		if retrieved.Readability != nil {
+			t.Errorf("Readability = %+v, want nil for an unscored page", retrieved.Readability)
+		}
 
 		if retrieved.ID != page.ID {
 			t.Errorf("ID = %q, want %q", retrieved.ID, page.ID)
@@ -173,6 +189,58 @@ func TestPageCRUD(t *testing.T) {
 		}
 	})
 
+	t.Run("SetPageImage", func(t *testing.T) {
+		newStory := models.NewStory(user.ID, "Illustrated Story", "")
+		if err := testDB.Database.CreateStory(ctx, newStory); err != nil {
+			t.Fatalf("setup failed: %v", err)
+		}
+
+		page := models.NewPage(newStory.ID, 1, "Draw a dragon", "A dragon appeared.")
+		if err := testDB.Database.CreatePage(ctx, page); err != nil {
+			t.Fatalf("setup failed: %v", err)
+		}
+
+		if err := testDB.Database.SetPageImage(ctx, page.ID, "assets/dragon.png"); err != nil {
+			t.Fatalf("SetPageImage failed: %v", err)
+		}
+
+		retrieved, err := testDB.Database.GetPageByID(ctx, page.ID)
+		if err != nil {
+			t.Fatalf("GetPageByID failed: %v", err)
+		}
+		if retrieved.ImagePath == nil || *retrieved.ImagePath != "assets/dragon.png" {
+			t.Errorf("ImagePath = %v, want assets/dragon.png", retrieved.ImagePath)
+		}
+
+		if err := testDB.Database.SetPageImage(ctx, "nonexistent-id", "x.png"); err != db.ErrPageNotFound {
+			t.Errorf("expected ErrPageNotFound, got %v", err)
+		}
+	})
+
+	t.Run("SetPageAudio", func(t *testing.T) {
+		newStory := models.NewStory(user.ID, "Narrated Story", "")
+		if err := testDB.Database.CreateStory(ctx, newStory); err != nil {
+			t.Fatalf("setup failed: %v", err)
+		}
+
+		page := models.NewPage(newStory.ID, 1, "Read to me", "Once upon a time.")
+		if err := testDB.Database.CreatePage(ctx, page); err != nil {
+			t.Fatalf("setup failed: %v", err)
+		}
+
+		if err := testDB.Database.SetPageAudio(ctx, page.ID, "assets/narration/abc.mp3"); err != nil {
+			t.Fatalf("SetPageAudio failed: %v", err)
+		}
+
+		retrieved, err := testDB.Database.GetPageByID(ctx, page.ID)
+		if err != nil {
+			t.Fatalf("GetPageByID failed: %v", err)
+		}
+		if retrieved.AudioPath == nil || *retrieved.AudioPath != "assets/narration/abc.mp3" {
+			t.Errorf("AudioPath = %v, want assets/narration/abc.mp3", retrieved.AudioPath)
+		}
+	})
+
 	t.Run("DeletePage", func(t *testing.T) {
 		newStory := models.NewStory(user.ID, "Delete Page Story", "")
 		if err := testDB.Database.CreateStory(ctx, newStory); err != nil {
@@ -0,0 +1,136 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/kbrakke/illustrated-primer/internal/models"
+)
+
+// CreateQuizAttempt records a finished quiz attempt and its answers in a
+// single transaction.
+func (db *Database) CreateQuizAttempt(ctx context.Context, attempt *models.QuizAttempt) error {
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO quiz_attempts (id, user_id, story_id, score, total, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	_, err = tx.Exec(ctx, query,
+		attempt.ID,
+		attempt.UserID,
+		attempt.StoryID,
+		attempt.Score,
+		attempt.Total,
+		attempt.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("insert quiz attempt: %w", err)
+	}
+
+	insert := `
+		INSERT INTO quiz_answers (attempt_id, position, question, kind, options, answer, chosen)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	batch := &pgx.Batch{}
+	for i, answer := range attempt.Answers {
+		batch.Queue(insert, attempt.ID, i, answer.Question, answer.Kind, answerOptions(answer), answer.Answer, answer.Chosen)
+	}
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("insert quiz answers: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
+// ListQuizAttemptsByUser retrieves a user's quiz attempts with their answers,
+// newest first.
+func (db *Database) ListQuizAttemptsByUser(ctx context.Context, userID string) ([]models.QuizAttempt, error) {
+	query := `
+		SELECT id, user_id, story_id, score, total, created_at
+		FROM quiz_attempts
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+	`
+	rows, err := db.pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("query quiz attempts: %w", err)
+	}
+	defer rows.Close()
+
+	var attempts []models.QuizAttempt
+	index := make(map[string]int)
+	for rows.Next() {
+		var attempt models.QuizAttempt
+		if err := rows.Scan(
+			&attempt.ID,
+			&attempt.UserID,
+			&attempt.StoryID,
+			&attempt.Score,
+			&attempt.Total,
+			&attempt.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan quiz attempt: %w", err)
+		}
+		index[attempt.ID] = len(attempts)
+		attempts = append(attempts, attempt)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate quiz attempts: %w", err)
+	}
+	if len(attempts) == 0 {
+		return attempts, nil
+	}
+
+	query = `
+		SELECT a.attempt_id, a.question, a.kind, a.options, a.answer, a.chosen
+		FROM quiz_answers a
+		JOIN quiz_attempts q ON q.id = a.attempt_id
+		WHERE q.user_id = $1
+		ORDER BY a.attempt_id, a.position
+	`
+	rows, err = db.pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("query quiz answers: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var attemptID string
+		var answer models.QuizAnswer
+		if err := rows.Scan(
+			&attemptID,
+			&answer.Question,
+			&answer.Kind,
+			&answer.Options,
+			&answer.Answer,
+			&answer.Chosen,
+		); err != nil {
+			return nil, fmt.Errorf("scan quiz answer: %w", err)
+		}
+		if i, ok := index[attemptID]; ok {
+			attempts[i].Answers = append(attempts[i].Answers, answer)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate quiz answers: %w", err)
+	}
+
+	return attempts, nil
+}
+
+// answerOptions returns an answer's options for the NOT NULL options column.
+func answerOptions(answer models.QuizAnswer) []string {
+	if answer.Options == nil {
+		return []string{}
+	}
+	return answer.Options
+}
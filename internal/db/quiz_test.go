@@ -0,0 +1,70 @@
+//go:build integration
+
+package db_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kbrakke/illustrated-primer/internal/models"
+	"github.com/kbrakke/illustrated-primer/testutil"
+)
+
+func TestQuizAttempts(t *testing.T) {
+	testDB := testutil.NewTestDatabase(t)
+	ctx := context.Background()
+
+	user := models.NewUser("Quiz Test User", "quiz-test@example.com")
+	if err := testDB.Database.CreateUser(ctx, user); err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+
+	story := models.NewStory(user.ID, "Quiz Test Story", "")
+	if err := testDB.Database.CreateStory(ctx, story); err != nil {
+		t.Fatalf("failed to create story: %v", err)
+	}
+
+	first := models.NewQuizAttempt(user.ID, story.ID)
+	first.CreatedAt -= 60
+	first.AddAnswer(models.QuizAnswer{Question: "Who found the door?", Kind: "text", Options: []string{"The fox", "The owl"}, Answer: 0, Chosen: 1})
+	second := models.NewQuizAttempt(user.ID, story.ID)
+	second.AddAnswer(models.QuizAnswer{Question: "Which animal?", Kind: "picture", Options: []string{"🦊", "🦉", "🐻"}, Answer: 0, Chosen: 0})
+	second.AddAnswer(models.QuizAnswer{Question: "Where did it go?", Kind: "text", Options: []string{"Home", "The sea"}, Answer: 0, Chosen: 0})
+	for _, attempt := range []*models.QuizAttempt{first, second} {
+		if err := testDB.Database.CreateQuizAttempt(ctx, attempt); err != nil {
+			t.Fatalf("CreateQuizAttempt failed: %v", err)
+		}
+	}
+
+	t.Run("ListQuizAttemptsByUser", func(t *testing.T) {
+		attempts, err := testDB.Database.ListQuizAttemptsByUser(ctx, user.ID)
+		if err != nil {
+			t.Fatalf("ListQuizAttemptsByUser failed: %v", err)
+		}
+		if len(attempts) != 2 || attempts[0].ID != second.ID {
+			t.Fatalf("attempts = %+v, want newest first", attempts)
+		}
+		if attempts[0].Score != 2 || attempts[0].Total != 2 || len(attempts[0].Answers) != 2 {
+			t.Errorf("second attempt = %+v", attempts[0])
+		}
+		if answer := attempts[0].Answers[0]; answer.Kind != "picture" || answer.Options[2] != "🐻" {
+			t.Errorf("answers out of order or incomplete: %+v", attempts[0].Answers)
+		}
+		if answer := attempts[1].Answers[0]; answer.Correct() {
+			t.Errorf("first attempt answer should be wrong: %+v", answer)
+		}
+	})
+
+	t.Run("AttemptsOutliveStory", func(t *testing.T) {
+		if err := testDB.Database.DeleteStory(ctx, story.ID); err != nil {
+			t.Fatalf("DeleteStory failed: %v", err)
+		}
+		attempts, err := testDB.Database.ListQuizAttemptsByUser(ctx, user.ID)
+		if err != nil {
+			t.Fatalf("ListQuizAttemptsByUser failed: %v", err)
+		}
+		if len(attempts) != 2 || attempts[0].StoryID != nil {
+			t.Errorf("attempts after deleting the story = %+v", attempts)
+		}
+	})
+}
@@ -0,0 +1,91 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kbrakke/illustrated-primer/internal/models"
+)
+
+// ErrScreenSessionNotFound is returned when a screen session doesn't exist
+// or has already ended.
+var ErrScreenSessionNotFound = errors.New("screen session not found")
+
+// StartScreenSession records the start of a child's session.
+func (db *Database) StartScreenSession(ctx context.Context, session *models.ScreenSession) error {
+	query := `
+		INSERT INTO screen_sessions (id, user_id, override, end_reason, started_at, last_seen_at, ended_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	_, err := db.pool.Exec(ctx, query,
+		session.ID,
+		session.UserID,
+		session.Override,
+		session.EndReason,
+		session.StartedAt,
+		session.LastSeenAt,
+		session.EndedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("insert screen session: %w", err)
+	}
+	return nil
+}
+
+// TouchScreenSession notes that an open session is still going, so a
+// session that is never ended still counts up to its last sign of life.
+func (db *Database) TouchScreenSession(ctx context.Context, id string, at time.Time) error {
+	query := `UPDATE screen_sessions SET last_seen_at = $2 WHERE id = $1 AND ended_at IS NULL`
+	result, err := db.pool.Exec(ctx, query, id, at.Unix())
+	if err != nil {
+		return fmt.Errorf("touch screen session: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrScreenSessionNotFound
+	}
+	return nil
+}
+
+// EndScreenSession records why and when an open session ended.
+func (db *Database) EndScreenSession(ctx context.Context, id, reason string, at time.Time) error {
+	query := `
+		UPDATE screen_sessions
+		SET end_reason = $2, last_seen_at = $3, ended_at = $3
+		WHERE id = $1 AND ended_at IS NULL
+	`
+	result, err := db.pool.Exec(ctx, query, id, reason, at.Unix())
+	if err != nil {
+		return fmt.Errorf("end screen session: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrScreenSessionNotFound
+	}
+	return nil
+}
+
+// ScreenTimeUsed adds up a child's sessions since a point in time, usually
+// the start of the day. Only the part of a session after since counts, and
+// time granted with the parent PIN doesn't count at all.
+func (db *Database) ScreenTimeUsed(ctx context.Context, userID string, since time.Time) (time.Duration, error) {
+	seconds, err := db.sessionSeconds(ctx, userID, since.Unix(), false)
+	if err != nil {
+		return 0, fmt.Errorf("query screen time used: %w", err)
+	}
+	return time.Duration(seconds) * time.Second, nil
+}
+
+// sessionSeconds adds up the seconds a child spent in screen sessions after
+// since, a Unix time. Only the part of a session after since counts.
+// Sessions granted with the parent PIN count only with includeOverrides.
+func (db *Database) sessionSeconds(ctx context.Context, userID string, since int64, includeOverrides bool) (int64, error) {
+	query := `
+		SELECT COALESCE(SUM(GREATEST(0, COALESCE(ended_at, last_seen_at) - GREATEST(started_at, $2))), 0)
+		FROM screen_sessions
+		WHERE user_id = $1 AND ($3 OR NOT override) AND COALESCE(ended_at, last_seen_at) > $2
+	`
+	var seconds int64
+	err := db.pool.QueryRow(ctx, query, userID, since, includeOverrides).Scan(&seconds)
+	return seconds, err
+}
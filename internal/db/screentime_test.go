@@ -0,0 +1,83 @@
+//go:build integration
+
+package db_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kbrakke/illustrated-primer/internal/db"
+	"github.com/kbrakke/illustrated-primer/internal/models"
+	"github.com/kbrakke/illustrated-primer/testutil"
+)
+
+func TestScreenSessions(t *testing.T) {
+	testDB := testutil.NewTestDatabase(t)
+	ctx := context.Background()
+	now := time.Now()
+	today := models.StartOfDay(now)
+
+	user := models.NewUser("Screen Session User", "screen-session@example.com")
+	if err := testDB.Database.CreateUser(ctx, user); err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+
+	start := func(startedAt time.Time, override bool) *models.ScreenSession {
+		t.Helper()
+		session := models.NewScreenSession(user.ID, override)
+		session.StartedAt, session.LastSeenAt = startedAt.Unix(), startedAt.Unix()
+		if err := testDB.Database.StartScreenSession(ctx, session); err != nil {
+			t.Fatalf("StartScreenSession failed: %v", err)
+		}
+		return session
+	}
+
+	t.Run("EndScreenSession", func(t *testing.T) {
+		session := start(now.Add(-10*time.Minute), false)
+		if err := testDB.Database.EndScreenSession(ctx, session.ID, models.SessionEndedLeft, now); err != nil {
+			t.Fatalf("EndScreenSession failed: %v", err)
+		}
+		err := testDB.Database.EndScreenSession(ctx, session.ID, models.SessionEndedLimit, now)
+		if !errors.Is(err, db.ErrScreenSessionNotFound) {
+			t.Errorf("ending a session twice: got %v, want ErrScreenSessionNotFound", err)
+		}
+		if err := testDB.Database.TouchScreenSession(ctx, session.ID, now); !errors.Is(err, db.ErrScreenSessionNotFound) {
+			t.Errorf("touching an ended session: got %v, want ErrScreenSessionNotFound", err)
+		}
+	})
+
+	t.Run("ScreenTimeUsed", func(t *testing.T) {
+		// Ten minutes from the session above, and five more from one that
+		// was never ended but was last seen five minutes in.
+		open := start(now.Add(-30*time.Minute), false)
+		if err := testDB.Database.TouchScreenSession(ctx, open.ID, now.Add(-25*time.Minute)); err != nil {
+			t.Fatalf("TouchScreenSession failed: %v", err)
+		}
+		// Parent overrides don't count.
+		override := start(now.Add(-20*time.Minute), true)
+		if err := testDB.Database.EndScreenSession(ctx, override.ID, models.SessionEndedLimit, now); err != nil {
+			t.Fatalf("EndScreenSession failed: %v", err)
+		}
+
+		used, err := testDB.Database.ScreenTimeUsed(ctx, user.ID, today)
+		if err != nil {
+			t.Fatalf("ScreenTimeUsed failed: %v", err)
+		}
+		if now.Add(-30 * time.Minute).Before(today) {
+			t.Skip("too close to midnight to count today's sessions")
+		}
+		if used != 15*time.Minute {
+			t.Errorf("used = %v, want 15m", used)
+		}
+
+		used, err = testDB.Database.ScreenTimeUsed(ctx, user.ID, now.Add(-5*time.Minute))
+		if err != nil {
+			t.Fatalf("ScreenTimeUsed failed: %v", err)
+		}
+		if used != 5*time.Minute {
+			t.Errorf("used in the last five minutes = %v, want 5m", used)
+		}
+	})
+}
@@ -16,14 +16,16 @@ var ErrStoryNotFound = errors.New("story not found")
 // CreateStory inserts a new story into the database.
 func (db *Database) CreateStory(ctx context.Context, story *models.Story) error {
 	query := `
-		INSERT INTO stories (id, user_id, title, summary, current_page, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO stories (id, user_id, title, summary, art_style, prompt_template, current_page, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 	`
 	_, err := db.pool.Exec(ctx, query,
 		story.ID,
 		story.UserID,
 		story.Title,
 		story.Summary,
+		story.ArtStyle,
+		story.PromptTemplate,
 		story.CurrentPage,
 		story.CreatedAt,
 		story.UpdatedAt,
@@ -37,7 +39,7 @@ func (db *Database) CreateStory(ctx context.Context, story *models.Story) error
 // GetStoryByID retrieves a story by its ID.
 func (db *Database) GetStoryByID(ctx context.Context, id string) (*models.Story, error) {
 	query := `
-		SELECT id, user_id, title, summary, current_page, created_at, updated_at
+		SELECT id, user_id, title, summary, art_style, prompt_template, current_page, created_at, updated_at
 		FROM stories
 		WHERE id = $1
 	`
@@ -47,6 +49,8 @@ func (db *Database) GetStoryByID(ctx context.Context, id string) (*models.Story,
 		&story.UserID,
 		&story.Title,
 		&story.Summary,
+		&story.ArtStyle,
+		&story.PromptTemplate,
 		&story.CurrentPage,
 		&story.CreatedAt,
 		&story.UpdatedAt,
@@ -63,7 +67,7 @@ func (db *Database) GetStoryByID(ctx context.Context, id string) (*models.Story,
 // ListStoriesByUser retrieves all stories for a user ordered by creation date.
 func (db *Database) ListStoriesByUser(ctx context.Context, userID string) ([]models.Story, error) {
 	query := `
-		SELECT id, user_id, title, summary, current_page, created_at, updated_at
+		SELECT id, user_id, title, summary, art_style, prompt_template, current_page, created_at, updated_at
 		FROM stories
 		WHERE user_id = $1
 		ORDER BY created_at DESC
@@ -82,6 +86,8 @@ func (db *Database) ListStoriesByUser(ctx context.Context, userID string) ([]mod
 			&story.UserID,
 			&story.Title,
 			&story.Summary,
+			&story.ArtStyle,
+			&story.PromptTemplate,
 			&story.CurrentPage,
 			&story.CreatedAt,
 			&story.UpdatedAt,
@@ -103,13 +109,15 @@ func (db *Database) UpdateStory(ctx context.Context, story *models.Story) error
 	story.UpdatedAt = time.Now().Unix()
 	query := `
 		UPDATE stories
-		SET title = $2, summary = $3, current_page = $4, updated_at = $5
+		SET title = $2, summary = $3, art_style = $4, prompt_template = $5, current_page = $6, updated_at = $7
 		WHERE id = $1
 	`
 	result, err := db.pool.Exec(ctx, query,
 		story.ID,
 		story.Title,
 		story.Summary,
+		story.ArtStyle,
+		story.PromptTemplate,
 		story.CurrentPage,
 		story.UpdatedAt,
 	)
@@ -152,6 +160,107 @@ func (db *Database) DeleteStory(ctx context.Context, id string) error {
 	return nil
 }
 
+// DuplicateStory copies a story and all of its pages under a new title.
+// The copy is written in a single transaction so a partial duplicate is never visible.
+func (db *Database) DuplicateStory(ctx context.Context, id, title string) (*models.Story, error) {
+	source, err := db.GetStoryByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	pages, err := db.ListPagesByStory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	story := models.NewStory(source.UserID, title, source.Summary)
+	story.ArtStyle = source.ArtStyle
+	story.PromptTemplate = source.PromptTemplate
+	story.CurrentPage = source.CurrentPage
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO stories (id, user_id, title, summary, art_style, prompt_template, current_page, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`,
+		story.ID,
+		story.UserID,
+		story.Title,
+		story.Summary,
+		story.ArtStyle,
+		story.PromptTemplate,
+		story.CurrentPage,
+		story.CreatedAt,
+		story.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("insert story copy: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO story_objectives (story_id, objective_id)
+		SELECT $1, objective_id FROM story_objectives WHERE story_id = $2
+	`, story.ID, source.ID)
+	if err != nil {
+		return nil, fmt.Errorf("copy story objectives: %w", err)
+	}
+
+	for _, page := range pages {
+		copied := models.NewPageWithSummary(story.ID, page.PageNum, page.Prompt, page.Completion, page.Summary)
+		copied.ImagePath = page.ImagePath
+		copied.AudioPath = page.AudioPath
+		copied.PromptTemplate = page.PromptTemplate
+		copied.Choices = page.Choices
+		copied.Readability = page.Readability
+
+		scores := readabilityColumnsOf(copied)
+		_, err = tx.Exec(ctx, `
+			INSERT INTO pages (id, story_id, page_num, prompt, completion, summary, image_path, audio_path, prompt_template, choices,
+				grade_level, reading_ease, words_per_sentence, rare_word_ratio, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+		`,
+			copied.ID,
+			copied.StoryID,
+			copied.PageNum,
+			copied.Prompt,
+			copied.Completion,
+			copied.Summary,
+			copied.ImagePath,
+			copied.AudioPath,
+			copied.PromptTemplate,
+			choices(copied),
+			scores.gradeLevel,
+			scores.readingEase,
+			scores.wordsPerSentence,
+			scores.rareWordRatio,
+			copied.CreatedAt,
+			copied.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("insert page copy: %w", err)
+		}
+
+		_, err = tx.Exec(ctx, `
+			INSERT INTO page_objectives (page_id, objective_id)
+			SELECT $1, objective_id FROM page_objectives WHERE page_id = $2
+		`, copied.ID, page.ID)
+		if err != nil {
+			return nil, fmt.Errorf("copy page objectives: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return story, nil
+}
+
 // GetStoryPageCount returns the number of pages in a story.
 func (db *Database) GetStoryPageCount(ctx context.Context, storyID string) (int64, error) {
 	query := `SELECT COUNT(*) FROM pages WHERE story_id = $1`
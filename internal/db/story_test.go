@@ -112,6 +112,8 @@ func TestStoryCRUD(t *testing.T) {
 
 		story.Title = "Updated Title"
 		story.Summary = "New summary"
+		story.ArtStyle = "pencil sketch"
+		story.PromptTemplate = "playful@2"
 		if err := testDB.Database.UpdateStory(ctx, story); err != nil {
 			t.Fatalf("UpdateStory failed: %v", err)
 		}
@@ -127,6 +129,12 @@ func TestStoryCRUD(t *testing.T) {
 		if retrieved.Summary != "New summary" {
 			t.Errorf("Summary = %q, want %q", retrieved.Summary, "New summary")
 		}
+		if retrieved.ArtStyle != "pencil sketch" {
+			t.Errorf("ArtStyle = %q, want %q", retrieved.ArtStyle, "pencil sketch")
+		}
+		if retrieved.PromptTemplate != "playful@2" {
+			t.Errorf("PromptTemplate = %q, want %q", retrieved.PromptTemplate, "playful@2")
+		}
 	})
 
 	t.Run("DeleteStory", func(t *testing.T) {
@@ -144,4 +152,47 @@ func TestStoryCRUD(t *testing.T) {
 			t.Errorf("expected ErrStoryNotFound after delete, got %v", err)
 		}
 	})
+
+	t.Run("DuplicateStory", func(t *testing.T) {
+		story := models.NewStory(user.ID, "Original", "Original summary")
+		if err := testDB.Database.CreateStory(ctx, story); err != nil {
+			t.Fatalf("setup failed: %v", err)
+		}
+		for i := int64(1); i <= 2; i++ {
+			page := models.NewPage(story.ID, i, "prompt", "completion")
+			if err := testDB.Database.CreatePage(ctx, page); err != nil {
+				t.Fatalf("setup failed: %v", err)
+			}
+		}
+
+		copied, err := testDB.Database.DuplicateStory(ctx, story.ID, "Original (copy)")
+		if err != nil {
+			t.Fatalf("DuplicateStory failed: %v", err)
+		}
+
+		if copied.ID == story.ID {
+			t.Error("expected duplicate to have a new ID")
+		}
+		if copied.Title != "Original (copy)" {
+			t.Errorf("Title = %q, want %q", copied.Title, "Original (copy)")
+		}
+		if copied.Summary != story.Summary {
+			t.Errorf("Summary = %q, want %q", copied.Summary, story.Summary)
+		}
+
+		pages, err := testDB.Database.ListPagesByStory(ctx, copied.ID)
+		if err != nil {
+			t.Fatalf("ListPagesByStory failed: %v", err)
+		}
+		if len(pages) != 2 {
+			t.Errorf("got %d pages, want 2", len(pages))
+		}
+	})
+
+	t.Run("DuplicateStory_NotFound", func(t *testing.T) {
+		_, err := testDB.Database.DuplicateStory(ctx, "nonexistent-id", "Copy")
+		if err != db.ErrStoryNotFound {
+			t.Errorf("expected ErrStoryNotFound, got %v", err)
+		}
+	})
 }
@@ -0,0 +1,117 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kbrakke/illustrated-primer/internal/models"
+)
+
+// RecordTokenUsage stores the tokens spent on a model request.
+func (db *Database) RecordTokenUsage(ctx context.Context, usage *models.TokenUsage) error {
+	query := `
+		INSERT INTO token_usage (id, user_id, story_id, kind, input_tokens, output_tokens, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	_, err := db.pool.Exec(ctx, query,
+		usage.ID,
+		usage.UserID,
+		usage.StoryID,
+		usage.Kind,
+		usage.InputTokens,
+		usage.OutputTokens,
+		usage.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("insert token usage: %w", err)
+	}
+	return nil
+}
+
+// GetChildSummary adds up what a child has done since a point in time, or
+// over all time if since is zero. Time spent adds up the child's screen
+// sessions like ScreenTimeUsed, but counts time granted with the parent PIN
+// too.
+func (db *Database) GetChildSummary(ctx context.Context, userID string, since time.Time) (*models.ChildSummary, error) {
+	summary := &models.ChildSummary{UserID: userID}
+	if !since.IsZero() {
+		summary.Since = since.Unix()
+	}
+
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM stories WHERE user_id = $1 AND created_at >= $2),
+			(SELECT COUNT(*) FROM pages p JOIN stories s ON s.id = p.story_id
+				WHERE s.user_id = $1 AND p.created_at >= $2),
+			(SELECT AVG(p.grade_level) FROM pages p JOIN stories s ON s.id = p.story_id
+				WHERE s.user_id = $1 AND p.created_at >= $2 AND p.grade_level IS NOT NULL),
+			(SELECT COUNT(*) FROM quiz_attempts WHERE user_id = $1 AND created_at >= $2),
+			(SELECT COALESCE(SUM(score), 0) FROM quiz_attempts WHERE user_id = $1 AND created_at >= $2),
+			(SELECT COALESCE(SUM(total), 0) FROM quiz_attempts WHERE user_id = $1 AND created_at >= $2),
+			(SELECT COUNT(*) FROM moderation_flags WHERE user_id = $1 AND created_at >= $2),
+			(SELECT COUNT(*) FROM moderation_flags WHERE user_id = $1 AND created_at >= $2 AND reviewed_at IS NULL),
+			(SELECT COUNT(*) FROM vocabulary WHERE user_id = $1 AND created_at >= $2),
+			(SELECT COUNT(*) FROM word_reviews WHERE user_id = $1 AND created_at >= $2),
+			(SELECT COALESCE(SUM(input_tokens), 0) FROM token_usage WHERE user_id = $1 AND created_at >= $2),
+			(SELECT COALESCE(SUM(output_tokens), 0) FROM token_usage WHERE user_id = $1 AND created_at >= $2)
+	`
+	err := db.pool.QueryRow(ctx, query, userID, summary.Since).Scan(
+		&summary.Stories,
+		&summary.Pages,
+		&summary.AverageGrade,
+		&summary.Quizzes,
+		&summary.QuizCorrect,
+		&summary.QuizAnswered,
+		&summary.Flags,
+		&summary.UnreviewedFlags,
+		&summary.NewWords,
+		&summary.WordsReviewed,
+		&summary.InputTokens,
+		&summary.OutputTokens,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query child summary: %w", err)
+	}
+
+	summary.ActiveSeconds, err = db.sessionSeconds(ctx, userID, summary.Since, true)
+	if err != nil {
+		return nil, fmt.Errorf("query time spent: %w", err)
+	}
+
+	query = `
+		SELECT o.id, o.subject_id, o.title, o.description, o.created_at, COUNT(*)
+		FROM page_objectives po
+		JOIN pages p ON p.id = po.page_id
+		JOIN stories s ON s.id = p.story_id
+		JOIN objectives o ON o.id = po.objective_id
+		WHERE s.user_id = $1 AND p.created_at >= $2
+		GROUP BY o.id
+		ORDER BY COUNT(*) DESC, o.title
+	`
+	rows, err := db.pool.Query(ctx, query, userID, summary.Since)
+	if err != nil {
+		return nil, fmt.Errorf("query objectives covered: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var p models.ObjectiveProgress
+		err := rows.Scan(
+			&p.ID,
+			&p.SubjectID,
+			&p.Title,
+			&p.Description,
+			&p.CreatedAt,
+			&p.Pages,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan objective covered: %w", err)
+		}
+		summary.Objectives = append(summary.Objectives, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate objectives covered: %w", err)
+	}
+	return summary, nil
+}
@@ -0,0 +1,157 @@
+//go:build integration
+
+package db_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kbrakke/illustrated-primer/internal/models"
+	"github.com/kbrakke/illustrated-primer/testutil"
+)
+
+func TestChildSummary(t *testing.T) {
+	testDB := testutil.NewTestDatabase(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	user := models.NewUser("Summary Test User", "summary-test@example.com")
+	if err := testDB.Database.CreateUser(ctx, user); err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	story := models.NewStory(user.ID, "Summary Test Story", "")
+	if err := testDB.Database.CreateStory(ctx, story); err != nil {
+		t.Fatalf("failed to create story: %v", err)
+	}
+
+	subject := models.NewSubject("Maths", "")
+	if err := testDB.Database.CreateSubject(ctx, subject); err != nil {
+		t.Fatalf("failed to create subject: %v", err)
+	}
+	counting := models.NewObjective(subject.ID, "Counting", "")
+	if err := testDB.Database.CreateObjective(ctx, counting); err != nil {
+		t.Fatalf("failed to create objective: %v", err)
+	}
+
+	// Two pages a minute apart, then one after an hour's break.
+	start := now.Add(-2 * time.Hour).Unix()
+	var page *models.Page
+	for i, at := range []int64{start, start + 60, start + 60 + 3600} {
+		page = models.NewPage(story.ID, int64(i+1), "prompt", "completion")
+		page.CreatedAt = at
+		page.Readability = &models.Readability{GradeLevel: float64(i + 1)}
+		if err := testDB.Database.CreatePage(ctx, page); err != nil {
+			t.Fatalf("CreatePage failed: %v", err)
+		}
+		if i > 0 {
+			if err := testDB.Database.SetPageObjectives(ctx, page.ID, []string{counting.ID}); err != nil {
+				t.Fatalf("SetPageObjectives failed: %v", err)
+			}
+		}
+	}
+
+	attempt := models.NewQuizAttempt(user.ID, story.ID)
+	attempt.AddAnswer(models.QuizAnswer{Question: "Who?", Kind: "text", Options: []string{"Fox", "Owl"}, Answer: 0, Chosen: 0})
+	attempt.AddAnswer(models.QuizAnswer{Question: "Where?", Kind: "text", Options: []string{"Home", "Sea"}, Answer: 0, Chosen: 1})
+	if err := testDB.Database.CreateQuizAttempt(ctx, attempt); err != nil {
+		t.Fatalf("CreateQuizAttempt failed: %v", err)
+	}
+
+	flag := models.NewModerationFlag(user.ID, "prompt", "block", []string{"violence"}, "original", "")
+	if err := testDB.Database.CreateModerationFlag(ctx, flag); err != nil {
+		t.Fatalf("CreateModerationFlag failed: %v", err)
+	}
+	for _, usage := range []*models.TokenUsage{
+		models.NewTokenUsage(user.ID, story.ID, models.UsagePage, 1000, 200),
+		models.NewTokenUsage(user.ID, "", models.UsageDefinition, 50, 10),
+	} {
+		if err := testDB.Database.RecordTokenUsage(ctx, usage); err != nil {
+			t.Fatalf("RecordTokenUsage failed: %v", err)
+		}
+	}
+	if err := testDB.Database.RecordWords(ctx, user.ID, story.ID, page.ID, []string{"enormous"}); err != nil {
+		t.Fatalf("RecordWords failed: %v", err)
+	}
+
+	// Ten minutes two hours ago, five granted with the parent PIN later on,
+	// and one still going that started before the Since cut-off.
+	for _, s := range []struct {
+		from, to time.Duration
+		override bool
+		ended    bool
+	}{
+		{-2 * time.Hour, -110 * time.Minute, false, true},
+		{-80 * time.Minute, -75 * time.Minute, true, true},
+		{-95 * time.Minute, -85 * time.Minute, false, false},
+	} {
+		session := models.NewScreenSession(user.ID, s.override)
+		session.StartedAt, session.LastSeenAt = now.Add(s.from).Unix(), now.Add(s.to).Unix()
+		if err := testDB.Database.StartScreenSession(ctx, session); err != nil {
+			t.Fatalf("StartScreenSession failed: %v", err)
+		}
+		if s.ended {
+			if err := testDB.Database.EndScreenSession(ctx, session.ID, models.SessionEndedLeft, now.Add(s.to)); err != nil {
+				t.Fatalf("EndScreenSession failed: %v", err)
+			}
+		}
+	}
+
+	t.Run("AllTime", func(t *testing.T) {
+		summary, err := testDB.Database.GetChildSummary(ctx, user.ID, time.Time{})
+		if err != nil {
+			t.Fatalf("GetChildSummary failed: %v", err)
+		}
+		if summary.Stories != 1 || summary.Pages != 3 {
+			t.Errorf("stories %d, pages %d; want 1 and 3", summary.Stories, summary.Pages)
+		}
+		if summary.ActiveSeconds != 600+300+600 {
+			t.Errorf("ActiveSeconds = %d, want 1500", summary.ActiveSeconds)
+		}
+		if summary.AverageGrade == nil || *summary.AverageGrade != 2 {
+			t.Errorf("AverageGrade = %v, want 2", summary.AverageGrade)
+		}
+		if len(summary.Objectives) != 1 || summary.Objectives[0].Title != "Counting" || summary.Objectives[0].Pages != 2 {
+			t.Errorf("Objectives = %+v", summary.Objectives)
+		}
+		if summary.Quizzes != 1 || summary.QuizPercent() != 50 {
+			t.Errorf("quizzes %d at %d%%", summary.Quizzes, summary.QuizPercent())
+		}
+		if summary.Flags != 1 || summary.UnreviewedFlags != 1 {
+			t.Errorf("flags %d, unreviewed %d", summary.Flags, summary.UnreviewedFlags)
+		}
+		if summary.InputTokens != 1050 || summary.OutputTokens != 210 {
+			t.Errorf("tokens %d in, %d out", summary.InputTokens, summary.OutputTokens)
+		}
+		if summary.NewWords != 1 {
+			t.Errorf("NewWords = %d, want 1", summary.NewWords)
+		}
+	})
+
+	t.Run("Since", func(t *testing.T) {
+		summary, err := testDB.Database.GetChildSummary(ctx, user.ID, now.Add(-90*time.Minute))
+		if err != nil {
+			t.Fatalf("GetChildSummary failed: %v", err)
+		}
+		if summary.Pages != 1 || summary.ActiveSeconds != 300+300 {
+			t.Errorf("pages %d, active %ds; want only the last page and 600s", summary.Pages, summary.ActiveSeconds)
+		}
+		if summary.Stories != 1 || summary.Quizzes != 1 {
+			t.Errorf("stories %d, quizzes %d; want those made just now", summary.Stories, summary.Quizzes)
+		}
+	})
+
+	t.Run("NothingDone", func(t *testing.T) {
+		other := models.NewUser("Idle User", "idle@example.com")
+		if err := testDB.Database.CreateUser(ctx, other); err != nil {
+			t.Fatalf("failed to create user: %v", err)
+		}
+		summary, err := testDB.Database.GetChildSummary(ctx, other.ID, time.Time{})
+		if err != nil {
+			t.Fatalf("GetChildSummary failed: %v", err)
+		}
+		if summary.Pages != 0 || summary.AverageGrade != nil || summary.Objectives != nil {
+			t.Errorf("summary = %+v, want nothing", summary)
+		}
+	})
+}
@@ -16,8 +16,9 @@ var ErrUserNotFound = errors.New("user not found")
 // CreateUser inserts a new user into the database.
 func (db *Database) CreateUser(ctx context.Context, user *models.User) error {
 	query := `
-		INSERT INTO users (id, name, email, email_verified, image, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO users (id, name, email, email_verified, image, theme, created_at, updated_at, birthdate, age_band, reading_level, interests, language, daily_minutes, allowed_from, allowed_until,
+			household_id, role, pin_hash, story_themes, banned_topics, sensitivities)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22)
 	`
 	_, err := db.pool.Exec(ctx, query,
 		user.ID,
@@ -25,8 +26,23 @@ func (db *Database) CreateUser(ctx context.Context, user *models.User) error {
 		user.Email,
 		user.EmailVerified,
 		user.Image,
+		user.Theme,
 		user.CreatedAt,
 		user.UpdatedAt,
+		user.Birthdate,
+		user.AgeBand,
+		user.ReadingLevel,
+		textArray(user.Interests),
+		user.Language,
+		user.DailyMinutes,
+		user.AllowedFrom,
+		user.AllowedUntil,
+		user.HouseholdID,
+		role(user),
+		user.PINHash,
+		textArray(user.StoryThemes),
+		textArray(user.BannedTopics),
+		textArray(user.Sensitivities),
 	)
 	if err != nil {
 		return fmt.Errorf("insert user: %w", err)
@@ -37,7 +53,8 @@ func (db *Database) CreateUser(ctx context.Context, user *models.User) error {
 // GetUserByID retrieves a user by their ID.
 func (db *Database) GetUserByID(ctx context.Context, id string) (*models.User, error) {
 	query := `
-		SELECT id, name, email, email_verified, image, created_at, updated_at
+		SELECT id, name, email, email_verified, image, theme, created_at, updated_at, birthdate, age_band, reading_level, interests, language,
+			daily_minutes, allowed_from, allowed_until, household_id, role, pin_hash, story_themes, banned_topics, sensitivities
 		FROM users
 		WHERE id = $1
 	`
@@ -48,8 +65,23 @@ func (db *Database) GetUserByID(ctx context.Context, id string) (*models.User, e
 		&user.Email,
 		&user.EmailVerified,
 		&user.Image,
+		&user.Theme,
 		&user.CreatedAt,
 		&user.UpdatedAt,
+		&user.Birthdate,
+		&user.AgeBand,
+		&user.ReadingLevel,
+		&user.Interests,
+		&user.Language,
+		&user.DailyMinutes,
+		&user.AllowedFrom,
+		&user.AllowedUntil,
+		&user.HouseholdID,
+		&user.Role,
+		&user.PINHash,
+		&user.StoryThemes,
+		&user.BannedTopics,
+		&user.Sensitivities,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -63,7 +95,8 @@ func (db *Database) GetUserByID(ctx context.Context, id string) (*models.User, e
 // GetUserByEmail retrieves a user by their email address.
 func (db *Database) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
 	query := `
-		SELECT id, name, email, email_verified, image, created_at, updated_at
+		SELECT id, name, email, email_verified, image, theme, created_at, updated_at, birthdate, age_band, reading_level, interests, language,
+			daily_minutes, allowed_from, allowed_until, household_id, role, pin_hash, story_themes, banned_topics, sensitivities
 		FROM users
 		WHERE email = $1
 	`
@@ -74,8 +107,23 @@ func (db *Database) GetUserByEmail(ctx context.Context, email string) (*models.U
 		&user.Email,
 		&user.EmailVerified,
 		&user.Image,
+		&user.Theme,
 		&user.CreatedAt,
 		&user.UpdatedAt,
+		&user.Birthdate,
+		&user.AgeBand,
+		&user.ReadingLevel,
+		&user.Interests,
+		&user.Language,
+		&user.DailyMinutes,
+		&user.AllowedFrom,
+		&user.AllowedUntil,
+		&user.HouseholdID,
+		&user.Role,
+		&user.PINHash,
+		&user.StoryThemes,
+		&user.BannedTopics,
+		&user.Sensitivities,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -89,7 +137,8 @@ func (db *Database) GetUserByEmail(ctx context.Context, email string) (*models.U
 // ListUsers retrieves all users ordered by creation date.
 func (db *Database) ListUsers(ctx context.Context) ([]models.User, error) {
 	query := `
-		SELECT id, name, email, email_verified, image, created_at, updated_at
+		SELECT id, name, email, email_verified, image, theme, created_at, updated_at, birthdate, age_band, reading_level, interests, language,
+			daily_minutes, allowed_from, allowed_until, household_id, role, pin_hash, story_themes, banned_topics, sensitivities
 		FROM users
 		ORDER BY created_at DESC
 	`
@@ -108,8 +157,23 @@ func (db *Database) ListUsers(ctx context.Context) ([]models.User, error) {
 			&user.Email,
 			&user.EmailVerified,
 			&user.Image,
+			&user.Theme,
 			&user.CreatedAt,
 			&user.UpdatedAt,
+			&user.Birthdate,
+			&user.AgeBand,
+			&user.ReadingLevel,
+			&user.Interests,
+			&user.Language,
+			&user.DailyMinutes,
+			&user.AllowedFrom,
+			&user.AllowedUntil,
+			&user.HouseholdID,
+			&user.Role,
+			&user.PINHash,
+			&user.StoryThemes,
+			&user.BannedTopics,
+			&user.Sensitivities,
 		); err != nil {
 			return nil, fmt.Errorf("scan user: %w", err)
 		}
@@ -128,7 +192,11 @@ func (db *Database) UpdateUser(ctx context.Context, user *models.User) error {
 	user.UpdatedAt = time.Now().Unix()
 	query := `
 		UPDATE users
-		SET name = $2, email = $3, email_verified = $4, image = $5, updated_at = $6
+		SET name = $2, email = $3, email_verified = $4, image = $5, theme = $6, updated_at = $7,
+			birthdate = $8, age_band = $9, reading_level = $10, interests = $11, language = $12,
+			daily_minutes = $13, allowed_from = $14, allowed_until = $15,
+			household_id = $16, role = $17, pin_hash = $18,
+			story_themes = $19, banned_topics = $20, sensitivities = $21
 		WHERE id = $1
 	`
 	result, err := db.pool.Exec(ctx, query,
@@ -137,7 +205,22 @@ func (db *Database) UpdateUser(ctx context.Context, user *models.User) error {
 		user.Email,
 		user.EmailVerified,
 		user.Image,
+		user.Theme,
 		user.UpdatedAt,
+		user.Birthdate,
+		user.AgeBand,
+		user.ReadingLevel,
+		textArray(user.Interests),
+		user.Language,
+		user.DailyMinutes,
+		user.AllowedFrom,
+		user.AllowedUntil,
+		user.HouseholdID,
+		role(user),
+		user.PINHash,
+		textArray(user.StoryThemes),
+		textArray(user.BannedTopics),
+		textArray(user.Sensitivities),
 	)
 	if err != nil {
 		return fmt.Errorf("update user: %w", err)
@@ -148,6 +231,22 @@ func (db *Database) UpdateUser(ctx context.Context, user *models.User) error {
 	return nil
 }
 
+// textArray returns a list for a NOT NULL TEXT[] column, such as interests.
+func textArray(list []string) []string {
+	if list == nil {
+		return []string{}
+	}
+	return list
+}
+
+// role returns the user's role for the NOT NULL role column.
+func role(user *models.User) models.Role {
+	if user.Role == "" {
+		return models.RoleChild
+	}
+	return user.Role
+}
+
 // DeleteUser deletes a user by their ID.
 func (db *Database) DeleteUser(ctx context.Context, id string) error {
 	query := `DELETE FROM users WHERE id = $1`
@@ -95,7 +95,9 @@ func TestUserCRUD(t *testing.T) {
 		}
 
 		newName := "Updated Name"
+		newTheme := "e-ink"
 		user.Name = &newName
+		user.Theme = &newTheme
 		if err := testDB.Database.UpdateUser(ctx, user); err != nil {
 			t.Fatalf("UpdateUser failed: %v", err)
 		}
@@ -108,6 +110,94 @@ func TestUserCRUD(t *testing.T) {
 		if *retrieved.Name != "Updated Name" {
 			t.Errorf("Name = %q, want %q", *retrieved.Name, "Updated Name")
 		}
+		if retrieved.ThemeName() != "e-ink" {
+			t.Errorf("Theme = %q, want %q", retrieved.ThemeName(), "e-ink")
+		}
+	})
+
+	t.Run("UpdateUser_Profile", func(t *testing.T) {
+		user := models.NewUser("Profile Test", "profile@example.com")
+		if err := testDB.Database.CreateUser(ctx, user); err != nil {
+			t.Fatalf("setup failed: %v", err)
+		}
+
+		birthdate, level, language := "2020-03-14", "beginning", "es"
+		user.Birthdate = &birthdate
+		user.ReadingLevel = &level
+		user.Language = &language
+		user.Interests = []string{"dinosaurs", "trains"}
+		if err := testDB.Database.UpdateUser(ctx, user); err != nil {
+			t.Fatalf("UpdateUser failed: %v", err)
+		}
+
+		retrieved, err := testDB.Database.GetUserByID(ctx, user.ID)
+		if err != nil {
+			t.Fatalf("GetUserByID failed: %v", err)
+		}
+		if retrieved.Birthdate == nil || *retrieved.Birthdate != birthdate {
+			t.Errorf("Birthdate = %v, want %q", retrieved.Birthdate, birthdate)
+		}
+		if retrieved.ReadingLevel == nil || *retrieved.ReadingLevel != level {
+			t.Errorf("ReadingLevel = %v, want %q", retrieved.ReadingLevel, level)
+		}
+		if retrieved.Language == nil || *retrieved.Language != language {
+			t.Errorf("Language = %v, want %q", retrieved.Language, language)
+		}
+		if len(retrieved.Interests) != 2 || retrieved.Interests[1] != "trains" {
+			t.Errorf("Interests = %v", retrieved.Interests)
+		}
+		if retrieved.AgeBand != nil {
+			t.Errorf("AgeBand = %q, want unset", *retrieved.AgeBand)
+		}
+	})
+
+	t.Run("UpdateUser_ScreenTime", func(t *testing.T) {
+		user := models.NewUser("Screen Time Test", "screentime@example.com")
+		if err := testDB.Database.CreateUser(ctx, user); err != nil {
+			t.Fatalf("setup failed: %v", err)
+		}
+		if retrieved, _ := testDB.Database.GetUserByID(ctx, user.ID); retrieved.ScreenTime().Limited() {
+			t.Errorf("a new user should have no limits, got %s", retrieved.ScreenTime())
+		}
+
+		limits := models.ScreenTime{DailyMinutes: 45, From: "07:00", Until: "19:30"}
+		user.SetScreenTime(limits)
+		if err := testDB.Database.UpdateUser(ctx, user); err != nil {
+			t.Fatalf("UpdateUser failed: %v", err)
+		}
+
+		retrieved, err := testDB.Database.GetUserByID(ctx, user.ID)
+		if err != nil {
+			t.Fatalf("GetUserByID failed: %v", err)
+		}
+		if got := retrieved.ScreenTime(); got != limits {
+			t.Errorf("ScreenTime() = %+v, want %+v", got, limits)
+		}
+	})
+
+	t.Run("UpdateUser_ContentPreferences", func(t *testing.T) {
+		user := models.NewUser("Preferences Test", "preferences@example.com")
+		if err := testDB.Database.CreateUser(ctx, user); err != nil {
+			t.Fatalf("setup failed: %v", err)
+		}
+
+		prefs := models.ContentPreferences{
+			StoryThemes:   []string{"dinosaurs"},
+			Banned:        []string{"scary monsters"},
+			Sensitivities: []string{"loud noises", "death"},
+		}
+		user.SetContentPreferences(prefs)
+		if err := testDB.Database.UpdateUser(ctx, user); err != nil {
+			t.Fatalf("UpdateUser failed: %v", err)
+		}
+
+		retrieved, err := testDB.Database.GetUserByID(ctx, user.ID)
+		if err != nil {
+			t.Fatalf("GetUserByID failed: %v", err)
+		}
+		if got := retrieved.ContentPreferences(); got.String() != prefs.String() {
+			t.Errorf("ContentPreferences() = %s, want %s", got, prefs)
+		}
 	})
 
 	t.Run("DeleteUser", func(t *testing.T) {
@@ -0,0 +1,235 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/kbrakke/illustrated-primer/internal/models"
+)
+
+// ErrWordNotFound is returned when a word isn't in a child's vocabulary.
+var ErrWordNotFound = errors.New("word not found")
+
+// ErrDefinitionNotFound is returned when a word has no cached definition.
+var ErrDefinitionNotFound = errors.New("definition not found")
+
+// RecordWords adds the words on a page to a child's vocabulary. New words are
+// due for their first review a day later. Words the child already knows keep
+// where they were first seen and their review schedule, and count another
+// sighting.
+func (db *Database) RecordWords(ctx context.Context, userID, storyID, pageID string, words []string) error {
+	query := `
+		INSERT INTO vocabulary (user_id, word, first_story_id, first_page_id, times_seen, due_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, 1, $5, $6, $6)
+		ON CONFLICT (user_id, word) DO UPDATE
+		SET times_seen = vocabulary.times_seen + 1, updated_at = EXCLUDED.updated_at
+	`
+	now := time.Now()
+	due := now.Add(models.FirstReview).Unix()
+	batch := &pgx.Batch{}
+	for _, word := range words {
+		batch.Queue(query, userID, word, storyID, pageID, due, now.Unix())
+	}
+	if err := db.pool.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("record words: %w", err)
+	}
+	return nil
+}
+
+// vocabularyQuery selects vocabulary entries with where each word was first
+// seen and its definition for the age band in $2.
+const vocabularyQuery = `
+	SELECT v.user_id, v.word, v.first_story_id, v.first_page_id, v.times_seen, v.created_at, v.updated_at,
+		v.ease, v.interval_days, v.repetitions, v.due_at, v.reviewed_at,
+		COALESCE(s.title, ''), COALESCE(p.page_num, 0), COALESCE(d.definition, '')
+	FROM vocabulary v
+	LEFT JOIN stories s ON s.id = v.first_story_id
+	LEFT JOIN pages p ON p.id = v.first_page_id
+	LEFT JOIN definitions d ON d.word = v.word AND d.age_band = $2
+`
+
+// ListVocabulary retrieves a child's words in alphabetical order, with
+// where each was first seen and its cached definition for the age band.
+func (db *Database) ListVocabulary(ctx context.Context, userID string, band models.AgeBand) ([]models.VocabularyEntry, error) {
+	query := vocabularyQuery + `
+		WHERE v.user_id = $1
+		ORDER BY v.word
+	`
+	return db.queryVocabulary(ctx, query, userID, string(band))
+}
+
+// ListDueWords retrieves up to limit of a child's words that are due for
+// review at now, most overdue first, with their definitions for the age band.
+func (db *Database) ListDueWords(ctx context.Context, userID string, band models.AgeBand, now time.Time, limit int) ([]models.VocabularyEntry, error) {
+	query := vocabularyQuery + `
+		WHERE v.user_id = $1 AND v.due_at <= $3
+		ORDER BY v.due_at, v.word
+		LIMIT $4
+	`
+	return db.queryVocabulary(ctx, query, userID, string(band), now.Unix(), limit)
+}
+
+// queryVocabulary runs a query built on vocabularyQuery.
+func (db *Database) queryVocabulary(ctx context.Context, query string, args ...any) ([]models.VocabularyEntry, error) {
+	rows, err := db.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query vocabulary: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []models.VocabularyEntry
+	for rows.Next() {
+		var entry models.VocabularyEntry
+		if err := rows.Scan(
+			&entry.UserID,
+			&entry.Word.Word,
+			&entry.FirstStoryID,
+			&entry.FirstPageID,
+			&entry.TimesSeen,
+			&entry.CreatedAt,
+			&entry.UpdatedAt,
+			&entry.Ease,
+			&entry.Interval,
+			&entry.Repetitions,
+			&entry.DueAt,
+			&entry.ReviewedAt,
+			&entry.StoryTitle,
+			&entry.PageNum,
+			&entry.Definition,
+		); err != nil {
+			return nil, fmt.Errorf("scan vocabulary: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate vocabulary: %w", err)
+	}
+	return entries, nil
+}
+
+// SaveWordReview stores a word's new review schedule and the outcome of the
+// review in a single transaction.
+func (db *Database) SaveWordReview(ctx context.Context, word *models.Word, review *models.WordReview) error {
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		UPDATE vocabulary
+		SET ease = $3, interval_days = $4, repetitions = $5, due_at = $6, reviewed_at = $7
+		WHERE user_id = $1 AND word = $2
+	`
+	result, err := tx.Exec(ctx, query,
+		word.UserID,
+		word.Word,
+		word.Ease,
+		word.Interval,
+		word.Repetitions,
+		word.DueAt,
+		word.ReviewedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("update word schedule: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrWordNotFound
+	}
+
+	insert := `
+		INSERT INTO word_reviews (id, user_id, word, recall, interval_days, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	_, err = tx.Exec(ctx, insert, review.ID, review.UserID, review.Word, int(review.Recall), review.Interval, review.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("insert word review: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
+// ListWordReviewsByUser retrieves the outcomes of a child's word reviews,
+// newest first.
+func (db *Database) ListWordReviewsByUser(ctx context.Context, userID string) ([]models.WordReview, error) {
+	query := `
+		SELECT id, user_id, word, recall, interval_days, created_at
+		FROM word_reviews
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+	`
+	rows, err := db.pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("query word reviews: %w", err)
+	}
+	defer rows.Close()
+
+	var reviews []models.WordReview
+	for rows.Next() {
+		var review models.WordReview
+		var recall int
+		if err := rows.Scan(
+			&review.ID,
+			&review.UserID,
+			&review.Word,
+			&recall,
+			&review.Interval,
+			&review.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan word review: %w", err)
+		}
+		review.Recall = models.Recall(recall)
+		reviews = append(reviews, review)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate word reviews: %w", err)
+	}
+	return reviews, nil
+}
+
+// GetDefinition retrieves the cached definition of a word for an age band.
+func (db *Database) GetDefinition(ctx context.Context, word string, band models.AgeBand) (*models.Definition, error) {
+	query := `
+		SELECT word, age_band, definition, created_at
+		FROM definitions
+		WHERE word = $1 AND age_band = $2
+	`
+	var def models.Definition
+	var ageBand string
+	err := db.pool.QueryRow(ctx, query, word, string(band)).Scan(
+		&def.Word,
+		&ageBand,
+		&def.Text,
+		&def.CreatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrDefinitionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query definition: %w", err)
+	}
+	def.AgeBand = models.AgeBand(ageBand)
+	return &def, nil
+}
+
+// SaveDefinition caches a definition, replacing any for the same word and
+// age band.
+func (db *Database) SaveDefinition(ctx context.Context, def *models.Definition) error {
+	query := `
+		INSERT INTO definitions (word, age_band, definition, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (word, age_band) DO UPDATE
+		SET definition = EXCLUDED.definition, created_at = EXCLUDED.created_at
+	`
+	_, err := db.pool.Exec(ctx, query, def.Word, string(def.AgeBand), def.Text, def.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("save definition: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+//go:build integration
+
+package db_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kbrakke/illustrated-primer/internal/db"
+	"github.com/kbrakke/illustrated-primer/internal/models"
+	"github.com/kbrakke/illustrated-primer/testutil"
+)
+
+func TestVocabulary(t *testing.T) {
+	testDB := testutil.NewTestDatabase(t)
+	ctx := context.Background()
+
+	user := models.NewUser("Vocabulary Test User", "vocabulary-test@example.com")
+	if err := testDB.Database.CreateUser(ctx, user); err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+
+	story := models.NewStory(user.ID, "Vocabulary Story", "")
+	if err := testDB.Database.CreateStory(ctx, story); err != nil {
+		t.Fatalf("failed to create story: %v", err)
+	}
+	first := models.NewPage(story.ID, 1, "prompt", "An enormous caterpillar.")
+	second := models.NewPage(story.ID, 2, "prompt", "The enormous cocoon was iridescent.")
+	for _, page := range []*models.Page{first, second} {
+		if err := testDB.Database.CreatePage(ctx, page); err != nil {
+			t.Fatalf("CreatePage failed: %v", err)
+		}
+	}
+
+	if err := testDB.Database.RecordWords(ctx, user.ID, story.ID, first.ID, []string{"caterpillar", "enormous"}); err != nil {
+		t.Fatalf("RecordWords failed: %v", err)
+	}
+	if err := testDB.Database.RecordWords(ctx, user.ID, story.ID, second.ID, []string{"enormous", "iridescent"}); err != nil {
+		t.Fatalf("RecordWords failed: %v", err)
+	}
+
+	t.Run("Definitions", func(t *testing.T) {
+		_, err := testDB.Database.GetDefinition(ctx, "enormous", models.AgeBandToddler)
+		if !errors.Is(err, db.ErrDefinitionNotFound) {
+			t.Fatalf("GetDefinition error = %v, want ErrDefinitionNotFound", err)
+		}
+
+		for _, text := range []string{"Very big.", "Really, really big, like an elephant."} {
+			if err := testDB.Database.SaveDefinition(ctx, models.NewDefinition("enormous", models.AgeBandToddler, text)); err != nil {
+				t.Fatalf("SaveDefinition failed: %v", err)
+			}
+		}
+		def, err := testDB.Database.GetDefinition(ctx, "enormous", models.AgeBandToddler)
+		if err != nil {
+			t.Fatalf("GetDefinition failed: %v", err)
+		}
+		if def.Text != "Really, really big, like an elephant." || def.AgeBand != models.AgeBandToddler {
+			t.Errorf("definition = %+v, want the replacement", def)
+		}
+	})
+
+	t.Run("ListVocabulary", func(t *testing.T) {
+		entries, err := testDB.Database.ListVocabulary(ctx, user.ID, models.AgeBandToddler)
+		if err != nil {
+			t.Fatalf("ListVocabulary failed: %v", err)
+		}
+		if len(entries) != 3 {
+			t.Fatalf("got %d words, want 3", len(entries))
+		}
+
+		enormous := entries[1]
+		if enormous.Word.Word != "enormous" || enormous.TimesSeen != 2 || enormous.PageNum != 1 || enormous.StoryTitle != story.Title {
+			t.Errorf("enormous = %+v, want first seen on page 1 and seen twice", enormous)
+		}
+		if enormous.Definition == "" || entries[0].Definition != "" {
+			t.Errorf("definitions = %q, %q; want only enormous defined", entries[0].Definition, enormous.Definition)
+		}
+		if entries[2].PageNum != 2 {
+			t.Errorf("iridescent first seen on page %d, want 2", entries[2].PageNum)
+		}
+
+		// Definitions are per age band.
+		entries, err = testDB.Database.ListVocabulary(ctx, user.ID, models.AgeBandReader)
+		if err != nil {
+			t.Fatalf("ListVocabulary failed: %v", err)
+		}
+		if entries[1].Definition != "" {
+			t.Errorf("a toddler definition was used for a reader: %q", entries[1].Definition)
+		}
+	})
+	t.Run("Reviews", func(t *testing.T) {
+		now := time.Now()
+		due, err := testDB.Database.ListDueWords(ctx, user.ID, models.AgeBandToddler, now, 10)
+		if err != nil {
+			t.Fatalf("ListDueWords failed: %v", err)
+		}
+		if len(due) != 0 {
+			t.Fatalf("new words are due tomorrow, got %d due now", len(due))
+		}
+
+		tomorrow := now.Add(models.FirstReview + time.Minute)
+		due, err = testDB.Database.ListDueWords(ctx, user.ID, models.AgeBandToddler, tomorrow, 2)
+		if err != nil {
+			t.Fatalf("ListDueWords failed: %v", err)
+		}
+		if len(due) != 2 || due[0].Ease != models.DefaultEase || due[0].ReviewedAt != nil {
+			t.Fatalf("due = %+v, want the two oldest words, never reviewed", due)
+		}
+
+		word := due[0].Word
+		word.Schedule(models.RecallGood, tomorrow)
+		if err := testDB.Database.SaveWordReview(ctx, &word, models.NewWordReview(word, models.RecallGood)); err != nil {
+			t.Fatalf("SaveWordReview failed: %v", err)
+		}
+
+		due, err = testDB.Database.ListDueWords(ctx, user.ID, models.AgeBandToddler, tomorrow, 10)
+		if err != nil {
+			t.Fatalf("ListDueWords failed: %v", err)
+		}
+		for _, entry := range due {
+			if entry.Word.Word == word.Word {
+				t.Errorf("%q is still due after it was reviewed", word.Word)
+			}
+		}
+		if len(due) != 2 {
+			t.Errorf("got %d due words, want 2", len(due))
+		}
+
+		reviews, err := testDB.Database.ListWordReviewsByUser(ctx, user.ID)
+		if err != nil {
+			t.Fatalf("ListWordReviewsByUser failed: %v", err)
+		}
+		if len(reviews) != 1 || reviews[0].Word != word.Word || reviews[0].Recall != models.RecallGood || reviews[0].Interval != 1 {
+			t.Errorf("reviews = %+v", reviews)
+		}
+
+		missing := models.Word{UserID: user.ID, Word: "nonexistent"}
+		missing.Schedule(models.RecallGood, tomorrow)
+		err = testDB.Database.SaveWordReview(ctx, &missing, models.NewWordReview(missing, models.RecallGood))
+		if !errors.Is(err, db.ErrWordNotFound) {
+			t.Errorf("SaveWordReview of an unknown word error = %v, want ErrWordNotFound", err)
+		}
+	})
+}
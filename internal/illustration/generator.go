@@ -0,0 +1,164 @@
+// Package illustration generates the pictures shown alongside story pages.
+package illustration
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+const (
+	// DefaultImageModel is the OpenAI model used for illustrations.
+	DefaultImageModel = "gpt-image-1"
+
+	// DefaultImageSize is the size of generated illustrations.
+	DefaultImageSize = "1024x1024"
+
+	imagesAPIURL = "https://api.openai.com/v1/images/generations"
+)
+
+// ImageGenerator turns an illustration prompt into PNG image data.
+type ImageGenerator interface {
+	Generate(ctx context.Context, prompt string) ([]byte, error)
+}
+
+// OpenAIGenerator implements ImageGenerator using the OpenAI Images API.
+type OpenAIGenerator struct {
+	apiKey     string
+	orgID      string
+	model      string
+	size       string
+	httpClient *http.Client
+	logger     *slog.Logger
+}
+
+// OpenAIOption is a function that configures an OpenAIGenerator.
+type OpenAIOption func(*OpenAIGenerator)
+
+// WithModel sets the image model to use.
+func WithModel(model string) OpenAIOption {
+	return func(g *OpenAIGenerator) {
+		g.model = model
+	}
+}
+
+// WithSize sets the image size, such as "1024x1024".
+func WithSize(size string) OpenAIOption {
+	return func(g *OpenAIGenerator) {
+		g.size = size
+	}
+}
+
+// WithOrgID sets the organization ID for project-scoped keys.
+func WithOrgID(orgID string) OpenAIOption {
+	return func(g *OpenAIGenerator) {
+		g.orgID = orgID
+	}
+}
+
+// WithLogger sets a custom logger.
+func WithLogger(logger *slog.Logger) OpenAIOption {
+	return func(g *OpenAIGenerator) {
+		g.logger = logger
+	}
+}
+
+// WithHTTPClient sets a custom HTTP client.
+func WithHTTPClient(client *http.Client) OpenAIOption {
+	return func(g *OpenAIGenerator) {
+		g.httpClient = client
+	}
+}
+
+// NewOpenAIGenerator creates an image generator with the given API key and options.
+func NewOpenAIGenerator(apiKey string, opts ...OpenAIOption) *OpenAIGenerator {
+	g := &OpenAIGenerator{
+		apiKey:     apiKey,
+		model:      DefaultImageModel,
+		size:       DefaultImageSize,
+		httpClient: http.DefaultClient,
+		logger:     slog.Default(),
+	}
+
+	for _, opt := range opts {
+		opt(g)
+	}
+
+	return g
+}
+
+// imagesRequest is the request body for the Images API.
+type imagesRequest struct {
+	Model          string `json:"model"`
+	Prompt         string `json:"prompt"`
+	Size           string `json:"size,omitempty"`
+	N              int    `json:"n"`
+	ResponseFormat string `json:"response_format,omitempty"`
+}
+
+// Generate requests an illustration and returns the decoded PNG data.
+func (g *OpenAIGenerator) Generate(ctx context.Context, prompt string) ([]byte, error) {
+	reqBody := imagesRequest{
+		Model:  g.model,
+		Prompt: prompt,
+		Size:   g.size,
+		N:      1,
+	}
+	// gpt-image models always return base64; DALL·E models default to URLs.
+	if strings.HasPrefix(g.model, "dall-e") {
+		reqBody.ResponseFormat = "b64_json"
+	}
+
+	bodyBytes, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", imagesAPIURL, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+g.apiKey)
+	if g.orgID != "" {
+		req.Header.Set("OpenAI-Organization", g.orgID)
+	}
+
+	g.logger.Debug("requesting illustration", "model", g.model, "size", g.size)
+
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var result struct {
+		Data []struct {
+			B64JSON string `json:"b64_json"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	if len(result.Data) == 0 || result.Data[0].B64JSON == "" {
+		return nil, fmt.Errorf("no image in response")
+	}
+
+	data, err := base64.StdEncoding.DecodeString(result.Data[0].B64JSON)
+	if err != nil {
+		return nil, fmt.Errorf("decode image data: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,156 @@
+package illustration
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"image/png"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kbrakke/illustrated-primer/internal/models"
+)
+
+func TestBuildPrompt(t *testing.T) {
+	page := models.Page{
+		Prompt:     "Tell me about a fox",
+		Completion: "## The Fox\n\nA **clever** fox lived in the woods. She loved to count the stars.",
+	}
+
+	prompt := BuildPrompt(page, "pencil sketch")
+	if !strings.Contains(prompt, "style of pencil sketch") {
+		t.Errorf("prompt should include the art style: %q", prompt)
+	}
+	if !strings.Contains(prompt, "The Fox A clever fox lived in the woods.") {
+		t.Errorf("prompt should include the plain scene text: %q", prompt)
+	}
+
+	if prompt := BuildPrompt(models.Page{Completion: "```\nprint(fox)\n```\nThe fox slept."}, ""); strings.Contains(prompt, "print") {
+		t.Errorf("prompt should leave out code blocks: %q", prompt)
+	}
+
+	if prompt := BuildPrompt(models.Page{Prompt: "A dragon"}, ""); !strings.Contains(prompt, DefaultArtStyle) || !strings.Contains(prompt, "A dragon") {
+		t.Errorf("expected default style and prompt fallback: %q", prompt)
+	}
+}
+
+func TestOpeningSentences(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		limit int
+		want  string
+	}{
+		{"short", "One. Two.", 20, "One. Two."},
+		{"whole sentences", "One two. Three four. Five six.", 22, "One two. Three four."},
+		{"long sentence", "one two three four five", 12, "one two…"},
+		{"decimal point", "Pi is 3.14 and more words here", 12, "Pi is 3.14…"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := openingSentences(tt.text, tt.limit); got != tt.want {
+				t.Errorf("openingSentences() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlaceholderGenerator(t *testing.T) {
+	gen := &PlaceholderGenerator{Size: 32}
+	ctx := context.Background()
+
+	a, err := gen.Generate(ctx, "a fox in the woods")
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	b, _ := gen.Generate(ctx, "a fox in the woods")
+	c, _ := gen.Generate(ctx, "a whale in the sea")
+
+	if !bytes.Equal(a, b) {
+		t.Error("same prompt should give the same image")
+	}
+	if bytes.Equal(a, c) {
+		t.Error("different prompts should give different images")
+	}
+
+	img, err := png.Decode(bytes.NewReader(a))
+	if err != nil {
+		t.Fatalf("placeholder is not a PNG: %v", err)
+	}
+	if img.Bounds().Dx() != 32 || img.Bounds().Dy() != 32 {
+		t.Errorf("unexpected size %v", img.Bounds())
+	}
+}
+
+func TestIllustrate(t *testing.T) {
+	dir := t.TempDir()
+	il := NewIllustrator(&PlaceholderGenerator{Size: 16}, dir, nil)
+	page := *models.NewPage("story-1", 1, "A fox", "The fox ran.")
+
+	path, err := il.Illustrate(context.Background(), page, "")
+	if err != nil {
+		t.Fatalf("Illustrate() error = %v", err)
+	}
+
+	if want := filepath.Join(dir, "illustrations", "story-1", page.ID+".png"); path != want {
+		t.Errorf("path = %q, want %q", path, want)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("illustration file missing: %v", err)
+	}
+
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("expected only the illustration in the directory, got %d entries", len(entries))
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestOpenAIGenerator(t *testing.T) {
+	image := []byte("\x89PNG fake")
+	var sent imagesRequest
+
+	client := &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if got := req.Header.Get("Authorization"); got != "Bearer test-key" {
+			t.Errorf("Authorization = %q", got)
+		}
+		if err := json.NewDecoder(req.Body).Decode(&sent); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		body := `{"data":[{"b64_json":"` + base64.StdEncoding.EncodeToString(image) + `"}]}`
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body))}, nil
+	})}
+
+	gen := NewOpenAIGenerator("test-key", WithHTTPClient(client), WithModel("dall-e-3"), WithSize("512x512"))
+	data, err := gen.Generate(context.Background(), "a fox")
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	if !bytes.Equal(data, image) {
+		t.Errorf("Generate() = %q, want %q", data, image)
+	}
+	if sent.Model != "dall-e-3" || sent.Size != "512x512" || sent.Prompt != "a fox" || sent.ResponseFormat != "b64_json" {
+		t.Errorf("unexpected request %+v", sent)
+	}
+}
+
+func TestOpenAIGenerator_APIError(t *testing.T) {
+	client := &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusBadRequest, Body: io.NopCloser(strings.NewReader(`{"error":"safety"}`))}, nil
+	})}
+
+	_, err := NewOpenAIGenerator("k", WithHTTPClient(client)).Generate(context.Background(), "x")
+	if err == nil || !strings.Contains(err.Error(), "status 400") {
+		t.Errorf("expected API error, got %v", err)
+	}
+}
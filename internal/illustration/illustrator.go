@@ -0,0 +1,67 @@
+package illustration
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/kbrakke/illustrated-primer/internal/models"
+)
+
+// Illustrator generates page illustrations and stores them as files in an
+// asset directory, laid out as <dir>/illustrations/<story id>/<page id>.png.
+type Illustrator struct {
+	generator ImageGenerator
+	dir       string
+	logger    *slog.Logger
+}
+
+// NewIllustrator creates an Illustrator that stores images under dir.
+func NewIllustrator(generator ImageGenerator, dir string, logger *slog.Logger) *Illustrator {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Illustrator{generator: generator, dir: dir, logger: logger}
+}
+
+// Illustrate generates the illustration for a page in the given art style
+// and returns the path of the stored file. The file is written under a
+// temporary name and renamed into place, so readers never see a partial image.
+func (il *Illustrator) Illustrate(ctx context.Context, page models.Page, style string) (string, error) {
+	prompt := BuildPrompt(page, style)
+	il.logger.Debug("generating illustration", "page_id", page.ID, "prompt_length", len(prompt))
+
+	data, err := il.generator.Generate(ctx, prompt)
+	if err != nil {
+		return "", fmt.Errorf("generate illustration: %w", err)
+	}
+
+	dir := filepath.Join(il.dir, "illustrations", page.StoryID)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("create illustration directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, page.ID+"-*.tmp")
+	if err != nil {
+		return "", fmt.Errorf("create illustration file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("write illustration: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("write illustration: %w", err)
+	}
+
+	path := filepath.Join(dir, page.ID+".png")
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", fmt.Errorf("store illustration: %w", err)
+	}
+
+	il.logger.Info("illustration stored", "page_id", page.ID, "path", path)
+	return path, nil
+}
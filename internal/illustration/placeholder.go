@@ -0,0 +1,80 @@
+package illustration
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+)
+
+// DefaultPlaceholderSize is the width and height of placeholder images.
+const DefaultPlaceholderSize = 256
+
+// PlaceholderGenerator implements ImageGenerator without any network access.
+// It draws a simple scene of colored circles whose layout and palette are
+// derived from a hash of the prompt, so the same page always gets the same
+// picture. It is meant for offline use and tests.
+type PlaceholderGenerator struct {
+	Size int
+}
+
+// NewPlaceholderGenerator creates a placeholder generator with the default size.
+func NewPlaceholderGenerator() *PlaceholderGenerator {
+	return &PlaceholderGenerator{Size: DefaultPlaceholderSize}
+}
+
+// Generate draws the placeholder for prompt as PNG data.
+func (g *PlaceholderGenerator) Generate(ctx context.Context, prompt string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	size := g.Size
+	if size <= 0 {
+		size = DefaultPlaceholderSize
+	}
+
+	sum := sha256.Sum256([]byte(prompt))
+	top := color.RGBA{R: sum[0], G: sum[1], B: sum[2], A: 0xff}
+	bottom := color.RGBA{R: sum[3], G: sum[4], B: sum[5], A: 0xff}
+
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		c := blend(top, bottom, y, size)
+		for x := 0; x < size; x++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+
+	// Four circles, each described by six bytes of the hash.
+	for i := 0; i < 4; i++ {
+		b := sum[6+i*6 : 12+i*6]
+		cx, cy := int(b[0])*size/256, int(b[1])*size/256
+		r := size/10 + int(b[2])*size/1024
+		fill := color.RGBA{R: b[3], G: b[4], B: b[5], A: 0xff}
+		for y := max(cy-r, 0); y < min(cy+r, size); y++ {
+			for x := max(cx-r, 0); x < min(cx+r, size); x++ {
+				if (x-cx)*(x-cx)+(y-cy)*(y-cy) <= r*r {
+					img.SetRGBA(x, y, fill)
+				}
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("encode placeholder: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// blend interpolates between two colors, step of total along the way.
+func blend(a, b color.RGBA, step, total int) color.RGBA {
+	mix := func(x, y uint8) uint8 {
+		return uint8((int(x)*(total-step) + int(y)*step) / total)
+	}
+	return color.RGBA{R: mix(a.R, b.R), G: mix(a.G, b.G), B: mix(a.B, b.B), A: 0xff}
+}
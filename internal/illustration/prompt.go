@@ -0,0 +1,65 @@
+package illustration
+
+import (
+	"strings"
+
+	"github.com/kbrakke/illustrated-primer/internal/markdown"
+	"github.com/kbrakke/illustrated-primer/internal/models"
+)
+
+const (
+	// DefaultArtStyle is used for stories that haven't chosen a style.
+	DefaultArtStyle = "soft watercolor with warm colors"
+
+	// maxSceneLength caps how much page text goes into an illustration prompt.
+	maxSceneLength = 400
+)
+
+// BuildPrompt derives an illustration prompt from a page and the story's art
+// style. The scene is the opening sentences of the completion, which is where
+// the story usually sets out what is happening; the child's prompt is used
+// when there is no completion.
+func BuildPrompt(page models.Page, style string) string {
+	if strings.TrimSpace(style) == "" {
+		style = DefaultArtStyle
+	}
+
+	scene := plainText(page.Completion)
+	if scene == "" {
+		scene = plainText(page.Prompt)
+	}
+
+	return "A children's picture book illustration in the style of " + strings.TrimSpace(style) + ". " +
+		"Scene: " + openingSentences(scene, maxSceneLength) + " " +
+		"Gentle and friendly, suitable for young children. No text, letters or words in the image."
+}
+
+// plainText strips markdown markup and code blocks and collapses whitespace.
+func plainText(text string) string {
+	return strings.Join(strings.Fields(markdown.PlainText(text)), " ")
+}
+
+// openingSentences returns the whole sentences at the start of text that fit
+// in limit bytes, or text cut at a word boundary if the first sentence alone
+// is too long.
+func openingSentences(text string, limit int) string {
+	if len(text) <= limit {
+		return text
+	}
+
+	end := 0
+	for i := 0; i < limit; i++ {
+		if strings.ContainsRune(".!?", rune(text[i])) && (i+1 == len(text) || text[i+1] == ' ') {
+			end = i + 1
+		}
+	}
+	if end > 0 {
+		return text[:end]
+	}
+
+	cut := strings.LastIndexByte(text[:limit], ' ')
+	if cut <= 0 {
+		cut = limit
+	}
+	return strings.TrimSpace(text[:cut]) + "…"
+}
@@ -0,0 +1,48 @@
+// Package markdown turns the markdown a story is written in back into plain
+// words, for the parts of the primer that read or picture a page rather than
+// show it.
+package markdown
+
+import "strings"
+
+// markup removes inline emphasis and code marks.
+var markup = strings.NewReplacer("**", "", "__", "", "`", "", "*", "")
+
+// PlainText returns the words of text without markdown: headings, quotes
+// and list markers are removed along with inline emphasis, fenced code
+// blocks are dropped entirely, and each paragraph is joined onto a single
+// line, with paragraphs separated by blank lines.
+func PlainText(text string) string {
+	var paragraphs []string
+	var current []string
+	inCode := false
+
+	flush := func() {
+		if len(current) > 0 {
+			paragraphs = append(paragraphs, strings.Join(current, " "))
+			current = nil
+		}
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inCode = !inCode
+			flush()
+			continue
+		}
+		if inCode {
+			continue
+		}
+
+		trimmed = markup.Replace(strings.TrimLeft(trimmed, "#>-*+ "))
+		if trimmed == "" {
+			flush()
+			continue
+		}
+		current = append(current, strings.Join(strings.Fields(trimmed), " "))
+	}
+	flush()
+
+	return strings.Join(paragraphs, "\n\n")
+}
@@ -0,0 +1,26 @@
+package markdown
+
+import "testing"
+
+func TestPlainText(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"empty", "", ""},
+		{"plain", "The fox ran home.", "The fox ran home."},
+		{"markup", "# The Fox\n\n> A **clever** fox\n- She *smiled* at `owl`.", "The Fox\n\nA clever fox She smiled at owl."},
+		{"paragraphs", "A fox\nran home.\n\n\nThen she slept.", "A fox ran home.\n\nThen she slept."},
+		{"fenced code", "Before.\n```go\nfmt.Println(\"hi\")\n```\nAfter.", "Before.\n\nAfter."},
+		{"tilde fence", "Before.\n~~~\ncode\n~~~\nAfter.", "Before.\n\nAfter."},
+		{"unclosed fence", "Before.\n```\ncode", "Before."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PlainText(tt.text); got != tt.want {
+				t.Errorf("PlainText(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Account links a user to a way of signing in, such as an OAuth provider.
+type Account struct {
+	ID                string  `json:"id"`
+	UserID            string  `json:"user_id"`
+	Type              string  `json:"type"`     // such as "oauth" or "email"
+	Provider          string  `json:"provider"` // such as "google"
+	ProviderAccountID string  `json:"provider_account_id"`
+	RefreshToken      *string `json:"-"`
+	AccessToken       *string `json:"-"`
+	ExpiresAt         *int64  `json:"expires_at"`
+	TokenType         *string `json:"token_type"`
+	Scope             *string `json:"scope"`
+	IDToken           *string `json:"-"`
+	SessionState      *string `json:"session_state"`
+	CreatedAt         int64   `json:"created_at"`
+	UpdatedAt         int64   `json:"updated_at"`
+}
+
+// NewAccount creates a new Account with a generated UUID and current timestamps.
+func NewAccount(userID, accountType, provider, providerAccountID string) *Account {
+	now := time.Now().Unix()
+	return &Account{
+		ID:                uuid.New().String(),
+		UserID:            userID,
+		Type:              accountType,
+		Provider:          provider,
+		ProviderAccountID: providerAccountID,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+}
+
+// Session is a signed-in user. Only a hash of the token handed to the
+// client is stored.
+type Session struct {
+	ID           string `json:"id"`
+	SessionToken string `json:"-"` // SHA-256 of the client's token
+	UserID       string `json:"user_id"`
+	Expires      int64  `json:"expires"`
+	CreatedAt    int64  `json:"created_at"`
+	UpdatedAt    int64  `json:"updated_at"`
+}
+
+// NewSession creates a Session for a token hash that expires at the given time.
+func NewSession(userID, tokenHash string, expires time.Time) *Session {
+	now := time.Now().Unix()
+	return &Session{
+		ID:           uuid.New().String(),
+		SessionToken: tokenHash,
+		UserID:       userID,
+		Expires:      expires.Unix(),
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+}
+
+// Expired reports whether the session has expired at now.
+func (s *Session) Expired(now time.Time) bool {
+	return now.Unix() >= s.Expires
+}
+
+// VerificationToken is a one-time sign-in link sent to an identifier, such
+// as an email address. Only a hash of the token in the link is stored.
+type VerificationToken struct {
+	Identifier string `json:"identifier"`
+	Token      string `json:"-"` // SHA-256 of the token in the link
+	Expires    int64  `json:"expires"`
+}
+
+// Expired reports whether the token has expired at now.
+func (t *VerificationToken) Expired(now time.Time) bool {
+	return now.Unix() >= t.Expires
+}
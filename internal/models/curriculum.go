@@ -0,0 +1,70 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Subject is an area of learning, such as math or colors.
+type Subject struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	CreatedAt   int64  `json:"created_at"`
+}
+
+// NewSubject creates a new Subject with a generated UUID and the current timestamp.
+func NewSubject(name, description string) *Subject {
+	return &Subject{
+		ID:          uuid.New().String(),
+		Name:        name,
+		Description: description,
+		CreatedAt:   time.Now().Unix(),
+	}
+}
+
+// Objective is something a child can learn from a story, such as
+// "count to 10" or "primary colors", within a subject.
+type Objective struct {
+	ID          string `json:"id"`
+	SubjectID   string `json:"subject_id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	CreatedAt   int64  `json:"created_at"`
+}
+
+// NewObjective creates a new Objective with a generated UUID and the current timestamp.
+func NewObjective(subjectID, title, description string) *Objective {
+	return &Objective{
+		ID:          uuid.New().String(),
+		SubjectID:   subjectID,
+		Title:       title,
+		Description: description,
+		CreatedAt:   time.Now().Unix(),
+	}
+}
+
+// ObjectiveProgress is how much of a story has worked on one of its objectives.
+type ObjectiveProgress struct {
+	Objective
+	Pages int64 `json:"pages"` // pages that touched the objective
+}
+
+// MatchObjectives returns the objectives whose titles are in titles,
+// ignoring case and surrounding space, in the order of objectives.
+func MatchObjectives(objectives []Objective, titles []string) []Objective {
+	wanted := make(map[string]bool, len(titles))
+	for _, title := range titles {
+		wanted[strings.ToLower(strings.TrimSpace(title))] = true
+	}
+
+	var matched []Objective
+	for _, objective := range objectives {
+		if wanted[strings.ToLower(strings.TrimSpace(objective.Title))] {
+			matched = append(matched, objective)
+		}
+	}
+	return matched
+}
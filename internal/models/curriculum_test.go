@@ -0,0 +1,36 @@
+package models
+
+import (
+	"testing"
+)
+
+func TestNewObjective(t *testing.T) {
+	subject := NewSubject("Math", "Numbers and counting")
+	objective := NewObjective(subject.ID, "Count to 10", "")
+
+	if subject.ID == "" || objective.ID == "" {
+		t.Error("expected non-empty IDs")
+	}
+	if objective.SubjectID != subject.ID {
+		t.Errorf("SubjectID = %q, want %q", objective.SubjectID, subject.ID)
+	}
+	if objective.CreatedAt == 0 {
+		t.Error("expected non-zero CreatedAt")
+	}
+}
+
+func TestMatchObjectives(t *testing.T) {
+	objectives := []Objective{
+		{ID: "count", Title: "Count to 10"},
+		{ID: "colors", Title: "Primary colors"},
+		{ID: "shapes", Title: "Shapes"},
+	}
+
+	matched := MatchObjectives(objectives, []string{" primary COLORS ", "count to 10", "Fractions"})
+	if len(matched) != 2 || matched[0].ID != "count" || matched[1].ID != "colors" {
+		t.Errorf("MatchObjectives() = %+v", matched)
+	}
+	if matched := MatchObjectives(objectives, nil); len(matched) != 0 {
+		t.Errorf("MatchObjectives(nil) = %+v, want none", matched)
+	}
+}
@@ -0,0 +1,92 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Household is a family: the guardians who look after it and the children
+// who read in it.
+type Household struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	CreatedAt int64  `json:"created_at"`
+	UpdatedAt int64  `json:"updated_at"`
+}
+
+// NewHousehold creates a new Household with a generated UUID and current timestamps.
+func NewHousehold(name string) *Household {
+	now := time.Now().Unix()
+	return &Household{
+		ID:        uuid.New().String(),
+		Name:      name,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// Role is what a user may do in their household.
+type Role string
+
+const (
+	RoleChild    Role = "child"    // reads stories
+	RoleGuardian Role = "guardian" // also deletes stories, changes settings and sets limits
+)
+
+// ParseRole returns the role with the given name.
+func ParseRole(name string) (Role, error) {
+	switch Role(name) {
+	case RoleChild, RoleGuardian:
+		return Role(name), nil
+	}
+	return "", fmt.Errorf("unknown role %q", name)
+}
+
+// MinPINLength is the fewest digits a guardian PIN can have.
+const MinPINLength = 4
+
+// ErrInvalidPIN is returned when a PIN is too short or isn't all digits.
+var ErrInvalidPIN = errors.New("PIN must be at least 4 digits")
+
+// IsGuardian reports whether the user looks after a household. Users
+// without a role are children.
+func (u *User) IsGuardian() bool {
+	return u.Role == RoleGuardian
+}
+
+// HasPIN reports whether the user has a PIN set.
+func (u *User) HasPIN() bool {
+	return u.PINHash != nil && *u.PINHash != ""
+}
+
+// SetPIN stores a bcrypt hash of a PIN of at least MinPINLength digits.
+func (u *User) SetPIN(pin string) error {
+	if len(pin) < MinPINLength {
+		return ErrInvalidPIN
+	}
+	for _, r := range pin {
+		if !unicode.IsDigit(r) {
+			return ErrInvalidPIN
+		}
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(pin), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("hash PIN: %w", err)
+	}
+	encoded := string(hash)
+	u.PINHash = &encoded
+	return nil
+}
+
+// CheckPIN reports whether pin is the user's PIN.
+func (u *User) CheckPIN(pin string) bool {
+	if !u.HasPIN() {
+		return false
+	}
+	return bcrypt.CompareHashAndPassword([]byte(*u.PINHash), []byte(pin)) == nil
+}
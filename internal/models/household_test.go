@@ -0,0 +1,50 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestUserPIN(t *testing.T) {
+	user := NewUser("Grown-up", "grownup@example.com")
+	if user.HasPIN() || user.CheckPIN("") {
+		t.Fatal("a new user should have no PIN")
+	}
+
+	for _, pin := range []string{"123", "12a4", ""} {
+		if err := user.SetPIN(pin); !errors.Is(err, ErrInvalidPIN) {
+			t.Errorf("SetPIN(%q) error = %v, want ErrInvalidPIN", pin, err)
+		}
+	}
+
+	if err := user.SetPIN("2468"); err != nil {
+		t.Fatalf("SetPIN() error = %v", err)
+	}
+	if !user.HasPIN() || strings.Contains(*user.PINHash, "2468") {
+		t.Fatalf("PINHash = %v, want a hash", user.PINHash)
+	}
+	if !user.CheckPIN("2468") || user.CheckPIN("2469") || user.CheckPIN("") {
+		t.Error("CheckPIN should only accept the PIN that was set")
+	}
+}
+
+func TestUserRole(t *testing.T) {
+	user := NewUser("Nell", "nell@example.com")
+	if user.IsGuardian() || user.Role != RoleChild {
+		t.Errorf("Role = %q, want a child by default", user.Role)
+	}
+	user.Role = ""
+	if user.IsGuardian() {
+		t.Error("a user without a role should be a child")
+	}
+
+	user.Role = RoleGuardian
+	if !user.IsGuardian() || user.ValidateProfile() != nil {
+		t.Error("guardian should be a valid role")
+	}
+	user.Role = "admin"
+	if err := user.ValidateProfile(); err == nil {
+		t.Error("ValidateProfile() should reject an unknown role")
+	}
+}
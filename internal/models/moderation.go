@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ModerationFlag records a prompt or completion that content moderation
+// objected to, so a parent can review it.
+type ModerationFlag struct {
+	ID           string   `json:"id"`
+	UserID       string   `json:"user_id"`
+	StoryID      *string  `json:"story_id"`
+	Source       string   `json:"source"` // "prompt" or "completion"
+	Action       string   `json:"action"` // "flag", "rewrite" or "block"
+	Categories   []string `json:"categories"`
+	OriginalText string   `json:"original_text"`
+	FinalText    string   `json:"final_text"` // what was used instead; empty if blocked
+	ReviewedAt   *int64   `json:"reviewed_at"`
+	CreatedAt    int64    `json:"created_at"`
+}
+
+// NewModerationFlag creates a new ModerationFlag with a generated UUID and
+// the current timestamp.
+func NewModerationFlag(userID, source, action string, categories []string, original, final string) *ModerationFlag {
+	return &ModerationFlag{
+		ID:           uuid.New().String(),
+		UserID:       userID,
+		Source:       source,
+		Action:       action,
+		Categories:   categories,
+		OriginalText: original,
+		FinalText:    final,
+		CreatedAt:    time.Now().Unix(),
+	}
+}
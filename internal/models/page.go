@@ -16,8 +16,24 @@ type Page struct {
 	Summary    string  `json:"summary"`
 	ImagePath  *string `json:"image_path"`
 	AudioPath  *string `json:"audio_path"`
-	CreatedAt  int64   `json:"created_at"`
-	UpdatedAt  int64   `json:"updated_at"`
+	// PromptTemplate is the "name@version" of the system prompt template
+	// that produced the completion; empty if unknown.
+	PromptTemplate string `json:"prompt_template"`
+	// Choices are the suggested next actions offered with the completion.
+	Choices []string `json:"choices"`
+	// Readability is how hard the completion is to read; nil for pages
+	// saved before it was measured.
+	Readability *Readability `json:"readability"`
+	CreatedAt   int64        `json:"created_at"`
+	UpdatedAt   int64        `json:"updated_at"`
+}
+
+// Readability scores a page's text.
+type Readability struct {
+	GradeLevel       float64 `json:"grade_level"`  // Flesch-Kincaid grade level
+	ReadingEase      float64 `json:"reading_ease"` // Flesch reading ease, higher is easier
+	WordsPerSentence float64 `json:"words_per_sentence"`
+	RareWordRatio    float64 `json:"rare_word_ratio"` // share of words off the common word list
 }
 
 // NewPage creates a new Page with a generated UUID and current timestamps.
@@ -0,0 +1,113 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ContentPreferences are a parent's wishes about what goes into their
+// child's stories: story themes to bring in often, topics to leave out
+// entirely, and sensitivities such as loud noises or death. Each entry is a
+// short phrase, such as "dinosaurs" or "scary monsters".
+//
+// Story themes are not the child's Interests, nor their colour Theme.
+// Interests are part of the child's profile, what they love, and are only
+// worked in where they fit; story themes are the grown-ups' wishes and are
+// brought in often, whatever the story.
+type ContentPreferences struct {
+	StoryThemes   []string
+	Banned        []string
+	Sensitivities []string
+}
+
+// preferenceLabels name the lists in the form ParseContentPreferences reads.
+var preferenceLabels = []string{"story themes", "avoid", "sensitive"}
+
+// ContentPreferences returns the user's content preferences.
+func (u *User) ContentPreferences() ContentPreferences {
+	return ContentPreferences{
+		StoryThemes:   u.StoryThemes,
+		Banned:        u.BannedTopics,
+		Sensitivities: u.Sensitivities,
+	}
+}
+
+// SetContentPreferences replaces the user's content preferences.
+func (u *User) SetContentPreferences(p ContentPreferences) {
+	u.StoryThemes, u.BannedTopics, u.Sensitivities = p.StoryThemes, p.Banned, p.Sensitivities
+}
+
+// ParseContentPreferences reads preferences written as labelled,
+// comma-separated lists separated by semicolons, any of them optional, such
+// as "story themes: dinosaurs, space; avoid: scary monsters; sensitive: death".
+// An empty string or "none" means no preferences.
+func ParseContentPreferences(text string) (ContentPreferences, error) {
+	var p ContentPreferences
+	text = strings.TrimSpace(text)
+	if text == "" || strings.EqualFold(text, "none") {
+		return p, nil
+	}
+
+	for _, part := range strings.Split(text, ";") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		label, list, ok := strings.Cut(part, ":")
+		if !ok {
+			return ContentPreferences{}, fmt.Errorf("%q has no label like story themes:, avoid: or sensitive:", strings.TrimSpace(part))
+		}
+		topics := splitTopics(list)
+		switch strings.ToLower(strings.TrimSpace(label)) {
+		case "story themes":
+			p.StoryThemes = topics
+		case "avoid":
+			p.Banned = topics
+		case "sensitive":
+			p.Sensitivities = topics
+		default:
+			return ContentPreferences{}, fmt.Errorf("unknown label %q; use story themes:, avoid: or sensitive:", strings.TrimSpace(label))
+		}
+	}
+	return p, nil
+}
+
+// splitTopics splits a comma-separated list, dropping blanks and repeats.
+func splitTopics(list string) []string {
+	var topics []string
+	seen := make(map[string]bool)
+	for _, topic := range strings.Split(list, ",") {
+		topic = strings.Join(strings.Fields(topic), " ")
+		key := strings.ToLower(topic)
+		if topic == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		topics = append(topics, topic)
+	}
+	return topics
+}
+
+// String writes the preferences in the form ParseContentPreferences reads,
+// or "none".
+func (p ContentPreferences) String() string {
+	var parts []string
+	for i, topics := range [][]string{p.StoryThemes, p.Banned, p.Sensitivities} {
+		if len(topics) > 0 {
+			parts = append(parts, preferenceLabels[i]+": "+strings.Join(topics, ", "))
+		}
+	}
+	if len(parts) == 0 {
+		return "none"
+	}
+	return strings.Join(parts, "; ")
+}
+
+// IsZero reports whether there are no preferences at all.
+func (p ContentPreferences) IsZero() bool {
+	return len(p.StoryThemes) == 0 && len(p.Banned) == 0 && len(p.Sensitivities) == 0
+}
+
+// Restricted reports whether anything has to be kept out of stories.
+func (p ContentPreferences) Restricted() bool {
+	return len(p.Banned) > 0 || len(p.Sensitivities) > 0
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseContentPreferences(t *testing.T) {
+	tests := []struct {
+		text string
+		want ContentPreferences
+	}{
+		{"", ContentPreferences{}},
+		{"none", ContentPreferences{}},
+		{"story themes: dinosaurs, space", ContentPreferences{StoryThemes: []string{"dinosaurs", "space"}}},
+		{"Avoid: scary  monsters, Scary Monsters,", ContentPreferences{Banned: []string{"scary monsters"}}},
+		{
+			"story themes: dinosaurs; avoid: scary monsters; sensitive: loud noises, death",
+			ContentPreferences{
+				StoryThemes:   []string{"dinosaurs"},
+				Banned:        []string{"scary monsters"},
+				Sensitivities: []string{"loud noises", "death"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		got, err := ParseContentPreferences(tt.text)
+		if err != nil {
+			t.Errorf("ParseContentPreferences(%q) error = %v", tt.text, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseContentPreferences(%q) = %+v, want %+v", tt.text, got, tt.want)
+		}
+		if again, _ := ParseContentPreferences(got.String()); !reflect.DeepEqual(again, got) {
+			t.Errorf("%q doesn't read back: %+v", got.String(), again)
+		}
+	}
+
+	for _, text := range []string{"dinosaurs", "likes: cats"} {
+		if _, err := ParseContentPreferences(text); err == nil {
+			t.Errorf("ParseContentPreferences(%q) should fail", text)
+		}
+	}
+}
+
+func TestUserContentPreferences(t *testing.T) {
+	user := NewUser("Ada", "ada@example.com")
+	if !user.ContentPreferences().IsZero() {
+		t.Error("a new user shouldn't have preferences")
+	}
+	prefs := ContentPreferences{StoryThemes: []string{"space"}, Sensitivities: []string{"death"}}
+	user.SetContentPreferences(prefs)
+	if got := user.ContentPreferences(); !reflect.DeepEqual(got, prefs) {
+		t.Errorf("ContentPreferences() = %+v, want %+v", got, prefs)
+	}
+	if !prefs.Restricted() || (ContentPreferences{StoryThemes: []string{"space"}}).Restricted() {
+		t.Error("only banned topics and sensitivities restrict stories")
+	}
+}
@@ -0,0 +1,113 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// AgeBand groups children whose stories need similar vocabulary and length.
+type AgeBand string
+
+const (
+	AgeBandToddler     AgeBand = "toddler"      // ages 2-3
+	AgeBandPreschool   AgeBand = "preschool"    // ages 4-5
+	AgeBandEarlyReader AgeBand = "early-reader" // ages 6-7
+	AgeBandReader      AgeBand = "reader"       // ages 8 and up
+)
+
+// AgeBands returns the age bands from youngest to oldest.
+func AgeBands() []AgeBand {
+	return []AgeBand{AgeBandToddler, AgeBandPreschool, AgeBandEarlyReader, AgeBandReader}
+}
+
+// ParseAgeBand returns the age band with the given name.
+func ParseAgeBand(name string) (AgeBand, error) {
+	for _, band := range AgeBands() {
+		if string(band) == name {
+			return band, nil
+		}
+	}
+	return "", fmt.Errorf("unknown age band %q", name)
+}
+
+// AgeBandFor returns the age band for a child of the given age in years.
+func AgeBandFor(age int) AgeBand {
+	switch {
+	case age <= 3:
+		return AgeBandToddler
+	case age <= 5:
+		return AgeBandPreschool
+	case age <= 7:
+		return AgeBandEarlyReader
+	default:
+		return AgeBandReader
+	}
+}
+
+// ReadingLevels lists the reading levels a child profile can have, from
+// children who are read to, to confident independent readers.
+var ReadingLevels = []string{"pre-reader", "beginning", "developing", "fluent"}
+
+// birthdateLayout is the format of User.Birthdate.
+const birthdateLayout = "2006-01-02"
+
+// Age returns the user's age in whole years on the given day, and false if
+// the birthdate is missing or malformed.
+func (u *User) Age(now time.Time) (int, bool) {
+	if u.Birthdate == nil {
+		return 0, false
+	}
+	born, err := time.Parse(birthdateLayout, *u.Birthdate)
+	if err != nil {
+		return 0, false
+	}
+
+	age := now.Year() - born.Year()
+	if now.Month() < born.Month() || (now.Month() == born.Month() && now.Day() < born.Day()) {
+		age--
+	}
+	return age, age >= 0
+}
+
+// Band returns the user's age band: the one set on the profile, or else the
+// one for their age. It is empty when neither is known.
+func (u *User) Band(now time.Time) AgeBand {
+	if u.AgeBand != nil {
+		if band, err := ParseAgeBand(*u.AgeBand); err == nil {
+			return band
+		}
+	}
+	if age, ok := u.Age(now); ok {
+		return AgeBandFor(age)
+	}
+	return ""
+}
+
+// ValidateProfile checks the child profile fields.
+func (u *User) ValidateProfile() error {
+	if u.Birthdate != nil {
+		if _, err := time.Parse(birthdateLayout, *u.Birthdate); err != nil {
+			return fmt.Errorf("birthdate %q is not a YYYY-MM-DD date", *u.Birthdate)
+		}
+	}
+	if u.AgeBand != nil {
+		if _, err := ParseAgeBand(*u.AgeBand); err != nil {
+			return err
+		}
+	}
+	if u.ReadingLevel != nil {
+		known := false
+		for _, level := range ReadingLevels {
+			known = known || level == *u.ReadingLevel
+		}
+		if !known {
+			return fmt.Errorf("unknown reading level %q", *u.ReadingLevel)
+		}
+	}
+	if u.Role != "" {
+		if _, err := ParseRole(string(u.Role)); err != nil {
+			return err
+		}
+	}
+	return u.ScreenTime().Validate()
+}
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// QuizAttempt is one run through a comprehension quiz, with the questions
+// as they were asked so a parent can review the answers later.
+type QuizAttempt struct {
+	ID        string       `json:"id"`
+	UserID    string       `json:"user_id"`
+	StoryID   *string      `json:"story_id"` // nil once the story is deleted
+	Score     int          `json:"score"`    // questions answered correctly
+	Total     int          `json:"total"`    // questions answered
+	Answers   []QuizAnswer `json:"answers"`
+	CreatedAt int64        `json:"created_at"`
+}
+
+// QuizAnswer is a question of a quiz and the option the child chose.
+type QuizAnswer struct {
+	Question string   `json:"question"`
+	Kind     string   `json:"kind"` // "text" or "picture"
+	Options  []string `json:"options"`
+	Answer   int      `json:"answer"` // index of the right option
+	Chosen   int      `json:"chosen"` // index of the option chosen
+}
+
+// Correct reports whether the right option was chosen.
+func (a QuizAnswer) Correct() bool {
+	return a.Chosen == a.Answer
+}
+
+// NewQuizAttempt creates a new QuizAttempt for a story with a generated UUID
+// and the current timestamp.
+func NewQuizAttempt(userID, storyID string) *QuizAttempt {
+	return &QuizAttempt{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		StoryID:   &storyID,
+		CreatedAt: time.Now().Unix(),
+	}
+}
+
+// AddAnswer records an answered question and updates the score.
+func (a *QuizAttempt) AddAnswer(answer QuizAnswer) {
+	a.Answers = append(a.Answers, answer)
+	a.Total++
+	if answer.Correct() {
+		a.Score++
+	}
+}
+
+// Percent returns the score as a percentage, 0 if nothing was answered.
+func (a *QuizAttempt) Percent() int {
+	if a.Total == 0 {
+		return 0
+	}
+	return a.Score * 100 / a.Total
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"testing"
+)
+
+func TestQuizAttemptScore(t *testing.T) {
+	attempt := NewQuizAttempt("user", "story")
+	if attempt.ID == "" || attempt.StoryID == nil || *attempt.StoryID != "story" {
+		t.Errorf("unexpected attempt: %+v", attempt)
+	}
+	if attempt.Percent() != 0 {
+		t.Errorf("Percent() of an empty attempt = %d, want 0", attempt.Percent())
+	}
+
+	attempt.AddAnswer(QuizAnswer{Question: "Who?", Options: []string{"Fox", "Owl"}, Answer: 0, Chosen: 0})
+	attempt.AddAnswer(QuizAnswer{Question: "Where?", Options: []string{"Woods", "Sea"}, Answer: 1, Chosen: 0})
+	attempt.AddAnswer(QuizAnswer{Question: "When?", Options: []string{"Day", "Night"}, Answer: 1, Chosen: 1})
+
+	if attempt.Score != 2 || attempt.Total != 3 {
+		t.Errorf("score = %d/%d, want 2/3", attempt.Score, attempt.Total)
+	}
+	if attempt.Percent() != 66 {
+		t.Errorf("Percent() = %d, want 66", attempt.Percent())
+	}
+	if attempt.Answers[1].Correct() {
+		t.Error("a wrong answer was marked correct")
+	}
+}
@@ -0,0 +1,108 @@
+package models
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Recall grades how well a child remembered a word when reviewing it, on
+// the SM-2 scale of 0 to 5. Grades below RecallHard count as forgotten.
+type Recall int
+
+const (
+	RecallForgot Recall = 1
+	RecallHard   Recall = 3
+	RecallGood   Recall = 4
+	RecallEasy   Recall = 5
+)
+
+// Remembered reports whether the grade counts as a successful recall.
+func (r Recall) Remembered() bool {
+	return r >= RecallHard
+}
+
+// String returns the grade as shown to the child.
+func (r Recall) String() string {
+	switch {
+	case r >= RecallEasy:
+		return "easy"
+	case r >= RecallGood:
+		return "remembered"
+	case r >= RecallHard:
+		return "tricky"
+	default:
+		return "forgot"
+	}
+}
+
+const (
+	// DefaultEase is the ease of a word that has never been reviewed.
+	DefaultEase = 2.5
+	// MinEase keeps hard words from being reviewed ever more often.
+	MinEase = 1.3
+	// FirstReview is how long after a word is first seen it is due for review.
+	FirstReview = 24 * time.Hour
+)
+
+// Schedule updates a word's review schedule after a review, with the SM-2
+// algorithm: a remembered word waits one day, then six, then its previous
+// interval times its ease; a forgotten one starts again from one day. The
+// ease goes up for easy recalls and down for hard ones.
+func (w *Word) Schedule(recall Recall, now time.Time) {
+	if w.Ease < MinEase {
+		w.Ease = DefaultEase
+	}
+
+	if recall.Remembered() {
+		switch w.Repetitions {
+		case 0:
+			w.Interval = 1
+		case 1:
+			w.Interval = 6
+		default:
+			w.Interval = int64(math.Round(float64(w.Interval) * w.Ease))
+		}
+		w.Repetitions++
+	} else {
+		w.Repetitions = 0
+		w.Interval = 1
+	}
+
+	q := float64(5 - recall)
+	w.Ease = math.Max(MinEase, w.Ease+0.1-q*(0.08+q*0.02))
+
+	reviewed := now.Unix()
+	w.ReviewedAt = &reviewed
+	w.DueAt = now.Add(time.Duration(w.Interval) * 24 * time.Hour).Unix()
+}
+
+// Due reports whether the word should be reviewed.
+func (w Word) Due(now time.Time) bool {
+	return w.DueAt <= now.Unix()
+}
+
+// WordReview is the outcome of reviewing a word, kept so a child's progress
+// can be followed over time.
+type WordReview struct {
+	ID        string `json:"id"`
+	UserID    string `json:"user_id"`
+	Word      string `json:"word"`
+	Recall    Recall `json:"recall"`
+	Interval  int64  `json:"interval"` // days until the next review, as scheduled
+	CreatedAt int64  `json:"created_at"`
+}
+
+// NewWordReview records the outcome of a review of a word that has just been
+// rescheduled.
+func NewWordReview(word Word, recall Recall) *WordReview {
+	return &WordReview{
+		ID:        uuid.New().String(),
+		UserID:    word.UserID,
+		Word:      word.Word,
+		Recall:    recall,
+		Interval:  word.Interval,
+		CreatedAt: time.Now().Unix(),
+	}
+}
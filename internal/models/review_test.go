@@ -0,0 +1,63 @@
+package models
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestWordSchedule(t *testing.T) {
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	word := Word{UserID: "user", Word: "enormous"}
+
+	steps := []struct {
+		recall   Recall
+		interval int64
+		reps     int64
+		ease     float64
+	}{
+		{RecallGood, 1, 1, 2.5},
+		{RecallGood, 6, 2, 2.5},
+		{RecallEasy, 15, 3, 2.6},
+		{RecallHard, 39, 4, 2.46},
+		{RecallForgot, 1, 0, 1.92},
+		{RecallGood, 1, 1, 1.92},
+	}
+	for i, step := range steps {
+		word.Schedule(step.recall, now)
+		if word.Interval != step.interval || word.Repetitions != step.reps || math.Abs(word.Ease-step.ease) > 1e-9 {
+			t.Fatalf("step %d (%s): interval %d, repetitions %d, ease %.2f; want %d, %d, %.2f",
+				i, step.recall, word.Interval, word.Repetitions, word.Ease, step.interval, step.reps, step.ease)
+		}
+		if want := now.AddDate(0, 0, int(step.interval)).Unix(); word.DueAt != want {
+			t.Errorf("step %d: due %d, want %d", i, word.DueAt, want)
+		}
+		if word.ReviewedAt == nil || *word.ReviewedAt != now.Unix() {
+			t.Errorf("step %d: reviewed at %v", i, word.ReviewedAt)
+		}
+	}
+
+	if word.Due(now) || !word.Due(now.AddDate(0, 0, 1)) {
+		t.Error("word should be due a day after a review scheduled for one day")
+	}
+}
+
+func TestWordScheduleMinEase(t *testing.T) {
+	word := Word{Ease: DefaultEase}
+	for range 10 {
+		word.Schedule(RecallForgot, time.Now())
+	}
+	if word.Ease != MinEase {
+		t.Errorf("ease = %v, want the minimum %v", word.Ease, MinEase)
+	}
+}
+
+func TestNewWordReview(t *testing.T) {
+	word := Word{UserID: "user", Word: "enormous"}
+	word.Schedule(RecallGood, time.Now())
+
+	review := NewWordReview(word, RecallGood)
+	if review.ID == "" || review.UserID != "user" || review.Word != "enormous" || review.Interval != 1 || review.Recall != RecallGood {
+		t.Errorf("review = %+v", review)
+	}
+}
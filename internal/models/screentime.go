@@ -0,0 +1,193 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ScreenTime is a child's screen-time allowance: how many minutes a day they
+// may spend on stories, and the hours of the day they may do it. Zero values
+// mean no limit.
+type ScreenTime struct {
+	DailyMinutes int64
+	From, Until  string // "HH:MM"; both empty for any hour
+}
+
+// clockLayout is the format of ScreenTime.From and ScreenTime.Until.
+const clockLayout = "15:04"
+
+// ScreenTime returns the user's screen-time allowance.
+func (u *User) ScreenTime() ScreenTime {
+	var s ScreenTime
+	if u.DailyMinutes != nil {
+		s.DailyMinutes = *u.DailyMinutes
+	}
+	if u.AllowedFrom != nil {
+		s.From = *u.AllowedFrom
+	}
+	if u.AllowedUntil != nil {
+		s.Until = *u.AllowedUntil
+	}
+	return s
+}
+
+// SetScreenTime replaces the user's screen-time allowance.
+func (u *User) SetScreenTime(s ScreenTime) {
+	u.DailyMinutes, u.AllowedFrom, u.AllowedUntil = nil, nil, nil
+	if s.DailyMinutes > 0 {
+		minutes := s.DailyMinutes
+		u.DailyMinutes = &minutes
+	}
+	if s.From != "" {
+		from, until := s.From, s.Until
+		u.AllowedFrom, u.AllowedUntil = &from, &until
+	}
+}
+
+// ParseScreenTime reads an allowance written as minutes a day and allowed
+// hours, either or both, such as "45m 07:00-19:30". An empty string or
+// "none" means no limit.
+func ParseScreenTime(text string) (ScreenTime, error) {
+	var s ScreenTime
+	fields := strings.FieldsFunc(text, func(r rune) bool { return r == ' ' || r == ',' })
+	if len(fields) == 1 && strings.EqualFold(fields[0], "none") {
+		return s, nil
+	}
+
+	for _, field := range fields {
+		if from, until, ok := strings.Cut(field, "-"); ok {
+			s.From, s.Until = from, until
+			continue
+		}
+		minutes, err := strconv.ParseInt(strings.TrimSuffix(strings.TrimSuffix(field, "min"), "m"), 10, 64)
+		if err != nil || minutes <= 0 {
+			return ScreenTime{}, fmt.Errorf("%q is neither minutes nor hours like 07:00-19:30", field)
+		}
+		s.DailyMinutes = minutes
+	}
+	return s, s.Validate()
+}
+
+// String writes the allowance in the form ParseScreenTime reads, or "none".
+func (s ScreenTime) String() string {
+	var parts []string
+	if s.DailyMinutes > 0 {
+		parts = append(parts, fmt.Sprintf("%dm", s.DailyMinutes))
+	}
+	if s.From != "" {
+		parts = append(parts, s.From+"-"+s.Until)
+	}
+	if len(parts) == 0 {
+		return "none"
+	}
+	return strings.Join(parts, " ")
+}
+
+// Validate checks that the allowed hours are HH:MM times.
+func (s ScreenTime) Validate() error {
+	if s.DailyMinutes < 0 {
+		return fmt.Errorf("daily minutes %d is negative", s.DailyMinutes)
+	}
+	if s.From == "" && s.Until == "" {
+		return nil
+	}
+	for _, clock := range []string{s.From, s.Until} {
+		if _, err := time.Parse(clockLayout, clock); err != nil {
+			return fmt.Errorf("allowed hour %q is not an HH:MM time", clock)
+		}
+	}
+	return nil
+}
+
+// Limited reports whether there is any limit at all.
+func (s ScreenTime) Limited() bool {
+	return s.DailyMinutes > 0 || s.From != ""
+}
+
+// Allowed reports whether now is within the allowed hours. Hours that end
+// before they start run past midnight, such as "18:00-08:00".
+func (s ScreenTime) Allowed(now time.Time) bool {
+	if s.From == "" {
+		return true
+	}
+	from, until, minute := clockMinute(s.From), clockMinute(s.Until), now.Hour()*60+now.Minute()
+	switch {
+	case from == until:
+		return true
+	case from < until:
+		return minute >= from && minute < until
+	default:
+		return minute >= from || minute < until
+	}
+}
+
+// Deadline returns when the child has to stop, given the time they have
+// already used today: when their minutes run out or the allowed hours end,
+// whichever comes first. It is the zero time when there is no limit, and
+// not after now when they can't start at all.
+func (s ScreenTime) Deadline(now time.Time, used time.Duration) time.Time {
+	var deadline time.Time
+	if s.DailyMinutes > 0 {
+		deadline = now.Add(time.Duration(s.DailyMinutes)*time.Minute - used)
+	}
+	if s.From != "" && s.From != s.Until {
+		if !s.Allowed(now) {
+			return now
+		}
+		end := StartOfDay(now).Add(time.Duration(clockMinute(s.Until)) * time.Minute)
+		if !end.After(now) {
+			end = end.AddDate(0, 0, 1)
+		}
+		if deadline.IsZero() || end.Before(deadline) {
+			deadline = end
+		}
+	}
+	return deadline
+}
+
+// StartOfDay returns midnight at the start of now's day, in now's location.
+func StartOfDay(now time.Time) time.Time {
+	year, month, day := now.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, now.Location())
+}
+
+// clockMinute returns the minute of the day of a validated HH:MM time.
+func clockMinute(clock string) int {
+	t, _ := time.Parse(clockLayout, clock)
+	return t.Hour()*60 + t.Minute()
+}
+
+// Why a screen session ended.
+const (
+	SessionEndedLeft       = "left"        // the child stopped
+	SessionEndedLimit      = "limit"       // the daily minutes ran out
+	SessionEndedQuietHours = "quiet-hours" // the allowed hours ended
+)
+
+// ScreenSession is one sitting of a child with the primer, from choosing
+// their name until they stop or are locked out.
+type ScreenSession struct {
+	ID         string `json:"id"`
+	UserID     string `json:"user_id"`
+	Override   bool   `json:"override"`   // unlocked with the parent PIN past the limit
+	EndReason  string `json:"end_reason"` // empty while the session is open
+	StartedAt  int64  `json:"started_at"`
+	LastSeenAt int64  `json:"last_seen_at"` // updated while open, so a crash loses little time
+	EndedAt    *int64 `json:"ended_at"`
+}
+
+// NewScreenSession creates a ScreenSession starting now.
+func NewScreenSession(userID string, override bool) *ScreenSession {
+	now := time.Now().Unix()
+	return &ScreenSession{
+		ID:         uuid.New().String(),
+		UserID:     userID,
+		Override:   override,
+		StartedAt:  now,
+		LastSeenAt: now,
+	}
+}
@@ -0,0 +1,114 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseScreenTime(t *testing.T) {
+	tests := []struct {
+		text string
+		want ScreenTime
+	}{
+		{"", ScreenTime{}},
+		{"none", ScreenTime{}},
+		{"45m", ScreenTime{DailyMinutes: 45}},
+		{"30", ScreenTime{DailyMinutes: 30}},
+		{"07:00-19:30", ScreenTime{From: "07:00", Until: "19:30"}},
+		{"45m, 07:00-19:30", ScreenTime{DailyMinutes: 45, From: "07:00", Until: "19:30"}},
+	}
+	for _, tt := range tests {
+		got, err := ParseScreenTime(tt.text)
+		if err != nil {
+			t.Errorf("ParseScreenTime(%q) error = %v", tt.text, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseScreenTime(%q) = %+v, want %+v", tt.text, got, tt.want)
+		}
+		if again, _ := ParseScreenTime(got.String()); again != got {
+			t.Errorf("%q doesn't read back: %+v", got.String(), again)
+		}
+	}
+
+	for _, text := range []string{"lots", "-5m", "7-19", "07:00-", "25:00-26:00"} {
+		if _, err := ParseScreenTime(text); err == nil {
+			t.Errorf("ParseScreenTime(%q) should fail", text)
+		}
+	}
+}
+
+func TestScreenTimeAllowed(t *testing.T) {
+	at := func(hour, minute int) time.Time { return time.Date(2026, 3, 1, hour, minute, 0, 0, time.UTC) }
+	day := ScreenTime{From: "07:00", Until: "19:30"}
+	night := ScreenTime{From: "18:00", Until: "08:00"}
+
+	tests := []struct {
+		s    ScreenTime
+		now  time.Time
+		want bool
+	}{
+		{ScreenTime{DailyMinutes: 30}, at(3, 0), true},
+		{day, at(6, 59), false},
+		{day, at(7, 0), true},
+		{day, at(19, 29), true},
+		{day, at(19, 30), false},
+		{night, at(23, 0), true},
+		{night, at(7, 59), true},
+		{night, at(12, 0), false},
+	}
+	for _, tt := range tests {
+		if got := tt.s.Allowed(tt.now); got != tt.want {
+			t.Errorf("%s at %s: Allowed = %v, want %v", tt.s, tt.now.Format("15:04"), got, tt.want)
+		}
+	}
+}
+
+func TestScreenTimeDeadline(t *testing.T) {
+	now := time.Date(2026, 3, 1, 19, 0, 0, 0, time.UTC)
+
+	if got := (ScreenTime{}).Deadline(now, time.Hour); !got.IsZero() {
+		t.Errorf("no limit: deadline %v, want none", got)
+	}
+	if got := (ScreenTime{DailyMinutes: 45}).Deadline(now, 20*time.Minute); !got.Equal(now.Add(25 * time.Minute)) {
+		t.Errorf("daily minutes: deadline %v", got)
+	}
+	if got := (ScreenTime{DailyMinutes: 45}).Deadline(now, time.Hour); got.After(now) {
+		t.Errorf("minutes used up: deadline %v should have passed", got)
+	}
+	if got := (ScreenTime{DailyMinutes: 45, From: "07:00", Until: "19:15"}).Deadline(now, 0); !got.Equal(now.Add(15 * time.Minute)) {
+		t.Errorf("allowed hours end first: deadline %v", got)
+	}
+	if got := (ScreenTime{From: "18:00", Until: "08:00"}).Deadline(now, 0); !got.Equal(now.Add(13 * time.Hour)) {
+		t.Errorf("overnight hours: deadline %v", got)
+	}
+	if got := (ScreenTime{From: "07:00", Until: "18:00"}).Deadline(now, 0); !got.Equal(now) {
+		t.Errorf("quiet hours: deadline %v, want now", got)
+	}
+}
+
+func TestUserScreenTime(t *testing.T) {
+	var user User
+	if user.ScreenTime().Limited() {
+		t.Error("a new user should have no limits")
+	}
+	want := ScreenTime{DailyMinutes: 30, From: "08:00", Until: "18:00"}
+	user.SetScreenTime(want)
+	if got := user.ScreenTime(); got != want {
+		t.Errorf("ScreenTime() = %+v, want %+v", got, want)
+	}
+	if err := user.ValidateProfile(); err != nil {
+		t.Errorf("ValidateProfile() error = %v", err)
+	}
+
+	from := "8am"
+	user.AllowedFrom = &from
+	if err := user.ValidateProfile(); err == nil {
+		t.Error("ValidateProfile() should reject hours that aren't HH:MM")
+	}
+
+	user.SetScreenTime(ScreenTime{})
+	if user.DailyMinutes != nil || user.AllowedFrom != nil || user.AllowedUntil != nil {
+		t.Error("clearing the limits should clear the fields")
+	}
+}
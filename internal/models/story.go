@@ -2,6 +2,8 @@ package models
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -9,13 +11,17 @@ import (
 
 // Story represents an interactive educational story belonging to a user.
 type Story struct {
-	ID          string `json:"id"`
-	UserID      string `json:"user_id"`
-	Title       string `json:"title"`
-	Summary     string `json:"summary"`
-	CurrentPage int64  `json:"current_page"`
-	CreatedAt   int64  `json:"created_at"`
-	UpdatedAt   int64  `json:"updated_at"`
+	ID       string `json:"id"`
+	UserID   string `json:"user_id"`
+	Title    string `json:"title"`
+	Summary  string `json:"summary"`
+	ArtStyle string `json:"art_style"` // illustration style; empty uses the default
+	// PromptTemplate pins the system prompt template, as "name@version" or
+	// "name" for its latest version; empty uses the built-in template.
+	PromptTemplate string `json:"prompt_template"`
+	CurrentPage    int64  `json:"current_page"`
+	CreatedAt      int64  `json:"created_at"`
+	UpdatedAt      int64  `json:"updated_at"`
 }
 
 // NewStory creates a new Story with a generated UUID and current timestamps.
@@ -40,3 +46,71 @@ func (s *Story) PageCountDisplay() string {
 	}
 	return fmt.Sprintf("%d pages", s.CurrentPage)
 }
+
+// StorySortOrder controls how a list of stories is ordered for display.
+type StorySortOrder int
+
+const (
+	// SortByRecent orders stories by most recent activity first.
+	SortByRecent StorySortOrder = iota
+	// SortByTitle orders stories alphabetically by title.
+	SortByTitle
+	// SortByLength orders stories by page count, longest first.
+	SortByLength
+)
+
+// String returns a display label for the sort order.
+func (o StorySortOrder) String() string {
+	switch o {
+	case SortByTitle:
+		return "title"
+	case SortByLength:
+		return "length"
+	default:
+		return "recent"
+	}
+}
+
+// Next returns the sort order that follows o, wrapping around.
+func (o StorySortOrder) Next() StorySortOrder {
+	return (o + 1) % 3
+}
+
+// SortStories sorts stories in place according to the given order.
+func SortStories(stories []Story, order StorySortOrder) {
+	sort.SliceStable(stories, func(i, j int) bool {
+		a, b := stories[i], stories[j]
+		switch order {
+		case SortByTitle:
+			return strings.ToLower(a.Title) < strings.ToLower(b.Title)
+		case SortByLength:
+			if a.CurrentPage != b.CurrentPage {
+				return a.CurrentPage > b.CurrentPage
+			}
+			return strings.ToLower(a.Title) < strings.ToLower(b.Title)
+		default:
+			if a.UpdatedAt != b.UpdatedAt {
+				return a.UpdatedAt > b.UpdatedAt
+			}
+			return a.CreatedAt > b.CreatedAt
+		}
+	})
+}
+
+// FilterStories returns the stories whose title or summary contains the query,
+// ignoring case. An empty query returns all stories.
+func FilterStories(stories []Story, query string) []Story {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return stories
+	}
+
+	var filtered []Story
+	for _, story := range stories {
+		if strings.Contains(strings.ToLower(story.Title), query) ||
+			strings.Contains(strings.ToLower(story.Summary), query) {
+			filtered = append(filtered, story)
+		}
+	}
+	return filtered
+}
@@ -63,3 +63,70 @@ func TestStoryPageCountDisplay(t *testing.T) {
 		})
 	}
 }
+
+func TestSortStories(t *testing.T) {
+	stories := []Story{
+		{ID: "a", Title: "banana", CurrentPage: 2, CreatedAt: 1, UpdatedAt: 10},
+		{ID: "b", Title: "Apple", CurrentPage: 7, CreatedAt: 2, UpdatedAt: 30},
+		{ID: "c", Title: "cherry", CurrentPage: 2, CreatedAt: 3, UpdatedAt: 20},
+	}
+
+	tests := []struct {
+		name     string
+		order    StorySortOrder
+		expected []string
+	}{
+		{name: "recent", order: SortByRecent, expected: []string{"b", "c", "a"}},
+		{name: "title", order: SortByTitle, expected: []string{"b", "a", "c"}},
+		{name: "length", order: SortByLength, expected: []string{"b", "a", "c"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sorted := append([]Story(nil), stories...)
+			SortStories(sorted, tt.order)
+			for i, id := range tt.expected {
+				if sorted[i].ID != id {
+					t.Errorf("position %d = %q, want %q", i, sorted[i].ID, id)
+				}
+			}
+		})
+	}
+}
+
+func TestStorySortOrderNext(t *testing.T) {
+	if SortByRecent.Next() != SortByTitle {
+		t.Errorf("SortByRecent.Next() = %v, want %v", SortByRecent.Next(), SortByTitle)
+	}
+	if SortByLength.Next() != SortByRecent {
+		t.Errorf("SortByLength.Next() = %v, want %v", SortByLength.Next(), SortByRecent)
+	}
+}
+
+func TestFilterStories(t *testing.T) {
+	stories := []Story{
+		{ID: "a", Title: "Dinosaur Days", Summary: ""},
+		{ID: "b", Title: "Ocean Friends", Summary: "A whale learns to count"},
+		{ID: "c", Title: "Space Trip", Summary: ""},
+	}
+
+	tests := []struct {
+		name     string
+		query    string
+		expected int
+	}{
+		{name: "empty query", query: "", expected: 3},
+		{name: "title match", query: "dino", expected: 1},
+		{name: "summary match", query: "WHALE", expected: 1},
+		{name: "no match", query: "pirates", expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := FilterStories(stories, tt.query)
+			if len(result) != tt.expected {
+				t.Errorf("FilterStories(%q) returned %d stories, want %d", tt.query, len(result), tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// ChildSummary is what a child has done since a point in time, for parents.
+type ChildSummary struct {
+	UserID          string              `json:"user_id"`
+	Since           int64               `json:"since"` // 0 for all time
+	Stories         int64               `json:"stories"`
+	Pages           int64               `json:"pages"`
+	ActiveSeconds   int64               `json:"active_seconds"` // in screen sessions
+	Objectives      []ObjectiveProgress `json:"objectives"`     // worked on, most pages first
+	Quizzes         int64               `json:"quizzes"`
+	QuizCorrect     int64               `json:"quiz_correct"`
+	QuizAnswered    int64               `json:"quiz_answered"`
+	Flags           int64               `json:"flags"`
+	UnreviewedFlags int64               `json:"unreviewed_flags"`
+	NewWords        int64               `json:"new_words"`
+	WordsReviewed   int64               `json:"words_reviewed"`
+	AverageGrade    *float64            `json:"average_grade"` // nil without scored pages
+	InputTokens     int64               `json:"input_tokens"`
+	OutputTokens    int64               `json:"output_tokens"`
+}
+
+// TimeSpent returns the time spent in the primer.
+func (s ChildSummary) TimeSpent() time.Duration {
+	return time.Duration(s.ActiveSeconds) * time.Second
+}
+
+// QuizPercent returns the share of quiz questions answered correctly as a
+// percentage, 0 if none were answered.
+func (s ChildSummary) QuizPercent() int {
+	if s.QuizAnswered == 0 {
+		return 0
+	}
+	return int(s.QuizCorrect * 100 / s.QuizAnswered)
+}
+
+// Tokens returns the input and output tokens spent together.
+func (s ChildSummary) Tokens() int64 {
+	return s.InputTokens + s.OutputTokens
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Kinds of model requests whose tokens are recorded.
+const (
+	UsagePage       = "page"       // writing a page, with any simplifying
+	UsageQuiz       = "quiz"       // writing a comprehension quiz
+	UsageDefinition = "definition" // explaining a word
+)
+
+// TokenUsage is the tokens spent on a model request for a child.
+type TokenUsage struct {
+	ID           string  `json:"id"`
+	UserID       string  `json:"user_id"`
+	StoryID      *string `json:"story_id"` // nil once the story is deleted
+	Kind         string  `json:"kind"`     // one of the Usage kinds
+	InputTokens  int64   `json:"input_tokens"`
+	OutputTokens int64   `json:"output_tokens"`
+	CreatedAt    int64   `json:"created_at"`
+}
+
+// NewTokenUsage creates a new TokenUsage with a generated UUID and the
+// current timestamp. storyID may be empty.
+func NewTokenUsage(userID, storyID, kind string, input, output int64) *TokenUsage {
+	usage := &TokenUsage{
+		ID:           uuid.New().String(),
+		UserID:       userID,
+		Kind:         kind,
+		InputTokens:  input,
+		OutputTokens: output,
+		CreatedAt:    time.Now().Unix(),
+	}
+	if storyID != "" {
+		usage.StoryID = &storyID
+	}
+	return usage
+}
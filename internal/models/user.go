@@ -13,8 +13,30 @@ type User struct {
 	Email         *string `json:"email"`
 	EmailVerified *int64  `json:"email_verified"`
 	Image         *string `json:"image"`
+	Theme         *string `json:"theme"`
+	HouseholdID   *string `json:"household_id"`
+	Role          Role    `json:"role"`
+	PINHash       *string `json:"-"` // bcrypt hash; guardians only
 	CreatedAt     int64   `json:"created_at"`
 	UpdatedAt     int64   `json:"updated_at"`
+
+	// Child profile, used to pitch stories at the right level
+	Birthdate    *string  `json:"birthdate"`     // YYYY-MM-DD
+	AgeBand      *string  `json:"age_band"`      // overrides the band derived from Birthdate
+	ReadingLevel *string  `json:"reading_level"` // one of ReadingLevels
+	Interests    []string `json:"interests"`
+	Language     *string  `json:"language"` // ISO 639-1 code, such as "en"
+
+	// Screen-time limits; see ScreenTime
+	DailyMinutes *int64  `json:"daily_minutes"`
+	AllowedFrom  *string `json:"allowed_from"`  // HH:MM
+	AllowedUntil *string `json:"allowed_until"` // HH:MM
+
+	// Content preferences, set by a guardian; see ContentPreferences for how
+	// StoryThemes differ from Interests
+	StoryThemes   []string `json:"story_themes"`
+	BannedTopics  []string `json:"banned_topics"`
+	Sensitivities []string `json:"sensitivities"`
 }
 
 // NewUser creates a new User with a generated UUID and current timestamps.
@@ -24,6 +46,7 @@ func NewUser(name, email string) *User {
 		ID:        uuid.New().String(),
 		Name:      &name,
 		Email:     &email,
+		Role:      RoleChild,
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
@@ -41,6 +64,14 @@ func (u *User) DisplayName() string {
 	return u.ID
 }
 
+// ThemeName returns the user's chosen TUI theme, or an empty string if unset.
+func (u *User) ThemeName() string {
+	if u.Theme != nil {
+		return *u.Theme
+	}
+	return ""
+}
+
 // DisplayEmail returns the email or an empty string if not set.
 func (u *User) DisplayEmail() string {
 	if u.Email != nil {
@@ -2,6 +2,7 @@ package models
 
 import (
 	"testing"
+	"time"
 )
 
 func TestNewUser(t *testing.T) {
@@ -89,3 +90,83 @@ func TestUserDisplayEmail(t *testing.T) {
 		}
 	})
 }
+
+func TestUserThemeName(t *testing.T) {
+	theme := "e-ink"
+	user := User{Theme: &theme}
+	if user.ThemeName() != "e-ink" {
+		t.Errorf("ThemeName() = %q, want %q", user.ThemeName(), "e-ink")
+	}
+
+	user = User{}
+	if user.ThemeName() != "" {
+		t.Errorf("ThemeName() = %q, want empty string", user.ThemeName())
+	}
+}
+
+func TestUserAge(t *testing.T) {
+	birthdate := "2019-06-15"
+	user := User{Birthdate: &birthdate}
+
+	tests := []struct {
+		now  time.Time
+		want int
+	}{
+		{time.Date(2025, 6, 14, 0, 0, 0, 0, time.UTC), 5},
+		{time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC), 6},
+		{time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), 6},
+	}
+	for _, tt := range tests {
+		if got, ok := user.Age(tt.now); !ok || got != tt.want {
+			t.Errorf("Age(%s) = %d, %v; want %d", tt.now.Format("2006-01-02"), got, ok, tt.want)
+		}
+	}
+
+	if _, ok := (&User{}).Age(time.Now()); ok {
+		t.Error("Age should be unknown without a birthdate")
+	}
+}
+
+func TestUserBand(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	birthdate := "2022-01-01" // 3 years old
+	user := User{Birthdate: &birthdate}
+
+	if got := user.Band(now); got != AgeBandToddler {
+		t.Errorf("Band() = %q, want toddler", got)
+	}
+
+	band := string(AgeBandEarlyReader)
+	user.AgeBand = &band
+	if got := user.Band(now); got != AgeBandEarlyReader {
+		t.Errorf("Band() = %q, want the profile's band to win", got)
+	}
+
+	if got := (&User{}).Band(now); got != "" {
+		t.Errorf("Band() = %q, want empty without a profile", got)
+	}
+}
+
+func TestAgeBandFor(t *testing.T) {
+	want := map[int]AgeBand{2: AgeBandToddler, 3: AgeBandToddler, 4: AgeBandPreschool, 6: AgeBandEarlyReader, 8: AgeBandReader, 11: AgeBandReader}
+	for age, band := range want {
+		if got := AgeBandFor(age); got != band {
+			t.Errorf("AgeBandFor(%d) = %q, want %q", age, got, band)
+		}
+	}
+}
+
+func TestValidateProfile(t *testing.T) {
+	bad := "15/06/2019"
+	if err := (&User{Birthdate: &bad}).ValidateProfile(); err == nil {
+		t.Error("expected an error for a malformed birthdate")
+	}
+	level := "expert"
+	if err := (&User{ReadingLevel: &level}).ValidateProfile(); err == nil {
+		t.Error("expected an error for an unknown reading level")
+	}
+	good, okLevel := "2019-06-15", "developing"
+	if err := (&User{Birthdate: &good, ReadingLevel: &okLevel}).ValidateProfile(); err != nil {
+		t.Errorf("ValidateProfile() error = %v", err)
+	}
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+)
+
+// Word is a challenging word a child has met in their stories.
+type Word struct {
+	UserID       string  `json:"user_id"`
+	Word         string  `json:"word"` // lower-cased
+	FirstStoryID *string `json:"first_story_id"`
+	FirstPageID  *string `json:"first_page_id"`
+	TimesSeen    int64   `json:"times_seen"` // pages the word has appeared on
+	CreatedAt    int64   `json:"created_at"` // first seen
+	UpdatedAt    int64   `json:"updated_at"` // last seen
+
+	// Review schedule; see Schedule.
+	Ease        float64 `json:"ease"`
+	Interval    int64   `json:"interval"`    // days between the last review and the next
+	Repetitions int64   `json:"repetitions"` // reviews recalled in a row
+	DueAt       int64   `json:"due_at"`
+	ReviewedAt  *int64  `json:"reviewed_at"` // nil until first reviewed
+}
+
+// VocabularyEntry is a word with where it was first seen and its
+// definition, for reviewing a child's words.
+type VocabularyEntry struct {
+	Word
+	StoryTitle string `json:"story_title"` // empty once the story is deleted
+	PageNum    int64  `json:"page_num"`    // 0 once the page is deleted
+	Definition string `json:"definition"`  // empty until one is generated
+}
+
+// Definition is a model-written explanation of a word, pitched at an age
+// band and cached so it is only generated once.
+type Definition struct {
+	Word      string  `json:"word"`
+	AgeBand   AgeBand `json:"age_band"` // empty for the general audience
+	Text      string  `json:"definition"`
+	CreatedAt int64   `json:"created_at"`
+}
+
+// NewDefinition creates a new Definition with the current timestamp.
+func NewDefinition(word string, band AgeBand, text string) *Definition {
+	return &Definition{
+		Word:      word,
+		AgeBand:   band,
+		Text:      text,
+		CreatedAt: time.Now().Unix(),
+	}
+}
@@ -0,0 +1,139 @@
+package narration
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kbrakke/illustrated-primer/internal/models"
+)
+
+// countingSpeaker records how often it is asked to speak.
+type countingSpeaker struct {
+	calls int
+	voice string
+}
+
+func (s *countingSpeaker) Speak(ctx context.Context, text string) ([]byte, error) {
+	s.calls++
+	return []byte("audio:" + text), nil
+}
+
+func (s *countingSpeaker) Format() string { return "wav" }
+func (s *countingSpeaker) ID() string     { return "test:" + s.voice }
+
+func TestText(t *testing.T) {
+	page := models.Page{Completion: "# The Fox\n\nA **clever** fox\nran home.\n\n```\ncode\n```\n- She *smiled*."}
+
+	want := "The Fox\n\nA clever fox ran home.\n\nShe smiled."
+	if got := Text(page); got != want {
+		t.Errorf("Text() = %q, want %q", got, want)
+	}
+}
+
+func TestNarrator(t *testing.T) {
+	dir := t.TempDir()
+	speaker := &countingSpeaker{voice: "a"}
+	n := NewNarrator(speaker, dir, nil)
+	ctx := context.Background()
+
+	page := *models.NewPage("story", 1, "prompt", "Once upon a time.")
+	path, err := n.Narrate(ctx, page)
+	if err != nil {
+		t.Fatalf("Narrate() error = %v", err)
+	}
+	if filepath.Dir(path) != filepath.Join(dir, "narration") || filepath.Ext(path) != ".wav" {
+		t.Errorf("unexpected path %q", path)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "audio:Once upon a time." {
+		t.Errorf("unexpected audio %q", data)
+	}
+
+	t.Run("cached by content", func(t *testing.T) {
+		copied := *models.NewPage("other story", 3, "other prompt", "Once upon a time.")
+		again, err := n.Narrate(ctx, copied)
+		if err != nil || again != path {
+			t.Errorf("Narrate() = %q, %v; want cached %q", again, err, path)
+		}
+		if speaker.calls != 1 {
+			t.Errorf("speaker called %d times, want 1", speaker.calls)
+		}
+	})
+
+	t.Run("voice changes the cache key", func(t *testing.T) {
+		other, _ := NewNarrator(&countingSpeaker{voice: "b"}, dir, nil).Narrate(ctx, page)
+		if other == path {
+			t.Error("different voices should not share audio")
+		}
+	})
+
+	t.Run("empty page", func(t *testing.T) {
+		if _, err := n.Narrate(ctx, models.Page{}); !errors.Is(err, ErrNothingToSay) {
+			t.Errorf("expected ErrNothingToSay, got %v", err)
+		}
+	})
+}
+
+func TestCommandSpeaker(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	ctx := context.Background()
+
+	t.Run("stdout", func(t *testing.T) {
+		s := &CommandSpeaker{Name: "sh", Args: []string{"-c", "tr a-z A-Z"}, Ext: "wav"}
+		audio, err := s.Speak(ctx, "hello")
+		if err != nil || string(audio) != "HELLO" {
+			t.Errorf("Speak() = %q, %v", audio, err)
+		}
+	})
+
+	t.Run("output file", func(t *testing.T) {
+		s := &CommandSpeaker{Name: "sh", Args: []string{"-c", `cat > "$0"`, OutputPlaceholder}, Ext: "wav"}
+		audio, err := s.Speak(ctx, "hello")
+		if err != nil || string(audio) != "hello" {
+			t.Errorf("Speak() = %q, %v", audio, err)
+		}
+	})
+
+	t.Run("failure includes stderr", func(t *testing.T) {
+		s := &CommandSpeaker{Name: "sh", Args: []string{"-c", "echo no voice >&2; exit 1"}, Ext: "wav"}
+		if _, err := s.Speak(ctx, "hello"); err == nil || !strings.Contains(err.Error(), "no voice") {
+			t.Errorf("expected error with stderr, got %v", err)
+		}
+	})
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestOpenAISpeaker(t *testing.T) {
+	var sent speechRequest
+	client := &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if err := json.NewDecoder(req.Body).Decode(&sent); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("ID3 audio"))}, nil
+	})}
+
+	s := NewOpenAISpeaker("key", WithHTTPClient(client), WithVoice("nova"))
+	audio, err := s.Speak(context.Background(), "Hello there.")
+	if err != nil || string(audio) != "ID3 audio" {
+		t.Fatalf("Speak() = %q, %v", audio, err)
+	}
+	if sent.Voice != "nova" || sent.Input != "Hello there." || sent.ResponseFormat != "mp3" || sent.Instructions == "" {
+		t.Errorf("unexpected request %+v", sent)
+	}
+
+	if NewOpenAISpeaker("key", WithModel("tts-1")).ID() == s.ID() {
+		t.Error("different models should have different IDs")
+	}
+}
@@ -0,0 +1,92 @@
+package narration
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/kbrakke/illustrated-primer/internal/markdown"
+	"github.com/kbrakke/illustrated-primer/internal/models"
+)
+
+// ErrNothingToSay is returned for pages without any text to narrate.
+var ErrNothingToSay = errors.New("page has no text to narrate")
+
+// Narrator renders pages to audio files in an asset directory. Files are
+// named by a hash of the voice and text, as <dir>/narration/<hash>.<format>,
+// so narrating the same text twice (a duplicated story, or a page whose
+// audio path was lost) reuses the existing file instead of calling the
+// speaker again.
+type Narrator struct {
+	speaker Speaker
+	dir     string
+	logger  *slog.Logger
+}
+
+// NewNarrator creates a Narrator that stores audio under dir.
+func NewNarrator(speaker Speaker, dir string, logger *slog.Logger) *Narrator {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Narrator{speaker: speaker, dir: dir, logger: logger}
+}
+
+// Narrate renders the page's story text and returns the path of the audio file.
+func (n *Narrator) Narrate(ctx context.Context, page models.Page) (string, error) {
+	text := Text(page)
+	if text == "" {
+		return "", ErrNothingToSay
+	}
+
+	path := n.path(text)
+	if _, err := os.Stat(path); err == nil {
+		n.logger.Debug("narration cache hit", "page_id", page.ID, "path", path)
+		return path, nil
+	}
+
+	audio, err := n.speaker.Speak(ctx, text)
+	if err != nil {
+		return "", fmt.Errorf("speak page: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("create narration directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "*.tmp")
+	if err != nil {
+		return "", fmt.Errorf("create narration file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(audio); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("write narration: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("write narration: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", fmt.Errorf("store narration: %w", err)
+	}
+
+	n.logger.Info("narration stored", "page_id", page.ID, "path", path)
+	return path, nil
+}
+
+// path returns the cache file for text in the speaker's voice.
+func (n *Narrator) path(text string) string {
+	sum := sha256.Sum256([]byte(n.speaker.ID() + "\x00" + text))
+	return filepath.Join(n.dir, "narration", hex.EncodeToString(sum[:])+"."+n.speaker.Format())
+}
+
+// Text returns the words of a page's completion as they should be read
+// aloud; see markdown.PlainText.
+func Text(page models.Page) string {
+	return markdown.PlainText(page.Completion)
+}
@@ -0,0 +1,201 @@
+package narration
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrStopped is returned by Playback.Wait when playback was stopped
+	// before the audio finished.
+	ErrStopped = errors.New("playback stopped")
+
+	// ErrNoPlayer is returned by DetectPlayer when no known audio player is installed.
+	ErrNoPlayer = errors.New("no audio player found")
+)
+
+// Player plays audio files.
+type Player interface {
+	Play(path string) (Playback, error)
+}
+
+// Playback is one audio file being played.
+type Playback interface {
+	Pause() error
+	Resume() error
+	Stop() error
+
+	// Wait blocks until playback ends. It returns nil when the audio played
+	// to the end and ErrStopped when Stop was called.
+	Wait() error
+
+	// Elapsed returns how much of the audio has been played, not counting
+	// time spent paused.
+	Elapsed() time.Duration
+}
+
+// FilePlaceholder in a CommandPlayer's arguments is replaced by the audio
+// file. Without it the file is appended as the last argument.
+const FilePlaceholder = "{file}"
+
+// CommandPlayer implements Player by running an external audio player, such
+// as mpv or ffplay, as a subprocess. Pausing suspends the process, which
+// every player handles without extra configuration.
+type CommandPlayer struct {
+	Name string
+	Args []string
+}
+
+// knownPlayers are tried in order by DetectPlayer. The later ones only
+// handle WAV, which the local speech engines produce.
+var knownPlayers = []CommandPlayer{
+	{Name: "mpv", Args: []string{"--no-video", "--really-quiet"}},
+	{Name: "ffplay", Args: []string{"-nodisp", "-autoexit", "-loglevel", "quiet"}},
+	{Name: "afplay"},
+	{Name: "paplay"},
+	{Name: "aplay", Args: []string{"-q"}},
+}
+
+// DetectPlayer returns the first known audio player found by lookPath
+// (normally exec.LookPath).
+func DetectPlayer(lookPath func(string) (string, error)) (*CommandPlayer, error) {
+	for _, p := range knownPlayers {
+		if _, err := lookPath(p.Name); err == nil {
+			player := p
+			return &player, nil
+		}
+	}
+	return nil, ErrNoPlayer
+}
+
+// ParsePlayer builds a CommandPlayer from a command line such as
+// "mpv --no-video {file}".
+func ParsePlayer(command string) (*CommandPlayer, error) {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("empty player command")
+	}
+	return &CommandPlayer{Name: fields[0], Args: fields[1:]}, nil
+}
+
+// Play starts playing the file at path.
+func (p *CommandPlayer) Play(path string) (Playback, error) {
+	args := make([]string, 0, len(p.Args)+1)
+	placed := false
+	for _, arg := range p.Args {
+		if strings.Contains(arg, FilePlaceholder) {
+			arg = strings.ReplaceAll(arg, FilePlaceholder, path)
+			placed = true
+		}
+		args = append(args, arg)
+	}
+	if !placed {
+		args = append(args, path)
+	}
+
+	cmd := exec.Command(p.Name, args...)
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("start %s: %w", p.Name, err)
+	}
+
+	pb := &processPlayback{cmd: cmd, started: time.Now(), done: make(chan struct{})}
+	go func() {
+		pb.err = cmd.Wait()
+		close(pb.done)
+	}()
+	return pb, nil
+}
+
+// processPlayback is a Playback backed by a player process.
+type processPlayback struct {
+	cmd  *exec.Cmd
+	done chan struct{}
+	err  error // set before done is closed
+
+	mu       sync.Mutex
+	started  time.Time
+	pausedAt time.Time // zero when playing
+	paused   time.Duration
+	stopped  bool
+}
+
+func (pb *processPlayback) Pause() error {
+	pb.mu.Lock()
+	defer pb.mu.Unlock()
+
+	if !pb.pausedAt.IsZero() {
+		return nil
+	}
+	if err := suspendProcess(pb.cmd.Process); err != nil {
+		return fmt.Errorf("pause playback: %w", err)
+	}
+	pb.pausedAt = time.Now()
+	return nil
+}
+
+func (pb *processPlayback) Resume() error {
+	pb.mu.Lock()
+	defer pb.mu.Unlock()
+
+	if pb.pausedAt.IsZero() {
+		return nil
+	}
+	if err := resumeProcess(pb.cmd.Process); err != nil {
+		return fmt.Errorf("resume playback: %w", err)
+	}
+	pb.paused += time.Since(pb.pausedAt)
+	pb.pausedAt = time.Time{}
+	return nil
+}
+
+func (pb *processPlayback) Stop() error {
+	pb.mu.Lock()
+	pb.stopped = true
+	wasPaused := !pb.pausedAt.IsZero()
+	pb.mu.Unlock()
+
+	select {
+	case <-pb.done:
+		return nil
+	default:
+	}
+
+	if err := pb.cmd.Process.Kill(); err != nil {
+		return fmt.Errorf("stop playback: %w", err)
+	}
+	if wasPaused {
+		// A stopped process only dies once it's allowed to run again.
+		_ = resumeProcess(pb.cmd.Process)
+	}
+	<-pb.done
+	return nil
+}
+
+func (pb *processPlayback) Wait() error {
+	<-pb.done
+
+	pb.mu.Lock()
+	defer pb.mu.Unlock()
+	if pb.stopped {
+		return ErrStopped
+	}
+	if pb.err != nil {
+		return fmt.Errorf("player exited: %w", pb.err)
+	}
+	return nil
+}
+
+func (pb *processPlayback) Elapsed() time.Duration {
+	pb.mu.Lock()
+	defer pb.mu.Unlock()
+
+	end := time.Now()
+	if !pb.pausedAt.IsZero() {
+		end = pb.pausedAt
+	}
+	return end.Sub(pb.started) - pb.paused
+}
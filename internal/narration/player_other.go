@@ -0,0 +1,14 @@
+//go:build !unix
+
+package narration
+
+import (
+	"errors"
+	"os"
+)
+
+var errPauseUnsupported = errors.New("pausing is not supported on this platform")
+
+func suspendProcess(p *os.Process) error { return errPauseUnsupported }
+
+func resumeProcess(p *os.Process) error { return errPauseUnsupported }
@@ -0,0 +1,75 @@
+//go:build unix
+
+package narration
+
+import (
+	"errors"
+	"os/exec"
+	"testing"
+	"time"
+)
+
+func TestCommandPlayer(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	t.Run("plays to the end", func(t *testing.T) {
+		player := &CommandPlayer{Name: "sh", Args: []string{"-c", `test "$0" = page.wav`, FilePlaceholder}}
+		pb, err := player.Play("page.wav")
+		if err != nil {
+			t.Fatalf("Play() error = %v", err)
+		}
+		if err := pb.Wait(); err != nil {
+			t.Errorf("Wait() = %v, want nil", err)
+		}
+	})
+
+	t.Run("pause and stop", func(t *testing.T) {
+		player := &CommandPlayer{Name: "sh", Args: []string{"-c", "sleep 10"}}
+		pb, err := player.Play("page.wav")
+		if err != nil {
+			t.Fatalf("Play() error = %v", err)
+		}
+
+		if err := pb.Pause(); err != nil {
+			t.Fatalf("Pause() error = %v", err)
+		}
+		before := pb.Elapsed()
+		time.Sleep(50 * time.Millisecond)
+		if pb.Elapsed() != before {
+			t.Error("elapsed time should not advance while paused")
+		}
+		if err := pb.Resume(); err != nil {
+			t.Fatalf("Resume() error = %v", err)
+		}
+
+		if err := pb.Pause(); err != nil {
+			t.Fatalf("Pause() error = %v", err)
+		}
+		if err := pb.Stop(); err != nil {
+			t.Fatalf("Stop() error = %v", err)
+		}
+		if err := pb.Wait(); !errors.Is(err, ErrStopped) {
+			t.Errorf("Wait() = %v, want ErrStopped", err)
+		}
+	})
+}
+
+func TestDetectPlayer(t *testing.T) {
+	lookPath := func(name string) (string, error) {
+		if name == "ffplay" || name == "aplay" {
+			return "/usr/bin/" + name, nil
+		}
+		return "", exec.ErrNotFound
+	}
+
+	player, err := DetectPlayer(lookPath)
+	if err != nil || player.Name != "ffplay" {
+		t.Errorf("DetectPlayer() = %v, %v; want ffplay", player, err)
+	}
+
+	if _, err := DetectPlayer(func(string) (string, error) { return "", exec.ErrNotFound }); !errors.Is(err, ErrNoPlayer) {
+		t.Errorf("expected ErrNoPlayer, got %v", err)
+	}
+}
@@ -0,0 +1,264 @@
+// Package narration renders story pages to speech.
+package narration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+const (
+	// DefaultSpeechModel is the OpenAI text-to-speech model.
+	DefaultSpeechModel = "gpt-4o-mini-tts"
+
+	// DefaultVoice is the OpenAI voice used for narration.
+	DefaultVoice = "fable"
+
+	speechAPIURL = "https://api.openai.com/v1/audio/speech"
+)
+
+// Speaker turns text into audio.
+type Speaker interface {
+	// Speak returns the audio for text, encoded as Format.
+	Speak(ctx context.Context, text string) ([]byte, error)
+
+	// Format is the audio file extension, such as "mp3" or "wav".
+	Format() string
+
+	// ID identifies the engine and voice, so audio from different voices is
+	// cached separately.
+	ID() string
+}
+
+// OpenAISpeaker implements Speaker using the OpenAI speech API.
+type OpenAISpeaker struct {
+	apiKey       string
+	orgID        string
+	model        string
+	voice        string
+	instructions string
+	httpClient   *http.Client
+	logger       *slog.Logger
+}
+
+// OpenAIOption is a function that configures an OpenAISpeaker.
+type OpenAIOption func(*OpenAISpeaker)
+
+// WithModel sets the speech model to use.
+func WithModel(model string) OpenAIOption {
+	return func(s *OpenAISpeaker) {
+		s.model = model
+	}
+}
+
+// WithVoice sets the voice to narrate with.
+func WithVoice(voice string) OpenAIOption {
+	return func(s *OpenAISpeaker) {
+		s.voice = voice
+	}
+}
+
+// WithInstructions sets how the voice should speak. Only newer models
+// (gpt-4o-mini-tts) support instructions.
+func WithInstructions(instructions string) OpenAIOption {
+	return func(s *OpenAISpeaker) {
+		s.instructions = instructions
+	}
+}
+
+// WithOrgID sets the organization ID for project-scoped keys.
+func WithOrgID(orgID string) OpenAIOption {
+	return func(s *OpenAISpeaker) {
+		s.orgID = orgID
+	}
+}
+
+// WithLogger sets a custom logger.
+func WithLogger(logger *slog.Logger) OpenAIOption {
+	return func(s *OpenAISpeaker) {
+		s.logger = logger
+	}
+}
+
+// WithHTTPClient sets a custom HTTP client.
+func WithHTTPClient(client *http.Client) OpenAIOption {
+	return func(s *OpenAISpeaker) {
+		s.httpClient = client
+	}
+}
+
+// NewOpenAISpeaker creates a speaker with the given API key and options.
+func NewOpenAISpeaker(apiKey string, opts ...OpenAIOption) *OpenAISpeaker {
+	s := &OpenAISpeaker{
+		apiKey:       apiKey,
+		model:        DefaultSpeechModel,
+		voice:        DefaultVoice,
+		instructions: "Read warmly and slowly, like a storyteller reading a picture book to a young child.",
+		httpClient:   http.DefaultClient,
+		logger:       slog.Default(),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Format returns "mp3".
+func (s *OpenAISpeaker) Format() string { return "mp3" }
+
+// ID returns the model and voice.
+func (s *OpenAISpeaker) ID() string {
+	return "openai:" + s.model + ":" + s.voice + ":" + s.instructions
+}
+
+// speechRequest is the request body for the speech API.
+type speechRequest struct {
+	Model          string `json:"model"`
+	Input          string `json:"input"`
+	Voice          string `json:"voice"`
+	Instructions   string `json:"instructions,omitempty"`
+	ResponseFormat string `json:"response_format"`
+}
+
+// Speak requests narration of text and returns the MP3 data.
+func (s *OpenAISpeaker) Speak(ctx context.Context, text string) ([]byte, error) {
+	reqBody := speechRequest{
+		Model:          s.model,
+		Input:          text,
+		Voice:          s.voice,
+		ResponseFormat: s.Format(),
+	}
+	if strings.HasPrefix(s.model, "gpt-") {
+		reqBody.Instructions = s.instructions
+	}
+
+	bodyBytes, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", speechAPIURL, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+s.apiKey)
+	if s.orgID != "" {
+		req.Header.Set("OpenAI-Organization", s.orgID)
+	}
+
+	s.logger.Debug("requesting narration", "model", s.model, "voice", s.voice, "length", len(text))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read audio: %w", err)
+	}
+	return data, nil
+}
+
+// OutputPlaceholder in a CommandSpeaker's arguments is replaced by the path
+// of a temporary file the command should write its audio to. Without it the
+// audio is read from the command's standard output.
+const OutputPlaceholder = "{output}"
+
+// CommandSpeaker implements Speaker by running a local text-to-speech
+// program, such as espeak-ng or piper, as a subprocess. The text is written
+// to the program's standard input.
+type CommandSpeaker struct {
+	Name   string   // program to run
+	Args   []string // arguments, optionally containing OutputPlaceholder
+	Ext    string   // audio format the program produces
+	Logger *slog.Logger
+}
+
+// NewEspeakSpeaker creates a speaker using espeak-ng with the given voice,
+// such as "en-us". An empty voice uses espeak's default.
+func NewEspeakSpeaker(voice string) *CommandSpeaker {
+	args := []string{"--stdin", "--stdout", "-s", "140"}
+	if voice != "" {
+		args = append(args, "-v", voice)
+	}
+	return &CommandSpeaker{Name: "espeak-ng", Args: args, Ext: "wav"}
+}
+
+// NewPiperSpeaker creates a speaker using piper with the given voice model file.
+func NewPiperSpeaker(model string) *CommandSpeaker {
+	return &CommandSpeaker{
+		Name: "piper",
+		Args: []string{"--model", model, "--output_file", OutputPlaceholder},
+		Ext:  "wav",
+	}
+}
+
+// Format returns the configured audio format.
+func (s *CommandSpeaker) Format() string { return s.Ext }
+
+// ID returns the command line.
+func (s *CommandSpeaker) ID() string {
+	return "command:" + s.Name + " " + strings.Join(s.Args, " ")
+}
+
+// Speak runs the command and returns the audio it produced.
+func (s *CommandSpeaker) Speak(ctx context.Context, text string) ([]byte, error) {
+	args := append([]string(nil), s.Args...)
+
+	output := ""
+	for i, arg := range args {
+		if strings.Contains(arg, OutputPlaceholder) {
+			if output == "" {
+				f, err := os.CreateTemp("", "primer-narration-*."+s.Ext)
+				if err != nil {
+					return nil, fmt.Errorf("create audio file: %w", err)
+				}
+				f.Close()
+				output = f.Name()
+				defer os.Remove(output)
+			}
+			args[i] = strings.ReplaceAll(arg, OutputPlaceholder, output)
+		}
+	}
+
+	cmd := exec.CommandContext(ctx, s.Name, args...)
+	cmd.Stdin = strings.NewReader(text)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if s.Logger != nil {
+		s.Logger.Debug("running speech command", "command", s.Name, "length", len(text))
+	}
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("run %s: %w: %s", s.Name, err, strings.TrimSpace(stderr.String()))
+	}
+
+	if output == "" {
+		return stdout.Bytes(), nil
+	}
+	data, err := os.ReadFile(output)
+	if err != nil {
+		return nil, fmt.Errorf("read audio file: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,143 @@
+package narration
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Cue marks when a sentence starts in a page's narration.
+type Cue struct {
+	Text  string        `json:"text"`
+	Start time.Duration `json:"start"` // nanoseconds from the start of the audio
+}
+
+// CueAt returns the index of the cue being spoken at elapsed, or -1 before
+// the first cue.
+func CueAt(cues []Cue, elapsed time.Duration) int {
+	current := -1
+	for i, cue := range cues {
+		if cue.Start > elapsed {
+			break
+		}
+		current = i
+	}
+	return current
+}
+
+// Sentences splits narration text into sentences. A sentence ends at '.',
+// '!' or '?' (with any closing quotes or brackets) when the next word doesn't
+// start in lowercase, so `"Hello!" she said.` stays together, and at
+// paragraph breaks.
+func Sentences(text string) []string {
+	var sentences []string
+	for _, paragraph := range strings.Split(text, "\n\n") {
+		words := strings.Fields(paragraph)
+		start := 0
+		for i, word := range words {
+			if i == len(words)-1 || endsSentence(word) && !startsLower(words[i+1]) {
+				sentences = append(sentences, strings.Join(words[start:i+1], " "))
+				start = i + 1
+			}
+		}
+	}
+	return sentences
+}
+
+func endsSentence(word string) bool {
+	word = strings.TrimRightFunc(word, func(r rune) bool {
+		return strings.ContainsRune(`"')]”’»`, r)
+	})
+	if word == "" {
+		return false
+	}
+	last, _ := utf8.DecodeLastRuneInString(word)
+	return unicode.Is(unicode.Sentence_Terminal, last)
+}
+
+func startsLower(word string) bool {
+	first, _ := utf8.DecodeRuneInString(word)
+	return unicode.IsLower(first)
+}
+
+// LoadCues returns sentence timing for an audio file, if any is available.
+// Engines that report word or sentence timing can leave it next to the audio
+// as <audio>.cues.json, a JSON array of Cue. Without that, timing is
+// estimated for WAV files by spreading the audio's duration over the
+// sentences by length. Other formats have no timing and return nil.
+func LoadCues(audioPath, text string) []Cue {
+	if data, err := os.ReadFile(audioPath + ".cues.json"); err == nil {
+		var cues []Cue
+		if json.Unmarshal(data, &cues) == nil && len(cues) > 0 {
+			return cues
+		}
+	}
+
+	if !strings.HasSuffix(strings.ToLower(audioPath), ".wav") {
+		return nil
+	}
+	duration, ok := wavDuration(audioPath)
+	if !ok {
+		return nil
+	}
+	return estimateCues(Sentences(text), duration)
+}
+
+// estimateCues assumes speech proceeds at a steady rate, so each sentence
+// takes a share of the duration proportional to its length.
+func estimateCues(sentences []string, duration time.Duration) []Cue {
+	total := 0
+	for _, s := range sentences {
+		total += len(s)
+	}
+	if total == 0 {
+		return nil
+	}
+
+	cues := make([]Cue, len(sentences))
+	offset := 0
+	for i, s := range sentences {
+		cues[i] = Cue{Text: s, Start: duration * time.Duration(offset) / time.Duration(total)}
+		offset += len(s)
+	}
+	return cues
+}
+
+// wavDuration reads the duration of a PCM WAV file from its header. Engines
+// streaming to stdout can't know the data size up front and write a
+// placeholder, so the size is capped at what the file actually holds.
+func wavDuration(path string) (time.Duration, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil || len(data) < 12 || string(data[:4]) != "RIFF" || string(data[8:12]) != "WAVE" {
+		return 0, false
+	}
+
+	var byteRate uint32
+	for pos := 12; pos+8 <= len(data); {
+		id := string(data[pos : pos+4])
+		size := binary.LittleEndian.Uint32(data[pos+4 : pos+8])
+		body := pos + 8
+
+		switch id {
+		case "fmt ":
+			if body+12 > len(data) {
+				return 0, false
+			}
+			byteRate = binary.LittleEndian.Uint32(data[body+8 : body+12])
+		case "data":
+			if byteRate == 0 {
+				return 0, false
+			}
+			available := uint64(len(data) - body)
+			dataSize := min(uint64(size), available)
+			return time.Duration(dataSize * uint64(time.Second) / uint64(byteRate)), true
+		}
+
+		pos = body + int(size) + int(size%2)
+	}
+	return 0, false
+}
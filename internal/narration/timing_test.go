@@ -0,0 +1,105 @@
+package narration
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSentences(t *testing.T) {
+	text := "Once upon a time, a fox lived here. \"Hello!\" she said. Was it late?\n\nThe end"
+	want := []string{
+		"Once upon a time, a fox lived here.",
+		"\"Hello!\" she said.",
+		"Was it late?",
+		"The end",
+	}
+	if got := Sentences(text); !reflect.DeepEqual(got, want) {
+		t.Errorf("Sentences() = %q, want %q", got, want)
+	}
+}
+
+func TestCueAt(t *testing.T) {
+	cues := []Cue{{Start: 0}, {Start: time.Second}, {Start: 3 * time.Second}}
+	tests := map[time.Duration]int{
+		-time.Second:            -1,
+		0:                       0,
+		1500 * time.Millisecond: 1,
+		10 * time.Second:        2,
+	}
+	for elapsed, want := range tests {
+		if got := CueAt(cues, elapsed); got != want {
+			t.Errorf("CueAt(%v) = %d, want %d", elapsed, got, want)
+		}
+	}
+}
+
+// writeWAV writes a mono 8kHz 16-bit WAV with the given data size in the
+// header and actual bytes of audio.
+func writeWAV(t *testing.T, path string, headerSize uint32, actual int) {
+	t.Helper()
+	le := binary.LittleEndian
+	buf := []byte("RIFF\x00\x00\x00\x00WAVEfmt ")
+	buf = le.AppendUint32(buf, 16)
+	buf = le.AppendUint16(buf, 1)     // PCM
+	buf = le.AppendUint16(buf, 1)     // channels
+	buf = le.AppendUint32(buf, 8000)  // sample rate
+	buf = le.AppendUint32(buf, 16000) // byte rate
+	buf = le.AppendUint16(buf, 2)     // block align
+	buf = le.AppendUint16(buf, 16)    // bits per sample
+	buf = append(buf, "data"...)
+	buf = le.AppendUint32(buf, headerSize)
+	buf = append(buf, make([]byte, actual)...)
+	if err := os.WriteFile(path, buf, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadCues(t *testing.T) {
+	dir := t.TempDir()
+	text := "Short one. A much longer second sentence."
+
+	t.Run("estimated from wav", func(t *testing.T) {
+		path := filepath.Join(dir, "page.wav")
+		writeWAV(t, path, 32000, 32000) // two seconds
+
+		cues := LoadCues(path, text)
+		if len(cues) != 2 || cues[0].Start != 0 {
+			t.Fatalf("unexpected cues %+v", cues)
+		}
+		// "Short one." is 10 of 40 characters.
+		if want := 2 * time.Second * 10 / 40; cues[1].Start != want {
+			t.Errorf("second cue starts at %v, want %v", cues[1].Start, want)
+		}
+	})
+
+	t.Run("streamed wav placeholder size", func(t *testing.T) {
+		path := filepath.Join(dir, "streamed.wav")
+		writeWAV(t, path, 0x7fffffff, 16000)
+		if d, ok := wavDuration(path); !ok || d != time.Second {
+			t.Errorf("wavDuration() = %v, %v; want 1s", d, ok)
+		}
+	})
+
+	t.Run("sidecar", func(t *testing.T) {
+		path := filepath.Join(dir, "page.mp3")
+		want := []Cue{{Text: "Short one.", Start: 0}, {Text: "A much longer second sentence.", Start: 800 * time.Millisecond}}
+		data, _ := json.Marshal(want)
+		if err := os.WriteFile(path+".cues.json", data, 0644); err != nil {
+			t.Fatal(err)
+		}
+		if got := LoadCues(path, text); !reflect.DeepEqual(got, want) {
+			t.Errorf("LoadCues() = %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("no timing for mp3", func(t *testing.T) {
+		if cues := LoadCues(filepath.Join(dir, "other.mp3"), text); cues != nil {
+			t.Errorf("expected no cues, got %+v", cues)
+		}
+	})
+}
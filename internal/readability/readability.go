@@ -0,0 +1,195 @@
+// Package readability measures how hard a page of a story is to read, so a
+// page can be checked against the reading level of the child it is for.
+package readability
+
+import (
+	_ "embed"
+	"strings"
+
+	"github.com/kbrakke/illustrated-primer/internal/models"
+	"github.com/kbrakke/illustrated-primer/internal/vocab"
+)
+
+const (
+	// MinWords is the shortest text whose scores are trusted; the formulas
+	// swing wildly on a sentence or two.
+	MinWords = 20
+	// Tolerance is how far above its target grade a page may score before it
+	// counts as too hard.
+	Tolerance = 1.0
+)
+
+//go:embed common.txt
+var commonList string
+
+// common is the set of words children know by sight.
+var common = func() map[string]bool {
+	words := make(map[string]bool)
+	for _, line := range strings.Split(commonList, "\n") {
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		for _, word := range strings.Fields(line) {
+			words[word] = true
+		}
+	}
+	return words
+}()
+
+// Stats are the counts the readability scores are computed from.
+type Stats struct {
+	Words     int
+	Sentences int
+	Syllables int
+	RareWords int // words not on the common word list, names excepted
+}
+
+// Analyze counts the words, sentences, syllables and rare words in text.
+func Analyze(text string) Stats {
+	var s Stats
+	names := vocab.Names(text)
+	for _, sentence := range vocab.Sentences(text) {
+		words := vocab.Tokens(sentence)
+		if len(words) == 0 {
+			continue
+		}
+		s.Sentences++
+		for _, word := range words {
+			s.Words++
+			s.Syllables += vocab.Syllables(word)
+			if !names[word] && !isCommon(word) {
+				s.RareWords++
+			}
+		}
+	}
+	return s
+}
+
+// isCommon reports whether word, or its stem without a common ending, is on
+// the common word list.
+func isCommon(word string) bool {
+	word = strings.ReplaceAll(word, "’", "'")
+	if common[word] {
+		return true
+	}
+	if i := strings.IndexByte(word, '\''); i > 0 {
+		word = word[:i] // contractions: "we'll", "i'm"
+	}
+	if common[word] {
+		return true
+	}
+	for _, suffix := range []string{"s", "es", "ed", "d", "ing", "er", "est", "ly", "y"} {
+		stem, ok := strings.CutSuffix(word, suffix)
+		if !ok || len(stem) < 2 {
+			continue
+		}
+		if common[stem] || common[stem+"e"] {
+			return true
+		}
+		// "running", "bigger": a doubled final consonant
+		if n := len(stem); n > 2 && stem[n-1] == stem[n-2] && common[stem[:n-1]] {
+			return true
+		}
+	}
+	for _, suffix := range []string{"ies", "ied", "ier", "iest", "ily"} {
+		if stem, ok := strings.CutSuffix(word, suffix); ok && common[stem+"y"] {
+			return true
+		}
+	}
+	return false
+}
+
+// WordsPerSentence is the average sentence length.
+func (s Stats) WordsPerSentence() float64 {
+	if s.Sentences == 0 {
+		return 0
+	}
+	return float64(s.Words) / float64(s.Sentences)
+}
+
+// syllablesPerWord is the average word length in syllables.
+func (s Stats) syllablesPerWord() float64 {
+	if s.Words == 0 {
+		return 0
+	}
+	return float64(s.Syllables) / float64(s.Words)
+}
+
+// GradeLevel is the Flesch-Kincaid grade level: roughly the school grade
+// a reader needs to be in to follow the text.
+func (s Stats) GradeLevel() float64 {
+	if s.Words == 0 {
+		return 0
+	}
+	return 0.39*s.WordsPerSentence() + 11.8*s.syllablesPerWord() - 15.59
+}
+
+// ReadingEase is the Flesch reading ease, from about 0 (very hard) to 100
+// (very easy); it can fall outside that range for extreme texts.
+func (s Stats) ReadingEase() float64 {
+	if s.Words == 0 {
+		return 0
+	}
+	return 206.835 - 1.015*s.WordsPerSentence() - 84.6*s.syllablesPerWord()
+}
+
+// RareWordRatio is the share of words that are not on the common word list.
+func (s Stats) RareWordRatio() float64 {
+	if s.Words == 0 {
+		return 0
+	}
+	return float64(s.RareWords) / float64(s.Words)
+}
+
+// Scores returns the scores stored with a page.
+func (s Stats) Scores() *models.Readability {
+	return &models.Readability{
+		GradeLevel:       s.GradeLevel(),
+		ReadingEase:      s.ReadingEase(),
+		WordsPerSentence: s.WordsPerSentence(),
+		RareWordRatio:    s.RareWordRatio(),
+	}
+}
+
+// Exceeds reports whether the text is too hard for a reader at the target
+// grade. Texts shorter than MinWords never are.
+func (s Stats) Exceeds(target float64) bool {
+	return s.Words >= MinWords && s.GradeLevel() > target+Tolerance
+}
+
+// Supports reports whether text in language, an ISO 639-1 code, can be
+// scored. The formulas, syllable counts and common word list are all for
+// English, like the words vocab picks.
+func Supports(language string) bool {
+	return vocab.Supports(language)
+}
+
+// bandGrades are the target grade levels for each age band.
+var bandGrades = map[models.AgeBand]float64{
+	models.AgeBandToddler:     1,
+	models.AgeBandPreschool:   2,
+	models.AgeBandEarlyReader: 3,
+	models.AgeBandReader:      6,
+}
+
+// defaultGrade is the target when the child's age band is unknown.
+const defaultGrade = 4
+
+// readingLevelGrades cap the target for children still learning to read.
+var readingLevelGrades = map[string]float64{
+	"beginning":  2,
+	"developing": 4,
+}
+
+// TargetGrade returns the grade level a page should be written at for a
+// child in the age band with the given reading level.
+func TargetGrade(band models.AgeBand, readingLevel string) float64 {
+	target, ok := bandGrades[band]
+	if !ok {
+		target = defaultGrade
+	}
+	if limit, ok := readingLevelGrades[readingLevel]; ok && limit < target {
+		target = limit
+	}
+	return target
+}
@@ -0,0 +1,121 @@
+package readability
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/kbrakke/illustrated-primer/internal/models"
+)
+
+const (
+	simple = `The cat sat on the bed. The dog ran to the cat. They played in the sun all day. Then they went home to sleep.`
+	hard   = `Photosynthesis, the remarkable biochemical process whereby chlorophyll-containing organisms transform luminous energy into chemical energy, sustains practically every terrestrial ecosystem. Consequently, botanists investigate its mechanisms meticulously.`
+)
+
+func TestAnalyze(t *testing.T) {
+	s := Analyze(`Then Nell ran home. "Where's the enormous kitten?" she asked.`)
+	want := Stats{Words: 10, Sentences: 3, Syllables: 13, RareWords: 1}
+	if s != want {
+		t.Errorf("Analyze() = %+v, want %+v", s, want)
+	}
+	if got := Analyze("  "); got != (Stats{}) {
+		t.Errorf("Analyze(blank) = %+v", got)
+	}
+}
+
+func TestIsCommon(t *testing.T) {
+	tests := map[string]bool{
+		"cat":      true,
+		"cats":     true,
+		"jumped":   true,
+		"running":  true,
+		"bigger":   true,
+		"liked":    true,
+		"cried":    true,
+		"puppies":  true,
+		"happily":  true,
+		"don't":    true,
+		"won’t":    true,
+		"we'll":    true,
+		"enormous": false,
+		"cocoon":   false,
+	}
+	for word, want := range tests {
+		if got := isCommon(word); got != want {
+			t.Errorf("isCommon(%q) = %v, want %v", word, got, want)
+		}
+	}
+}
+
+func TestScores(t *testing.T) {
+	easy, tough := Analyze(simple), Analyze(hard)
+	if easy.GradeLevel() >= 2 || tough.GradeLevel() <= 12 {
+		t.Errorf("grade levels = %.1f and %.1f, want easy below 2 and hard above 12", easy.GradeLevel(), tough.GradeLevel())
+	}
+	if easy.ReadingEase() <= tough.ReadingEase() {
+		t.Errorf("reading ease %.1f should be above %.1f", easy.ReadingEase(), tough.ReadingEase())
+	}
+	if easy.RareWordRatio() != 0 || tough.RareWordRatio() < 0.5 {
+		t.Errorf("rare word ratios = %.2f and %.2f", easy.RareWordRatio(), tough.RareWordRatio())
+	}
+
+	scores := easy.Scores()
+	if scores.WordsPerSentence != easy.WordsPerSentence() || math.Abs(scores.GradeLevel-easy.GradeLevel()) > 1e-9 {
+		t.Errorf("Scores() = %+v", scores)
+	}
+	if (Stats{}).GradeLevel() != 0 || (Stats{}).WordsPerSentence() != 0 {
+		t.Error("empty text should score zero")
+	}
+}
+
+func TestExceeds(t *testing.T) {
+	tough := Analyze(hard)
+	if !tough.Exceeds(6) {
+		t.Error("hard text should exceed grade 6")
+	}
+	if Analyze(simple).Exceeds(1) {
+		t.Error("simple text should not exceed grade 1")
+	}
+	short := Analyze("Photosynthesis transforms luminous energy.")
+	if short.GradeLevel() <= 2 || short.Exceeds(2) {
+		t.Errorf("a text under %d words should never exceed its target", MinWords)
+	}
+	if long := Analyze(strings.Repeat(simple+" ", 3)); long.Words < MinWords || long.Exceeds(1) {
+		t.Errorf("repeating simple text should stay simple: %+v", long)
+	}
+}
+
+func TestTargetGrade(t *testing.T) {
+	tests := []struct {
+		band  models.AgeBand
+		level string
+		want  float64
+	}{
+		{models.AgeBandToddler, "", 1},
+		{models.AgeBandEarlyReader, "fluent", 3},
+		{models.AgeBandReader, "", 6},
+		{models.AgeBandReader, "beginning", 2},
+		{models.AgeBandReader, "developing", 4},
+		{models.AgeBandPreschool, "developing", 2},
+		{"", "", 4},
+	}
+	for _, tt := range tests {
+		if got := TargetGrade(tt.band, tt.level); got != tt.want {
+			t.Errorf("TargetGrade(%q, %q) = %v, want %v", tt.band, tt.level, got, tt.want)
+		}
+	}
+}
+
+func TestSupports(t *testing.T) {
+	for _, language := range []string{"", "en", "EN", "en-GB"} {
+		if !Supports(language) {
+			t.Errorf("Supports(%q) = false, want true", language)
+		}
+	}
+	for _, language := range []string{"fr", "es", "de", "eng"} {
+		if Supports(language) {
+			t.Errorf("Supports(%q) = true, want false", language)
+		}
+	}
+}
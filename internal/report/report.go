@@ -0,0 +1,154 @@
+// Package report writes the weekly progress reports parents can export from
+// the parent dashboard, as Markdown and HTML.
+package report
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+
+	"github.com/kbrakke/illustrated-primer/internal/models"
+)
+
+// Week is the period a weekly report covers.
+const Week = 7 * 24 * time.Hour
+
+//go:embed templates/*
+var templateFS embed.FS
+
+var funcs = map[string]any{
+	"date":     func(unix int64) string { return time.Unix(unix, 0).Format("Mon 2 Jan") },
+	"duration": FormatDuration,
+	"grade":    func(grade float64) string { return fmt.Sprintf("%.1f", grade) },
+	"join":     strings.Join,
+	"action":   func(action string) string { return actions[action] },
+}
+
+// actions describe what moderation did, by ModerationFlag.Action.
+var actions = map[string]string{
+	"flag":    "flagged",
+	"rewrite": "rewritten",
+	"block":   "blocked",
+}
+
+var (
+	markdownTemplate = template.Must(template.New("weekly.md.tmpl").Funcs(funcs).ParseFS(templateFS, "templates/weekly.md.tmpl"))
+	htmlTemplate     = htmltemplate.Must(htmltemplate.New("weekly.html.tmpl").Funcs(funcs).ParseFS(templateFS, "templates/weekly.html.tmpl"))
+)
+
+// Weekly is a child's progress over the week up to To.
+type Weekly struct {
+	Child   models.User
+	From    time.Time
+	To      time.Time
+	Summary models.ChildSummary // since From
+	Quizzes []models.QuizAttempt
+	Flags   []models.ModerationFlag
+	Words   []string // new words met
+}
+
+// NewWeekly builds the report for the week up to to, keeping the quizzes,
+// flags and words from that week. summary should cover the same week.
+func NewWeekly(child models.User, to time.Time, summary models.ChildSummary,
+	quizzes []models.QuizAttempt, flags []models.ModerationFlag, words []models.VocabularyEntry) Weekly {
+	w := Weekly{Child: child, From: to.Add(-Week), To: to, Summary: summary}
+	from := w.From.Unix()
+	for _, quiz := range quizzes {
+		if quiz.CreatedAt >= from {
+			w.Quizzes = append(w.Quizzes, quiz)
+		}
+	}
+	for _, flag := range flags {
+		if flag.CreatedAt >= from {
+			w.Flags = append(w.Flags, flag)
+		}
+	}
+	for _, word := range words {
+		if word.CreatedAt >= from {
+			w.Words = append(w.Words, word.Word.Word)
+		}
+	}
+	return w
+}
+
+// Name returns the child's name for the report.
+func (w Weekly) Name() string {
+	return w.Child.DisplayName()
+}
+
+// Markdown writes the report as Markdown.
+func (w Weekly) Markdown(out io.Writer) error {
+	if err := markdownTemplate.Execute(out, w); err != nil {
+		return fmt.Errorf("render markdown report: %w", err)
+	}
+	return nil
+}
+
+// HTML writes the report as a standalone HTML page.
+func (w Weekly) HTML(out io.Writer) error {
+	if err := htmlTemplate.Execute(out, w); err != nil {
+		return fmt.Errorf("render html report: %w", err)
+	}
+	return nil
+}
+
+// Write writes the report to dir as Markdown and HTML files named after the
+// child and the last day of the week, and returns their paths.
+func Write(dir string, w Weekly) ([]string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create report directory: %w", err)
+	}
+
+	base := filepath.Join(dir, fmt.Sprintf("%s-%s", slug(w.Name()), w.To.Format("2006-01-02")))
+	formats := []struct {
+		ext    string
+		render func(io.Writer) error
+	}{
+		{".md", w.Markdown},
+		{".html", w.HTML},
+	}
+
+	var paths []string
+	for _, format := range formats {
+		var b bytes.Buffer
+		if err := format.render(&b); err != nil {
+			return nil, err
+		}
+		path := base + format.ext
+		if err := os.WriteFile(path, b.Bytes(), 0o644); err != nil {
+			return nil, fmt.Errorf("write report: %w", err)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// slug turns a name into a file name: lower-case letters and digits joined
+// by dashes.
+func slug(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) == 0 {
+		return "child"
+	}
+	return strings.Join(words, "-")
+}
+
+// FormatDuration formats a time spent in hours and minutes, like "1h 05m" or
+// "25m".
+func FormatDuration(d time.Duration) string {
+	minutes := int(d.Round(time.Minute).Minutes())
+	if minutes < 60 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	return fmt.Sprintf("%dh %02dm", minutes/60, minutes%60)
+}
@@ -0,0 +1,133 @@
+package report
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kbrakke/illustrated-primer/internal/models"
+)
+
+func testWeekly(t *testing.T) Weekly {
+	t.Helper()
+	to := time.Date(2026, 3, 8, 18, 0, 0, 0, time.Local)
+	name := "Nell Hardy"
+	grade := 2.25
+	summary := models.ChildSummary{
+		Stories: 2, Pages: 9, ActiveSeconds: 3900,
+		Objectives: []models.ObjectiveProgress{
+			{Objective: models.Objective{Title: "Counting"}, Pages: 3},
+			{Objective: models.Objective{Title: "Colors"}, Pages: 1},
+		},
+		Quizzes: 1, QuizCorrect: 2, QuizAnswered: 3,
+		Flags: 1, UnreviewedFlags: 1,
+		NewWords: 1, WordsReviewed: 4, AverageGrade: &grade,
+		InputTokens: 12000, OutputTokens: 3400,
+	}
+
+	recent, old := to.Add(-24*time.Hour).Unix(), to.Add(-10*24*time.Hour).Unix()
+	quizzes := []models.QuizAttempt{
+		{Score: 2, Total: 3, CreatedAt: recent},
+		{Score: 1, Total: 3, CreatedAt: old},
+	}
+	flags := []models.ModerationFlag{
+		{Source: "prompt", Action: "block", Categories: []string{"violence"}, OriginalText: "<b>fight</b>", CreatedAt: recent},
+		{Source: "prompt", Action: "flag", OriginalText: "old", CreatedAt: old},
+	}
+	words := []models.VocabularyEntry{
+		{Word: models.Word{Word: "enormous", CreatedAt: recent}},
+		{Word: models.Word{Word: "cocoon", CreatedAt: old}},
+	}
+	return NewWeekly(models.User{ID: "nell", Name: &name}, to, summary, quizzes, flags, words)
+}
+
+func TestNewWeekly(t *testing.T) {
+	w := testWeekly(t)
+	if len(w.Quizzes) != 1 || len(w.Flags) != 1 || strings.Join(w.Words, ",") != "enormous" {
+		t.Errorf("report kept quizzes %v, flags %v, words %v; want only this week's", w.Quizzes, w.Flags, w.Words)
+	}
+	if got := w.To.Sub(w.From); got != Week {
+		t.Errorf("report covers %v, want a week", got)
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	var b bytes.Buffer
+	if err := testWeekly(t).Markdown(&b); err != nil {
+		t.Fatal(err)
+	}
+	got := b.String()
+	for _, want := range []string{
+		"# Weekly report for Nell Hardy",
+		"Sun 1 Mar to Sun 8 Mar",
+		"| Time spent | 1h 05m |",
+		"| Quizzes | 1, 66% correct |",
+		"| Reading level | grade 2.2 |",
+		"| Flagged by moderation | 1 (1 not reviewed) |",
+		"- Counting: 3 pages\n- Colors: 1 page\n",
+		"- Sat 7 Mar: 2 of 3 correct\n",
+		"\nenormous\n",
+		`prompt blocked (violence): "<b>fight</b>"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("markdown missing %q:\n%s", want, got)
+		}
+	}
+
+	b.Reset()
+	if err := (Weekly{Child: models.User{ID: "sam"}}).Markdown(&b); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"No learning objectives", "No quizzes", "No new words", "Nothing was flagged", "not measured"} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("empty report missing %q:\n%s", want, b.String())
+		}
+	}
+}
+
+func TestHTML(t *testing.T) {
+	var b bytes.Buffer
+	if err := testWeekly(t).HTML(&b); err != nil {
+		t.Fatal(err)
+	}
+	got := b.String()
+	if !strings.Contains(got, "<h1>Weekly report for Nell Hardy</h1>") || !strings.Contains(got, "<li>Counting: 3 pages</li>") {
+		t.Errorf("html report incomplete:\n%s", got)
+	}
+	if strings.Contains(got, "<b>fight</b>") || !strings.Contains(got, "&lt;b&gt;fight&lt;/b&gt;") {
+		t.Errorf("flagged text not escaped:\n%s", got)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	dir := t.TempDir() + "/reports"
+	paths, err := Write(dir, testWeekly(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{dir + "/nell-hardy-2026-03-08.md", dir + "/nell-hardy-2026-03-08.html"}
+	if strings.Join(paths, " ") != strings.Join(want, " ") {
+		t.Fatalf("paths = %v, want %v", paths, want)
+	}
+	for _, path := range paths {
+		if info, err := os.Stat(path); err != nil || info.Size() == 0 {
+			t.Errorf("%s not written: %v", path, err)
+		}
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := map[time.Duration]string{
+		0:                               "0m",
+		25 * time.Minute:                "25m",
+		65*time.Minute + 40*time.Second: "1h 06m",
+		3 * time.Hour:                   "3h 00m",
+	}
+	for d, want := range tests {
+		if got := FormatDuration(d); got != want {
+			t.Errorf("FormatDuration(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
@@ -27,16 +27,29 @@ func NewLoader(database *db.Database, logger *slog.Logger) *Loader {
 }
 
 // LoadFromDirectory loads all seed data from the specified directory.
-// It expects users.json, stories.json, and pages.json files.
+// It expects households.json, users.json, curriculum.json, stories.json,
+// and pages.json files.
 func (l *Loader) LoadFromDirectory(dir string) error {
 	ctx := context.Background()
 
+	// Load households
+	householdsFile := filepath.Join(dir, "households.json")
+	if err := l.loadHouseholds(ctx, householdsFile); err != nil {
+		return fmt.Errorf("load households: %w", err)
+	}
+
 	// Load users
 	usersFile := filepath.Join(dir, "users.json")
 	if err := l.loadUsers(ctx, usersFile); err != nil {
 		return fmt.Errorf("load users: %w", err)
 	}
 
+	// Load curriculum
+	curriculumFile := filepath.Join(dir, "curriculum.json")
+	if err := l.loadCurriculum(ctx, curriculumFile); err != nil {
+		return fmt.Errorf("load curriculum: %w", err)
+	}
+
 	// Load stories
 	storiesFile := filepath.Join(dir, "stories.json")
 	if err := l.loadStories(ctx, storiesFile); err != nil {
@@ -53,6 +66,46 @@ func (l *Loader) LoadFromDirectory(dir string) error {
 	return nil
 }
 
+func (l *Loader) loadHouseholds(ctx context.Context, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			l.logger.Warn("households seed file not found", "path", path)
+			return nil
+		}
+		return fmt.Errorf("read file: %w", err)
+	}
+
+	var households []models.Household
+	if err := json.Unmarshal(data, &households); err != nil {
+		return fmt.Errorf("parse JSON: %w", err)
+	}
+
+	for _, household := range households {
+		// Check if household already exists
+		existing, err := l.db.GetHouseholdByID(ctx, household.ID)
+		if err == nil && existing != nil {
+			l.logger.Debug("household already exists, skipping", "id", household.ID)
+			continue
+		}
+
+		if err := l.db.CreateHousehold(ctx, &household); err != nil {
+			l.logger.Error("failed to create household", "id", household.ID, "error", err)
+			continue
+		}
+		l.logger.Info("created household", "id", household.ID, "name", household.Name)
+	}
+
+	return nil
+}
+
+// seedUser is a user in users.json, with a guardian's PIN in plain text. It
+// is hashed before the user is stored.
+type seedUser struct {
+	models.User
+	PIN string `json:"pin"`
+}
+
 func (l *Loader) loadUsers(ctx context.Context, path string) error {
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -63,12 +116,13 @@ func (l *Loader) loadUsers(ctx context.Context, path string) error {
 		return fmt.Errorf("read file: %w", err)
 	}
 
-	var users []models.User
+	var users []seedUser
 	if err := json.Unmarshal(data, &users); err != nil {
 		return fmt.Errorf("parse JSON: %w", err)
 	}
 
-	for _, user := range users {
+	for _, seeded := range users {
+		user := seeded.User
 		// Check if user already exists
 		existing, err := l.db.GetUserByID(ctx, user.ID)
 		if err == nil && existing != nil {
@@ -76,6 +130,17 @@ func (l *Loader) loadUsers(ctx context.Context, path string) error {
 			continue
 		}
 
+		if err := user.ValidateProfile(); err != nil {
+			l.logger.Error("invalid user profile", "id", user.ID, "error", err)
+			continue
+		}
+		if seeded.PIN != "" {
+			if err := user.SetPIN(seeded.PIN); err != nil {
+				l.logger.Error("invalid user PIN", "id", user.ID, "error", err)
+				continue
+			}
+		}
+
 		if err := l.db.CreateUser(ctx, &user); err != nil {
 			l.logger.Error("failed to create user", "id", user.ID, "error", err)
 			continue
@@ -86,6 +151,78 @@ func (l *Loader) loadUsers(ctx context.Context, path string) error {
 	return nil
 }
 
+// seedSubject is a subject in curriculum.json, with its objectives.
+type seedSubject struct {
+	models.Subject
+	Objectives []models.Objective `json:"objectives"`
+}
+
+func (l *Loader) loadCurriculum(ctx context.Context, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			l.logger.Warn("curriculum seed file not found", "path", path)
+			return nil
+		}
+		return fmt.Errorf("read file: %w", err)
+	}
+
+	var subjects []seedSubject
+	if err := json.Unmarshal(data, &subjects); err != nil {
+		return fmt.Errorf("parse JSON: %w", err)
+	}
+
+	existingSubjects, err := l.db.ListSubjects(ctx)
+	if err != nil {
+		return fmt.Errorf("list subjects: %w", err)
+	}
+	existingObjectives, err := l.db.ListObjectives(ctx)
+	if err != nil {
+		return fmt.Errorf("list objectives: %w", err)
+	}
+	exists := make(map[string]bool)
+	for _, subject := range existingSubjects {
+		exists[subject.ID] = true
+	}
+	for _, objective := range existingObjectives {
+		exists[objective.ID] = true
+	}
+
+	for _, subject := range subjects {
+		// Objectives can be added to a subject that already exists.
+		if exists[subject.ID] {
+			l.logger.Debug("subject already exists, skipping", "id", subject.ID)
+		} else {
+			if err := l.db.CreateSubject(ctx, &subject.Subject); err != nil {
+				l.logger.Error("failed to create subject", "id", subject.ID, "error", err)
+				continue
+			}
+			l.logger.Info("created subject", "id", subject.ID, "name", subject.Name)
+		}
+
+		for _, objective := range subject.Objectives {
+			if exists[objective.ID] {
+				l.logger.Debug("objective already exists, skipping", "id", objective.ID)
+				continue
+			}
+			objective.SubjectID = subject.ID
+			if err := l.db.CreateObjective(ctx, &objective); err != nil {
+				l.logger.Error("failed to create objective", "id", objective.ID, "error", err)
+				continue
+			}
+			l.logger.Info("created objective", "id", objective.ID, "title", objective.Title)
+		}
+	}
+
+	return nil
+}
+
+// seedStory is a story in stories.json, with the IDs of the objectives it teaches.
+type seedStory struct {
+	models.Story
+	Objectives []string `json:"objectives"`
+}
+
 func (l *Loader) loadStories(ctx context.Context, path string) error {
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -96,7 +233,7 @@ func (l *Loader) loadStories(ctx context.Context, path string) error {
 		return fmt.Errorf("read file: %w", err)
 	}
 
-	var stories []models.Story
+	var stories []seedStory
 	if err := json.Unmarshal(data, &stories); err != nil {
 		return fmt.Errorf("parse JSON: %w", err)
 	}
@@ -109,10 +246,15 @@ func (l *Loader) loadStories(ctx context.Context, path string) error {
 			continue
 		}
 
-		if err := l.db.CreateStory(ctx, &story); err != nil {
+		if err := l.db.CreateStory(ctx, &story.Story); err != nil {
 			l.logger.Error("failed to create story", "id", story.ID, "error", err)
 			continue
 		}
+		if len(story.Objectives) > 0 {
+			if err := l.db.SetStoryObjectives(ctx, story.ID, story.Objectives); err != nil {
+				l.logger.Error("failed to set story objectives", "id", story.ID, "error", err)
+			}
+		}
 		l.logger.Info("created story", "id", story.ID, "title", story.Title)
 	}
 
@@ -3,14 +3,27 @@ package tui
 import (
 	"context"
 	"fmt"
+	"image"
 	"log/slog"
+	"os"
+	"strings"
+	"time"
 
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/kbrakke/illustrated-primer/internal/ai"
 	"github.com/kbrakke/illustrated-primer/internal/db"
+	"github.com/kbrakke/illustrated-primer/internal/illustration"
 	"github.com/kbrakke/illustrated-primer/internal/models"
+	"github.com/kbrakke/illustrated-primer/internal/narration"
+	"github.com/kbrakke/illustrated-primer/internal/readability"
+	"github.com/kbrakke/illustrated-primer/internal/voice"
+	"github.com/muesli/termenv"
 )
 
 // AppMode represents the current mode/screen of the application.
@@ -21,6 +34,25 @@ const (
 	ModeStoryList
 	ModeStoryView
 	ModeChat
+	ModeQuiz
+	ModeWords
+	ModeReview
+	ModeParent
+	ModeLocked
+)
+
+// storyInputKind identifies what the shared text input is collecting in the story list.
+type storyInputKind int
+
+const (
+	storyInputNone storyInputKind = iota
+	storyInputTitle
+	storyInputRename
+	storyInputSummary
+	storyInputArtStyle
+	storyInputPromptTemplate
+	storyInputFilter
+	storyInputGuardianPIN
 )
 
 // Model is the main application state for BubbleTea.
@@ -36,22 +68,119 @@ type Model struct {
 	currentUser  *models.User
 	currentStory *models.Story
 
+	// Curriculum
+	subjects        []models.Subject
+	curriculum      []models.Objective // all objectives, grouped by subject
+	storyObjectives []models.Objective // objectives the current story teaches
+
 	// UI State
 	selectedIndex       int
 	inputBuffer         string
 	conversationHistory []string
 	streamingResponse   string
+	streamRendered      string
+	stream              *ai.Stream
+	responseTemplate    string    // prompt template of the response in progress
+	usage               *ai.Usage // tokens spent on the response in progress
+	streamView          *streamRender
 	isLoading           bool
 	statusMessage       string
 
+	// Story list state
+	storyInput    storyInputKind
+	storySort     models.StorySortOrder
+	storyFilter   string
+	confirmDelete bool
+	picker        *objectivePicker // open while choosing a new story's objectives
+
+	// Guardian-only actions wait for a guardian's PIN, then replay
+	guarded          tea.KeyMsg // the action waiting for a PIN
+	guardianApproved bool       // set while the approved action replays
+
+	// Households
+	scope     *householdScope // who can be chosen; nil for everyone
+	switching *models.User    // the child waiting for a guardian's PIN to switch to
+
+	// Wrong PINs at any prompt, kept while prompts open and close
+	pinAttempts    int       // in a row
+	pinLockedUntil time.Time // when prompts take PINs again
+
+	// Chat composer state
+	drafts       map[string]string // unsent messages keyed by story ID
+	historyIndex int               // position in prompt history, -1 when not browsing
+	historyDraft string            // draft saved while browsing history
+	offerChoices bool              // ask for suggested next actions with each page
+	simplify     bool              // rewrite pages that read above the child's level
+	choices      []string          // next actions offered with the latest page
+	choiceIndex  int               // choice copied into the composer, -1 for none
+
+	// Read-aloud state
+	reading    bool               // read-aloud mode is on in the story view
+	readIndex  int                // index in pages of the page being read
+	readID     int                // identifies the current playback; stale messages are ignored
+	playback   narration.Playback // nil while narration is being prepared
+	readPaused bool
+	cues       []narration.Cue // sentence timing, when available
+	cueIndex   int             // sentence being spoken, -1 if unknown
+
+	// Quiz state
+	quiz   *quizState // nil while the questions are being written
+	quizID int        // identifies the current quiz; stale messages are ignored
+
+	// Vocabulary state
+	vocabulary  []models.VocabularyEntry // the child's words, for "my words"
+	definitions map[string]string        // by word, pitched at the child's age band
+	defining    map[string]bool          // words whose definitions are being looked up
+	glossary    bool                     // the story view lists definitions of highlighted words
+	wordIndex   int                      // word selected in "my words"
+
+	// Review state
+	dueWords []models.VocabularyEntry // words due for review, most overdue first
+	woven    map[string]bool          // due words already woven into a page
+	weaving  []string                 // due words asked for in the page being written
+	review   *reviewState             // nil while the due words load
+
+	// Screen time
+	screen *screenState // the current child's session, nil until one is chosen
+
+	// Parent dashboard state
+	parent    *parentState // open while in the parent dashboard
+	parentPIN string       // unlocks the parent dashboard; empty disables it
+	reportDir string       // where weekly reports are exported
+
+	// Voice input state
+	listening    bool             // recording was requested; recording is nil until it starts
+	recording    *voice.Recording // capture in progress
+	recordID     int              // identifies the current recording; stale messages are ignored
+	transcribing bool
+
 	// BubbleTea components
 	textInput textinput.Model
+	composer  textarea.Model
 	spinner   spinner.Model
+	help      help.Model
+	keys      KeyMap
+
+	// Appearance
+	markdown bool
+	themes   []Theme
+	theme    Theme
+	styles   Styles
+	profile  termenv.Profile
+	graphics GraphicsProtocol
+	images   *imageCache
 
 	// Dependencies
-	db       *db.Database
-	aiClient ai.Client
-	logger   *slog.Logger
+	db          *db.Database
+	aiClient    ai.Client
+	guard       *ai.Guard
+	prompts     *ai.Registry
+	illustrator *illustration.Illustrator
+	narrator    *narration.Narrator
+	player      narration.Player
+	recorder    *voice.Recorder
+	transcriber voice.Transcriber
+	logger      *slog.Logger
 
 	// Dimensions
 	width  int
@@ -65,6 +194,7 @@ type usersLoadedMsg struct {
 }
 
 type storiesLoadedMsg struct {
+	userID  string
 	stories []models.Story
 	err     error
 }
@@ -74,12 +204,19 @@ type pagesLoadedMsg struct {
 	err   error
 }
 
+type aiStreamStartedMsg struct {
+	stream   *ai.Stream
+	prompt   string     // the prompt as sent, after moderation
+	verdict  ai.Verdict // moderation of the prompt
+	template string     // the prompt template used, "" for the fallback prompt
+}
+
 type aiChunkMsg struct {
 	content string
 }
 
 type aiDoneMsg struct {
-	fullResponse string
+	tokens ai.Tokens // what the streamed response used
 }
 
 type aiErrorMsg struct {
@@ -92,28 +229,249 @@ type storyCreatedMsg struct {
 }
 
 type pageSavedMsg struct {
+	page *models.Page
+	err  error
+}
+
+type pageIllustratedMsg struct {
+	pageID string
+	path   string
+	err    error
+}
+
+type pageNarratedMsg struct {
+	pageID string
+	path   string
+	err    error
+}
+
+type storyUpdatedMsg struct {
+	story *models.Story
+	err   error
+}
+
+type storyDeletedMsg struct {
+	id  string
 	err error
 }
 
+type storyDuplicatedMsg struct {
+	story *models.Story
+	err   error
+}
+
+type userUpdatedMsg struct {
+	user *models.User
+	err  error
+}
+
+type imageLoadedMsg struct {
+	path string
+	img  image.Image
+	err  error
+}
+
+// Option is a function that configures a Model.
+type Option func(*Model)
+
+// WithKeyMap sets the key bindings used by the application.
+func WithKeyMap(keys KeyMap) Option {
+	return func(m *Model) {
+		m.keys = keys
+	}
+}
+
+// WithThemes adds themes to the built-in set. A theme with the same name as
+// a built-in replaces it.
+func WithThemes(themes []Theme) Option {
+	return func(m *Model) {
+		for _, theme := range themes {
+			replaced := false
+			for i := range m.themes {
+				if m.themes[i].Name == theme.Name {
+					m.themes[i] = theme
+					replaced = true
+				}
+			}
+			if !replaced {
+				m.themes = append(m.themes, theme)
+			}
+		}
+	}
+}
+
+// WithColorProfile overrides the detected terminal color profile.
+func WithColorProfile(profile termenv.Profile) Option {
+	return func(m *Model) {
+		m.profile = profile
+	}
+}
+
+// WithMarkdown enables or disables markdown rendering of completions.
+// When disabled, completions are shown as plain wrapped text.
+func WithMarkdown(enabled bool) Option {
+	return func(m *Model) {
+		m.markdown = enabled
+	}
+}
+
+// WithChoices enables or disables suggested next actions. When enabled,
+// each page is requested as structured output with 2-4 choices the child
+// can pick from instead of writing the next prompt.
+func WithChoices(enabled bool) Option {
+	return func(m *Model) {
+		m.offerChoices = enabled
+	}
+}
+
+// WithSimplify enables or disables reading-level enforcement. When enabled,
+// a page that scores above the child's target grade level is rewritten more
+// simply before it is shown.
+func WithSimplify(enabled bool) Option {
+	return func(m *Model) {
+		m.simplify = enabled
+	}
+}
+
+// WithParentPIN sets the PIN that unlocks the parent dashboard. Without
+// one the dashboard can't be opened.
+func WithParentPIN(pin string) Option {
+	return func(m *Model) {
+		m.parentPIN = pin
+	}
+}
+
+// WithReportDir sets the directory weekly reports are exported to.
+func WithReportDir(dir string) Option {
+	return func(m *Model) {
+		m.reportDir = dir
+	}
+}
+
+// WithGraphics overrides the detected terminal graphics protocol used to
+// draw page illustrations. GraphicsNone hides them.
+func WithGraphics(protocol GraphicsProtocol) Option {
+	return func(m *Model) {
+		m.graphics = protocol
+	}
+}
+
+// WithModeration checks prompts before they are sent and completions before
+// they are shown and saved.
+func WithModeration(guard *ai.Guard) Option {
+	return func(m *Model) {
+		m.guard = guard
+	}
+}
+
+// WithPromptTemplates sets the registry of system prompt templates that
+// stories are assigned to and pin.
+func WithPromptTemplates(registry *ai.Registry) Option {
+	return func(m *Model) {
+		m.prompts = registry
+	}
+}
+
+// WithIllustrator enables illustration generation for new pages.
+func WithIllustrator(illustrator *illustration.Illustrator) Option {
+	return func(m *Model) {
+		m.illustrator = illustrator
+	}
+}
+
+// WithNarrator enables text-to-speech narration of new pages.
+func WithNarrator(narrator *narration.Narrator) Option {
+	return func(m *Model) {
+		m.narrator = narrator
+	}
+}
+
+// WithPlayer sets the audio player used to read pages aloud.
+func WithPlayer(player narration.Player) Option {
+	return func(m *Model) {
+		m.player = player
+	}
+}
+
+// WithVoiceInput enables spoken prompts in the chat: audio is captured with
+// recorder and turned into text with transcriber.
+func WithVoiceInput(recorder *voice.Recorder, transcriber voice.Transcriber) Option {
+	return func(m *Model) {
+		m.recorder = recorder
+		m.transcriber = transcriber
+	}
+}
+
 // New creates a new TUI Model with the given dependencies.
-func New(database *db.Database, aiClient ai.Client, logger *slog.Logger) Model {
+func New(database *db.Database, aiClient ai.Client, logger *slog.Logger, opts ...Option) Model {
 	ti := textinput.New()
-	ti.Placeholder = "Type your message..."
 	ti.CharLimit = 1000
 	ti.Width = 60
 
 	s := spinner.New()
 	s.Spinner = spinner.Dot
 
-	return Model{
-		mode:      ModeUserSelection,
-		running:   true,
-		textInput: ti,
-		spinner:   s,
-		db:        database,
-		aiClient:  aiClient,
-		logger:    logger,
+	m := Model{
+		mode:         ModeUserSelection,
+		running:      true,
+		textInput:    ti,
+		composer:     newComposer(),
+		spinner:      s,
+		drafts:       make(map[string]string),
+		definitions:  make(map[string]string),
+		defining:     make(map[string]bool),
+		woven:        make(map[string]bool),
+		historyIndex: -1,
+		offerChoices: true,
+		choiceIndex:  -1,
+		cueIndex:     -1,
+		markdown:     true,
+		reportDir:    "reports",
+		streamView:   &streamRender{},
+		help:         help.New(),
+		keys:         DefaultKeyMap,
+		themes:       BuiltinThemes(),
+		profile:      lipgloss.ColorProfile(),
+		graphics:     DetectGraphics(os.Getenv),
+		images:       newImageCache(),
+		db:           database,
+		aiClient:     aiClient,
+		prompts:      ai.NewRegistry(),
+		logger:       logger,
+	}
+
+	for _, opt := range opts {
+		opt(&m)
 	}
+
+	m.applyTheme(DefaultThemeName)
+	return m
+}
+
+// applyTheme switches to the named theme, degraded for the terminal's color
+// profile. Unknown names fall back to the default theme.
+func (m *Model) applyTheme(name string) {
+	theme := m.themes[0]
+	for _, t := range m.themes {
+		if t.Name == name {
+			theme = t
+			break
+		}
+	}
+
+	m.theme = theme
+	m.styles = NewStyles(theme.ForProfile(m.profile))
+	m.help.Styles = helpStyles(theme.ForProfile(m.profile))
+}
+
+// nextThemeName returns the theme after the current one, wrapping around.
+func (m Model) nextThemeName() string {
+	for i, t := range m.themes {
+		if t.Name == m.theme.Name {
+			return m.themes[(i+1)%len(m.themes)].Name
+		}
+	}
+	return DefaultThemeName
 }
 
 // Init initializes the model and returns the initial command.
@@ -136,7 +494,7 @@ func (m Model) loadUsers() tea.Cmd {
 func (m Model) loadStories(userID string) tea.Cmd {
 	return func() tea.Msg {
 		stories, err := m.db.ListStoriesByUser(context.Background(), userID)
-		return storiesLoadedMsg{stories: stories, err: err}
+		return storiesLoadedMsg{userID: userID, stories: stories, err: err}
 	}
 }
 
@@ -148,43 +506,195 @@ func (m Model) loadPages(storyID string) tea.Cmd {
 	}
 }
 
-// sendMessage sends a message to the AI and streams the response.
+// loadIllustrations decodes the illustrations of pages that aren't cached yet.
+func (m Model) loadIllustrations(pages []models.Page) tea.Cmd {
+	if m.graphics == GraphicsNone {
+		return nil
+	}
+
+	var cmds []tea.Cmd
+	for _, page := range pages {
+		if page.ImagePath == nil || !m.images.needsLoad(*page.ImagePath) {
+			continue
+		}
+		path := *page.ImagePath
+		cmds = append(cmds, func() tea.Msg {
+			img, err := loadImage(path)
+			return imageLoadedMsg{path: path, img: img, err: err}
+		})
+	}
+	return tea.Batch(cmds...)
+}
+
+// sendMessage sends a message to the AI and starts streaming the response.
+// With moderation enabled the message is checked first, and may be rewritten
+// or blocked.
 func (m Model) sendMessage(message string) tea.Cmd {
 	return func() tea.Msg {
 		ctx := context.Background()
-		ch, err := m.aiClient.GenerateResponseStream(ctx, message, m.conversationHistory)
+		opts, templateID := m.storyOptions()
+
+		verdict := ai.Verdict{Action: ai.ActionAllow, Text: message}
+		if m.guard != nil {
+			var err error
+			verdict, err = m.guard.Check(ctx, ai.SourcePrompt, message)
+			if err != nil {
+				return aiErrorMsg{err: err}
+			}
+			if verdict.Action == ai.ActionBlock {
+				return promptBlockedMsg{prompt: message, verdict: verdict}
+			}
+		}
+
+		stream, err := m.aiClient.GenerateResponseStream(ctx, verdict.Text, m.conversationHistory, opts)
 		if err != nil {
 			return aiErrorMsg{err: err}
 		}
+		return aiStreamStartedMsg{stream: stream, prompt: verdict.Text, verdict: verdict, template: templateID}
+	}
+}
 
-		// Collect the full response from the stream
-		var fullResponse string
-		for chunk := range ch {
-			fullResponse += chunk
-		}
+// storyOptions returns the options for asking for a page of the current
+// story: the system prompt for the current child and story, and the page
+// format when pages come with choices. It also returns the ID of the
+// template the prompt was rendered from, which is empty if rendering failed
+// and the default prompt applies.
+func (m Model) storyOptions() (ai.RequestOptions, string) {
+	var opts ai.RequestOptions
+	if m.structuredPages() {
+		opts.Format = ai.StoryPageFormat
+	}
+	// The system prompt comes from the story's template, pitched at the
+	// child's age and reading level.
+	tmpl := m.promptTemplate()
+	pc := ai.NewPromptContext(m.currentUser, m.currentStory, time.Now())
+	pc.Objectives = m.objectiveTitles()
+	pc.Review = m.weaving
+	pc.WrapUp = m.screen != nil && m.screen.wrappingUp
+	system, err := tmpl.Render(pc)
+	if err != nil {
+		m.logger.Error("failed to render system prompt, using the default", "error", err)
+		return opts, ""
+	}
+	opts.System = system
+	return opts, tmpl.ID()
+}
+
+// promptTemplate returns the template pinned by the current story. A story
+// pinned to a template that is no longer available falls back to the default.
+func (m Model) promptTemplate() *ai.PromptTemplate {
+	pinned := ""
+	if m.currentStory != nil {
+		pinned = m.currentStory.PromptTemplate
+	}
+	tmpl, err := m.prompts.Lookup(pinned)
+	if err != nil {
+		m.logger.Warn("prompt template not found, using the default", "template", pinned, "error", err)
+		tmpl, _ = m.prompts.Lookup(ai.DefaultTemplate)
+	}
+	return tmpl
+}
 
-		return aiDoneMsg{fullResponse: fullResponse}
+// finishResponse ends a chat turn: the page is saved and added to the
+// conversation, and its choices are offered.
+func (m *Model) finishResponse(prompt string, page ai.StoryPage) tea.Cmd {
+	var cmd tea.Cmd
+	if prompt != "" {
+		cmd = m.savePage(prompt, page)
+		m.conversationHistory = append(m.conversationHistory, prompt, page.Text)
+		m.choices = page.Choices
+		m.choiceIndex = -1
 	}
+	m.isLoading = false
+	m.stream = nil
+	m.inputBuffer = ""
+	m.streamingResponse = ""
+	m.streamRendered = ""
+	m.statusMessage = "Response received"
+	return cmd
 }
 
-// createStory creates a new story.
-func (m Model) createStory(title string) tea.Cmd {
+// waitForChunk reads the next piece of a streaming response.
+func waitForChunk(stream *ai.Stream) tea.Cmd {
+	return func() tea.Msg {
+		chunk, ok := <-stream.Chunks
+		if !ok {
+			return aiDoneMsg{tokens: stream.Tokens()}
+		}
+		return aiChunkMsg{content: chunk}
+	}
+}
+
+// createStory creates a new story teaching the given objectives.
+func (m Model) createStory(title string, objectiveIDs []string) tea.Cmd {
 	return func() tea.Msg {
 		if m.currentUser == nil {
 			return storyCreatedMsg{err: fmt.Errorf("no user selected")}
 		}
 
 		story := models.NewStory(m.currentUser.ID, title, "")
-		err := m.db.CreateStory(context.Background(), story)
-		if err != nil {
+		story.PromptTemplate = m.prompts.Choose().ID()
+		ctx := context.Background()
+		if err := m.db.CreateStory(ctx, story); err != nil {
 			return storyCreatedMsg{err: err}
 		}
+		if len(objectiveIDs) > 0 {
+			if err := m.db.SetStoryObjectives(ctx, story.ID, objectiveIDs); err != nil {
+				return storyCreatedMsg{err: err}
+			}
+		}
 		return storyCreatedMsg{story: story}
 	}
 }
 
-// savePage saves a new page to the database.
-func (m Model) savePage(prompt, completion string) tea.Cmd {
+// updateStory persists changes to an existing story.
+func (m Model) updateStory(story models.Story) tea.Cmd {
+	return func() tea.Msg {
+		err := m.db.UpdateStory(context.Background(), &story)
+		if err != nil {
+			return storyUpdatedMsg{err: err}
+		}
+		return storyUpdatedMsg{story: &story}
+	}
+}
+
+// deleteStory removes a story and its pages.
+func (m Model) deleteStory(id string) tea.Cmd {
+	return func() tea.Msg {
+		err := m.db.DeleteStory(context.Background(), id)
+		return storyDeletedMsg{id: id, err: err}
+	}
+}
+
+// duplicateStory copies a story and its pages under a new title.
+func (m Model) duplicateStory(story models.Story) tea.Cmd {
+	return func() tea.Msg {
+		title := story.Title + " (copy)"
+		copied, err := m.db.DuplicateStory(context.Background(), story.ID, title)
+		if err != nil {
+			return storyDuplicatedMsg{err: err}
+		}
+		return storyDuplicatedMsg{story: copied}
+	}
+}
+
+// updateUser persists changes to a user.
+func (m Model) updateUser(user models.User) tea.Cmd {
+	return func() tea.Msg {
+		err := m.db.UpdateUser(context.Background(), &user)
+		if err != nil {
+			return userUpdatedMsg{err: err}
+		}
+		return userUpdatedMsg{user: &user}
+	}
+}
+
+// savePage saves a new page to the database with its readability scores,
+// the story objectives it worked on and its challenging words added to the
+// child's vocabulary.
+func (m Model) savePage(prompt string, response ai.StoryPage) tea.Cmd {
+	touched := models.MatchObjectives(m.storyObjectives, response.Objectives)
+	usage := m.usage
 	return func() tea.Msg {
 		if m.currentStory == nil {
 			return pageSavedMsg{err: fmt.Errorf("no story selected")}
@@ -196,16 +706,95 @@ func (m Model) savePage(prompt, completion string) tea.Cmd {
 			return pageSavedMsg{err: err}
 		}
 
-		page := models.NewPage(m.currentStory.ID, pageNum, prompt, completion)
+		page := models.NewPage(m.currentStory.ID, pageNum, prompt, response.Text)
+		page.PromptTemplate = m.responseTemplate
+		page.Choices = response.Choices
+		if m.scoresReadability() {
+			page.Readability = readability.Analyze(response.Text).Scores()
+		}
 		if err := m.db.CreatePage(ctx, page); err != nil {
 			return pageSavedMsg{err: err}
 		}
 
+		if len(touched) > 0 {
+			ids := make([]string, len(touched))
+			for i, objective := range touched {
+				ids[i] = objective.ID
+			}
+			if err := m.db.SetPageObjectives(ctx, page.ID, ids); err != nil {
+				return pageSavedMsg{err: err}
+			}
+		}
+
 		if err := m.db.IncrementCurrentPage(ctx, m.currentStory.ID); err != nil {
 			return pageSavedMsg{err: err}
 		}
+		if usage != nil {
+			m.recordUsage(ctx, models.UsagePage, usage.Tokens())
+		}
+
+		// A page without its words in the vocabulary is still a page.
+		if words := m.storyWords(page.Completion); len(words) > 0 && m.currentUser != nil {
+			if err := m.db.RecordWords(ctx, m.currentUser.ID, m.currentStory.ID, page.ID, words); err != nil {
+				m.logger.Error("failed to record words", "page_id", page.ID, "error", err)
+			}
+		}
+
+		return pageSavedMsg{page: page}
+	}
+}
+
+// illustrationTimeout bounds a single illustration request.
+const illustrationTimeout = 2 * time.Minute
+
+// illustratePage generates and stores the illustration for a saved page in
+// the background; the chat carries on while it runs.
+func (m Model) illustratePage(page models.Page) tea.Cmd {
+	if m.illustrator == nil {
+		return nil
+	}
+
+	style := ""
+	if m.currentStory != nil {
+		style = m.currentStory.ArtStyle
+	}
+
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), illustrationTimeout)
+		defer cancel()
+
+		path, err := m.illustrator.Illustrate(ctx, page, style)
+		if err != nil {
+			return pageIllustratedMsg{pageID: page.ID, err: err}
+		}
+		if err := m.db.SetPageImage(ctx, page.ID, path); err != nil {
+			return pageIllustratedMsg{pageID: page.ID, err: err}
+		}
+		return pageIllustratedMsg{pageID: page.ID, path: path}
+	}
+}
 
-		return pageSavedMsg{}
+// narrationTimeout bounds rendering a single page to speech.
+const narrationTimeout = 2 * time.Minute
+
+// narratePage renders a saved page to speech in the background.
+func (m Model) narratePage(page models.Page) tea.Cmd {
+	if m.narrator == nil {
+		return nil
+	}
+
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), narrationTimeout)
+		defer cancel()
+
+		path, err := m.narrator.Narrate(ctx, page)
+		if err != nil {
+			return pageNarratedMsg{pageID: page.ID, err: err}
+		}
+		if err := m.db.SetPageAudio(ctx, page.ID, path); err != nil {
+			return pageNarratedMsg{pageID: page.ID, err: err}
+		}
+		return pageNarratedMsg{pageID: page.ID, path: path}
 	}
 }
 
@@ -218,11 +807,46 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.width = msg.Width
 		m.height = msg.Height
 		m.textInput.Width = msg.Width - 10
+		m.composer.SetWidth(msg.Width - 10)
+		if m.streamingResponse != "" {
+			m.streamRendered = m.streamView.Render(m.pageText(m.streamingResponse), m.width-10, m.renderCompletion)
+		}
+		m.help.Width = msg.Width
 		return m, nil
 
 	case tea.KeyMsg:
 		return m.handleKeyPress(msg)
 
+	case readAloudStartedMsg, readAloudEndedMsg, readAloudTickMsg:
+		return m.updateReadAloud(msg)
+
+	case promptBlockedMsg, completionModeratedMsg, moderationRecordedMsg:
+		return m.updateModeration(msg)
+
+	case curriculumLoadedMsg, storyObjectivesLoadedMsg:
+		return m.updateCurriculum(msg)
+
+	case quizGeneratedMsg, quizSavedMsg:
+		return m.updateQuiz(msg)
+
+	case vocabularyLoadedMsg, wordDefinedMsg:
+		return m.updateVocabulary(msg)
+
+	case dueWordsLoadedMsg, wordReviewedMsg:
+		return m.updateReview(msg)
+
+	case childSummariesLoadedMsg, reportExportedMsg:
+		return m.updateParent(msg)
+
+	case screenSessionStartedMsg, screenTickMsg:
+		return m.updateScreenTime(msg)
+
+	case pageSimplifiedMsg:
+		return m.updateReadability(msg)
+
+	case recordingStartedMsg, recordingTimeoutMsg, transcribedMsg:
+		return m.updateVoice(msg)
+
 	case spinner.TickMsg:
 		var cmd tea.Cmd
 		m.spinner, cmd = m.spinner.Update(msg)
@@ -238,6 +862,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case storiesLoadedMsg:
+		// A child only ever sees their own stories, even if someone else's
+		// finish loading after they took over.
+		if m.currentUser == nil || msg.userID != m.currentUser.ID {
+			break
+		}
 		if msg.err != nil {
 			m.statusMessage = fmt.Sprintf("Error loading stories: %v", msg.err)
 			m.logger.Error("failed to load stories", "error", msg.err)
@@ -258,19 +887,43 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			for _, page := range msg.pages {
 				m.conversationHistory = append(m.conversationHistory, page.Prompt, page.Completion)
 			}
+			m.choices = nil
+			if len(msg.pages) > 0 {
+				m.choices = msg.pages[len(msg.pages)-1].Choices
+			}
+			m.choiceIndex = -1
 			m.statusMessage = fmt.Sprintf("Loaded %d pages", len(msg.pages))
+			cmds = append(cmds, m.loadIllustrations(msg.pages))
 		}
 
+	case aiStreamStartedMsg:
+		m.stream = msg.stream
+		m.responseTemplate = msg.template
+		m.usage = &ai.Usage{}
+		cmds = append(cmds, m.recordModeration(ai.SourcePrompt, m.inputBuffer, msg.verdict), waitForChunk(m.stream))
+		m.inputBuffer = msg.prompt
+
+	case aiChunkMsg:
+		m.streamingResponse += msg.content
+		// A moderated or simplified response is only shown once it has
+		// been checked.
+		if m.guard == nil && !m.simplify && !m.restricted() {
+			m.streamRendered = m.streamView.Render(m.pageText(m.streamingResponse), m.width-10, m.renderCompletion)
+		}
+		cmds = append(cmds, waitForChunk(m.stream))
+
 	case aiDoneMsg:
-		m.isLoading = false
-		m.streamingResponse = msg.fullResponse
-		// Save the page
+		m.usage.Add(msg.tokens)
+		page := m.readResponse(m.streamingResponse)
 		if m.inputBuffer != "" {
-			cmds = append(cmds, m.savePage(m.inputBuffer, msg.fullResponse))
-			m.conversationHistory = append(m.conversationHistory, m.inputBuffer, msg.fullResponse)
-			m.inputBuffer = ""
+			if simplify := m.simplifyCompletion(m.inputBuffer, page); simplify != nil {
+				m.stream = nil
+				m.statusMessage = "Making the story easier to read..."
+				cmds = append(cmds, simplify)
+				break
+			}
 		}
-		m.statusMessage = "Response received"
+		cmds = append(cmds, m.checkCompletion(m.inputBuffer, page))
 
 	case aiErrorMsg:
 		m.isLoading = false
@@ -292,6 +945,103 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.logger.Error("failed to save page", "error", msg.err)
 		} else {
 			m.logger.Info("page saved successfully")
+			if m.currentStory != nil && msg.page.StoryID == m.currentStory.ID {
+				m.pages = append(m.pages, *msg.page)
+			}
+			cmds = append(cmds, m.illustratePage(*msg.page), m.narratePage(*msg.page))
+		}
+
+	case pageIllustratedMsg:
+		if msg.err != nil {
+			m.logger.Error("failed to illustrate page", "page_id", msg.pageID, "error", msg.err)
+			if !m.isLoading {
+				m.statusMessage = fmt.Sprintf("Illustration failed: %v", msg.err)
+			}
+		} else {
+			for i := range m.pages {
+				if m.pages[i].ID == msg.pageID {
+					path := msg.path
+					m.pages[i].ImagePath = &path
+					cmds = append(cmds, m.loadIllustrations(m.pages[i:i+1]))
+				}
+			}
+		}
+
+	case storyUpdatedMsg:
+		if msg.err != nil {
+			m.statusMessage = fmt.Sprintf("Error updating story: %v", msg.err)
+			m.logger.Error("failed to update story", "error", msg.err)
+		} else {
+			for i := range m.stories {
+				if m.stories[i].ID == msg.story.ID {
+					m.stories[i] = *msg.story
+				}
+			}
+			m.statusMessage = fmt.Sprintf("Updated story: %s", msg.story.Title)
+		}
+
+	case storyDeletedMsg:
+		if msg.err != nil {
+			m.statusMessage = fmt.Sprintf("Error deleting story: %v", msg.err)
+			m.logger.Error("failed to delete story", "error", msg.err)
+		} else {
+			for i := range m.stories {
+				if m.stories[i].ID == msg.id {
+					m.stories = append(m.stories[:i], m.stories[i+1:]...)
+					break
+				}
+			}
+			m.clampStorySelection()
+			m.statusMessage = "Story deleted"
+		}
+
+	case storyDuplicatedMsg:
+		if msg.err != nil {
+			m.statusMessage = fmt.Sprintf("Error duplicating story: %v", msg.err)
+			m.logger.Error("failed to duplicate story", "error", msg.err)
+		} else {
+			m.stories = append([]models.Story{*msg.story}, m.stories...)
+			m.statusMessage = fmt.Sprintf("Created copy: %s", msg.story.Title)
+		}
+
+	case imageLoadedMsg:
+		if msg.err != nil {
+			m.images.failed[msg.path] = true
+			m.logger.Warn("failed to load illustration", "path", msg.path, "error", msg.err)
+		} else {
+			m.images.images[msg.path] = msg.img
+		}
+
+	case pageNarratedMsg:
+		if msg.err != nil {
+			m.logger.Error("failed to narrate page", "page_id", msg.pageID, "error", msg.err)
+			if !m.isLoading {
+				m.statusMessage = fmt.Sprintf("Narration failed: %v", msg.err)
+			}
+		} else {
+			for i := range m.pages {
+				if m.pages[i].ID == msg.pageID {
+					path := msg.path
+					m.pages[i].AudioPath = &path
+
+					// Start reading if this page was waiting for its narration.
+					if m.reading && m.playback == nil && i == m.readIndex && m.player != nil {
+						cmds = append(cmds, m.playPage(m.readID, m.pages[i]))
+					}
+				}
+			}
+		}
+
+	case userUpdatedMsg:
+		if msg.err != nil {
+			m.statusMessage = fmt.Sprintf("Error saving user: %v", msg.err)
+			m.logger.Error("failed to update user", "error", msg.err)
+		} else {
+			for i := range m.users {
+				if m.users[i].ID == msg.user.ID {
+					m.users[i] = *msg.user
+				}
+			}
 		}
 	}
 
@@ -301,15 +1051,24 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 // handleKeyPress processes keyboard input.
 func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	// Global quit handling
-	if msg.String() == "ctrl+c" {
+	if key.Matches(msg, m.keys.ForceQuit) {
+		m.stopReading()
+		m.cancelRecording()
 		m.running = false
-		return m, tea.Quit
+		return m, tea.Sequence(m.endScreenSession(models.SessionEndedLeft), tea.Quit)
 	}
 
-	// Handle quit in non-chat modes
-	if m.mode != ModeChat && msg.String() == "q" {
-		m.running = false
-		return m, tea.Quit
+	// Handle quit and help in non-chat modes, unless the user is typing
+	if m.mode != ModeChat && !m.textInput.Focused() {
+		switch {
+		case key.Matches(msg, m.keys.Quit):
+			m.stopReading()
+			m.running = false
+			return m, tea.Sequence(m.endScreenSession(models.SessionEndedLeft), tea.Quit)
+		case key.Matches(msg, m.keys.Help):
+			m.help.ShowAll = !m.help.ShowAll
+			return m, nil
+		}
 	}
 
 	switch m.mode {
@@ -321,91 +1080,351 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.handleStoryViewKeys(msg)
 	case ModeChat:
 		return m.handleChatKeys(msg)
+	case ModeQuiz:
+		return m.handleQuizKeys(msg)
+	case ModeWords:
+		return m.handleWordsKeys(msg)
+	case ModeReview:
+		return m.handleReviewKeys(msg)
+	case ModeParent:
+		return m.handleParentKeys(msg)
+	case ModeLocked:
+		return m.handleLockedKeys(msg)
 	}
 
 	return m, nil
 }
 
 func (m Model) handleUserSelectionKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "up", "k":
+	if m.switching != nil {
+		return m.handleSwitchKeys(msg)
+	}
+
+	users := m.selectableUsers()
+	switch {
+	case key.Matches(msg, m.keys.Up):
 		if m.selectedIndex > 0 {
 			m.selectedIndex--
 		}
-	case "down", "j":
-		if m.selectedIndex < len(m.users)-1 {
+	case key.Matches(msg, m.keys.Down):
+		if m.selectedIndex < len(users)-1 {
 			m.selectedIndex++
 		}
-	case "enter":
-		if len(m.users) > 0 && m.selectedIndex < len(m.users) {
-			m.currentUser = &m.users[m.selectedIndex]
-			m.mode = ModeStoryList
-			m.selectedIndex = 0
-			return m, m.loadStories(m.currentUser.ID)
+	case key.Matches(msg, m.keys.Select):
+		if m.selectedIndex < len(users) {
+			user := users[m.selectedIndex]
+			switch {
+			case user.IsGuardian():
+				m.openParent(&user)
+			case m.needsSwitchPIN(user):
+				m.beginSwitch(user)
+			default:
+				return m.selectChild(user)
+			}
 		}
+	case key.Matches(msg, m.keys.Parent):
+		m.openParent(nil)
 	}
 	return m, nil
 }
 
+// selectChild starts a child's turn: their stories, words and screen time.
+func (m Model) selectChild(child models.User) (tea.Model, tea.Cmd) {
+	for i := range m.users {
+		if m.users[i].ID == child.ID {
+			m.currentUser = &m.users[i]
+		}
+	}
+	m.scope = &householdScope{household: child.HouseholdID, child: child.ID}
+	m.applyTheme(m.currentUser.ThemeName())
+	m.mode = ModeStoryList
+	m.selectedIndex = 0
+	m.vocabulary = nil
+	m.definitions = make(map[string]string)
+	m.defining = make(map[string]bool)
+	m.dueWords = nil
+	m.woven = make(map[string]bool)
+	m.screen = &screenState{limits: m.currentUser.ScreenTime()}
+	return m, tea.Batch(m.loadStories(m.currentUser.ID), m.loadCurriculum(), m.loadDueWords(), m.startScreenTime(*m.currentUser, false))
+}
+
 func (m Model) handleStoryListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "up", "k":
+	if m.storyInput != storyInputNone {
+		return m.handleStoryInputKeys(msg)
+	}
+	if m.picker != nil {
+		return m.handleObjectivePickerKeys(msg)
+	}
+
+	if m.confirmDelete {
+		m.confirmDelete = false
+		story, ok := m.selectedStory()
+		if ok && key.Matches(msg, m.keys.Confirm) {
+			m.statusMessage = fmt.Sprintf("Deleting %s...", story.Title)
+			return m, m.deleteStory(story.ID)
+		}
+		m.statusMessage = "Delete cancelled"
+		return m, nil
+	}
+
+	if m.needsGuardian(msg) {
+		if m.pinLockedOut() {
+			return m, nil
+		}
+		m.guarded = msg
+		m.beginStoryInput(storyInputGuardianPIN, "", "Ask a grown-up to enter their PIN:")
+		m.textInput.EchoMode = textinput.EchoPassword
+		return m, nil
+	}
+
+	visible := m.visibleStories()
+
+	switch {
+	case key.Matches(msg, m.keys.Up):
 		if m.selectedIndex > 0 {
 			m.selectedIndex--
 		}
-	case "down", "j":
-		if m.selectedIndex < len(m.stories)-1 {
+	case key.Matches(msg, m.keys.Down):
+		if m.selectedIndex < len(visible)-1 {
 			m.selectedIndex++
 		}
-	case "enter":
-		if len(m.stories) > 0 && m.selectedIndex < len(m.stories) {
-			m.currentStory = &m.stories[m.selectedIndex]
+	case key.Matches(msg, m.keys.Select):
+		if story, ok := m.selectedStory(); ok {
+			m.currentStory = &story
+			m.storyObjectives = nil
 			m.mode = ModeStoryView
-			return m, m.loadPages(m.currentStory.ID)
-		}
-	case "n":
-		// Enter new story creation mode
-		m.textInput.Focus()
-		m.statusMessage = "Enter story title:"
-		m.inputBuffer = ""
-	case "esc":
-		if m.textInput.Focused() {
-			m.textInput.Blur()
+			return m, tea.Batch(m.loadPages(m.currentStory.ID), m.loadStoryObjectives(m.currentStory.ID))
+		}
+	case key.Matches(msg, m.keys.NewStory):
+		m.beginStoryInput(storyInputTitle, "", "Enter story title:")
+	case key.Matches(msg, m.keys.Rename):
+		if story, ok := m.selectedStory(); ok {
+			m.beginStoryInput(storyInputRename, story.Title, "Enter new title:")
+		}
+	case key.Matches(msg, m.keys.EditSummary):
+		if story, ok := m.selectedStory(); ok {
+			m.beginStoryInput(storyInputSummary, story.Summary, "Enter story summary:")
+		}
+	case key.Matches(msg, m.keys.ArtStyle):
+		if story, ok := m.selectedStory(); ok {
+			m.beginStoryInput(storyInputArtStyle, story.ArtStyle, "Describe the illustration style (empty for default):")
+		}
+	case key.Matches(msg, m.keys.PromptTemplate):
+		if story, ok := m.selectedStory(); ok {
+			m.beginStoryInput(storyInputPromptTemplate, story.PromptTemplate, "Pin a prompt template as name@version, or name for its latest version:")
+		}
+	case key.Matches(msg, m.keys.Duplicate):
+		if story, ok := m.selectedStory(); ok {
+			m.statusMessage = fmt.Sprintf("Duplicating %s...", story.Title)
+			return m, m.duplicateStory(story)
+		}
+	case key.Matches(msg, m.keys.Delete):
+		if story, ok := m.selectedStory(); ok {
+			m.confirmDelete = true
+			m.statusMessage = fmt.Sprintf("Delete %q and all its pages? (%s to confirm)", story.Title, m.keys.Confirm.Help().Key)
+		}
+	case key.Matches(msg, m.keys.Sort):
+		m.storySort = m.storySort.Next()
+		m.selectedIndex = 0
+		m.statusMessage = fmt.Sprintf("Sorted by %s", m.storySort)
+	case key.Matches(msg, m.keys.Filter):
+		m.beginStoryInput(storyInputFilter, m.storyFilter, "Filter stories:")
+	case key.Matches(msg, m.keys.Words):
+		m.mode = ModeWords
+		m.wordIndex = 0
+		return m, m.loadVocabulary()
+	case key.Matches(msg, m.keys.Practice):
+		return m, m.startReview()
+	case key.Matches(msg, m.keys.Theme):
+		if m.currentUser != nil {
+			name := m.nextThemeName()
+			m.applyTheme(name)
+			user := *m.currentUser
+			user.Theme = &name
+			m.currentUser = &user
+			m.statusMessage = fmt.Sprintf("Theme: %s", name)
+			return m, m.updateUser(user)
+		}
+	case key.Matches(msg, m.keys.Back):
+		if m.storyFilter != "" {
+			m.storyFilter = ""
+			m.selectedIndex = 0
 			m.statusMessage = ""
 		} else {
-			m.mode = ModeUserSelection
+			return m, m.leaveChild()
+		}
+	}
+	return m, nil
+}
+
+// handleStoryInputKeys routes keys to the text input while a story list prompt is open.
+func (m Model) handleStoryInputKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	kind := m.storyInput
+
+	switch {
+	case key.Matches(msg, m.keys.Back):
+		if kind == storyInputFilter {
+			m.storyFilter = ""
 			m.selectedIndex = 0
-			m.currentUser = nil
 		}
-	default:
-		// Handle text input for new story
-		if m.textInput.Focused() {
-			var cmd tea.Cmd
-			m.textInput, cmd = m.textInput.Update(msg)
-			if msg.String() == "enter" && m.textInput.Value() != "" {
-				title := m.textInput.Value()
-				m.textInput.SetValue("")
-				m.textInput.Blur()
-				return m, m.createStory(title)
+		m.endStoryInput()
+		m.statusMessage = ""
+		return m, nil
+	case key.Matches(msg, m.keys.Select):
+		value := m.textInput.Value()
+		story, hasStory := m.selectedStory()
+		m.endStoryInput()
+		m.statusMessage = ""
+
+		switch kind {
+		case storyInputGuardianPIN:
+			if _, err := m.checkPIN(m.guardians(m.currentUser), value); err != nil {
+				// Too many wrong PINs end the child's turn, as on the
+				// dashboard and the lock screen.
+				if m.wrongPIN() {
+					cmd := m.leaveChild()
+					m.pinLockedOut()
+					return m, cmd
+				}
+				m.statusMessage = "That PIN isn't right."
+				return m, nil
 			}
+			m.pinAttempts = 0
+			m.guardianApproved = true
+			updated, cmd := m.handleStoryListKeys(m.guarded)
+			m = updated.(Model)
+			m.guardianApproved = false
 			return m, cmd
+		case storyInputTitle:
+			if value != "" {
+				return m, m.beginObjectivePicker(value)
+			}
+		case storyInputRename:
+			if value != "" && hasStory {
+				story.Title = value
+				return m, m.updateStory(story)
+			}
+		case storyInputSummary:
+			if hasStory {
+				story.Summary = value
+				return m, m.updateStory(story)
+			}
+		case storyInputArtStyle:
+			if hasStory {
+				story.ArtStyle = value
+				return m, m.updateStory(story)
+			}
+		case storyInputPromptTemplate:
+			if hasStory {
+				if _, err := m.prompts.Lookup(value); err != nil {
+					m.statusMessage = fmt.Sprintf("Can't pin prompt template: %v", err)
+					return m, nil
+				}
+				story.PromptTemplate = value
+				return m, m.updateStory(story)
+			}
 		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.textInput, cmd = m.textInput.Update(msg)
+	if kind == storyInputFilter {
+		m.storyFilter = m.textInput.Value()
+		m.selectedIndex = 0
+	}
+	return m, cmd
+}
+
+// beginStoryInput focuses the shared text input for a story list prompt.
+func (m *Model) beginStoryInput(kind storyInputKind, value, status string) {
+	m.storyInput = kind
+	m.textInput.SetValue(value)
+	m.textInput.CursorEnd()
+	m.textInput.Focus()
+	m.statusMessage = status
+}
+
+// endStoryInput closes the story list prompt and clears the text input.
+func (m *Model) endStoryInput() {
+	m.storyInput = storyInputNone
+	m.textInput.SetValue("")
+	m.textInput.EchoMode = textinput.EchoNormal
+	m.textInput.Blur()
+}
+
+// visibleStories returns the stories shown in the list after filtering and sorting.
+func (m Model) visibleStories() []models.Story {
+	visible := append([]models.Story(nil), models.FilterStories(m.stories, m.storyFilter)...)
+	models.SortStories(visible, m.storySort)
+	return visible
+}
+
+// selectedStory returns the story under the cursor in the visible list.
+func (m Model) selectedStory() (models.Story, bool) {
+	visible := m.visibleStories()
+	if m.selectedIndex < 0 || m.selectedIndex >= len(visible) {
+		return models.Story{}, false
+	}
+	return visible[m.selectedIndex], true
+}
+
+// clampStorySelection keeps the cursor within the visible list after it shrinks.
+func (m *Model) clampStorySelection() {
+	if n := len(m.visibleStories()); m.selectedIndex >= n {
+		m.selectedIndex = n - 1
+	}
+	if m.selectedIndex < 0 {
+		m.selectedIndex = 0
 	}
-	return m, nil
 }
 
 func (m Model) handleStoryViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "enter":
+	switch {
+	case key.Matches(msg, m.keys.StartChat):
+		m.stopReading()
 		m.mode = ModeChat
-		m.textInput.Focus()
+		m.restoreDraft()
 		m.streamingResponse = ""
-	case "esc":
+		return m, m.composer.Focus()
+	case key.Matches(msg, m.keys.Back):
+		if m.reading {
+			m.stopReading()
+			m.statusMessage = ""
+			return m, nil
+		}
 		m.mode = ModeStoryList
 		m.selectedIndex = 0
 		m.currentStory = nil
 		m.pages = nil
+		m.readIndex = 0
+		m.glossary = false
 		m.conversationHistory = nil
+	case key.Matches(msg, m.keys.PlayPause):
+		switch {
+		case len(m.pages) == 0:
+		case !m.reading:
+			return m, m.startReading(min(m.readIndex, len(m.pages)-1))
+		default:
+			m.togglePause()
+		}
+	case key.Matches(msg, m.keys.NextPage):
+		if m.reading && m.readIndex+1 < len(m.pages) {
+			return m, m.startReading(m.readIndex + 1)
+		}
+	case key.Matches(msg, m.keys.PrevPage):
+		if m.reading && m.readIndex > 0 {
+			return m, m.startReading(m.readIndex - 1)
+		}
+	case key.Matches(msg, m.keys.StopReading):
+		if m.reading {
+			m.stopReading()
+			m.statusMessage = "Stopped reading"
+		}
+	case key.Matches(msg, m.keys.Quiz):
+		return m, m.startQuiz()
+	case key.Matches(msg, m.keys.Glossary):
+		return m, m.toggleGlossary()
 	}
 	return m, nil
 }
@@ -416,34 +1435,98 @@ func (m Model) handleChatKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
-	switch msg.String() {
-	case "esc":
-		if m.textInput.Value() != "" {
-			m.textInput.SetValue("")
-		} else {
-			m.mode = ModeStoryView
-			m.textInput.Blur()
-		}
-	case "enter":
-		if m.textInput.Value() != "" {
-			message := m.textInput.Value()
-			m.inputBuffer = message
-			m.textInput.SetValue("")
-			m.isLoading = true
-			m.statusMessage = "Thinking..."
-			return m, tea.Batch(
-				m.spinner.Tick,
-				m.sendMessage(message),
-			)
+	switch {
+	case key.Matches(msg, m.keys.Back):
+		if m.listening {
+			m.cancelRecording()
+			m.statusMessage = "Recording cancelled"
+			return m, nil
+		}
+		m.saveDraft()
+		m.mode = ModeStoryView
+		m.composer.Blur()
+	case key.Matches(msg, m.keys.Record):
+		return m, m.toggleRecording()
+	case m.listening || m.transcribing:
+		// Hold the composer still until the transcript arrives.
+		return m, nil
+	case key.Matches(msg, m.keys.NextChoice) && len(m.choices) > 0:
+		m.pickChoice(1)
+	case key.Matches(msg, m.keys.PrevChoice) && len(m.choices) > 0:
+		m.pickChoice(-1)
+	case key.Matches(msg, m.keys.Send):
+		if strings.TrimSpace(m.composer.Value()) != "" {
+			message := m.composer.Value()
+			m.composer.Reset()
+			m.historyIndex = -1
+			m.historyDraft = ""
+			if m.currentStory != nil {
+				delete(m.drafts, m.currentStory.ID)
+			}
+			return m, m.beginTurn(message)
 		}
+	case key.Matches(msg, m.keys.Newline):
+		m.composer.InsertString("\n")
+	case key.Matches(msg, m.keys.HistoryPrev) && m.composer.Line() == 0 && len(m.promptHistory()) > 0:
+		m.recallPrevious()
+	case key.Matches(msg, m.keys.HistoryNext) && m.composer.Line() == m.composer.LineCount()-1 && m.historyIndex >= 0:
+		m.recallNext()
 	default:
 		var cmd tea.Cmd
-		m.textInput, cmd = m.textInput.Update(msg)
+		m.composer, cmd = m.composer.Update(msg)
 		return m, cmd
 	}
 	return m, nil
 }
 
+// beginTurn sends the child's message and waits for the page written in
+// reply.
+func (m *Model) beginTurn(message string) tea.Cmd {
+	m.inputBuffer = message
+	m.isLoading = true
+	m.weaving = m.wordsToWeave()
+	m.choices = nil
+	m.choiceIndex = -1
+	m.streamingResponse = ""
+	m.streamRendered = ""
+	m.streamView = &streamRender{}
+	m.statusMessage = "Thinking..."
+	return tea.Batch(
+		m.spinner.Tick,
+		m.sendMessage(message),
+	)
+}
+
+// renderCompletion renders an AI completion as markdown, or as plain
+// wrapped text when markdown is disabled.
+func (m Model) renderCompletion(text string, width int) string {
+	if !m.markdown {
+		return wrapText(text, width)
+	}
+	return renderMarkdown(text, width, m.styles)
+}
+
+// renderIllustration draws a page's illustration scaled to the viewport:
+// the text width, and at most a third of the screen height so the page text
+// stays visible. It returns "" when the page has none or it isn't loaded.
+func (m Model) renderIllustration(page models.Page) string {
+	if page.ImagePath == nil {
+		return ""
+	}
+
+	width, height := m.width-10, m.height/3
+	if m.width <= 0 {
+		width, height = 70, 12
+	}
+
+	art, err := m.images.Render(*page.ImagePath, m.graphics, m.profile, width, max(height, 4))
+	if err != nil {
+		m.logger.Warn("failed to render illustration", "path", *page.ImagePath, "error", err)
+		return ""
+	}
+	return art
+}
+
 // View renders the current state of the model.
 func (m Model) View() string {
 	return RenderView(m)
@@ -0,0 +1,74 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kbrakke/illustrated-primer/internal/ai"
+)
+
+// structuredPages reports whether pages are requested as structured output:
+// for their choices, or to track the story's objectives.
+func (m Model) structuredPages() bool {
+	return m.offerChoices || len(m.storyObjectives) > 0
+}
+
+// pageText returns the story text of a response that may still be streaming.
+func (m Model) pageText(raw string) string {
+	if !m.structuredPages() {
+		return raw
+	}
+	return ai.PartialPageText(raw)
+}
+
+// readResponse reads a finished response into a page. A response that isn't
+// a valid structured page is used as text, without choices or objectives.
+func (m Model) readResponse(raw string) ai.StoryPage {
+	if !m.structuredPages() {
+		return ai.StoryPage{Text: raw}
+	}
+	page, err := ai.ParseStoryPage(raw)
+	if err != nil {
+		m.logger.Warn("response was not a structured page", "error", err)
+		return ai.StoryPage{Text: ai.PartialPageText(raw)}
+	}
+	if !m.offerChoices {
+		page.Choices = nil
+	}
+	return page
+}
+
+// pickChoice moves through the offered choices, copying the current one
+// into the composer where it can be sent or edited.
+func (m *Model) pickChoice(step int) {
+	n := len(m.choices)
+	if m.choiceIndex < 0 && step < 0 {
+		m.choiceIndex = n - 1
+	} else {
+		m.choiceIndex = ((m.choiceIndex+step)%n + n) % n
+	}
+	m.composer.SetValue(m.choices[m.choiceIndex])
+	m.historyIndex = -1
+}
+
+// renderChoices lists the next actions offered with the latest page.
+func renderChoices(m Model) string {
+	if len(m.choices) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(m.styles.Normal.Render(fmt.Sprintf("What happens next? (%s to choose)", m.keys.NextChoice.Help().Key)))
+	b.WriteString("\n")
+	for i, choice := range m.choices {
+		line := fmt.Sprintf("%d. %s", i+1, choice)
+		if i == m.choiceIndex {
+			b.WriteString(m.styles.Selected.Render("▸ " + line))
+		} else {
+			b.WriteString(m.styles.Normal.Render("  " + line))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	return b.String()
+}
@@ -0,0 +1,119 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kbrakke/illustrated-primer/internal/ai"
+	"github.com/kbrakke/illustrated-primer/internal/models"
+	"github.com/kbrakke/illustrated-primer/testutil"
+)
+
+const structuredPage = `{"text":"The fox found a door.","choices":["Open the door","Knock first","Run home"]}`
+
+// finish streams a started response to the end and applies the result.
+func finish(t *testing.T, m Model, msg tea.Msg) Model {
+	t.Helper()
+	updated, _ := m.Update(msg)
+	m = updated.(Model)
+	for {
+		msg := waitForChunk(m.stream)()
+		updated, _ := m.Update(msg)
+		m = updated.(Model)
+		if strings.Contains(m.streamRendered, `"text"`) {
+			t.Errorf("raw JSON was shown while streaming: %q", m.streamRendered)
+		}
+		if _, done := msg.(aiDoneMsg); done {
+			return m
+		}
+	}
+}
+
+func TestChoicesOffered(t *testing.T) {
+	m := newStoryTestModel(testutil.NewMockAIClient(structuredPage), ModeChat)
+	m, msg := send(t, m, "tell me a story")
+	if format := m.aiClient.(*testutil.MockAIClient).LastCall().Options.Format; format != ai.StoryPageFormat {
+		t.Errorf("page requested with format %+v, want the story page format", format)
+	}
+	m = finish(t, m, msg)
+
+	if got := m.conversationHistory[1]; got != "The fox found a door." {
+		t.Errorf("completion = %q, want the page text", got)
+	}
+	if strings.Join(m.choices, "|") != "Open the door|Knock first|Run home" {
+		t.Fatalf("choices = %v", m.choices)
+	}
+	if view := renderChat(m); !strings.Contains(view, "2. Knock first") {
+		t.Errorf("choices not rendered:\n%s", view)
+	}
+
+	// Tab and shift+tab copy choices into the composer, wrapping around.
+	for _, step := range []struct {
+		key  tea.KeyType
+		want string
+	}{
+		{tea.KeyTab, "Open the door"},
+		{tea.KeyTab, "Knock first"},
+		{tea.KeyShiftTab, "Open the door"},
+		{tea.KeyShiftTab, "Run home"},
+	} {
+		updated, _ := m.Update(tea.KeyMsg{Type: step.key})
+		m = updated.(Model)
+		if got := m.composer.Value(); got != step.want {
+			t.Errorf("composer = %q, want %q", got, step.want)
+		}
+	}
+
+	// Sending clears the choices until the next page offers its own.
+	m, msg = send(t, m, m.composer.Value())
+	if len(m.choices) != 0 || m.choiceIndex != -1 {
+		t.Errorf("choices still offered after sending: %v", m.choices)
+	}
+	if started := msg.(aiStreamStartedMsg); started.prompt != "Run home" {
+		t.Errorf("sent %q, want the chosen action", started.prompt)
+	}
+}
+
+func TestChoicesFallBackToText(t *testing.T) {
+	m := newStoryTestModel(testutil.NewMockAIClient("Once upon a time..."), ModeChat)
+	m, msg := send(t, m, "tell me a story")
+	m = finish(t, m, msg)
+
+	if got := m.conversationHistory[1]; got != "Once upon a time..." || len(m.choices) != 0 {
+		t.Errorf("completion = %q with choices %v, want the plain text", got, m.choices)
+	}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if got := updated.(Model).composer.Value(); got != "" {
+		t.Errorf("tab without choices changed the composer to %q", got)
+	}
+}
+
+func TestChoicesRestoredFromLastPage(t *testing.T) {
+	m := newStoryTestModel(testutil.NewMockAIClient(""), ModeChat)
+	updated, _ := m.Update(pagesLoadedMsg{pages: []models.Page{
+		{PageNum: 1, Choices: []string{"a", "b"}},
+		{PageNum: 2, Choices: []string{"Climb the tree", "Call for help"}},
+	}})
+	if got := updated.(Model).choices; strings.Join(got, "|") != "Climb the tree|Call for help" {
+		t.Errorf("choices = %v, want the last page's", got)
+	}
+}
+
+func TestChoicesModerated(t *testing.T) {
+	m := newStoryTestModel(testutil.NewMockAIClient(`{"text":"The dragon sneezed.","choices":["Say bless you","Yell shit","Hide"]}`),
+		ModeChat, withRuleModeration(t))
+
+	m, msg := send(t, m, "tell me a story")
+	m, cmd := stream(t, m, msg)
+	updated, _ := m.Update(cmd())
+	m = updated.(Model)
+
+	if len(m.conversationHistory) != 2 || m.conversationHistory[1] != "The dragon sneezed." {
+		t.Fatalf("history = %v", m.conversationHistory)
+	}
+	if len(m.choices) != 0 {
+		t.Errorf("choices that needed rewriting should be dropped, got %v", m.choices)
+	}
+}
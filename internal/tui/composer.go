@@ -0,0 +1,104 @@
+package tui
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/kbrakke/illustrated-primer/internal/ai"
+)
+
+const (
+	// composerCharLimit is the maximum length of a single chat message.
+	composerCharLimit = 4000
+
+	// composerHeight is the number of visible lines in the chat composer.
+	composerHeight = 4
+)
+
+// newComposer creates the multiline chat input. Enter is reserved for
+// sending, so the textarea's own newline binding is disabled and newlines
+// are inserted through KeyMap.Newline instead.
+func newComposer() textarea.Model {
+	ta := textarea.New()
+	ta.Placeholder = "Type your message..."
+	ta.CharLimit = composerCharLimit
+	ta.ShowLineNumbers = false
+	ta.SetWidth(60)
+	ta.SetHeight(composerHeight)
+	ta.KeyMap.InsertNewline.SetEnabled(false)
+	return ta
+}
+
+// promptHistory returns the prompts sent so far in the current story, oldest first.
+func (m Model) promptHistory() []string {
+	var prompts []string
+	for i := 0; i < len(m.conversationHistory); i += 2 {
+		prompts = append(prompts, m.conversationHistory[i])
+	}
+	return prompts
+}
+
+// recallPrevious replaces the composer contents with the previous prompt.
+// The unsent draft is kept so that recallNext can restore it.
+func (m *Model) recallPrevious() {
+	prompts := m.promptHistory()
+	switch {
+	case len(prompts) == 0:
+		return
+	case m.historyIndex < 0:
+		m.historyDraft = m.composer.Value()
+		m.historyIndex = len(prompts) - 1
+	case m.historyIndex > 0:
+		m.historyIndex--
+	}
+
+	m.composer.SetValue(prompts[m.historyIndex])
+}
+
+// recallNext moves forward through the prompt history, restoring the draft
+// after the most recent prompt.
+func (m *Model) recallNext() {
+	if m.historyIndex < 0 {
+		return
+	}
+
+	prompts := m.promptHistory()
+	if m.historyIndex < len(prompts)-1 {
+		m.historyIndex++
+		m.composer.SetValue(prompts[m.historyIndex])
+		return
+	}
+
+	m.historyIndex = -1
+	m.composer.SetValue(m.historyDraft)
+	m.historyDraft = ""
+}
+
+// saveDraft stores the composer contents for the current story.
+func (m *Model) saveDraft() {
+	if m.currentStory == nil {
+		return
+	}
+	if value := m.composer.Value(); value != "" {
+		m.drafts[m.currentStory.ID] = value
+	} else {
+		delete(m.drafts, m.currentStory.ID)
+	}
+}
+
+// restoreDraft loads the saved draft for the current story into the composer.
+func (m *Model) restoreDraft() {
+	m.composer.Reset()
+	m.historyIndex = -1
+	m.historyDraft = ""
+	if m.currentStory != nil {
+		m.composer.SetValue(m.drafts[m.currentStory.ID])
+	}
+}
+
+// composerCounter describes the length of the message being composed.
+func composerCounter(text string) string {
+	return fmt.Sprintf("%d/%d chars • ~%d tokens",
+		utf8.RuneCountInString(text), composerCharLimit, ai.EstimateTokens(text))
+}
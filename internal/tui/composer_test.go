@@ -0,0 +1,58 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/kbrakke/illustrated-primer/internal/models"
+)
+
+func TestComposerHistory(t *testing.T) {
+	m := newTestModel(nil)
+	m.conversationHistory = []string{"first prompt", "first reply", "second prompt", "second reply"}
+	m.composer.SetValue("unsent")
+
+	m.recallPrevious()
+	if got := m.composer.Value(); got != "second prompt" {
+		t.Fatalf("after first recall = %q, want %q", got, "second prompt")
+	}
+
+	m.recallPrevious()
+	m.recallPrevious()
+	if got := m.composer.Value(); got != "first prompt" {
+		t.Fatalf("recall past oldest = %q, want %q", got, "first prompt")
+	}
+
+	m.recallNext()
+	if got := m.composer.Value(); got != "second prompt" {
+		t.Fatalf("after next = %q, want %q", got, "second prompt")
+	}
+
+	m.recallNext()
+	if got := m.composer.Value(); got != "unsent" {
+		t.Errorf("draft not restored, got %q", got)
+	}
+	if m.historyIndex != -1 {
+		t.Errorf("historyIndex = %d, want -1", m.historyIndex)
+	}
+}
+
+func TestComposerDrafts(t *testing.T) {
+	m := newTestModel(nil)
+	m.conversationHistory = []string{"first prompt", "first reply", "second prompt", "second reply"}
+	m.currentStory = &models.Story{ID: "story-1"}
+
+	m.composer.SetValue("once upon a time")
+	m.saveDraft()
+	m.composer.Reset()
+
+	m.restoreDraft()
+	if got := m.composer.Value(); got != "once upon a time" {
+		t.Errorf("restored draft = %q, want %q", got, "once upon a time")
+	}
+
+	m.composer.Reset()
+	m.saveDraft()
+	if _, ok := m.drafts["story-1"]; ok {
+		t.Error("expected empty draft to be removed")
+	}
+}
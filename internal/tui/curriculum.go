@@ -0,0 +1,163 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kbrakke/illustrated-primer/internal/models"
+)
+
+// objectivePicker is the checklist of objectives shown while creating a story.
+type objectivePicker struct {
+	title    string          // title of the story being created
+	cursor   int             // index in the curriculum
+	selected map[string]bool // by objective ID
+}
+
+type curriculumLoadedMsg struct {
+	subjects   []models.Subject
+	objectives []models.Objective
+	err        error
+}
+
+type storyObjectivesLoadedMsg struct {
+	storyID    string
+	objectives []models.Objective
+	err        error
+}
+
+// loadCurriculum loads the subjects and objectives stories can be given.
+func (m Model) loadCurriculum() tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+		subjects, err := m.db.ListSubjects(ctx)
+		if err != nil {
+			return curriculumLoadedMsg{err: err}
+		}
+		objectives, err := m.db.ListObjectives(ctx)
+		return curriculumLoadedMsg{subjects: subjects, objectives: objectives, err: err}
+	}
+}
+
+// loadStoryObjectives loads the objectives a story teaches.
+func (m Model) loadStoryObjectives(storyID string) tea.Cmd {
+	return func() tea.Msg {
+		objectives, err := m.db.ListStoryObjectives(context.Background(), storyID)
+		return storyObjectivesLoadedMsg{storyID: storyID, objectives: objectives, err: err}
+	}
+}
+
+// objectiveTitles returns the titles of the current story's objectives.
+func (m Model) objectiveTitles() []string {
+	titles := make([]string, len(m.storyObjectives))
+	for i, objective := range m.storyObjectives {
+		titles[i] = objective.Title
+	}
+	return titles
+}
+
+// updateCurriculum handles curriculum messages.
+func (m Model) updateCurriculum(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case curriculumLoadedMsg:
+		if msg.err != nil {
+			m.logger.Error("failed to load curriculum", "error", msg.err)
+			return m, nil
+		}
+		m.subjects = msg.subjects
+		m.curriculum = msg.objectives
+
+	case storyObjectivesLoadedMsg:
+		if m.currentStory == nil || m.currentStory.ID != msg.storyID {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.logger.Error("failed to load story objectives", "story_id", msg.storyID, "error", msg.err)
+			return m, nil
+		}
+		m.storyObjectives = msg.objectives
+	}
+	return m, nil
+}
+
+// beginObjectivePicker opens the objective checklist for a new story, or
+// creates it straight away if there is no curriculum to choose from.
+func (m *Model) beginObjectivePicker(title string) tea.Cmd {
+	if len(m.curriculum) == 0 {
+		return m.createStory(title, nil)
+	}
+	m.picker = &objectivePicker{title: title, selected: make(map[string]bool)}
+	m.statusMessage = fmt.Sprintf("Choose what %q should teach (%s to toggle, %s to create)",
+		title, m.keys.Toggle.Help().Key, m.keys.Select.Help().Key)
+	return nil
+}
+
+// handleObjectivePickerKeys routes keys to the objective checklist.
+func (m Model) handleObjectivePickerKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		if m.picker.cursor > 0 {
+			m.picker.cursor--
+		}
+	case key.Matches(msg, m.keys.Down):
+		if m.picker.cursor < len(m.curriculum)-1 {
+			m.picker.cursor++
+		}
+	case key.Matches(msg, m.keys.Toggle):
+		id := m.curriculum[m.picker.cursor].ID
+		m.picker.selected[id] = !m.picker.selected[id]
+	case key.Matches(msg, m.keys.Select):
+		var ids []string
+		for _, objective := range m.curriculum {
+			if m.picker.selected[objective.ID] {
+				ids = append(ids, objective.ID)
+			}
+		}
+		title := m.picker.title
+		m.picker = nil
+		m.statusMessage = ""
+		return m, m.createStory(title, ids)
+	case key.Matches(msg, m.keys.Back):
+		m.picker = nil
+		m.statusMessage = "New story cancelled"
+	}
+	return m, nil
+}
+
+// renderObjectivePicker lists the curriculum by subject, marking the
+// objectives chosen for the new story.
+func renderObjectivePicker(m Model) string {
+	subjects := make(map[string]string, len(m.subjects))
+	for _, subject := range m.subjects {
+		subjects[subject.ID] = subject.Name
+	}
+
+	var b strings.Builder
+	b.WriteString(m.styles.Normal.Render(fmt.Sprintf("What should %q teach?", m.picker.title)))
+	b.WriteString("\n")
+	subjectID := ""
+	for i, objective := range m.curriculum {
+		if objective.SubjectID != subjectID {
+			subjectID = objective.SubjectID
+			b.WriteString(m.styles.Help.UnsetMarginTop().Render(subjects[subjectID]))
+			b.WriteString("\n")
+		}
+
+		mark := "[ ]"
+		if m.picker.selected[objective.ID] {
+			mark = "[x]"
+		}
+		line := fmt.Sprintf("%s %s", mark, objective.Title)
+		if i == m.picker.cursor {
+			b.WriteString(m.styles.Selected.Render("▸ " + line))
+		} else {
+			b.WriteString(m.styles.Normal.Render("  " + line))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	return b.String()
+}
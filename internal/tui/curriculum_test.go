@@ -0,0 +1,129 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kbrakke/illustrated-primer/internal/models"
+	"github.com/kbrakke/illustrated-primer/testutil"
+)
+
+var (
+	testSubjects   = []models.Subject{{ID: "art", Name: "Art"}, {ID: "math", Name: "Math"}}
+	testCurriculum = []models.Objective{
+		{ID: "colors", SubjectID: "art", Title: "Primary colors"},
+		{ID: "shapes", SubjectID: "art", Title: "Circles and squares"},
+		{ID: "counting", SubjectID: "math", Title: "Count to 10"},
+	}
+)
+
+func TestObjectivePicker(t *testing.T) {
+	m := newTestModel(nil, withUser(models.User{ID: "user"}))
+	m.mode = ModeStoryList
+	m.subjects, m.curriculum = testSubjects, testCurriculum
+
+	m, _ = press(m, "n")
+	m.textInput.SetValue("The Painted Fox")
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if m.picker == nil || cmd != nil {
+		t.Fatalf("the objective picker should open before the story is created")
+	}
+
+	// Choose the first and last objectives.
+	m, _ = press(m, " ")
+	m, _ = press(m, "j")
+	m, _ = press(m, "j")
+	m, _ = press(m, " ")
+	m, _ = press(m, "j") // stays on the last objective
+
+	view := renderStoryList(m)
+	for _, want := range []string{"Art", "Math", "[x] Primary colors", "[ ] Circles and squares", "▸ [x] Count to 10"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("picker view missing %q:\n%s", want, view)
+		}
+	}
+
+	// Toggling again deselects.
+	m, _ = press(m, " ")
+	if m.picker.selected["counting"] {
+		t.Error("toggling twice should deselect the objective")
+	}
+
+	updated, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if m.picker != nil || cmd == nil {
+		t.Error("enter should close the picker and create the story")
+	}
+}
+
+func TestObjectivePickerCancel(t *testing.T) {
+	m := newTestModel(nil, withUser(models.User{ID: "user"}))
+	m.mode = ModeStoryList
+	m.subjects, m.curriculum = testSubjects, testCurriculum
+	m.beginObjectivePicker("The Painted Fox")
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	if m.picker != nil || cmd != nil {
+		t.Error("esc should close the picker without creating the story")
+	}
+	if m.mode != ModeStoryList {
+		t.Errorf("mode = %v, want the story list", m.mode)
+	}
+}
+
+func TestObjectivePickerSkippedWithoutCurriculum(t *testing.T) {
+	m := newTestModel(nil, withUser(models.User{ID: "user"}))
+	m.mode = ModeStoryList
+	m.subjects, m.curriculum = testSubjects, testCurriculum
+	m.curriculum = nil
+
+	if cmd := m.beginObjectivePicker("The Painted Fox"); cmd == nil || m.picker != nil {
+		t.Error("without a curriculum the story should be created straight away")
+	}
+}
+
+func TestObjectivesReadFromResponse(t *testing.T) {
+	m := newStoryTestModel(testutil.NewMockAIClient(`{"text":"Three red apples.","choices":["Eat one","Count again"],"objectives":["count to 10"]}`), ModeChat)
+	m.offerChoices = false
+	m.storyObjectives = []models.Objective{{ID: "counting", Title: "Count to 10"}}
+
+	if got := m.pageText(`{"text":"Three red`); got != "Three red" {
+		t.Errorf("pageText = %q, want the partial text", got)
+	}
+
+	page := m.readResponse(`{"text":"Three red apples.","choices":["Eat one","Count again"],"objectives":["count to 10"]}`)
+	if page.Text != "Three red apples." || page.Choices != nil {
+		t.Errorf("page = %+v, want the text without choices", page)
+	}
+	matched := models.MatchObjectives(m.storyObjectives, page.Objectives)
+	if len(matched) != 1 || matched[0].ID != "counting" {
+		t.Errorf("matched objectives = %+v", matched)
+	}
+
+	m.storyObjectives = nil
+	if page := m.readResponse("Plain text."); page.Text != "Plain text." {
+		t.Errorf("without choices or objectives the response is plain text, got %+v", page)
+	}
+}
+
+func TestStoryObjectivesLoaded(t *testing.T) {
+	m := newTestModel(nil, withUser(models.User{ID: "user"}))
+	m.mode = ModeStoryList
+	m.subjects, m.curriculum = testSubjects, testCurriculum
+	m.currentStory = &models.Story{ID: "story"}
+	objectives := []models.Objective{{ID: "counting", Title: "Count to 10"}}
+
+	updated, _ := m.Update(storyObjectivesLoadedMsg{storyID: "other", objectives: objectives})
+	if got := updated.(Model).storyObjectives; got != nil {
+		t.Errorf("objectives of another story were kept: %+v", got)
+	}
+
+	updated, _ = m.Update(storyObjectivesLoadedMsg{storyID: "story", objectives: objectives})
+	m = updated.(Model)
+	if titles := m.objectiveTitles(); len(titles) != 1 || titles[0] != "Count to 10" {
+		t.Errorf("objectiveTitles = %v", titles)
+	}
+}
@@ -0,0 +1,95 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kbrakke/illustrated-primer/internal/models"
+)
+
+// householdScope is the household the primer is being used in, set when a
+// child is chosen or a guardian unlocks the dashboard. Only its users can be
+// chosen, and while a child is using the primer, switching to another child
+// takes a guardian's PIN.
+type householdScope struct {
+	household *string // nil for users without a household
+	child     string  // ID of the child using the primer, "" after a guardian
+}
+
+// selectableUsers returns the users in the active household, or every user
+// before anyone has been chosen and after the parent PIN.
+func (m Model) selectableUsers() []models.User {
+	if m.scope == nil {
+		return m.users
+	}
+	var users []models.User
+	for _, user := range m.users {
+		if sameHousehold(user.HouseholdID, m.scope.household) {
+			users = append(users, user)
+		}
+	}
+	return users
+}
+
+// needsSwitchPIN reports whether choosing a child means switching away from
+// the child using the primer, which a guardian has to approve. Without any
+// PIN to ask for, children switch freely, as they did before households.
+func (m Model) needsSwitchPIN(child models.User) bool {
+	if m.scope == nil || m.scope.child == "" || m.scope.child == child.ID {
+		return false
+	}
+	return m.canUnlock(m.guardians(&child))
+}
+
+// beginSwitch asks for a guardian's PIN before switching to a child.
+func (m *Model) beginSwitch(child models.User) {
+	if m.pinLockedOut() {
+		return
+	}
+	m.switching = &child
+	m.textInput.SetValue("")
+	m.textInput.EchoMode = textinput.EchoPassword
+	m.textInput.Focus()
+	m.statusMessage = fmt.Sprintf("Ask a grown-up to enter their PIN to switch to %s:", child.DisplayName())
+}
+
+// endSwitch closes the PIN prompt for switching children.
+func (m *Model) endSwitch() {
+	m.switching = nil
+	m.textInput.SetValue("")
+	m.textInput.EchoMode = textinput.EchoNormal
+	m.textInput.Blur()
+}
+
+// handleSwitchKeys takes a guardian's PIN to switch to another child. Too
+// many wrong PINs close the prompt and lock it, as on the dashboard.
+func (m Model) handleSwitchKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Back):
+		m.endSwitch()
+		m.statusMessage = ""
+	case key.Matches(msg, m.keys.Select):
+		child := *m.switching
+		if _, err := m.checkPIN(m.guardians(&child), m.textInput.Value()); err != nil {
+			m.textInput.SetValue("")
+			if m.wrongPIN() {
+				m.endSwitch()
+				m.pinLockedOut()
+				return m, nil
+			}
+			m.statusMessage = "That PIN isn't right. Try again:"
+			return m, nil
+		}
+		m.pinAttempts = 0
+		m.endSwitch()
+		m.statusMessage = ""
+		return m.selectChild(child)
+	default:
+		var cmd tea.Cmd
+		m.textInput, cmd = m.textInput.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
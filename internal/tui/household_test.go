@@ -0,0 +1,308 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kbrakke/illustrated-primer/internal/models"
+	"github.com/kbrakke/illustrated-primer/testutil"
+)
+
+// testHouseholds returns the users of two households: the first with a
+// guardian "mum" (PIN 2468) and a child "ada", the second with a guardian
+// "dad" (PIN 1357) and a child "ben".
+func testHouseholds(t *testing.T) []models.User {
+	t.Helper()
+	first, second := "first", "second"
+	mum := models.User{ID: "mum", HouseholdID: &first, Role: models.RoleGuardian}
+	dad := models.User{ID: "dad", HouseholdID: &second, Role: models.RoleGuardian}
+	if err := mum.SetPIN("2468"); err != nil {
+		t.Fatal(err)
+	}
+	if err := dad.SetPIN("1357"); err != nil {
+		t.Fatal(err)
+	}
+	children := testChildren()
+	children[0].HouseholdID, children[1].HouseholdID = &first, &second
+	return append([]models.User{mum, dad}, children...)
+}
+
+func TestGuardianDashboard(t *testing.T) {
+	m := newTestModel(testutil.NewMockAIClient(""), withUsers(testHouseholds(t)...))
+	if view := renderUserSelection(m); !strings.Contains(view, "(grown-up)") {
+		t.Errorf("guardians should be marked:\n%s", view)
+	}
+
+	// Choosing a guardian asks for their PIN, not anyone else's.
+	m, _ = enter(m)
+	if m.mode != ModeParent || m.parent == nil || m.parent.guardian.ID != "mum" {
+		t.Fatalf("mode = %v, want mum's PIN prompt", m.mode)
+	}
+	m, _ = typePIN(m, "1357")
+	if m.parent.unlocked || m.pinAttempts != 1 {
+		t.Fatal("another guardian's PIN shouldn't unlock the dashboard")
+	}
+	m, _ = typePIN(m, "2468")
+	if !m.parent.unlocked {
+		t.Fatal("the guardian's PIN should unlock the dashboard")
+	}
+	if len(m.parent.children) != 1 || m.parent.children[0].ID != "ada" {
+		t.Fatalf("children = %+v, want only the guardian's household", m.parent.children)
+	}
+
+	// P takes any guardian's PIN, and shows that guardian's household.
+	m.closeParent()
+	m, _ = press(m, "P")
+	m, _ = typePIN(m, "1357")
+	if !m.parent.unlocked || m.parent.guardian.ID != "dad" {
+		t.Fatal("any guardian's PIN should open the dashboard")
+	}
+	if len(m.parent.children) != 1 || m.parent.children[0].ID != "ben" {
+		t.Errorf("children = %+v, want dad's household", m.parent.children)
+	}
+}
+
+func TestGuardianWithoutPIN(t *testing.T) {
+	m := newTestModel(testutil.NewMockAIClient(""), withUsers(testHouseholds(t)...))
+	m.users[0].PINHash = nil
+	m, _ = enter(m)
+	if m.mode != ModeUserSelection || !strings.Contains(m.statusMessage, "--set-pin") {
+		t.Errorf("mode = %v, status %q, want to be told to set a PIN", m.mode, m.statusMessage)
+	}
+
+	// The parent PIN stands in for every guardian and sees every child.
+	m = newTestModel(testutil.NewMockAIClient(""), WithParentPIN("9999"), withUsers(testHouseholds(t)...))
+	m, _ = press(m, "P")
+	m, _ = typePIN(m, "9999")
+	if !m.parent.unlocked || m.parent.guardian != nil || len(m.parent.children) != 2 {
+		t.Errorf("the parent PIN should show every child; children %+v", m.parent.children)
+	}
+}
+
+func TestGuardianOnlyActions(t *testing.T) {
+	m := newTestModel(testutil.NewMockAIClient(""), withUsers(testHouseholds(t)...))
+	m.currentUser = &m.users[2]
+	m.mode = ModeStoryList
+	m.stories = []models.Story{{ID: "story", UserID: "ada", Title: "Fox"}}
+
+	m, _ = press(m, "d")
+	if m.confirmDelete || m.storyInput != storyInputGuardianPIN {
+		t.Fatal("deleting a story should ask for a guardian's PIN")
+	}
+	if view := RenderView(m); strings.Contains(view, "2468") || !strings.Contains(view, "Grown-ups Only") {
+		t.Errorf("PIN prompt:\n%s", view)
+	}
+
+	// The other household's guardian can't approve it.
+	m, _ = typePIN(m, "1357")
+	if m.confirmDelete || m.storyInput != storyInputNone {
+		t.Fatal("a wrong PIN should close the prompt without deleting")
+	}
+
+	m, _ = press(m, "d")
+	m, _ = typePIN(m, "2468")
+	if !m.confirmDelete || m.guardianApproved {
+		t.Fatal("the guardian's PIN should carry on with the delete, once")
+	}
+	m, _ = press(m, "n")
+
+	// Approval covers one action; settings ask again.
+	m, _ = press(m, "a")
+	if m.storyInput != storyInputGuardianPIN {
+		t.Fatal("changing the art style should ask for a guardian's PIN")
+	}
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	if m.storyInput != storyInputNone || m.textInput.EchoMode != textinput.EchoNormal {
+		t.Error("cancelling should close the PIN prompt")
+	}
+
+	// Stories that finish loading for someone else are dropped.
+	updated, _ = m.Update(storiesLoadedMsg{userID: "ben", stories: []models.Story{{ID: "other", UserID: "ben"}}})
+	if m = updated.(Model); len(m.stories) != 1 || m.stories[0].ID != "story" {
+		t.Errorf("stories = %+v, want only ada's", m.stories)
+	}
+}
+
+func TestGuardianOnlyKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		key      string
+		approved func(m Model) bool // the action went ahead
+	}{
+		{"delete", "d", func(m Model) bool { return m.confirmDelete }},
+		{"edit summary", "e", func(m Model) bool { return m.storyInput == storyInputSummary }},
+		{"art style", "a", func(m Model) bool { return m.storyInput == storyInputArtStyle }},
+		{"prompt template", "v", func(m Model) bool { return m.storyInput == storyInputPromptTemplate }},
+		{"theme", "t", func(m Model) bool { return m.currentUser.ThemeName() != "" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestModel(testutil.NewMockAIClient(""), withUsers(testHouseholds(t)...))
+			m.currentUser = &m.users[2]
+			m.mode = ModeStoryList
+			m.stories = []models.Story{{ID: "story", UserID: "ada", Title: "Fox"}}
+
+			m, _ = press(m, tt.key)
+			if m.storyInput != storyInputGuardianPIN || tt.approved(m) {
+				t.Fatalf("%s should ask for a guardian's PIN first", tt.name)
+			}
+			m, _ = typePIN(m, "2468")
+			if !tt.approved(m) {
+				t.Errorf("%s should go ahead once a guardian approves", tt.name)
+			}
+		})
+	}
+
+	// Everyday actions don't need a grown-up.
+	m := newTestModel(testutil.NewMockAIClient(""), withUsers(testHouseholds(t)...))
+	m.currentUser = &m.users[2]
+	m.mode = ModeStoryList
+	m.stories = []models.Story{{ID: "story", UserID: "ada", Title: "Fox"}}
+	for _, k := range []string{"r", "n", "/"} {
+		if m.needsGuardian(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}) {
+			t.Errorf("%q shouldn't need a guardian", k)
+		}
+	}
+}
+
+func TestActionsWithoutGuardians(t *testing.T) {
+	m := newTestModel(testutil.NewMockAIClient(""), withUsers(testChildren()...))
+	m.currentUser = &m.users[0]
+	m.mode = ModeStoryList
+	m.stories = []models.Story{{ID: "story", UserID: "ada", Title: "Fox"}}
+
+	m, _ = press(m, "d")
+	if !m.confirmDelete {
+		t.Error("without any PIN, deleting should stay open")
+	}
+}
+
+func TestSwitchChildNeedsGuardian(t *testing.T) {
+	m := newTestModel(testutil.NewMockAIClient(""), withUsers(testHouseholds(t)...))
+	first := "first"
+	m.users = append(m.users, models.User{ID: "cy", HouseholdID: &first})
+
+	// Ada starts; afterwards only her household is listed.
+	m.selectedIndex = 2
+	m, _ = enter(m)
+	if m.mode != ModeStoryList || m.currentUser.ID != "ada" {
+		t.Fatalf("mode = %v, want ada's stories", m.mode)
+	}
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	view := renderUserSelection(m)
+	if strings.Contains(view, "ben") || strings.Contains(view, "dad") || !strings.Contains(view, "cy") {
+		t.Errorf("only ada's household should be listed:\n%s", view)
+	}
+
+	// Going back to herself needs no PIN.
+	m.selectedIndex = 1
+	m, _ = enter(m)
+	if m.mode != ModeStoryList || m.currentUser.ID != "ada" {
+		t.Fatalf("mode = %v, want ada back without a PIN", m.mode)
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+
+	// Switching to her brother does.
+	m.selectedIndex = 2
+	m, _ = enter(m)
+	if m.mode != ModeUserSelection || m.switching == nil || m.switching.ID != "cy" {
+		t.Fatalf("mode = %v, want a guardian's PIN before switching", m.mode)
+	}
+	m, _ = typePIN(m, "1357")
+	if m.mode != ModeUserSelection || m.pinAttempts != 1 {
+		t.Fatal("another household's guardian can't approve the switch")
+	}
+	m, _ = typePIN(m, "2468")
+	if m.mode != ModeStoryList || m.currentUser.ID != "cy" || m.switching != nil {
+		t.Fatalf("mode = %v, want cy's stories once a guardian approves", m.mode)
+	}
+	if m.textInput.EchoMode != textinput.EchoNormal {
+		t.Error("the PIN prompt should be reset")
+	}
+}
+
+func TestSwitchChildTooManyPINs(t *testing.T) {
+	m := newTestModel(testutil.NewMockAIClient(""), withUsers(testHouseholds(t)...))
+	first := "first"
+	m.users = append(m.users, models.User{ID: "cy", HouseholdID: &first})
+	m.scope = &householdScope{household: &first, child: "ada"}
+
+	m.selectedIndex = 2
+	m, _ = enter(m)
+	for i := 0; i < maxPINAttempts; i++ {
+		m, _ = typePIN(m, "0000")
+	}
+	if m.switching != nil || m.mode != ModeUserSelection || !strings.HasPrefix(m.statusMessage, "Too many wrong PINs.") {
+		t.Errorf("status = %q, want the prompt closed after %d wrong PINs", m.statusMessage, maxPINAttempts)
+	}
+
+	// Choosing the child again doesn't give more guesses, even with the
+	// right PIN.
+	m, _ = enter(m)
+	if m.switching != nil || !strings.HasPrefix(m.statusMessage, "Too many wrong PINs.") {
+		t.Fatalf("status = %q, want the switch prompt to stay locked", m.statusMessage)
+	}
+	m.pinLockedUntil = time.Now().Add(-time.Second)
+	m, _ = enter(m)
+	m, _ = typePIN(m, "2468")
+	if m.currentUser == nil || m.currentUser.ID != "cy" {
+		t.Errorf("currentUser = %v, want cy once the lockout is over", m.currentUser)
+	}
+}
+
+func TestGuardianWidensHousehold(t *testing.T) {
+	m := newTestModel(testutil.NewMockAIClient(""), WithParentPIN("9999"), withUsers(testHouseholds(t)...))
+	first := "first"
+	m.scope = &householdScope{household: &first, child: "ada"}
+
+	// A guardian unlocking the dashboard lets children in their household
+	// be chosen freely.
+	m, _ = press(m, "P")
+	m, _ = typePIN(m, "2468")
+	m.closeParent()
+	if m.scope == nil || m.scope.child != "" || len(m.selectableUsers()) != 2 {
+		t.Errorf("scope = %+v, want mum's household with no child", m.scope)
+	}
+
+	// The parent PIN lists everyone.
+	m, _ = press(m, "P")
+	m, _ = typePIN(m, "9999")
+	m.closeParent()
+	if m.scope != nil || len(m.selectableUsers()) != 4 {
+		t.Errorf("scope = %+v, want every user", m.scope)
+	}
+}
+
+func TestGuardianOnlyActionsTooManyPINs(t *testing.T) {
+	m := newTestModel(testutil.NewMockAIClient(""), withUsers(testHouseholds(t)...))
+	m.currentUser = &m.users[2]
+	m.mode = ModeStoryList
+	m.stories = []models.Story{{ID: "story", UserID: "ada", Title: "Fox"}}
+
+	for i := 1; i < maxPINAttempts; i++ {
+		m, _ = press(m, "d")
+		m, _ = typePIN(m, "0000")
+		if m.mode != ModeStoryList || m.pinAttempts != i {
+			t.Fatalf("attempt %d: mode = %v, attempts %d", i, m.mode, m.pinAttempts)
+		}
+	}
+	m, _ = press(m, "d")
+	m, _ = typePIN(m, "0000")
+	if m.mode != ModeUserSelection || m.currentUser != nil || !strings.HasPrefix(m.statusMessage, "Too many wrong PINs.") {
+		t.Errorf("mode = %v, status %q, want the child's turn ended", m.mode, m.statusMessage)
+	}
+
+	// Coming back as the child doesn't give more guesses.
+	m.currentUser = &m.users[2]
+	m.mode = ModeStoryList
+	m, _ = press(m, "d")
+	if m.storyInput == storyInputGuardianPIN || m.confirmDelete {
+		t.Errorf("storyInput = %v, want the guardian prompt locked", m.storyInput)
+	}
+}
@@ -0,0 +1,387 @@
+package tui
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"hash/fnv"
+	"image"
+	"image/color"
+	_ "image/gif" // register decoders for illustration files
+	_ "image/jpeg"
+	"image/png"
+	"os"
+	"strings"
+
+	"github.com/muesli/termenv"
+)
+
+// GraphicsProtocol is how page illustrations are drawn in the terminal.
+type GraphicsProtocol int
+
+const (
+	GraphicsBlocks GraphicsProtocol = iota // colored half-block characters
+	GraphicsKitty                          // Kitty graphics protocol
+	GraphicsITerm                          // iTerm2 inline images
+	GraphicsSixel                          // DEC Sixel
+	GraphicsASCII                          // brightness ramp, no color
+	GraphicsNone                           // illustrations are not shown
+)
+
+var graphicsNames = map[GraphicsProtocol]string{
+	GraphicsBlocks: "blocks",
+	GraphicsKitty:  "kitty",
+	GraphicsITerm:  "iterm",
+	GraphicsSixel:  "sixel",
+	GraphicsASCII:  "ascii",
+	GraphicsNone:   "none",
+}
+
+// String returns the name of the protocol.
+func (g GraphicsProtocol) String() string {
+	if name, ok := graphicsNames[g]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// ParseGraphicsProtocol returns the protocol with the given name.
+func ParseGraphicsProtocol(name string) (GraphicsProtocol, error) {
+	for g, n := range graphicsNames {
+		if strings.EqualFold(name, n) {
+			return g, nil
+		}
+	}
+	return GraphicsNone, fmt.Errorf("unknown graphics protocol %q", name)
+}
+
+// DetectGraphics guesses the terminal's graphics support from its environment.
+// Terminals are identified by the variables they set themselves; anything
+// unrecognised, including tmux and screen which don't pass images through,
+// gets half-block rendering.
+func DetectGraphics(getenv func(string) string) GraphicsProtocol {
+	term := getenv("TERM")
+	program := getenv("TERM_PROGRAM")
+
+	switch {
+	case getenv("TMUX") != "" || strings.HasPrefix(term, "screen"):
+		return GraphicsBlocks
+	case getenv("KITTY_WINDOW_ID") != "" || term == "xterm-kitty" || program == "ghostty":
+		return GraphicsKitty
+	case program == "iTerm.app" || program == "WezTerm" || getenv("LC_TERMINAL") == "iTerm2":
+		return GraphicsITerm
+	case strings.Contains(term, "sixel") || strings.HasPrefix(term, "foot") || program == "mlterm":
+		return GraphicsSixel
+	default:
+		return GraphicsBlocks
+	}
+}
+
+const (
+	// cellPixelWidth and cellPixelHeight approximate the size of a terminal
+	// cell in pixels. Terminals don't report it portably, so pixel protocols
+	// are sized in cells where they allow it and scaled with these otherwise.
+	cellPixelWidth  = 10
+	cellPixelHeight = 20
+
+	// asciiRamp orders characters from darkest to brightest.
+	asciiRamp = " .:-=+*#%@"
+)
+
+// loadImage decodes the image file at path.
+func loadImage(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open image: %w", err)
+	}
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("decode image %s: %w", path, err)
+	}
+	return img, nil
+}
+
+// fitImage returns the size in cells of an image scaled to fit within
+// maxCols×maxRows while keeping its aspect ratio. Cells are about twice as
+// tall as they are wide.
+func fitImage(bounds image.Rectangle, maxCols, maxRows int) (cols, rows int) {
+	w, h := bounds.Dx(), bounds.Dy()
+	if w <= 0 || h <= 0 || maxCols <= 0 || maxRows <= 0 {
+		return 0, 0
+	}
+
+	cols = maxCols
+	rows = (h*cols + w) / (2 * w) // rounded h/w*cols/2
+	if rows > maxRows {
+		rows = maxRows
+		cols = (2*w*rows + h/2) / h
+	}
+	return max(cols, 1), max(rows, 1)
+}
+
+// scaleImage resizes img to w×h pixels by averaging the source pixels each
+// destination pixel covers. Transparent areas come out black.
+func scaleImage(img image.Image, w, h int) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	b := img.Bounds()
+	sw, sh := b.Dx(), b.Dy()
+
+	for y := 0; y < h; y++ {
+		y0 := b.Min.Y + y*sh/h
+		y1 := max(b.Min.Y+(y+1)*sh/h, y0+1)
+		for x := 0; x < w; x++ {
+			x0 := b.Min.X + x*sw/w
+			x1 := max(b.Min.X+(x+1)*sw/w, x0+1)
+
+			var r, g, bl, n uint32
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					pr, pg, pb, _ := img.At(sx, sy).RGBA()
+					r, g, bl, n = r+pr, g+pg, bl+pb, n+1
+				}
+			}
+			dst.SetRGBA(x, y, color.RGBA{
+				R: uint8(r / n >> 8),
+				G: uint8(g / n >> 8),
+				B: uint8(bl / n >> 8),
+				A: 0xff,
+			})
+		}
+	}
+	return dst
+}
+
+// renderBlocks draws img with upper half blocks, each cell showing two
+// pixels: the top in the foreground color and the bottom in the background.
+func renderBlocks(img image.Image, cols, rows int, profile termenv.Profile) string {
+	scaled := scaleImage(img, cols, rows*2)
+
+	var b strings.Builder
+	for y := 0; y < rows; y++ {
+		if y > 0 {
+			b.WriteString("\n")
+		}
+		last := ""
+		for x := 0; x < cols; x++ {
+			top := profile.FromColor(scaled.RGBAAt(x, 2*y)).Sequence(false)
+			bottom := profile.FromColor(scaled.RGBAAt(x, 2*y+1)).Sequence(true)
+			if seq := top + ";" + bottom; seq != last {
+				b.WriteString(termenv.CSI + seq + "m")
+				last = seq
+			}
+			b.WriteString("▀")
+		}
+		b.WriteString(termenv.CSI + termenv.ResetSeq + "m")
+	}
+	return b.String()
+}
+
+// renderASCII draws img as characters picked by brightness.
+func renderASCII(img image.Image, cols, rows int) string {
+	scaled := scaleImage(img, cols, rows)
+
+	var b strings.Builder
+	for y := 0; y < rows; y++ {
+		if y > 0 {
+			b.WriteString("\n")
+		}
+		for x := 0; x < cols; x++ {
+			c := scaled.RGBAAt(x, y)
+			luma := (299*int(c.R) + 587*int(c.G) + 114*int(c.B)) / 1000
+			b.WriteByte(asciiRamp[luma*len(asciiRamp)/256])
+		}
+	}
+	return b.String()
+}
+
+// encodePNG scales img to fill cols×rows cells and returns it as base64 PNG.
+func encodePNG(img image.Image, cols, rows int) (string, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, scaleImage(img, cols*cellPixelWidth, rows*cellPixelHeight)); err != nil {
+		return "", fmt.Errorf("encode image: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// renderKitty draws img with the Kitty graphics protocol. The payload is sent
+// in chunks of at most 4096 bytes as the protocol requires. Reusing id for
+// the same illustration replaces it rather than leaking image memory, and
+// C=1 leaves the cursor where it was.
+func renderKitty(img image.Image, cols, rows int, id uint32) (string, error) {
+	data, err := encodePNG(img, cols, rows)
+	if err != nil {
+		return "", err
+	}
+
+	const chunkSize = 4096
+	var b strings.Builder
+	for i := 0; i < len(data); i += chunkSize {
+		end := min(i+chunkSize, len(data))
+		more := 0
+		if end < len(data) {
+			more = 1
+		}
+		if i == 0 {
+			fmt.Fprintf(&b, "\x1b_Ga=T,f=100,i=%d,c=%d,r=%d,C=1,q=2,m=%d;%s\x1b\\", id, cols, rows, more, data[i:end])
+		} else {
+			fmt.Fprintf(&b, "\x1b_Gm=%d;%s\x1b\\", more, data[i:end])
+		}
+	}
+	return b.String(), nil
+}
+
+// renderITerm draws img with the iTerm2 inline image protocol.
+func renderITerm(img image.Image, cols, rows int) (string, error) {
+	data, err := encodePNG(img, cols, rows)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("\x1b]1337;File=inline=1;width=%d;height=%d;preserveAspectRatio=1:%s\a", cols, rows, data), nil
+}
+
+// renderSixel draws img as Sixel graphics using a 6×6×6 color cube palette.
+func renderSixel(img image.Image, cols, rows int) string {
+	w, h := cols*cellPixelWidth, rows*cellPixelHeight
+	scaled := scaleImage(img, w, h)
+
+	level := func(v uint8) int { return (int(v)*5 + 127) / 255 }
+	index := func(x, y int) int {
+		c := scaled.RGBAAt(x, y)
+		return level(c.R)*36 + level(c.G)*6 + level(c.B)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "\x1bPq\"1;1;%d;%d", w, h)
+	for i := 0; i < 216; i++ {
+		fmt.Fprintf(&b, "#%d;2;%d;%d;%d", i, i/36*20, i/6%6*20, i%6*20)
+	}
+
+	colors := make([]int, w)
+	for band := 0; band < h; band += 6 {
+		// Collect the sixel bits of each color used in this band.
+		masks := make(map[int][]byte)
+		var order []int
+		for x := 0; x < w; x++ {
+			for dy := 0; dy < 6 && band+dy < h; dy++ {
+				c := index(x, band+dy)
+				if masks[c] == nil {
+					masks[c] = make([]byte, w)
+					order = append(order, c)
+				}
+				masks[c][x] |= 1 << dy
+			}
+		}
+
+		for i, c := range order {
+			if i > 0 {
+				b.WriteByte('$') // back to the start of the band
+			}
+			fmt.Fprintf(&b, "#%d", c)
+			for x := range colors {
+				colors[x] = int(masks[c][x])
+			}
+			writeSixelRuns(&b, colors)
+		}
+		b.WriteByte('-')
+	}
+
+	b.WriteString("\x1b\\")
+	return b.String()
+}
+
+// writeSixelRuns writes one color's row of sixels with run-length encoding.
+func writeSixelRuns(b *strings.Builder, bits []int) {
+	for x := 0; x < len(bits); {
+		run := 1
+		for x+run < len(bits) && bits[x+run] == bits[x] {
+			run++
+		}
+		ch := byte('?' + bits[x])
+		if run > 3 {
+			fmt.Fprintf(b, "!%d%c", run, ch)
+		} else {
+			b.WriteString(strings.Repeat(string(ch), run))
+		}
+		x += run
+	}
+}
+
+// imageCache holds decoded illustrations and their rendered forms. It is
+// shared by pointer between copies of the Model, like streamRender.
+type imageCache struct {
+	images   map[string]image.Image
+	failed   map[string]bool
+	rendered map[string]string
+}
+
+func newImageCache() *imageCache {
+	return &imageCache{
+		images:   make(map[string]image.Image),
+		failed:   make(map[string]bool),
+		rendered: make(map[string]string),
+	}
+}
+
+// needsLoad reports whether the image at path has not been loaded or tried yet.
+func (c *imageCache) needsLoad(path string) bool {
+	_, loaded := c.images[path]
+	return !loaded && !c.failed[path]
+}
+
+// Render returns the illustration at path drawn with the given protocol and
+// scaled to fit maxCols×maxRows, or "" if it isn't loaded. The result is
+// always exactly as many lines tall as the image so the layout around it is
+// stable. Pixel protocols draw from the first line with the cursor saved and
+// restored, and the remaining lines are left blank for the image to cover.
+func (c *imageCache) Render(path string, protocol GraphicsProtocol, profile termenv.Profile, maxCols, maxRows int) (string, error) {
+	img, ok := c.images[path]
+	if !ok || protocol == GraphicsNone {
+		return "", nil
+	}
+	if protocol == GraphicsBlocks && profile == termenv.Ascii {
+		protocol = GraphicsASCII
+	}
+
+	cols, rows := fitImage(img.Bounds(), maxCols, maxRows)
+	if cols == 0 {
+		return "", nil
+	}
+
+	cacheKey := fmt.Sprintf("%s|%s|%d|%dx%d", path, protocol, profile, cols, rows)
+	if out, ok := c.rendered[cacheKey]; ok {
+		return out, nil
+	}
+
+	var seq string
+	var err error
+	switch protocol {
+	case GraphicsKitty:
+		seq, err = renderKitty(img, cols, rows, imageID(path))
+	case GraphicsITerm:
+		seq, err = renderITerm(img, cols, rows)
+	case GraphicsSixel:
+		seq = renderSixel(img, cols, rows)
+	case GraphicsASCII:
+		c.rendered[cacheKey] = renderASCII(img, cols, rows)
+		return c.rendered[cacheKey], nil
+	default:
+		c.rendered[cacheKey] = renderBlocks(img, cols, rows, profile)
+		return c.rendered[cacheKey], nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	out := "\x1b7" + seq + "\x1b8" + strings.Repeat("\n", rows-1)
+	c.rendered[cacheKey] = out
+	return out, nil
+}
+
+// imageID derives a stable, non-zero Kitty image ID from a path.
+func imageID(path string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(path))
+	return h.Sum32()&0x7fffffff | 1
+}
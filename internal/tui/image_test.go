@@ -0,0 +1,177 @@
+package tui
+
+import (
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+
+	"github.com/muesli/termenv"
+)
+
+func testImage(w, h int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x * 255 / w), G: uint8(y * 255 / h), B: 128, A: 255})
+		}
+	}
+	return img
+}
+
+func TestDetectGraphics(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want GraphicsProtocol
+	}{
+		{"kitty", map[string]string{"TERM": "xterm-kitty"}, GraphicsKitty},
+		{"kitty window", map[string]string{"KITTY_WINDOW_ID": "1"}, GraphicsKitty},
+		{"ghostty", map[string]string{"TERM_PROGRAM": "ghostty"}, GraphicsKitty},
+		{"iterm", map[string]string{"TERM_PROGRAM": "iTerm.app"}, GraphicsITerm},
+		{"wezterm", map[string]string{"TERM_PROGRAM": "WezTerm"}, GraphicsITerm},
+		{"foot", map[string]string{"TERM": "foot"}, GraphicsSixel},
+		{"sixel term", map[string]string{"TERM": "xterm-sixel"}, GraphicsSixel},
+		{"tmux", map[string]string{"TERM": "xterm-kitty", "TMUX": "/tmp/tmux"}, GraphicsBlocks},
+		{"plain xterm", map[string]string{"TERM": "xterm-256color"}, GraphicsBlocks},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DetectGraphics(func(k string) string { return tt.env[k] })
+			if got != tt.want {
+				t.Errorf("DetectGraphics() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseGraphicsProtocol(t *testing.T) {
+	for g, name := range graphicsNames {
+		got, err := ParseGraphicsProtocol(strings.ToUpper(name))
+		if err != nil || got != g {
+			t.Errorf("ParseGraphicsProtocol(%q) = %s, %v", name, got, err)
+		}
+	}
+	if _, err := ParseGraphicsProtocol("hologram"); err == nil {
+		t.Error("expected error for unknown protocol")
+	}
+}
+
+func TestFitImage(t *testing.T) {
+	tests := []struct {
+		name             string
+		w, h             int
+		maxCols, maxRows int
+		cols, rows       int
+	}{
+		{"limited by width", 400, 200, 40, 20, 40, 10},
+		{"limited by height", 200, 400, 40, 10, 10, 10},
+		{"square", 100, 100, 80, 40, 80, 40},
+		{"tiny", 1, 1000, 80, 5, 1, 5},
+		{"empty", 0, 0, 80, 5, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cols, rows := fitImage(image.Rect(0, 0, tt.w, tt.h), tt.maxCols, tt.maxRows)
+			if cols != tt.cols || rows != tt.rows {
+				t.Errorf("fitImage() = %dx%d, want %dx%d", cols, rows, tt.cols, tt.rows)
+			}
+		})
+	}
+}
+
+func TestScaleImage(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	for x := 0; x < 4; x++ {
+		src.Set(x, 0, color.White)
+		src.Set(x, 1, color.Black)
+	}
+
+	scaled := scaleImage(src, 2, 1)
+	if got := scaled.RGBAAt(0, 0); got.R != 127 || got.A != 255 {
+		t.Errorf("expected averaged gray, got %v", got)
+	}
+}
+
+func TestRenderBlocksAndASCIISize(t *testing.T) {
+	img := testImage(64, 32)
+
+	for name, out := range map[string]string{
+		"blocks": renderBlocks(img, 16, 4, termenv.TrueColor),
+		"ascii":  renderASCII(img, 16, 4),
+	} {
+		lines := strings.Split(out, "\n")
+		if len(lines) != 4 {
+			t.Fatalf("%s: expected 4 lines, got %d", name, len(lines))
+		}
+		for i, line := range lines {
+			if w := displayWidth(line); w != 16 {
+				t.Errorf("%s: line %d has width %d, want 16", name, i, w)
+			}
+		}
+	}
+}
+
+func TestRenderKittyChunks(t *testing.T) {
+	out, err := renderKitty(testImage(200, 200), 40, 20, 7)
+	if err != nil {
+		t.Fatalf("renderKitty() error = %v", err)
+	}
+
+	chunks := strings.Split(strings.TrimSuffix(out, "\x1b\\"), "\x1b\\")
+	if !strings.HasPrefix(chunks[0], "\x1b_Ga=T,f=100,i=7,c=40,r=20,C=1") {
+		t.Errorf("unexpected first chunk header: %.40q", chunks[0])
+	}
+	for i, chunk := range chunks {
+		payload := chunk[strings.IndexByte(chunk, ';')+1:]
+		if len(payload) > 4096 {
+			t.Errorf("chunk %d payload is %d bytes", i, len(payload))
+		}
+		last := i == len(chunks)-1
+		if strings.Contains(chunk, "m=1;") == last {
+			t.Errorf("chunk %d has wrong continuation flag", i)
+		}
+	}
+}
+
+func TestRenderSixel(t *testing.T) {
+	out := renderSixel(testImage(20, 20), 2, 1)
+
+	if !strings.HasPrefix(out, "\x1bPq\"1;1;20;20") || !strings.HasSuffix(out, "\x1b\\") {
+		t.Errorf("malformed sixel sequence: %.40q", out)
+	}
+	// 20 pixel rows make four bands of six.
+	if bands := strings.Count(out, "-"); bands != 4 {
+		t.Errorf("expected 4 bands, got %d", bands)
+	}
+}
+
+func TestImageCacheRender(t *testing.T) {
+	cache := newImageCache()
+	cache.images["page.png"] = testImage(100, 50)
+
+	out, err := cache.Render("page.png", GraphicsSixel, termenv.TrueColor, 20, 10)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if lines := strings.Count(out, "\n") + 1; lines != 5 {
+		t.Errorf("pixel image should reserve 5 lines, got %d", lines)
+	}
+	if w := displayWidth(out); w != 0 {
+		t.Errorf("escape sequences should have no width, got %d", w)
+	}
+
+	ascii, _ := cache.Render("page.png", GraphicsBlocks, termenv.Ascii, 20, 10)
+	if strings.Contains(ascii, "\x1b") {
+		t.Error("blocks should fall back to plain ASCII without color support")
+	}
+
+	if out, _ := cache.Render("missing.png", GraphicsBlocks, termenv.TrueColor, 20, 10); out != "" {
+		t.Error("unloaded images should render as nothing")
+	}
+	if !cache.needsLoad("missing.png") || cache.needsLoad("page.png") {
+		t.Error("needsLoad reports the wrong images")
+	}
+}
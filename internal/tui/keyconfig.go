@@ -0,0 +1,152 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// bindings returns the configurable bindings keyed by their config file name.
+func (k *KeyMap) bindings() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"up":              &k.Up,
+		"down":            &k.Down,
+		"select":          &k.Select,
+		"back":            &k.Back,
+		"quit":            &k.Quit,
+		"force_quit":      &k.ForceQuit,
+		"help":            &k.Help,
+		"new_story":       &k.NewStory,
+		"rename":          &k.Rename,
+		"edit_summary":    &k.EditSummary,
+		"art_style":       &k.ArtStyle,
+		"prompt_template": &k.PromptTemplate,
+		"duplicate":       &k.Duplicate,
+		"delete":          &k.Delete,
+		"confirm":         &k.Confirm,
+		"sort":            &k.Sort,
+		"filter":          &k.Filter,
+		"theme":           &k.Theme,
+		"toggle":          &k.Toggle,
+		"words":           &k.Words,
+		"practice":        &k.Practice,
+		"start_chat":      &k.StartChat,
+		"play_pause":      &k.PlayPause,
+		"next_page":       &k.NextPage,
+		"prev_page":       &k.PrevPage,
+		"stop_reading":    &k.StopReading,
+		"quiz":            &k.Quiz,
+		"glossary":        &k.Glossary,
+		"send":            &k.Send,
+		"newline":         &k.Newline,
+		"history_prev":    &k.HistoryPrev,
+		"history_next":    &k.HistoryNext,
+		"record":          &k.Record,
+		"next_choice":     &k.NextChoice,
+		"prev_choice":     &k.PrevChoice,
+		"recall_forgot":   &k.RecallForgot,
+		"recall_hard":     &k.RecallHard,
+		"recall_good":     &k.RecallGood,
+		"recall_easy":     &k.RecallEasy,
+		"parent":          &k.Parent,
+		"export":          &k.Export,
+		"limits":          &k.Limits,
+		"topics":          &k.Topics,
+	}
+}
+
+// modeActions lists the bindings that are active together in each mode.
+// Two bindings in the same mode must not share a key.
+var modeActions = map[AppMode][]string{
+	ModeUserSelection: {"up", "down", "select", "back", "quit", "force_quit", "help", "parent"},
+	ModeStoryList: {
+		"up", "down", "select", "back", "quit", "force_quit", "help",
+		"new_story", "rename", "edit_summary", "art_style", "prompt_template", "duplicate", "delete", "sort", "filter", "theme", "toggle", "words", "practice",
+	},
+	ModeStoryView: {
+		"start_chat", "back", "quit", "force_quit", "help",
+		"play_pause", "next_page", "prev_page", "stop_reading", "quiz", "glossary",
+	},
+	ModeQuiz:  {"up", "down", "select", "back", "quit", "force_quit", "help"},
+	ModeWords: {"up", "down", "back", "quit", "force_quit", "help"},
+	ModeReview: {
+		"select", "back", "quit", "force_quit", "help",
+		"recall_forgot", "recall_hard", "recall_good", "recall_easy",
+	},
+	ModeParent: {"up", "down", "select", "back", "quit", "force_quit", "help", "export", "limits", "topics"},
+	ModeLocked: {"select", "back", "force_quit"},
+	ModeChat:   {"send", "newline", "history_prev", "history_next", "record", "next_choice", "prev_choice", "back", "force_quit"},
+}
+
+// LoadKeyMap reads key binding overrides from a JSON file and applies them on
+// top of DefaultKeyMap. The file maps action names to lists of keys, e.g.
+//
+//	{"up": ["up", "w"], "new_story": ["N"]}
+//
+// Unknown actions, empty key lists and keys bound to two actions in the same
+// mode are reported as errors.
+func LoadKeyMap(path string) (KeyMap, error) {
+	keys := DefaultKeyMap
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return keys, fmt.Errorf("read keymap file: %w", err)
+	}
+
+	var overrides map[string][]string
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		return keys, fmt.Errorf("parse keymap file: %w", err)
+	}
+
+	if err := keys.Apply(overrides); err != nil {
+		return keys, err
+	}
+	return keys, nil
+}
+
+// Apply replaces the keys of the named bindings and validates the result.
+func (k *KeyMap) Apply(overrides map[string][]string) error {
+	bindings := k.bindings()
+
+	for name, keys := range overrides {
+		binding, ok := bindings[name]
+		if !ok {
+			return fmt.Errorf("unknown key binding %q", name)
+		}
+		if len(keys) == 0 {
+			return fmt.Errorf("key binding %q has no keys", name)
+		}
+		binding.SetKeys(keys...)
+		binding.SetHelp(strings.Join(keys, "/"), binding.Help().Desc)
+	}
+
+	return k.Validate()
+}
+
+// Validate checks that no key is bound to more than one action within a mode.
+func (k *KeyMap) Validate() error {
+	bindings := k.bindings()
+
+	modes := make([]AppMode, 0, len(modeActions))
+	for mode := range modeActions {
+		modes = append(modes, mode)
+	}
+	sort.Slice(modes, func(i, j int) bool { return modes[i] < modes[j] })
+
+	for _, mode := range modes {
+		owners := make(map[string]string)
+		for _, name := range modeActions[mode] {
+			for _, keyName := range bindings[name].Keys() {
+				if other, taken := owners[keyName]; taken {
+					return fmt.Errorf("key %q is bound to both %q and %q", keyName, other, name)
+				}
+				owners[keyName] = name
+			}
+		}
+	}
+	return nil
+}
@@ -1,27 +1,162 @@
 package tui
 
-import "github.com/charmbracelet/bubbles/key"
+import (
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+)
 
 // KeyMap defines the key bindings for the application.
 type KeyMap struct {
-	Up       key.Binding
-	Down     key.Binding
-	Select   key.Binding
-	Back     key.Binding
-	Quit     key.Binding
-	NewStory key.Binding
+	Up        key.Binding
+	Down      key.Binding
+	Select    key.Binding
+	Back      key.Binding
+	Quit      key.Binding
+	ForceQuit key.Binding
+	Help      key.Binding
+	NewStory  key.Binding
+
+	// Story management
+	Rename         key.Binding
+	EditSummary    key.Binding
+	ArtStyle       key.Binding
+	PromptTemplate key.Binding
+	Duplicate      key.Binding
+	Delete         key.Binding
+	Confirm        key.Binding
+	Sort           key.Binding
+	Filter         key.Binding
+	Theme          key.Binding
+	Toggle         key.Binding
+	Words          key.Binding
+	Practice       key.Binding
+
+	// Story view and chat
+	StartChat   key.Binding
+	PlayPause   key.Binding
+	NextPage    key.Binding
+	PrevPage    key.Binding
+	StopReading key.Binding
+	Quiz        key.Binding
+	Glossary    key.Binding
+	Send        key.Binding
+	Newline     key.Binding
+	HistoryPrev key.Binding
+	HistoryNext key.Binding
+	Record      key.Binding
+	NextChoice  key.Binding
+	PrevChoice  key.Binding
+
+	// Word practice
+	RecallForgot key.Binding
+	RecallHard   key.Binding
+	RecallGood   key.Binding
+	RecallEasy   key.Binding
+	// Parent dashboard
+	Parent key.Binding
+	Export key.Binding
+	Limits key.Binding
+	Topics key.Binding
 }
 
-// ShortHelp returns key bindings for the short help view.
-func (k KeyMap) ShortHelp() []key.Binding {
-	return []key.Binding{k.Select, k.Back, k.Quit}
+// modeHelp is the help.KeyMap shown for a single application mode.
+type modeHelp struct {
+	short []key.Binding
+	full  [][]key.Binding
 }
 
-// FullHelp returns key bindings for the full help view.
-func (k KeyMap) FullHelp() [][]key.Binding {
-	return [][]key.Binding{
-		{k.Up, k.Down, k.Select},
-		{k.Back, k.Quit, k.NewStory},
+func (h modeHelp) ShortHelp() []key.Binding  { return h.short }
+func (h modeHelp) FullHelp() [][]key.Binding { return h.full }
+
+// HelpFor returns the bindings relevant to the given mode for rendering with bubbles/help.
+func (k KeyMap) HelpFor(mode AppMode) help.KeyMap {
+	switch mode {
+	case ModeStoryList:
+		return modeHelp{
+			short: []key.Binding{k.Up, k.Down, k.Select, k.NewStory, k.Back, k.Help, k.Quit},
+			full: [][]key.Binding{
+				{k.Up, k.Down, k.Select, k.Back},
+				{k.NewStory, k.Rename, k.EditSummary, k.ArtStyle, k.PromptTemplate},
+				{k.Duplicate, k.Delete, k.Sort, k.Filter},
+				{k.Toggle, k.Words, k.Practice, k.Theme, k.Help, k.Quit},
+			},
+		}
+	case ModeStoryView:
+		return modeHelp{
+			short: []key.Binding{k.StartChat, k.PlayPause, k.Quiz, k.Back, k.Help, k.Quit},
+			full: [][]key.Binding{
+				{k.StartChat, k.Quiz, k.Glossary, k.Back},
+				{k.PlayPause, k.StopReading},
+				{k.PrevPage, k.NextPage},
+				{k.Help, k.Quit},
+			},
+		}
+	case ModeChat:
+		return modeHelp{
+			short: []key.Binding{k.Send, k.Newline, k.NextChoice, k.Record, k.HistoryPrev, k.Back, k.ForceQuit},
+			full: [][]key.Binding{
+				{k.Send, k.Newline, k.Record},
+				{k.NextChoice, k.PrevChoice},
+				{k.HistoryPrev, k.HistoryNext},
+				{k.Back, k.ForceQuit},
+			},
+		}
+	case ModeQuiz:
+		return modeHelp{
+			short: []key.Binding{k.Up, k.Down, k.Select, k.Back, k.Help, k.Quit},
+			full: [][]key.Binding{
+				{k.Up, k.Down, k.Select},
+				{k.Back, k.Help, k.Quit},
+			},
+		}
+	case ModeWords:
+		return modeHelp{
+			short: []key.Binding{k.Up, k.Down, k.Back, k.Help, k.Quit},
+			full: [][]key.Binding{
+				{k.Up, k.Down},
+				{k.Back, k.Help, k.Quit},
+			},
+		}
+	case ModeReview:
+		return modeHelp{
+			short: []key.Binding{k.Select, k.RecallForgot, k.RecallHard, k.RecallGood, k.RecallEasy, k.Back, k.Quit},
+			full: [][]key.Binding{
+				{k.Select, k.Back},
+				{k.RecallForgot, k.RecallHard, k.RecallGood, k.RecallEasy},
+				{k.Help, k.Quit},
+			},
+		}
+	case ModeParent:
+		return modeHelp{
+			short: []key.Binding{k.Up, k.Down, k.Export, k.Limits, k.Topics, k.Back, k.Help, k.Quit},
+			full: [][]key.Binding{
+				{k.Up, k.Down, k.Export, k.Limits, k.Topics},
+				{k.Back, k.Help, k.Quit},
+			},
+		}
+	case ModeLocked:
+		return modeHelp{
+			short: []key.Binding{k.Select, k.Back, k.ForceQuit},
+			full: [][]key.Binding{
+				{k.Select, k.Back, k.ForceQuit},
+			},
+		}
+	case ModeUserSelection:
+		return modeHelp{
+			short: []key.Binding{k.Up, k.Down, k.Select, k.Parent, k.Help, k.Quit},
+			full: [][]key.Binding{
+				{k.Up, k.Down, k.Select},
+				{k.Parent, k.Help, k.Quit},
+			},
+		}
+	default:
+		return modeHelp{
+			short: []key.Binding{k.Up, k.Down, k.Select, k.Help, k.Quit},
+			full: [][]key.Binding{
+				{k.Up, k.Down, k.Select},
+				{k.Help, k.Quit},
+			},
+		}
 	}
 }
 
@@ -44,11 +179,159 @@ var DefaultKeyMap = KeyMap{
 		key.WithHelp("esc", "back"),
 	),
 	Quit: key.NewBinding(
-		key.WithKeys("q", "ctrl+c"),
+		key.WithKeys("q"),
 		key.WithHelp("q", "quit"),
 	),
+	ForceQuit: key.NewBinding(
+		key.WithKeys("ctrl+c"),
+		key.WithHelp("ctrl+c", "quit"),
+	),
+	Help: key.NewBinding(
+		key.WithKeys("?"),
+		key.WithHelp("?", "more keys"),
+	),
 	NewStory: key.NewBinding(
 		key.WithKeys("n"),
 		key.WithHelp("n", "new story"),
 	),
+	Rename: key.NewBinding(
+		key.WithKeys("r"),
+		key.WithHelp("r", "rename"),
+	),
+	EditSummary: key.NewBinding(
+		key.WithKeys("e"),
+		key.WithHelp("e", "edit summary"),
+	),
+	ArtStyle: key.NewBinding(
+		key.WithKeys("a"),
+		key.WithHelp("a", "art style"),
+	),
+	PromptTemplate: key.NewBinding(
+		key.WithKeys("v"),
+		key.WithHelp("v", "prompt template"),
+	),
+	Duplicate: key.NewBinding(
+		key.WithKeys("c"),
+		key.WithHelp("c", "duplicate"),
+	),
+	Delete: key.NewBinding(
+		key.WithKeys("d"),
+		key.WithHelp("d", "delete"),
+	),
+	Confirm: key.NewBinding(
+		key.WithKeys("y", "Y"),
+		key.WithHelp("y", "confirm"),
+	),
+	Sort: key.NewBinding(
+		key.WithKeys("s"),
+		key.WithHelp("s", "sort"),
+	),
+	Filter: key.NewBinding(
+		key.WithKeys("/"),
+		key.WithHelp("/", "filter"),
+	),
+	Theme: key.NewBinding(
+		key.WithKeys("t"),
+		key.WithHelp("t", "theme"),
+	),
+	Toggle: key.NewBinding(
+		key.WithKeys(" "),
+		key.WithHelp("space", "toggle objective"),
+	),
+	Words: key.NewBinding(
+		key.WithKeys("m"),
+		key.WithHelp("m", "my words"),
+	),
+	Practice: key.NewBinding(
+		key.WithKeys("p"),
+		key.WithHelp("p", "practise words"),
+	),
+	StartChat: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "start chat"),
+	),
+	PlayPause: key.NewBinding(
+		key.WithKeys(" ", "p"),
+		key.WithHelp("space", "read aloud/pause"),
+	),
+	NextPage: key.NewBinding(
+		key.WithKeys("right", "l"),
+		key.WithHelp("→/l", "next page"),
+	),
+	PrevPage: key.NewBinding(
+		key.WithKeys("left", "h"),
+		key.WithHelp("←/h", "previous page"),
+	),
+	StopReading: key.NewBinding(
+		key.WithKeys("x"),
+		key.WithHelp("x", "stop reading"),
+	),
+	Quiz: key.NewBinding(
+		key.WithKeys("z"),
+		key.WithHelp("z", "quiz"),
+	),
+	Glossary: key.NewBinding(
+		key.WithKeys("g"),
+		key.WithHelp("g", "word meanings"),
+	),
+	Send: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "send"),
+	),
+	Newline: key.NewBinding(
+		key.WithKeys("alt+enter", "shift+enter", "ctrl+j"),
+		key.WithHelp("alt+enter", "new line"),
+	),
+	HistoryPrev: key.NewBinding(
+		key.WithKeys("up"),
+		key.WithHelp("↑", "previous prompt"),
+	),
+	HistoryNext: key.NewBinding(
+		key.WithKeys("down"),
+		key.WithHelp("↓", "next prompt"),
+	),
+	Record: key.NewBinding(
+		key.WithKeys("ctrl+r"),
+		key.WithHelp("ctrl+r", "speak"),
+	),
+	NextChoice: key.NewBinding(
+		key.WithKeys("tab"),
+		key.WithHelp("tab", "next choice"),
+	),
+	PrevChoice: key.NewBinding(
+		key.WithKeys("shift+tab"),
+		key.WithHelp("shift+tab", "previous choice"),
+	),
+	RecallForgot: key.NewBinding(
+		key.WithKeys("1"),
+		key.WithHelp("1", "forgot"),
+	),
+	RecallHard: key.NewBinding(
+		key.WithKeys("2"),
+		key.WithHelp("2", "tricky"),
+	),
+	RecallGood: key.NewBinding(
+		key.WithKeys("3"),
+		key.WithHelp("3", "remembered"),
+	),
+	RecallEasy: key.NewBinding(
+		key.WithKeys("4"),
+		key.WithHelp("4", "easy"),
+	),
+	Parent: key.NewBinding(
+		key.WithKeys("P"),
+		key.WithHelp("P", "parent dashboard"),
+	),
+	Export: key.NewBinding(
+		key.WithKeys("e"),
+		key.WithHelp("e", "export weekly report"),
+	),
+	Limits: key.NewBinding(
+		key.WithKeys("l"),
+		key.WithHelp("l", "screen time"),
+	),
+	Topics: key.NewBinding(
+		key.WithKeys("t"),
+		key.WithHelp("t", "topics"),
+	),
 }
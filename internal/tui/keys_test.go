@@ -0,0 +1,80 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDefaultKeyMapValid(t *testing.T) {
+	keys := DefaultKeyMap
+	if err := keys.Validate(); err != nil {
+		t.Fatalf("DefaultKeyMap.Validate() = %v", err)
+	}
+}
+
+func TestKeyMapApply(t *testing.T) {
+	tests := []struct {
+		name      string
+		overrides map[string][]string
+		wantErr   bool
+	}{
+		{
+			name:      "rebind action",
+			overrides: map[string][]string{"new_story": {"N"}},
+		},
+		{
+			name:      "same key in different modes",
+			overrides: map[string][]string{"send": {"ctrl+s"}, "rename": {"ctrl+s"}},
+		},
+		{
+			name:      "unknown action",
+			overrides: map[string][]string{"fly": {"f"}},
+			wantErr:   true,
+		},
+		{
+			name:      "empty key list",
+			overrides: map[string][]string{"up": {}},
+			wantErr:   true,
+		},
+		{
+			name:      "conflict within mode",
+			overrides: map[string][]string{"rename": {"d"}},
+			wantErr:   true,
+		},
+		{
+			name:      "conflict with global quit",
+			overrides: map[string][]string{"sort": {"q"}},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys := DefaultKeyMap
+			err := keys.Apply(tt.overrides)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Apply() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadKeyMap(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	if err := os.WriteFile(path, []byte(`{"up": ["up", "w"]}`), 0644); err != nil {
+		t.Fatalf("write keymap: %v", err)
+	}
+
+	keys, err := LoadKeyMap(path)
+	if err != nil {
+		t.Fatalf("LoadKeyMap failed: %v", err)
+	}
+
+	if got := keys.Up.Help().Key; got != "up/w" {
+		t.Errorf("Up help key = %q, want %q", got, "up/w")
+	}
+	if got := DefaultKeyMap.Up.Keys(); len(got) != 2 || got[1] != "k" {
+		t.Errorf("DefaultKeyMap was modified: %v", got)
+	}
+}
@@ -0,0 +1,260 @@
+package tui
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	headingPattern = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	bulletPattern  = regexp.MustCompile(`^(\s*)[-*+]\s+(.*)$`)
+	orderedPattern = regexp.MustCompile(`^(\s*)(\d+)[.)]\s+(.*)$`)
+	rulePattern    = regexp.MustCompile(`^(?:(?:-\s*){3,}|(?:\*\s*){3,}|(?:_\s*){3,})$`)
+)
+
+// inlineStyle is a set of inline markdown emphasis flags.
+type inlineStyle int
+
+const (
+	inlineBold inlineStyle = 1 << iota
+	inlineItalic
+	inlineCode
+)
+
+// span is a run of text with a single inline style.
+type span struct {
+	text  string
+	style inlineStyle
+}
+
+// renderMarkdown renders a subset of markdown (headings, lists, quotes, rules,
+// code blocks and inline emphasis) for the terminal, wrapped to width.
+// Block structure follows CommonMark loosely; anything unrecognised is
+// rendered as a plain paragraph.
+func renderMarkdown(text string, width int, s Styles) string {
+	if width <= 0 {
+		width = 80
+	}
+
+	var out []string
+	var para []string
+	inCode := false
+
+	flush := func() {
+		if len(para) > 0 {
+			out = append(out, wrapSpans(parseInline(strings.Join(para, " ")), width, "", "", s))
+			para = nil
+		}
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "```") {
+			flush()
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			out = append(out, s.Code.Render("  "+line))
+			continue
+		}
+
+		switch {
+		case trimmed == "":
+			flush()
+			if len(out) > 0 && out[len(out)-1] != "" {
+				out = append(out, "")
+			}
+		case headingPattern.MatchString(trimmed):
+			flush()
+			heading := headingPattern.FindStringSubmatch(trimmed)[2]
+			out = append(out, s.Heading.Render(wrapSpans(parseInline(heading), width, "", "", s)))
+		case rulePattern.MatchString(trimmed):
+			flush()
+			out = append(out, s.Help.UnsetMarginTop().Render(strings.Repeat("─", min(width, 40))))
+		case bulletPattern.MatchString(line):
+			flush()
+			match := bulletPattern.FindStringSubmatch(line)
+			indent := listIndent(match[1])
+			out = append(out, wrapSpans(parseInline(match[2]), width, indent+"• ", indent+"  ", s))
+		case orderedPattern.MatchString(line):
+			flush()
+			match := orderedPattern.FindStringSubmatch(line)
+			indent := listIndent(match[1])
+			marker := match[2] + ". "
+			out = append(out, wrapSpans(parseInline(match[3]), width, indent+marker, indent+strings.Repeat(" ", len(marker)), s))
+		case strings.HasPrefix(trimmed, ">"):
+			flush()
+			quote := strings.TrimSpace(strings.TrimPrefix(trimmed, ">"))
+			out = append(out, s.Quote.Render(wrapSpans(parseInline(quote), width, "│ ", "│ ", s)))
+		default:
+			para = append(para, trimmed)
+		}
+	}
+	flush()
+
+	return strings.Trim(strings.Join(out, "\n"), "\n")
+}
+
+// listIndent normalises leading list whitespace to two spaces per level.
+func listIndent(leading string) string {
+	return strings.Repeat("  ", len(strings.ReplaceAll(leading, "\t", "    "))/2)
+}
+
+// parseInline splits text into spans of bold, italic and code text.
+// Emphasis markers without a matching closer are kept as literal text.
+func parseInline(text string) []span {
+	var spans []span
+	var current strings.Builder
+	style := inlineStyle(0)
+
+	emit := func() {
+		if current.Len() > 0 {
+			spans = append(spans, span{text: current.String(), style: style})
+			current.Reset()
+		}
+	}
+
+	for i := 0; i < len(text); {
+		rest := text[i:]
+		switch {
+		case rest[0] == '`':
+			if end := strings.IndexByte(rest[1:], '`'); end >= 0 {
+				emit()
+				spans = append(spans, span{text: rest[1 : end+1], style: style | inlineCode})
+				i += end + 2
+				continue
+			}
+		case strings.HasPrefix(rest, "**") || strings.HasPrefix(rest, "__"):
+			marker := rest[:2]
+			if style&inlineBold != 0 || strings.Contains(rest[2:], marker) {
+				emit()
+				style ^= inlineBold
+				i += 2
+				continue
+			}
+		case rest[0] == '*' || rest[0] == '_':
+			marker := rest[:1]
+			opening := style&inlineItalic == 0
+			wordStart := i == 0 || !isWordByte(text[i-1])
+			if !opening || (wordStart && len(rest) > 1 && rest[1] != ' ' && strings.Contains(rest[1:], marker)) {
+				emit()
+				style ^= inlineItalic
+				i++
+				continue
+			}
+		}
+		current.WriteByte(text[i])
+		i++
+	}
+	emit()
+
+	return spans
+}
+
+func isWordByte(b byte) bool {
+	return b == '_' || b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z'
+}
+
+// wrapSpans word-wraps styled spans to width. The first line starts with
+// prefix and continuation lines with indent. Each word is styled before
+// wrapping; wrapLine ignores escape sequences when measuring, so styling
+// never affects where lines break.
+func wrapSpans(spans []span, width int, prefix, indent string, s Styles) string {
+	var b strings.Builder
+	for _, sp := range spans {
+		for j, part := range strings.Split(sp.text, " ") {
+			if j > 0 {
+				b.WriteString(" ")
+			}
+			if part != "" {
+				b.WriteString(styleInline(span{text: part, style: sp.style}, s))
+			}
+		}
+	}
+	return wrapLine(b.String(), width, prefix, indent)
+}
+
+// styleInline renders a span with the styles for its emphasis flags.
+func styleInline(sp span, s Styles) string {
+	if sp.style == 0 {
+		return sp.text
+	}
+
+	style := lipgloss.NewStyle()
+	if sp.style&inlineCode != 0 {
+		style = s.Code
+	}
+	if sp.style&inlineBold != 0 {
+		style = style.Bold(true)
+	}
+	if sp.style&inlineItalic != 0 {
+		style = style.Italic(true)
+	}
+	return style.Render(sp.text)
+}
+
+// streamRender incrementally renders a completion as it streams in. Blocks
+// that are finished (followed by a blank line outside a code fence) are
+// rendered once and kept, so earlier paragraphs don't reflow while the model
+// is still writing the current one.
+type streamRender struct {
+	width    int
+	consumed int    // bytes of the source already rendered into done
+	done     string // rendered completed blocks
+}
+
+// Render returns the rendered source, reusing completed blocks from earlier calls.
+func (r *streamRender) Render(source string, width int, render func(string, int) string) string {
+	if width != r.width || len(source) < r.consumed {
+		*r = streamRender{width: width}
+	}
+
+	if boundary := completedBoundary(source); boundary > r.consumed {
+		if block := strings.Trim(source[r.consumed:boundary], "\n"); block != "" {
+			if r.done != "" {
+				r.done += "\n\n"
+			}
+			r.done += render(block, width)
+		}
+		r.consumed = boundary
+	}
+
+	tail := strings.Trim(source[r.consumed:], "\n")
+	switch {
+	case tail == "":
+		return r.done
+	case r.done == "":
+		return render(tail, width)
+	default:
+		return r.done + "\n\n" + render(tail, width)
+	}
+}
+
+// completedBoundary returns the offset just past the last blank line in
+// source that is not inside a code fence.
+func completedBoundary(source string) int {
+	boundary := 0
+	inCode := false
+	offset := 0
+
+	for _, line := range strings.SplitAfter(source, "\n") {
+		offset += len(line)
+		if !strings.HasSuffix(line, "\n") {
+			break // the last line is still being written
+		}
+
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inCode = !inCode
+		}
+		if trimmed == "" && !inCode {
+			boundary = offset
+		}
+	}
+
+	return boundary
+}
@@ -0,0 +1,122 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+func plainStyles() Styles {
+	lipgloss.SetColorProfile(termenv.Ascii)
+	return NewStyles(Theme{})
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	s := plainStyles()
+
+	tests := []struct {
+		name     string
+		input    string
+		width    int
+		expected string
+	}{
+		{
+			name:     "plain paragraph joins lines",
+			input:    "The fox\njumped high.",
+			width:    80,
+			expected: "The fox jumped high.",
+		},
+		{
+			name:     "emphasis markers removed",
+			input:    "A **big** and *tiny* `word`.",
+			width:    80,
+			expected: "A big and tiny word.",
+		},
+		{
+			name:     "snake case kept",
+			input:    "my_var_name",
+			width:    80,
+			expected: "my_var_name",
+		},
+		{
+			name:     "unmatched marker kept",
+			input:    "2 * 3 = 6",
+			width:    80,
+			expected: "2 * 3 = 6",
+		},
+		{
+			name:     "bullets with hanging indent",
+			input:    "- one two three four",
+			width:    12,
+			expected: "• one two\n  three four",
+		},
+		{
+			name:     "ordered list",
+			input:    "1. First\n2. Second",
+			width:    80,
+			expected: "1. First\n2. Second",
+		},
+		{
+			name:     "heading",
+			input:    "## Counting Stars",
+			width:    80,
+			expected: "Counting Stars",
+		},
+		{
+			name:     "paragraphs separated by one blank line",
+			input:    "One.\n\n\n\nTwo.",
+			width:    80,
+			expected: "One.\n\nTwo.",
+		},
+		{
+			name:     "code block kept verbatim",
+			input:    "```\n*not italic*\n```",
+			width:    80,
+			expected: "  *not italic*",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := renderMarkdown(tt.input, tt.width, s)
+			if got != tt.expected {
+				t.Errorf("renderMarkdown(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestStreamRenderKeepsCompletedBlocks(t *testing.T) {
+	calls := 0
+	render := func(text string, width int) string {
+		calls++
+		return strings.ToUpper(text)
+	}
+
+	r := &streamRender{}
+	got := r.Render("first para\n\nsecond", 80, render)
+	if got != "FIRST PARA\n\nSECOND" {
+		t.Fatalf("Render = %q", got)
+	}
+	if r.done != "FIRST PARA" {
+		t.Errorf("done = %q, want completed first paragraph", r.done)
+	}
+
+	calls = 0
+	got = r.Render("first para\n\nsecond para", 80, render)
+	if got != "FIRST PARA\n\nSECOND PARA" {
+		t.Fatalf("Render = %q", got)
+	}
+	if calls != 1 {
+		t.Errorf("render called %d times, want only the tail to be re-rendered", calls)
+	}
+}
+
+func TestCompletedBoundaryIgnoresCodeFences(t *testing.T) {
+	source := "intro\n\n```\ncode\n\nmore code\n"
+	if got := completedBoundary(source); got != len("intro\n\n") {
+		t.Errorf("completedBoundary = %d, want %d", got, len("intro\n\n"))
+	}
+}
@@ -0,0 +1,183 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kbrakke/illustrated-primer/internal/ai"
+	"github.com/kbrakke/illustrated-primer/internal/models"
+)
+
+// promptBlockedMsg reports that moderation stopped a prompt from being sent.
+type promptBlockedMsg struct {
+	prompt  string
+	verdict ai.Verdict
+}
+
+// completionModeratedMsg carries the moderation verdicts on a finished
+// completion and the choices offered with it.
+type completionModeratedMsg struct {
+	prompt         string
+	page           ai.StoryPage // as written by the model, again if it broke the child's preferences
+	original       string       // the page text as first written
+	preferences    ai.Verdict   // on the child's content preferences
+	verdict        ai.Verdict
+	choicesVerdict ai.Verdict // zero when there were no choices to check
+	choicesErr     error
+	err            error
+}
+
+type moderationRecordedMsg struct {
+	err error
+}
+
+// checkCompletion moderates a finished completion if a guard is set or the
+// child has topics to keep out, and otherwise finishes the chat turn with it.
+func (m *Model) checkCompletion(prompt string, page ai.StoryPage) tea.Cmd {
+	if (m.guard != nil || m.restricted()) && prompt != "" {
+		m.stream = nil
+		m.statusMessage = "Checking the story..."
+		return m.moderateCompletion(prompt, page)
+	}
+	return m.finishResponse(prompt, page)
+}
+
+// moderateCompletion checks a finished completion and its choices before
+// they are shown and saved: first against the child's content preferences,
+// writing the page again if it breaks them, and then with the guard.
+func (m Model) moderateCompletion(prompt string, page ai.StoryPage) tea.Cmd {
+	guard, preferences := m.guard, m.preferenceGuard()
+	return func() tea.Msg {
+		ctx := context.Background()
+		msg := completionModeratedMsg{prompt: prompt, page: page, original: page.Text, preferences: allowed(page.Text)}
+		if preferences != nil {
+			msg.page, msg.preferences, msg.err = m.respectPreferences(ctx, preferences, prompt, page)
+			if msg.err != nil || msg.preferences.Action == ai.ActionBlock {
+				return msg
+			}
+			page = msg.page
+		}
+		if guard == nil {
+			msg.verdict, msg.choicesVerdict = allowed(page.Text), allowed("")
+			return msg
+		}
+
+		msg.verdict, msg.err = guard.Check(ctx, ai.SourceCompletion, page.Text)
+		if msg.err == nil && msg.verdict.Action != ai.ActionBlock && len(page.Choices) > 0 {
+			msg.choicesVerdict, msg.choicesErr = guard.Check(ctx, ai.SourceCompletion, strings.Join(page.Choices, "\n"))
+		}
+		return msg
+	}
+}
+
+// allowed is the verdict on text nothing was checked for.
+func allowed(text string) ai.Verdict {
+	return ai.Verdict{Action: ai.ActionAllow, Text: text}
+}
+
+// keptChoices returns the choices that may be offered: all of them if they
+// passed moderation unchanged, or none, leaving the child to write their own.
+func keptChoices(msg completionModeratedMsg) []string {
+	if msg.choicesErr != nil {
+		return nil
+	}
+	switch msg.choicesVerdict.Action {
+	case ai.ActionAllow, ai.ActionFlag:
+		return msg.page.Choices
+	default:
+		return nil
+	}
+}
+
+// recordModeration stores a verdict that wasn't a plain allow for parents to review.
+func (m Model) recordModeration(source ai.Source, original string, verdict ai.Verdict) tea.Cmd {
+	if verdict.Action == ai.ActionAllow || m.currentUser == nil {
+		return nil
+	}
+
+	final := verdict.Text
+	if verdict.Action == ai.ActionBlock {
+		final = ""
+	}
+	flag := models.NewModerationFlag(m.currentUser.ID, string(source), string(verdict.Action),
+		verdict.Categories(), original, final)
+	if m.currentStory != nil {
+		storyID := m.currentStory.ID
+		flag.StoryID = &storyID
+	}
+
+	m.logger.Warn("content moderated",
+		"source", source,
+		"action", verdict.Action,
+		"categories", verdict.Categories(),
+	)
+
+	return func() tea.Msg {
+		return moderationRecordedMsg{err: m.db.CreateModerationFlag(context.Background(), flag)}
+	}
+}
+
+// updateModeration handles moderation messages.
+func (m Model) updateModeration(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case promptBlockedMsg:
+		m.isLoading = false
+		m.inputBuffer = ""
+		m.statusMessage = "Let's try asking that a different way."
+		return m, m.recordModeration(ai.SourcePrompt, msg.prompt, msg.verdict)
+
+	case completionModeratedMsg:
+		if msg.err != nil {
+			m.isLoading = false
+			m.inputBuffer = ""
+			m.streamingResponse = ""
+			m.statusMessage = fmt.Sprintf("Couldn't check the story, so it wasn't shown: %v", msg.err)
+			m.logger.Error("failed to moderate completion", "error", msg.err)
+			return m, nil
+		}
+
+		recordPreferences := m.recordModeration(ai.SourceCompletion, msg.original, msg.preferences)
+		if msg.preferences.Action == ai.ActionBlock {
+			m.isLoading = false
+			m.inputBuffer = ""
+			m.streamingResponse = ""
+			m.statusMessage = "The story wandered somewhere it shouldn't. Let's try again!"
+			return m, recordPreferences
+		}
+
+		record := m.recordModeration(ai.SourceCompletion, msg.page.Text, msg.verdict)
+		if msg.verdict.Action == ai.ActionBlock {
+			m.isLoading = false
+			m.inputBuffer = ""
+			m.streamingResponse = ""
+			m.statusMessage = "The story wandered somewhere it shouldn't. Let's try again!"
+			return m, tea.Batch(recordPreferences, record)
+		}
+
+		var recordChoices tea.Cmd
+		if msg.choicesErr != nil {
+			m.logger.Error("failed to moderate choices, not offering them", "error", msg.choicesErr)
+		} else if len(msg.page.Choices) > 0 {
+			// Choices aren't rewritten, only dropped.
+			verdict := msg.choicesVerdict
+			if verdict.Action == ai.ActionRewrite {
+				verdict.Action = ai.ActionBlock
+			}
+			recordChoices = m.recordModeration(ai.SourceCompletion, strings.Join(msg.page.Choices, "\n"), verdict)
+		}
+
+		page := msg.page
+		page.Text = msg.verdict.Text
+		page.Choices = keptChoices(msg)
+		return m, tea.Batch(recordPreferences, record, recordChoices, m.finishResponse(msg.prompt, page))
+
+	case moderationRecordedMsg:
+		if msg.err != nil {
+			m.logger.Error("failed to record moderation flag", "error", msg.err)
+		}
+	}
+
+	return m, nil
+}
@@ -0,0 +1,98 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kbrakke/illustrated-primer/internal/ai"
+	"github.com/kbrakke/illustrated-primer/testutil"
+)
+
+// send types a prompt and sends it, returning the message produced by sendMessage.
+func send(t *testing.T, m Model, prompt string) (Model, tea.Msg) {
+	t.Helper()
+	m.composer.SetValue(prompt)
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	for _, msg := range collectMsgs(cmd) {
+		if _, tick := msg.(spinner.TickMsg); !tick {
+			return updated.(Model), msg
+		}
+	}
+	t.Fatal("message was not sent")
+	return m, nil
+}
+
+// stream feeds a started stream's chunks into the model until it is done.
+func stream(t *testing.T, m Model, msg tea.Msg) (Model, tea.Cmd) {
+	t.Helper()
+	updated, _ := m.Update(msg)
+	m = updated.(Model)
+	for {
+		msg := waitForChunk(m.stream)()
+		updated, cmd := m.Update(msg)
+		m = updated.(Model)
+		if _, done := msg.(aiDoneMsg); done {
+			return m, cmd
+		}
+		if m.streamRendered != "" {
+			t.Error("a moderated response was shown before it was checked")
+		}
+	}
+}
+
+func TestModerationRewritesPrompt(t *testing.T) {
+	client := testutil.NewMockAIClient("Once upon a time...")
+	m := newStoryTestModel(client, ModeChat, withRuleModeration(t))
+
+	m, msg := send(t, m, "my email is sam@example.com, tell me a story")
+	m, cmd := stream(t, m, msg)
+
+	if got := client.Calls[0].Message; got != "my email is [private], tell me a story" {
+		t.Errorf("sent %q, want the rewritten prompt", got)
+	}
+	if m.statusMessage != "Checking the story..." {
+		t.Errorf("status = %q while checking", m.statusMessage)
+	}
+
+	updated, _ := m.Update(cmd())
+	m = updated.(Model)
+	if m.isLoading || len(m.conversationHistory) != 2 {
+		t.Fatalf("response should be finished, history %v", m.conversationHistory)
+	}
+	if m.conversationHistory[0] != "my email is [private], tell me a story" {
+		t.Errorf("saved prompt = %q", m.conversationHistory[0])
+	}
+}
+
+func TestModerationBlocksPrompt(t *testing.T) {
+	client := testutil.NewMockAIClient("unused")
+	// Rules carry their own action; use a rule without one so the policy applies.
+	rules, _ := ai.NewRuleModerator([]ai.Rule{{Category: "personal-information", Pattern: `\d{3}-\d{4}`}})
+	guard := &ai.Guard{Moderators: []ai.Moderator{rules}, Policy: ai.Policy{Categories: map[string]ai.Action{"personal-information": ai.ActionBlock}}}
+	m := newStoryTestModel(client, ModeChat, WithModeration(guard))
+
+	m, msg := send(t, m, "call 555-1234")
+	if _, ok := msg.(promptBlockedMsg); !ok {
+		t.Fatalf("got %T, want promptBlockedMsg", msg)
+	}
+	updated, _ := m.Update(msg)
+	m = updated.(Model)
+	if m.isLoading || client.CallCount() != 0 || len(m.conversationHistory) != 0 {
+		t.Error("a blocked prompt must not be sent")
+	}
+}
+
+func TestModerationBlocksCompletion(t *testing.T) {
+	m := newStoryTestModel(testutil.NewMockAIClient("The shit hit the fan."), ModeChat, withRuleModeration(t))
+	m.guard.Moderators[0], _ = ai.NewRuleModerator([]ai.Rule{{Category: "profanity", Words: []string{"shit"}, Action: ai.ActionBlock}})
+
+	m, msg := send(t, m, "tell me a story")
+	m, cmd := stream(t, m, msg)
+	updated, saveCmd := m.Update(cmd())
+	m = updated.(Model)
+
+	if m.isLoading || len(m.conversationHistory) != 0 || saveCmd != nil {
+		t.Error("a blocked completion must not be shown or saved")
+	}
+}
@@ -0,0 +1,603 @@
+package tui
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kbrakke/illustrated-primer/internal/ai"
+	"github.com/kbrakke/illustrated-primer/internal/models"
+	"github.com/kbrakke/illustrated-primer/internal/report"
+)
+
+const (
+	// maxPINAttempts is how many wrong PINs in a row lock every PIN prompt.
+	maxPINAttempts = 3
+	// pinLockout is how long PIN prompts stay locked after that.
+	pinLockout = 5 * time.Minute
+)
+
+// parentState is the parent dashboard: locked until a guardian's PIN is
+// entered, then a summary of each child they look after.
+type parentState struct {
+	guardian *models.User  // whose PIN unlocks it, nil for any guardian's or the parent PIN
+	children []models.User // shown once unlocked
+	unlocked bool
+	editing  bool                            // setting the selected child's screen time or topics
+	topics   bool                            // editing topics rather than screen time
+	index    int                             // child selected
+	week     map[string]*models.ChildSummary // by user ID, nil while loading
+	total    map[string]*models.ChildSummary
+}
+
+type childSummariesLoadedMsg struct {
+	week  map[string]*models.ChildSummary
+	total map[string]*models.ChildSummary
+	err   error
+}
+
+type reportExportedMsg struct {
+	paths []string
+	err   error
+}
+
+// recordUsage stores the tokens a request spent for the current child. It
+// runs inside commands, so a failure is only logged.
+func (m Model) recordUsage(ctx context.Context, kind string, tokens ai.Tokens) {
+	if m.currentUser == nil || tokens.Input+tokens.Output == 0 {
+		return
+	}
+	storyID := ""
+	if m.currentStory != nil {
+		storyID = m.currentStory.ID
+	}
+	if err := m.db.RecordTokenUsage(ctx, models.NewTokenUsage(m.currentUser.ID, storyID, kind, tokens.Input, tokens.Output)); err != nil {
+		m.logger.Error("failed to record token usage", "kind", kind, "error", err)
+	}
+}
+
+// openParent locks the app behind a guardian's PIN and asks for it. A nil
+// guardian accepts any guardian's PIN.
+func (m *Model) openParent(guardian *models.User) {
+	if !m.canUnlock(m.unlockers(guardian)) {
+		if guardian != nil {
+			m.statusMessage = fmt.Sprintf("%s has no PIN yet. Set one with --set-pin.", guardian.DisplayName())
+		} else {
+			m.statusMessage = "Set PRIMER_PARENT_PIN or a guardian PIN to use the parent dashboard."
+		}
+		return
+	}
+	if m.pinLockedOut() {
+		return
+	}
+	m.mode = ModeParent
+	m.parent = &parentState{guardian: guardian}
+	m.textInput.SetValue("")
+	m.textInput.EchoMode = textinput.EchoPassword
+	m.textInput.Focus()
+	m.statusMessage = "Enter the parent PIN:"
+	if guardian != nil {
+		m.statusMessage = fmt.Sprintf("Enter %s's PIN:", guardian.DisplayName())
+	}
+}
+
+// closeParent leaves the parent dashboard for the user selection.
+func (m *Model) closeParent() {
+	m.mode = ModeUserSelection
+	m.parent = nil
+	m.textInput.SetValue("")
+	m.textInput.EchoMode = textinput.EchoNormal
+	m.textInput.Blur()
+}
+
+// loadChildSummaries adds up what each child has done this week and over
+// all time.
+func (m Model) loadChildSummaries(users []models.User) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+		weekStart := time.Now().Add(-report.Week)
+		msg := childSummariesLoadedMsg{
+			week:  make(map[string]*models.ChildSummary, len(users)),
+			total: make(map[string]*models.ChildSummary, len(users)),
+		}
+		for _, user := range users {
+			week, err := m.db.GetChildSummary(ctx, user.ID, weekStart)
+			if err != nil {
+				return childSummariesLoadedMsg{err: err}
+			}
+			total, err := m.db.GetChildSummary(ctx, user.ID, time.Time{})
+			if err != nil {
+				return childSummariesLoadedMsg{err: err}
+			}
+			msg.week[user.ID], msg.total[user.ID] = week, total
+		}
+		return msg
+	}
+}
+
+// exportReport writes the selected child's weekly report to the report
+// directory.
+func (m Model) exportReport(child models.User) tea.Cmd {
+	dir := m.reportDir
+	return func() tea.Msg {
+		ctx := context.Background()
+		now := time.Now()
+		summary, err := m.db.GetChildSummary(ctx, child.ID, now.Add(-report.Week))
+		if err != nil {
+			return reportExportedMsg{err: err}
+		}
+		quizzes, err := m.db.ListQuizAttemptsByUser(ctx, child.ID)
+		if err != nil {
+			return reportExportedMsg{err: err}
+		}
+		flags, err := m.db.ListModerationFlagsByUser(ctx, child.ID, true)
+		if err != nil {
+			return reportExportedMsg{err: err}
+		}
+		words, err := m.db.ListVocabulary(ctx, child.ID, child.Band(now))
+		if err != nil {
+			return reportExportedMsg{err: err}
+		}
+
+		weekly := report.NewWeekly(child, now, *summary, quizzes, flags, words)
+		paths, err := report.Write(dir, weekly)
+		return reportExportedMsg{paths: paths, err: err}
+	}
+}
+
+// updateParent handles parent dashboard messages.
+func (m Model) updateParent(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case childSummariesLoadedMsg:
+		if m.parent == nil {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.statusMessage = fmt.Sprintf("Error loading progress: %v", msg.err)
+			m.logger.Error("failed to load child summaries", "error", msg.err)
+			return m, nil
+		}
+		m.parent.week, m.parent.total = msg.week, msg.total
+
+	case reportExportedMsg:
+		if msg.err != nil {
+			m.statusMessage = fmt.Sprintf("Error exporting report: %v", msg.err)
+			m.logger.Error("failed to export report", "error", msg.err)
+			return m, nil
+		}
+		m.statusMessage = fmt.Sprintf("Report saved to %s", strings.Join(msg.paths, " and "))
+	}
+	return m, nil
+}
+
+// handleParentKeys takes the PIN while the dashboard is locked, then moves
+// between children and exports their reports.
+func (m Model) handleParentKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	p := m.parent
+	if p == nil {
+		return m, nil
+	}
+	if p.editing && p.topics {
+		return m.handleTopicsKeys(msg)
+	}
+	if p.editing {
+		return m.handleLimitsKeys(msg)
+	}
+	if key.Matches(msg, m.keys.Back) {
+		m.closeParent()
+		m.statusMessage = ""
+		return m, nil
+	}
+
+	if !p.unlocked {
+		if !key.Matches(msg, m.keys.Select) {
+			var cmd tea.Cmd
+			m.textInput, cmd = m.textInput.Update(msg)
+			return m, cmd
+		}
+		guardian, err := m.checkPIN(m.unlockers(p.guardian), m.textInput.Value())
+		if err != nil {
+			m.textInput.SetValue("")
+			if m.wrongPIN() {
+				m.closeParent()
+				m.pinLockedOut()
+				return m, nil
+			}
+			m.statusMessage = "That PIN isn't right. Try again:"
+			return m, nil
+		}
+		m.pinAttempts = 0
+		p.unlocked = true
+		p.guardian = guardian
+		p.children = m.children(guardian)
+		m.scope = nil
+		if guardian != nil {
+			m.scope = &householdScope{household: guardian.HouseholdID}
+		}
+		m.textInput.SetValue("")
+		m.textInput.EchoMode = textinput.EchoNormal
+		m.textInput.Blur()
+		m.statusMessage = ""
+		return m, tea.Batch(m.spinner.Tick, m.loadChildSummaries(p.children))
+	}
+
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		if p.index > 0 {
+			p.index--
+		}
+	case key.Matches(msg, m.keys.Down):
+		if p.index < len(p.children)-1 {
+			p.index++
+		}
+	case key.Matches(msg, m.keys.Export):
+		if p.index < len(p.children) {
+			child := p.children[p.index]
+			m.statusMessage = fmt.Sprintf("Exporting %s's weekly report...", child.DisplayName())
+			return m, m.exportReport(child)
+		}
+	case key.Matches(msg, m.keys.Limits):
+		if p.index < len(p.children) {
+			p.editing = true
+			limits := p.children[p.index].ScreenTime()
+			m.textInput.SetValue("")
+			if limits.Limited() {
+				m.textInput.SetValue(limits.String())
+			}
+			m.textInput.CursorEnd()
+			m.textInput.Focus()
+			m.statusMessage = "Minutes a day and allowed hours, such as 45m 07:00-19:30, or none:"
+		}
+	case key.Matches(msg, m.keys.Topics):
+		if p.index < len(p.children) {
+			p.editing, p.topics = true, true
+			prefs := p.children[p.index].ContentPreferences()
+			m.textInput.SetValue("")
+			if !prefs.IsZero() {
+				m.textInput.SetValue(prefs.String())
+			}
+			m.textInput.CursorEnd()
+			m.textInput.Focus()
+			m.statusMessage = "Topics, such as story themes: dinosaurs; avoid: scary monsters; sensitive: loud noises, death, or none:"
+		}
+	}
+	return m, nil
+}
+
+// handleLimitsKeys edits the selected child's screen time.
+func (m Model) handleLimitsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	p := m.parent
+	switch {
+	case key.Matches(msg, m.keys.Back):
+		p.editing = false
+		m.textInput.Blur()
+		m.statusMessage = ""
+	case key.Matches(msg, m.keys.Select):
+		limits, err := models.ParseScreenTime(m.textInput.Value())
+		if err != nil {
+			m.statusMessage = fmt.Sprintf("Invalid screen time: %v", err)
+			return m, nil
+		}
+		p.editing = false
+		m.textInput.Blur()
+		user := p.children[p.index]
+		user.SetScreenTime(limits)
+		m.replaceChild(user)
+		m.statusMessage = fmt.Sprintf("Screen time for %s: %s", user.DisplayName(), screenTimeLine(limits))
+		return m, m.updateUser(user)
+	default:
+		var cmd tea.Cmd
+		m.textInput, cmd = m.textInput.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+// handleTopicsKeys edits the selected child's content preferences.
+func (m Model) handleTopicsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	p := m.parent
+	switch {
+	case key.Matches(msg, m.keys.Back):
+		p.editing, p.topics = false, false
+		m.textInput.Blur()
+		m.statusMessage = ""
+	case key.Matches(msg, m.keys.Select):
+		prefs, err := models.ParseContentPreferences(m.textInput.Value())
+		if err != nil {
+			m.statusMessage = fmt.Sprintf("Invalid topics: %v", err)
+			return m, nil
+		}
+		p.editing, p.topics = false, false
+		m.textInput.Blur()
+		user := p.children[p.index]
+		user.SetContentPreferences(prefs)
+		m.replaceChild(user)
+		m.statusMessage = fmt.Sprintf("Topics for %s: %s", user.DisplayName(), prefs)
+		return m, m.updateUser(user)
+	default:
+		var cmd tea.Cmd
+		m.textInput, cmd = m.textInput.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+// replaceChild puts an edited child back in the dashboard and the user list.
+func (m *Model) replaceChild(user models.User) {
+	m.parent.children[m.parent.index] = user
+	for i := range m.users {
+		if m.users[i].ID == user.ID {
+			m.users[i] = user
+		}
+	}
+}
+
+// errWrongPIN is returned by checkPIN for a PIN that doesn't match.
+var errWrongPIN = errors.New("wrong PIN")
+
+// checkPIN returns the guardian whose PIN was entered. The parent PIN, which
+// stands in for every guardian, is compared in constant time and returns nil.
+func (m Model) checkPIN(guardians []models.User, entered string) (*models.User, error) {
+	for i := range guardians {
+		if guardians[i].CheckPIN(entered) {
+			return &guardians[i], nil
+		}
+	}
+	if m.parentPIN != "" && subtle.ConstantTimeCompare([]byte(entered), []byte(m.parentPIN)) == 1 {
+		return nil, nil
+	}
+	return nil, errWrongPIN
+}
+
+// pinLockedOut reports whether too many wrong PINs have locked every PIN
+// prompt for now, saying so in the status bar.
+func (m *Model) pinLockedOut() bool {
+	wait := time.Until(m.pinLockedUntil)
+	if wait <= 0 {
+		return false
+	}
+	m.statusMessage = fmt.Sprintf("Too many wrong PINs. Try again in %d minutes.", int(wait.Minutes())+1)
+	return true
+}
+
+// wrongPIN counts a wrong PIN at any prompt and reports whether it locked
+// them all. The count is kept on the model, so closing a prompt and opening
+// it again doesn't start over.
+func (m *Model) wrongPIN() bool {
+	m.pinAttempts++
+	if m.pinAttempts < maxPINAttempts {
+		return false
+	}
+	m.pinAttempts = 0
+	m.pinLockedUntil = time.Now().Add(pinLockout)
+	return true
+}
+
+// canUnlock reports whether any of the guardians, or the parent PIN, can
+// approve something.
+func (m Model) canUnlock(guardians []models.User) bool {
+	return len(guardians) > 0 || m.parentPIN != ""
+}
+
+// guardians returns the guardians with a PIN who look after a child: those
+// in the child's household. A nil child returns every guardian with a PIN.
+func (m Model) guardians(child *models.User) []models.User {
+	var guardians []models.User
+	for _, user := range m.users {
+		if user.IsGuardian() && user.HasPIN() && (child == nil || sameHousehold(user.HouseholdID, child.HouseholdID)) {
+			guardians = append(guardians, user)
+		}
+	}
+	return guardians
+}
+
+// unlockers returns whose PIN unlocks the dashboard for a guardian, or for
+// any guardian when guardian is nil.
+func (m Model) unlockers(guardian *models.User) []models.User {
+	if guardian == nil {
+		return m.guardians(nil)
+	}
+	if !guardian.HasPIN() {
+		return nil
+	}
+	return []models.User{*guardian}
+}
+
+// children returns the children a guardian looks after: those in their
+// household. A nil guardian, unlocked with the parent PIN, sees every child.
+func (m Model) children(guardian *models.User) []models.User {
+	var children []models.User
+	for _, user := range m.users {
+		if !user.IsGuardian() && (guardian == nil || sameHousehold(user.HouseholdID, guardian.HouseholdID)) {
+			children = append(children, user)
+		}
+	}
+	return children
+}
+
+// sameHousehold reports whether two household IDs match. Users without a
+// household share the same, unnamed one.
+func sameHousehold(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// needsGuardian reports whether a story list key is for a guardian-only
+// action that a guardian hasn't approved yet: deleting a story, changing
+// what steers how it is written (its summary, art style and prompt
+// template), or changing the child's own settings (their theme). Key
+// bindings are only set from a file, outside the primer. Without any PIN to
+// ask for, every action is open, as it was before households.
+func (m Model) needsGuardian(msg tea.KeyMsg) bool {
+	if m.guardianApproved || m.currentUser == nil {
+		return false
+	}
+	switch {
+	case key.Matches(msg, m.keys.Theme):
+	case key.Matches(msg, m.keys.Delete, m.keys.EditSummary, m.keys.ArtStyle, m.keys.PromptTemplate):
+		if _, ok := m.selectedStory(); !ok {
+			return false
+		}
+	default:
+		return false
+	}
+	return m.canUnlock(m.guardians(m.currentUser))
+}
+
+// renderParent shows the PIN prompt, or each child's progress this week and
+// over all time.
+func renderParent(m Model) string {
+	var b strings.Builder
+	b.WriteString(m.styles.Header.Render("Parent Dashboard"))
+	b.WriteString("\n\n")
+
+	p := m.parent
+	switch {
+	case p == nil:
+	case !p.unlocked:
+		b.WriteString(m.styles.Normal.Render("This area is for grown-ups."))
+		b.WriteString("\n\n")
+		b.WriteString(m.textInput.View())
+		b.WriteString("\n")
+
+	case p.week == nil:
+		b.WriteString(m.styles.Spinner.Render(m.spinner.View()))
+		b.WriteString(m.styles.Normal.Render(" Adding up progress..."))
+		b.WriteString("\n")
+
+	default:
+		if len(p.children) == 0 {
+			b.WriteString(m.styles.Normal.Render("No children in this household yet."))
+			b.WriteString("\n")
+		}
+		for i, user := range p.children {
+			line := fmt.Sprintf("%s - %s", user.DisplayName(), weekLine(p.week[user.ID]))
+			if i == p.index {
+				b.WriteString(m.styles.Selected.Render("▸ " + line))
+			} else {
+				b.WriteString(m.styles.Normal.Render("  " + line))
+			}
+			b.WriteString("\n")
+		}
+		if p.index < len(p.children) {
+			user := p.children[p.index]
+			b.WriteString("\n")
+			b.WriteString(renderChildSummary(m, p.week[user.ID], p.total[user.ID]))
+			b.WriteString(m.styles.Normal.Render("Screen time: " + screenTimeLine(user.ScreenTime())))
+			b.WriteString("\n")
+			b.WriteString(m.styles.Normal.Render("Topics: " + user.ContentPreferences().String()))
+			b.WriteString("\n")
+			if p.editing {
+				b.WriteString(m.textInput.View())
+				b.WriteString("\n")
+			}
+		}
+	}
+
+	b.WriteString("\n")
+	b.WriteString(renderHelp(m))
+	return b.String()
+}
+
+// weekLine sums up a child's week in a few words for the list of children.
+func weekLine(week *models.ChildSummary) string {
+	if week == nil || week.Pages == 0 {
+		return "no stories this week"
+	}
+	line := fmt.Sprintf("%d %s this week", week.Pages, plural(week.Pages, "page", "pages"))
+	if week.UnreviewedFlags > 0 {
+		line += fmt.Sprintf(", %d flagged", week.UnreviewedFlags)
+	}
+	return line
+}
+
+// renderChildSummary lays out a child's progress this week next to all time.
+func renderChildSummary(m Model, week, total *models.ChildSummary) string {
+	if week == nil || total == nil {
+		return ""
+	}
+
+	rows := []struct {
+		label       string
+		week, total string
+	}{
+		{"Stories started", fmt.Sprint(week.Stories), fmt.Sprint(total.Stories)},
+		{"Pages written", fmt.Sprint(week.Pages), fmt.Sprint(total.Pages)},
+		{"Time spent", report.FormatDuration(week.TimeSpent()), report.FormatDuration(total.TimeSpent())},
+		{"Quizzes", quizCell(week), quizCell(total)},
+		{"New words", fmt.Sprint(week.NewWords), fmt.Sprint(total.NewWords)},
+		{"Words practised", fmt.Sprint(week.WordsReviewed), fmt.Sprint(total.WordsReviewed)},
+		{"Reading level", gradeCell(week.AverageGrade), gradeCell(total.AverageGrade)},
+		{"Flagged", flagCell(week), flagCell(total)},
+		{"Tokens used", tokenCount(week.Tokens()), tokenCount(total.Tokens())},
+	}
+
+	var b strings.Builder
+	b.WriteString(m.styles.PageNum.Render(fmt.Sprintf("%-18s %-14s %s", "", "This week", "All time")))
+	b.WriteString("\n")
+	for _, row := range rows {
+		b.WriteString(m.styles.Normal.Render(fmt.Sprintf("%-18s %-14s %s", row.label, row.week, row.total)))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	if len(week.Objectives) == 0 {
+		b.WriteString(m.styles.Normal.Render("No learning objectives this week."))
+	} else {
+		covered := make([]string, len(week.Objectives))
+		for i, objective := range week.Objectives {
+			covered[i] = fmt.Sprintf("%s (%d %s)", objective.Title, objective.Pages, plural(objective.Pages, "page", "pages"))
+		}
+		b.WriteString(m.styles.Normal.Render(wrapText("Objectives this week: "+strings.Join(covered, ", "), m.width-10)))
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// quizCell shows the number of quizzes and the share answered correctly.
+func quizCell(s *models.ChildSummary) string {
+	if s.Quizzes == 0 {
+		return "none"
+	}
+	return fmt.Sprintf("%d, %d%% right", s.Quizzes, s.QuizPercent())
+}
+
+// gradeCell shows an average reading grade level.
+func gradeCell(grade *float64) string {
+	if grade == nil {
+		return "-"
+	}
+	return fmt.Sprintf("grade %.1f", *grade)
+}
+
+// flagCell shows how many moderation flags there were and how many are new.
+func flagCell(s *models.ChildSummary) string {
+	if s.UnreviewedFlags == 0 {
+		return fmt.Sprint(s.Flags)
+	}
+	return fmt.Sprintf("%d (%d new)", s.Flags, s.UnreviewedFlags)
+}
+
+// tokenCount shows a token count in thousands once it is large.
+func tokenCount(n int64) string {
+	if n < 1000 {
+		return fmt.Sprint(n)
+	}
+	return fmt.Sprintf("%.1fk", float64(n)/1000)
+}
+
+// plural picks the singular or plural form of a word for n.
+func plural(n int64, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
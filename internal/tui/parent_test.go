@@ -0,0 +1,239 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kbrakke/illustrated-primer/internal/models"
+	"github.com/kbrakke/illustrated-primer/testutil"
+)
+
+// testChildren returns the children to choose from: "ada", named Ada, and
+// "ben".
+func testChildren() []models.User {
+	name := "Ada"
+	return []models.User{{ID: "ada", Name: &name}, {ID: "ben"}}
+}
+
+func typePIN(m Model, pin string) (Model, tea.Cmd) {
+	m, _ = press(m, pin)
+	return enter(m)
+}
+
+func TestParentWithoutPIN(t *testing.T) {
+	m := newTestModel(testutil.NewMockAIClient(""), withUsers(testChildren()...))
+	m, _ = press(m, "P")
+	if m.mode != ModeUserSelection || m.parent != nil {
+		t.Fatalf("mode = %v, want the dashboard to stay closed without a PIN", m.mode)
+	}
+	if !strings.Contains(m.statusMessage, "PRIMER_PARENT_PIN") {
+		t.Errorf("status = %q", m.statusMessage)
+	}
+}
+
+func TestParentPIN(t *testing.T) {
+	m := newTestModel(testutil.NewMockAIClient(""), WithParentPIN("2468"), withUsers(testChildren()...))
+	m, _ = press(m, "P")
+	if m.mode != ModeParent || m.parent == nil || m.parent.unlocked {
+		t.Fatalf("mode = %v, want the locked dashboard", m.mode)
+	}
+
+	// Keys go to the PIN, not to quit or help.
+	m, cmd := typePIN(m, "q?")
+	if m.mode != ModeParent || m.pinAttempts != 1 || cmd != nil {
+		t.Fatalf("a wrong PIN should be counted; attempts %d", m.pinAttempts)
+	}
+	if view := renderParent(m); strings.Contains(view, "Ada") {
+		t.Errorf("children shown before the PIN:\n%s", view)
+	}
+
+	m, cmd = typePIN(m, "2468")
+	if !m.parent.unlocked || cmd == nil {
+		t.Fatal("the right PIN should unlock the dashboard and load progress")
+	}
+	if m.textInput.Value() != "" || m.textInput.Focused() {
+		t.Error("the PIN should be cleared once unlocked")
+	}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	if m.mode != ModeUserSelection || m.parent != nil {
+		t.Errorf("mode = %v, want back to the user selection", m.mode)
+	}
+}
+
+func TestParentTooManyPINs(t *testing.T) {
+	m := newTestModel(testutil.NewMockAIClient(""), WithParentPIN("2468"), withUsers(testChildren()...))
+	m, _ = press(m, "P")
+	for i := 0; i < maxPINAttempts; i++ {
+		m, _ = typePIN(m, "1111")
+	}
+	if m.mode != ModeUserSelection || m.parent != nil {
+		t.Fatalf("mode = %v, want the dashboard closed", m.mode)
+	}
+	if m.statusMessage != "Too many wrong PINs. Try again in 5 minutes." {
+		t.Errorf("status = %q", m.statusMessage)
+	}
+
+	// Reopening the dashboard doesn't start the count over.
+	m, _ = press(m, "P")
+	if m.mode != ModeUserSelection || m.parent != nil {
+		t.Fatalf("mode = %v, want the dashboard to stay locked", m.mode)
+	}
+	m.pinLockedUntil = time.Now().Add(-time.Second)
+	m, _ = press(m, "P")
+	m, _ = typePIN(m, "2468")
+	if m.parent == nil || !m.parent.unlocked {
+		t.Error("the dashboard should open again once the lockout is over")
+	}
+}
+
+func TestRenderParent(t *testing.T) {
+	m := newTestModel(testutil.NewMockAIClient(""), WithParentPIN("2468"), withUsers(testChildren()...))
+	m, _ = press(m, "P")
+	m, _ = typePIN(m, "2468")
+	if view := renderParent(m); !strings.Contains(view, "Adding up progress") {
+		t.Errorf("loading not shown:\n%s", view)
+	}
+
+	grade := 2.4
+	week := map[string]*models.ChildSummary{
+		"ada": {UserID: "ada", Stories: 1, Pages: 5, ActiveSeconds: 1500, Quizzes: 2, QuizCorrect: 3, QuizAnswered: 4,
+			Flags: 2, UnreviewedFlags: 1, AverageGrade: &grade, InputTokens: 1200, OutputTokens: 300,
+			Objectives: []models.ObjectiveProgress{{Objective: models.Objective{Title: "Counting to ten"}, Pages: 3}}},
+		"ben": {UserID: "ben"},
+	}
+	total := map[string]*models.ChildSummary{
+		"ada": {UserID: "ada", Stories: 4, Pages: 21, ActiveSeconds: 3900},
+		"ben": {UserID: "ben"},
+	}
+	updated, _ := m.Update(childSummariesLoadedMsg{week: week, total: total})
+	m = updated.(Model)
+
+	view := renderParent(m)
+	for _, want := range []string{
+		"Ada - 5 pages this week, 1 flagged", "no stories this week",
+		"25m", "1h 05m", "2, 75% right", "grade 2.4", "2 (1 new)", "1.5k", "Counting to ten (3 pages)",
+	} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q:\n%s", want, view)
+		}
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m = updated.(Model)
+	if m.parent.index != 1 {
+		t.Fatalf("index = %d, want the second child", m.parent.index)
+	}
+	if view := renderParent(m); !strings.Contains(view, "No learning objectives this week.") {
+		t.Errorf("second child's summary not shown:\n%s", view)
+	}
+	m, cmd := press(m, "e")
+	if cmd == nil || !strings.Contains(m.statusMessage, "Exporting") {
+		t.Errorf("export not started; status %q", m.statusMessage)
+	}
+}
+
+func TestParentReportExported(t *testing.T) {
+	m := newTestModel(testutil.NewMockAIClient(""), withUsers(testChildren()...))
+	updated, _ := m.Update(reportExportedMsg{paths: []string{"reports/ada-2026-10-19.md", "reports/ada-2026-10-19.html"}})
+	m = updated.(Model)
+	if m.statusMessage != "Report saved to reports/ada-2026-10-19.md and reports/ada-2026-10-19.html" {
+		t.Errorf("status = %q", m.statusMessage)
+	}
+
+	updated, _ = m.Update(reportExportedMsg{err: errors.New("disk full")})
+	m = updated.(Model)
+	if !strings.Contains(m.statusMessage, "disk full") {
+		t.Errorf("status = %q", m.statusMessage)
+	}
+}
+
+func TestParentScreenTime(t *testing.T) {
+	m := newTestModel(testutil.NewMockAIClient(""), WithParentPIN("2468"), withUsers(testChildren()...))
+	m, _ = press(m, "P")
+	m, _ = typePIN(m, "2468")
+	updated, _ := m.Update(childSummariesLoadedMsg{week: map[string]*models.ChildSummary{}, total: map[string]*models.ChildSummary{}})
+	m = updated.(Model)
+	if view := renderParent(m); !strings.Contains(view, "Screen time: no limits") {
+		t.Errorf("limits not shown:\n%s", view)
+	}
+
+	m, _ = press(m, "l")
+	if !m.parent.editing || !m.textInput.Focused() {
+		t.Fatal("l should edit the child's screen time")
+	}
+	m, _ = press(m, "lots")
+	m, _ = enter(m)
+	if !m.parent.editing || !strings.Contains(m.statusMessage, "Invalid screen time") {
+		t.Fatalf("invalid limits should be refused; status %q", m.statusMessage)
+	}
+
+	m.textInput.SetValue("45m 07:00-19:30")
+	m, cmd := enter(m)
+	if m.parent.editing || cmd == nil {
+		t.Fatal("valid limits should be saved")
+	}
+	if got := m.users[0].ScreenTime(); got != (models.ScreenTime{DailyMinutes: 45, From: "07:00", Until: "19:30"}) {
+		t.Errorf("ScreenTime() = %+v", got)
+	}
+	if view := renderParent(m); !strings.Contains(view, "Screen time: 45 minutes a day, between 07:00 and 19:30") {
+		t.Errorf("new limits not shown:\n%s", view)
+	}
+
+	m, _ = press(m, "l")
+	if m.textInput.Value() != "45m 07:00-19:30" {
+		t.Errorf("input = %q, want the current limits", m.textInput.Value())
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	if m.parent == nil || m.parent.editing {
+		t.Error("esc should only stop editing")
+	}
+}
+
+func TestParentTopics(t *testing.T) {
+	m := newTestModel(testutil.NewMockAIClient(""), WithParentPIN("2468"), withUsers(testChildren()...))
+	m, _ = press(m, "P")
+	m, _ = typePIN(m, "2468")
+	updated, _ := m.Update(childSummariesLoadedMsg{week: map[string]*models.ChildSummary{}, total: map[string]*models.ChildSummary{}})
+	m = updated.(Model)
+	if view := renderParent(m); !strings.Contains(view, "Topics: none") {
+		t.Errorf("topics not shown:\n%s", view)
+	}
+
+	m, _ = press(m, "t")
+	if !m.parent.editing || !m.parent.topics || !m.textInput.Focused() {
+		t.Fatal("t should edit the child's topics")
+	}
+	m, _ = press(m, "dinosaurs")
+	m, _ = enter(m)
+	if !m.parent.editing || !strings.Contains(m.statusMessage, "Invalid topics") {
+		t.Fatalf("topics without a label should be refused; status %q", m.statusMessage)
+	}
+
+	m.textInput.SetValue("story themes: dinosaurs; sensitive: loud noises")
+	m, cmd := enter(m)
+	if m.parent.editing || m.parent.topics || cmd == nil {
+		t.Fatal("valid topics should be saved")
+	}
+	if got := m.users[0].ContentPreferences(); got.String() != "story themes: dinosaurs; sensitive: loud noises" {
+		t.Errorf("ContentPreferences() = %s", got)
+	}
+	if view := renderParent(m); !strings.Contains(view, "Topics: story themes: dinosaurs; sensitive: loud noises") {
+		t.Errorf("new topics not shown:\n%s", view)
+	}
+
+	m, _ = press(m, "t")
+	if m.textInput.Value() != "story themes: dinosaurs; sensitive: loud noises" {
+		t.Errorf("input = %q, want the current topics", m.textInput.Value())
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	if m.parent == nil || m.parent.editing || m.parent.topics {
+		t.Error("esc should only stop editing")
+	}
+}
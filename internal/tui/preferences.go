@@ -0,0 +1,75 @@
+package tui
+
+import (
+	"context"
+	"strings"
+
+	"github.com/kbrakke/illustrated-primer/internal/ai"
+)
+
+// maxRegenerations is how many more times a page that breaks the child's
+// content preferences is written before it is dropped.
+const maxRegenerations = 2
+
+// restricted reports whether the current child has topics to keep out of
+// their stories.
+func (m Model) restricted() bool {
+	return m.currentUser != nil && m.currentUser.ContentPreferences().Restricted()
+}
+
+// preferenceGuard returns a guard for the current child's banned topics and
+// sensitivities, or nil if they have none.
+func (m Model) preferenceGuard() *ai.Guard {
+	if m.currentUser == nil {
+		return nil
+	}
+	guard, err := ai.NewPreferenceGuard(m.currentUser.ContentPreferences())
+	if err != nil {
+		m.logger.Error("failed to compile content preferences", "error", err)
+		return nil
+	}
+	return guard
+}
+
+// respectPreferences checks a page and its choices against the child's
+// content preferences, having the model write it again, up to
+// maxRegenerations times, while it breaks them. It returns the page to use
+// and the verdict on the page as first written: ActionAllow if it passed,
+// ActionRewrite with the new text if a later attempt passed, or ActionBlock
+// if none did. The verdict's findings cover every attempt.
+func (m Model) respectPreferences(ctx context.Context, guard *ai.Guard, prompt string, page ai.StoryPage) (ai.StoryPage, ai.Verdict, error) {
+	verdict, err := guard.Check(ctx, ai.SourceCompletion, pageContent(page))
+	if err != nil || verdict.Action == ai.ActionAllow {
+		return page, verdict, err
+	}
+	verdict.Text = page.Text
+
+	// Each attempt is asked for the same way as the first.
+	opts, _ := m.storyOptions()
+
+	for i := 0; i < maxRegenerations; i++ {
+		text, tokens, err := ai.RegeneratePage(ctx, m.aiClient, prompt, m.conversationHistory, verdict, opts)
+		m.usage.Add(tokens)
+		if err != nil {
+			return page, ai.Verdict{}, err
+		}
+		page = m.readResponse(text)
+
+		again, err := guard.Check(ctx, ai.SourceCompletion, pageContent(page))
+		if err != nil {
+			return page, ai.Verdict{}, err
+		}
+		if again.Action == ai.ActionAllow {
+			verdict.Action, verdict.Text = ai.ActionRewrite, page.Text
+			return page, verdict, nil
+		}
+		verdict.Findings = append(verdict.Findings, again.Findings...)
+	}
+	return page, verdict, nil
+}
+
+// pageContent returns the text of a page and its choices, for checking
+// them together.
+func pageContent(page ai.StoryPage) string {
+	return strings.Join(append([]string{page.Text}, page.Choices...), "\n")
+}
@@ -0,0 +1,98 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kbrakke/illustrated-primer/internal/ai"
+	"github.com/kbrakke/illustrated-primer/internal/models"
+	"github.com/kbrakke/illustrated-primer/testutil"
+)
+
+// noMonsters are the preferences of a child who loves dinosaurs and
+// mustn't hear about scary monsters.
+var noMonsters = models.ContentPreferences{
+	StoryThemes: []string{"dinosaurs"},
+	Banned:      []string{"scary monsters"},
+}
+
+func TestPreferencesRegeneratePage(t *testing.T) {
+	child := models.User{ID: "user"}
+	child.SetContentPreferences(noMonsters)
+	client := testutil.NewMockAIClient("Then a scary monster crept in.")
+	m := newStoryTestModel(client, ModeChat, withUser(child))
+
+	m, msg := send(t, m, "what happens next?")
+	m, cmd := stream(t, m, msg)
+	if m.statusMessage != "Checking the story..." {
+		t.Errorf("status = %q while checking", m.statusMessage)
+	}
+	if sent := client.Calls[0].Message; sent != "what happens next?" {
+		t.Errorf("sent %q", sent)
+	}
+
+	client.Response = "Then a friendly dinosaur stomped in."
+	moderated, ok := cmd().(completionModeratedMsg)
+	if !ok {
+		t.Fatal("the page should be checked against the child's preferences")
+	}
+	if moderated.preferences.Action != ai.ActionRewrite || moderated.page.Text != "Then a friendly dinosaur stomped in." {
+		t.Fatalf("preferences = %s, page %q; want the page written again", moderated.preferences.Action, moderated.page.Text)
+	}
+	if client.CallCount() != 2 || !strings.Contains(client.LastCall().Message, `without "scary monster"`) {
+		t.Errorf("calls = %+v, want one regeneration naming what to leave out", client.Calls)
+	}
+
+	updated, _ := m.Update(moderated)
+	m = updated.(Model)
+	if m.isLoading || len(m.conversationHistory) != 2 || m.conversationHistory[1] != "Then a friendly dinosaur stomped in." {
+		t.Errorf("history = %q, want the rewritten page", m.conversationHistory)
+	}
+}
+
+func TestPreferencesBlockPage(t *testing.T) {
+	child := models.User{ID: "user"}
+	child.SetContentPreferences(noMonsters)
+	client := testutil.NewMockAIClient("Scary monsters everywhere!")
+	m := newStoryTestModel(client, ModeChat, withUser(child))
+
+	m, msg := send(t, m, "what happens next?")
+	m, cmd := stream(t, m, msg)
+	moderated := cmd().(completionModeratedMsg)
+	if moderated.preferences.Action != ai.ActionBlock {
+		t.Fatalf("preferences = %s, want the page blocked", moderated.preferences.Action)
+	}
+	if client.CallCount() != 1+maxRegenerations {
+		t.Errorf("calls = %d, want %d", client.CallCount(), 1+maxRegenerations)
+	}
+
+	updated, record := m.Update(moderated)
+	m = updated.(Model)
+	if m.isLoading || len(m.conversationHistory) != 0 || record == nil {
+		t.Error("a page that keeps breaking the preferences must be dropped and recorded")
+	}
+	if !strings.Contains(m.statusMessage, "wandered") {
+		t.Errorf("status = %q", m.statusMessage)
+	}
+}
+
+func TestPreferencesWithoutRestrictions(t *testing.T) {
+	child := models.User{ID: "user"}
+	child.SetContentPreferences(models.ContentPreferences{StoryThemes: noMonsters.StoryThemes})
+	m := newStoryTestModel(testutil.NewMockAIClient("A scary monster."), ModeChat, withUser(child))
+
+	m, msg := send(t, m, "what happens next?")
+	updated, _ := m.Update(msg)
+	m = updated.(Model)
+	for {
+		msg := waitForChunk(m.stream)()
+		updated, _ := m.Update(msg)
+		m = updated.(Model)
+		if _, done := msg.(aiDoneMsg); done {
+			break
+		}
+	}
+	if len(m.conversationHistory) != 2 {
+		t.Errorf("history = %q, want themes alone not to hold the page back", m.conversationHistory)
+	}
+}
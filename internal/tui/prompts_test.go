@@ -0,0 +1,66 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kbrakke/illustrated-primer/internal/ai"
+	"github.com/kbrakke/illustrated-primer/internal/models"
+	"github.com/kbrakke/illustrated-primer/testutil"
+)
+
+func TestPromptTemplateRecordedOnResponse(t *testing.T) {
+	registry := ai.NewRegistry()
+	if err := registry.Add("playful", 1, "Be playful with {{.Name}}."); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		pinned string
+		want   string
+	}{
+		{"", ai.DefaultTemplate},
+		{"playful", "playful@1"},
+		{"playful@1", "playful@1"},
+		{"serious@3", ai.DefaultTemplate}, // no longer available
+	}
+	for _, tt := range tests {
+		m := newStoryTestModel(testutil.NewMockAIClient("Once upon a time..."), ModeChat, WithPromptTemplates(registry))
+		m.currentStory.PromptTemplate = tt.pinned
+		m, msg := send(t, m, "tell me a story")
+
+		started, ok := msg.(aiStreamStartedMsg)
+		if !ok {
+			t.Fatalf("got %T, want aiStreamStartedMsg", msg)
+		}
+		if started.template != tt.want {
+			t.Errorf("pinned %q: template = %q, want %q", tt.pinned, started.template, tt.want)
+		}
+		updated, _ := m.Update(msg)
+		if got := updated.(Model).responseTemplate; got != tt.want {
+			t.Errorf("pinned %q: responseTemplate = %q, want %q", tt.pinned, got, tt.want)
+		}
+	}
+}
+
+func TestPinPromptTemplate(t *testing.T) {
+	registry := ai.NewRegistry()
+	if err := registry.Add("playful", 1, "Be playful with {{.Name}}."); err != nil {
+		t.Fatal(err)
+	}
+	m := newStoryTestModel(testutil.NewMockAIClient("Once upon a time..."), ModeStoryList, WithPromptTemplates(registry))
+	m.stories = []models.Story{*m.currentStory}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("v")})
+	m = updated.(Model)
+	if m.storyInput != storyInputPromptTemplate {
+		t.Fatalf("storyInput = %v, want the prompt template input", m.storyInput)
+	}
+
+	m.textInput.SetValue("serious@3")
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if cmd != nil || m.statusMessage == "" {
+		t.Errorf("an unknown template should be refused, status %q", m.statusMessage)
+	}
+}
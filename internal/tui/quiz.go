@@ -0,0 +1,278 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kbrakke/illustrated-primer/internal/ai"
+	"github.com/kbrakke/illustrated-primer/internal/models"
+)
+
+// quizState is a comprehension quiz in progress. The quiz is finished when
+// index reaches the number of questions.
+type quizState struct {
+	questions []ai.QuizQuestion
+	index     int  // question being asked
+	cursor    int  // highlighted option
+	answered  bool // feedback on the current question is showing
+	attempt   *models.QuizAttempt
+}
+
+// question returns the question being asked.
+func (q *quizState) question() ai.QuizQuestion {
+	return q.questions[q.index]
+}
+
+// finished reports whether every question has been answered.
+func (q *quizState) finished() bool {
+	return q.index >= len(q.questions)
+}
+
+type quizGeneratedMsg struct {
+	id      int
+	quiz    ai.Quiz
+	text    string     // the questions and options, as moderated
+	verdict ai.Verdict // zero without moderation
+	err     error
+}
+
+type quizSavedMsg struct {
+	err error
+}
+
+// startQuiz switches to the quiz and generates questions about the latest
+// pages in the background.
+func (m *Model) startQuiz() tea.Cmd {
+	if len(m.pages) == 0 {
+		m.statusMessage = fmt.Sprintf("Write some of the story first (%s), then try a quiz.", m.keys.StartChat.Help().Key)
+		return nil
+	}
+
+	m.stopReading()
+	m.mode = ModeQuiz
+	m.quiz = nil
+	m.quizID++
+	m.statusMessage = "Thinking up some questions..."
+
+	id, client, guard, pages := m.quizID, m.aiClient, m.guard, m.pages
+	pc := ai.NewPromptContext(m.currentUser, m.currentStory, time.Now())
+	return tea.Batch(m.spinner.Tick, func() tea.Msg {
+		ctx := context.Background()
+		quiz, tokens, err := ai.GenerateQuiz(ctx, client, pc, pages)
+		m.recordUsage(ctx, models.UsageQuiz, tokens)
+		if err != nil {
+			return quizGeneratedMsg{id: id, err: err}
+		}
+
+		msg := quizGeneratedMsg{id: id, quiz: quiz, text: quizText(quiz)}
+		if guard != nil {
+			msg.verdict, msg.err = guard.Check(ctx, ai.SourceCompletion, msg.text)
+		}
+		return msg
+	})
+}
+
+// quizText joins a quiz's questions and options for moderation.
+func quizText(quiz ai.Quiz) string {
+	var b strings.Builder
+	for _, q := range quiz.Questions {
+		b.WriteString(q.Question)
+		b.WriteString("\n")
+		for _, option := range q.Options {
+			b.WriteString(option)
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// saveQuizAttempt records a finished attempt.
+func (m Model) saveQuizAttempt(attempt *models.QuizAttempt) tea.Cmd {
+	return func() tea.Msg {
+		return quizSavedMsg{err: m.db.CreateQuizAttempt(context.Background(), attempt)}
+	}
+}
+
+// updateQuiz handles quiz messages.
+func (m Model) updateQuiz(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case quizGeneratedMsg:
+		if msg.id != m.quizID || m.mode != ModeQuiz {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.mode = ModeStoryView
+			m.statusMessage = fmt.Sprintf("Couldn't make a quiz: %v", msg.err)
+			m.logger.Error("failed to generate quiz", "error", msg.err)
+			return m, nil
+		}
+
+		// Quizzes aren't rewritten, only dropped.
+		verdict := msg.verdict
+		if verdict.Action == ai.ActionRewrite {
+			verdict.Action = ai.ActionBlock
+		}
+		record := m.recordModeration(ai.SourceCompletion, msg.text, verdict)
+		if verdict.Action == ai.ActionBlock {
+			m.mode = ModeStoryView
+			m.statusMessage = "The questions wandered somewhere they shouldn't. Let's try again later!"
+			return m, record
+		}
+
+		m.quiz = &quizState{
+			questions: msg.quiz.Questions,
+			attempt:   models.NewQuizAttempt(m.currentUser.ID, m.currentStory.ID),
+		}
+		m.statusMessage = ""
+		return m, record
+
+	case quizSavedMsg:
+		if msg.err != nil {
+			m.statusMessage = fmt.Sprintf("Error saving quiz: %v", msg.err)
+			m.logger.Error("failed to save quiz attempt", "error", msg.err)
+		}
+	}
+	return m, nil
+}
+
+// handleQuizKeys moves through the quiz: choose an option, see whether it
+// was right, then go on to the next question.
+func (m Model) handleQuizKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if key.Matches(msg, m.keys.Back) {
+		if m.quiz != nil && !m.quiz.finished() {
+			m.statusMessage = "Quiz stopped"
+		}
+		m.mode = ModeStoryView
+		m.quiz = nil
+		m.quizID++
+		return m, nil
+	}
+	if m.quiz == nil {
+		return m, nil
+	}
+
+	q := m.quiz
+	if q.finished() {
+		if key.Matches(msg, m.keys.Select) {
+			m.mode = ModeStoryView
+			m.quiz = nil
+		}
+		return m, nil
+	}
+
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		if !q.answered && q.cursor > 0 {
+			q.cursor--
+		}
+	case key.Matches(msg, m.keys.Down):
+		if !q.answered && q.cursor < len(q.question().Options)-1 {
+			q.cursor++
+		}
+	case key.Matches(msg, m.keys.Select):
+		if !q.answered {
+			question := q.question()
+			q.attempt.AddAnswer(models.QuizAnswer{
+				Question: question.Question,
+				Kind:     string(question.Kind),
+				Options:  question.Options,
+				Answer:   question.Answer,
+				Chosen:   q.cursor,
+			})
+			q.answered = true
+			return m, nil
+		}
+
+		q.index++
+		q.cursor = 0
+		q.answered = false
+		if q.finished() {
+			m.statusMessage = fmt.Sprintf("Quiz finished: %d of %d right", q.attempt.Score, q.attempt.Total)
+			return m, m.saveQuizAttempt(q.attempt)
+		}
+	}
+	return m, nil
+}
+
+// renderQuiz shows the current question with feedback once it is answered,
+// and the score at the end.
+func renderQuiz(m Model) string {
+	var b strings.Builder
+
+	title := ""
+	if m.currentStory != nil {
+		title = m.currentStory.Title
+	}
+	b.WriteString(m.styles.Header.Render(fmt.Sprintf("Quiz: %s", title)))
+	b.WriteString("\n\n")
+
+	q := m.quiz
+	switch {
+	case q == nil:
+		b.WriteString(m.styles.Spinner.Render(m.spinner.View()))
+		b.WriteString(m.styles.Normal.Render(" Thinking up some questions..."))
+		b.WriteString("\n")
+
+	case q.finished():
+		b.WriteString(m.styles.Highlight.Render(fmt.Sprintf("You got %d of %d right!", q.attempt.Score, q.attempt.Total)))
+		b.WriteString("\n")
+		b.WriteString(m.styles.Normal.Render(quizCheer(q.attempt)))
+		b.WriteString("\n")
+
+	default:
+		question := q.question()
+		b.WriteString(m.styles.PageNum.Render(fmt.Sprintf("Question %d of %d", q.index+1, len(q.questions))))
+		b.WriteString("\n\n")
+		b.WriteString(m.styles.Normal.Render(wrapText(question.Question, m.width-10)))
+		b.WriteString("\n\n")
+
+		for i, option := range question.Options {
+			if question.Kind == ai.QuestionPicture {
+				option = " " + option + " "
+			}
+			mark := "  "
+			if q.answered && i == question.Answer {
+				mark = "✓ "
+			} else if q.answered && i == q.cursor {
+				mark = "✗ "
+			}
+			switch {
+			case i == q.cursor:
+				b.WriteString(m.styles.Selected.Render("▸ " + mark + option))
+			default:
+				b.WriteString(m.styles.Normal.Render("  " + mark + option))
+			}
+			b.WriteString("\n")
+		}
+
+		if q.answered {
+			b.WriteString("\n")
+			if q.cursor == question.Answer {
+				b.WriteString(m.styles.Highlight.Render("That's right!"))
+			} else {
+				b.WriteString(m.styles.Normal.Render(fmt.Sprintf("Not quite. It was %s.", question.Options[question.Answer])))
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	b.WriteString("\n")
+	b.WriteString(renderHelp(m))
+	return b.String()
+}
+
+// quizCheer is the encouragement shown with the final score.
+func quizCheer(attempt *models.QuizAttempt) string {
+	switch percent := attempt.Percent(); {
+	case percent == 100:
+		return "Every single one. You were really listening!"
+	case percent >= 50:
+		return "Great remembering! Let's keep the story going."
+	default:
+		return "Good try! Stories are even more fun the second time."
+	}
+}
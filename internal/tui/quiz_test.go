@@ -0,0 +1,128 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kbrakke/illustrated-primer/internal/models"
+	"github.com/kbrakke/illustrated-primer/testutil"
+)
+
+const testQuiz = `{"questions":[
+	{"question":"Who found the door?","kind":"text","options":["The fox","The owl","The bear"],"answer":0},
+	{"question":"Which animal knocked?","kind":"picture","options":["🦊","🦉"],"answer":1}
+]}`
+
+func enter(m Model) (Model, tea.Cmd) {
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	return updated.(Model), cmd
+}
+
+func TestQuiz(t *testing.T) {
+	m := newStoryTestModel(testutil.NewMockAIClient(testQuiz), ModeStoryView, withUser(models.User{ID: "user"}), withPages("The fox found a door. The owl knocked."))
+
+	m, cmd := press(m, "z")
+	if m.mode != ModeQuiz || m.quiz != nil {
+		t.Fatalf("mode = %v, want the quiz while questions are written", m.mode)
+	}
+	if view := renderQuiz(m); !strings.Contains(view, "Thinking up some questions") {
+		t.Errorf("no progress shown while generating:\n%s", view)
+	}
+	updated, _ := m.Update(findMsg[quizGeneratedMsg](t, cmd))
+	m = updated.(Model)
+	if m.quiz == nil || len(m.quiz.questions) != 2 {
+		t.Fatalf("quiz = %+v", m.quiz)
+	}
+
+	// A wrong answer to the first question shows the right one.
+	m, _ = press(m, "j")
+	m, _ = enter(m)
+	view := renderQuiz(m)
+	for _, want := range []string{"Question 1 of 2", "✓ The fox", "✗ The owl", "Not quite. It was The fox."} {
+		if !strings.Contains(view, want) {
+			t.Errorf("feedback missing %q:\n%s", want, view)
+		}
+	}
+	m, _ = press(m, "j") // the answer is locked in
+	if m.quiz.cursor != 1 {
+		t.Errorf("cursor moved after answering: %d", m.quiz.cursor)
+	}
+
+	// The second question is a picture question, answered correctly.
+	m, _ = enter(m)
+	m, _ = press(m, "j")
+	m, _ = enter(m)
+	if view := renderQuiz(m); !strings.Contains(view, "That's right!") {
+		t.Errorf("no praise for a right answer:\n%s", view)
+	}
+
+	m, cmd = enter(m)
+	if cmd == nil {
+		t.Fatal("a finished quiz should be saved")
+	}
+	attempt := m.quiz.attempt
+	if attempt.Score != 1 || attempt.Total != 2 || attempt.Answers[1].Kind != "picture" || *attempt.StoryID != "story" {
+		t.Errorf("attempt = %+v", attempt)
+	}
+	if view := renderQuiz(m); !strings.Contains(view, "You got 1 of 2 right!") {
+		t.Errorf("score not shown:\n%s", view)
+	}
+
+	m, _ = enter(m)
+	if m.mode != ModeStoryView || m.quiz != nil {
+		t.Errorf("mode = %v, want the story view after the score", m.mode)
+	}
+}
+
+func TestQuizStopped(t *testing.T) {
+	m := newStoryTestModel(testutil.NewMockAIClient(testQuiz), ModeStoryView, withUser(models.User{ID: "user"}), withPages("The fox found a door. The owl knocked."))
+	m, cmd := press(m, "z")
+	generated := findMsg[quizGeneratedMsg](t, cmd)
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	if m.mode != ModeStoryView {
+		t.Fatalf("mode = %v, want the story view", m.mode)
+	}
+
+	// Questions arriving after the quiz was stopped are ignored.
+	updated, _ = m.Update(generated)
+	if m = updated.(Model); m.mode != ModeStoryView || m.quiz != nil {
+		t.Errorf("a stopped quiz was resumed")
+	}
+}
+
+func TestQuizNeedsPages(t *testing.T) {
+	m := newStoryTestModel(testutil.NewMockAIClient(testQuiz), ModeStoryView, withUser(models.User{ID: "user"}), withPages("The fox found a door. The owl knocked."))
+	m.pages = nil
+
+	m, cmd := press(m, "z")
+	if m.mode != ModeStoryView || cmd != nil || m.statusMessage == "" {
+		t.Errorf("a quiz needs pages to ask about; mode %v, status %q", m.mode, m.statusMessage)
+	}
+}
+
+func TestQuizFailures(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		opts     []Option
+	}{
+		{"unparseable", "Here are some questions!", nil},
+		{"moderated", `{"questions":[{"question":"Email me at fox@example.com?","kind":"text","options":["Yes","No"],"answer":0}]}`,
+			[]Option{withRuleModeration(t)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option{withUser(models.User{ID: "user"}), withPages("The fox found a door. The owl knocked.")}, tt.opts...)
+			m := newStoryTestModel(testutil.NewMockAIClient(tt.response), ModeStoryView, opts...)
+			m, cmd := press(m, "z")
+			updated, _ := m.Update(findMsg[quizGeneratedMsg](t, cmd))
+			m = updated.(Model)
+			if m.mode != ModeStoryView || m.quiz != nil || m.statusMessage == "" {
+				t.Errorf("mode = %v, status %q; want the story view with an explanation", m.mode, m.statusMessage)
+			}
+		})
+	}
+}
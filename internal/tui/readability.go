@@ -0,0 +1,72 @@
+package tui
+
+import (
+	"context"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kbrakke/illustrated-primer/internal/ai"
+	"github.com/kbrakke/illustrated-primer/internal/readability"
+)
+
+// pageSimplifiedMsg carries a rewrite of a page that read above the child's
+// level.
+type pageSimplifiedMsg struct {
+	prompt string
+	page   ai.StoryPage // as written by the model
+	text   string       // the rewrite
+	err    error
+}
+
+// targetGrade returns the reading grade level pages are written for the
+// current child at.
+func (m Model) targetGrade() float64 {
+	level := ""
+	if m.currentUser != nil && m.currentUser.ReadingLevel != nil {
+		level = *m.currentUser.ReadingLevel
+	}
+	return readability.TargetGrade(m.band(), level)
+}
+
+// scoresReadability reports whether the current child's pages are written
+// in a language readability can score.
+func (m Model) scoresReadability() bool {
+	return readability.Supports(m.language())
+}
+
+// simplifyCompletion asks the model to rewrite a finished page that reads
+// above the child's level. It returns nil if simplifying is off, the page
+// isn't in English or it is easy enough already.
+func (m Model) simplifyCompletion(prompt string, page ai.StoryPage) tea.Cmd {
+	target := m.targetGrade()
+	if !m.simplify || !m.scoresReadability() || !readability.Analyze(page.Text).Exceeds(target) {
+		return nil
+	}
+
+	client, usage := m.aiClient, m.usage
+	pc := ai.NewPromptContext(m.currentUser, m.currentStory, time.Now())
+	pc.Review = m.weaving
+	return func() tea.Msg {
+		text, tokens, err := ai.SimplifyPage(context.Background(), client, pc, page.Text, target)
+		usage.Add(tokens)
+		return pageSimplifiedMsg{prompt: prompt, page: page, text: text, err: err}
+	}
+}
+
+// updateReadability handles readability messages.
+func (m Model) updateReadability(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case pageSimplifiedMsg:
+		page := msg.page
+		switch {
+		case msg.err != nil:
+			m.logger.Error("failed to simplify page, keeping it as written", "error", msg.err)
+		case readability.Analyze(msg.text).GradeLevel() < readability.Analyze(page.Text).GradeLevel():
+			page.Text = msg.text
+		default:
+			m.logger.Warn("simplified page was no easier, keeping it as written")
+		}
+		return m, m.checkCompletion(msg.prompt, page)
+	}
+	return m, nil
+}
@@ -0,0 +1,107 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/kbrakke/illustrated-primer/internal/ai"
+	"github.com/kbrakke/illustrated-primer/internal/models"
+	"github.com/kbrakke/illustrated-primer/testutil"
+)
+
+const (
+	hardPage = `The fox encountered an extraordinarily magnificent, iridescent caterpillar meandering purposefully beneath the enormous, overhanging vegetation, and contemplated whether it was perhaps undergoing metamorphosis.`
+	easyPage = `The fox saw a big green bug. It was under a tree. The fox sat down to look at it. The bug was going to change. It would be a butterfly soon.`
+)
+
+func TestSimplifyHardPage(t *testing.T) {
+	band := string(models.AgeBandPreschool)
+	m := newStoryTestModel(testutil.NewMockAIClient(hardPage), ModeChat, WithChoices(false), withUser(models.User{ID: "user", AgeBand: &band}), WithSimplify(true))
+
+	m, msg := send(t, m, "tell me a story")
+	m, cmd := stream(t, m, msg)
+	if m.statusMessage != "Making the story easier to read..." || len(m.conversationHistory) != 0 {
+		t.Fatalf("status = %q; the page should be held back while it is simplified", m.statusMessage)
+	}
+	simplified := findMsg[pageSimplifiedMsg](t, cmd)
+	if simplified.page.Text != hardPage {
+		t.Errorf("simplifying %q, want the page as written", simplified.page.Text)
+	}
+
+	simplified.text = easyPage
+	updated, _ := m.Update(simplified)
+	m = updated.(Model)
+	if got := m.conversationHistory; len(got) != 2 || got[1] != easyPage {
+		t.Errorf("history = %q, want the simplified page", got)
+	}
+	if m.isLoading {
+		t.Error("still loading after the simplified page was shown")
+	}
+}
+
+func TestSimplifyKeepsPageWhenNoEasier(t *testing.T) {
+	tests := map[string]pageSimplifiedMsg{
+		"harder": {text: hardPage + " " + hardPage},
+		"error":  {err: ai.ErrEmptySimplification},
+	}
+	for name, msg := range tests {
+		t.Run(name, func(t *testing.T) {
+			band := string(models.AgeBandPreschool)
+			m := newStoryTestModel(testutil.NewMockAIClient(hardPage), ModeChat, WithChoices(false), withUser(models.User{ID: "user", AgeBand: &band}), WithSimplify(true))
+			m.isLoading = true
+			msg.prompt, msg.page = "tell me a story", ai.StoryPage{Text: hardPage}
+			updated, _ := m.Update(msg)
+			if m = updated.(Model); len(m.conversationHistory) != 2 || m.conversationHistory[1] != hardPage {
+				t.Errorf("history = %q, want the page as written", m.conversationHistory)
+			}
+		})
+	}
+}
+
+func TestSimplifyModeratesRewrite(t *testing.T) {
+	band := string(models.AgeBandPreschool)
+	m := newStoryTestModel(testutil.NewMockAIClient(hardPage), ModeChat, WithChoices(false), withUser(models.User{ID: "user", AgeBand: &band}), WithSimplify(true), withRuleModeration(t))
+	updated, cmd := m.Update(pageSimplifiedMsg{prompt: "tell me a story", page: ai.StoryPage{Text: hardPage}, text: easyPage})
+	m = updated.(Model)
+	if moderated := findMsg[completionModeratedMsg](t, cmd); moderated.page.Text != easyPage {
+		t.Errorf("moderating %q, want the simplified page", moderated.page.Text)
+	}
+	if len(m.conversationHistory) != 0 {
+		t.Error("the simplified page was shown before it was checked")
+	}
+}
+
+func TestSimplifyEasyPage(t *testing.T) {
+	band := string(models.AgeBandPreschool)
+	m := newStoryTestModel(testutil.NewMockAIClient(easyPage), ModeChat, WithChoices(false), withUser(models.User{ID: "user", AgeBand: &band}), WithSimplify(true))
+	m, msg := send(t, m, "tell me a story")
+	m, _ = stream(t, m, msg)
+	if len(m.conversationHistory) != 2 {
+		t.Errorf("history = %q, want the page shown as written", m.conversationHistory)
+	}
+}
+
+func TestSimplifyOff(t *testing.T) {
+	band := string(models.AgeBandPreschool)
+	m := newStoryTestModel(testutil.NewMockAIClient(hardPage), ModeChat, WithChoices(false), withUser(models.User{ID: "user", AgeBand: &band}))
+	if m.simplifyCompletion("tell me a story", ai.StoryPage{Text: hardPage}) != nil {
+		t.Error("a page was simplified with simplifying off")
+	}
+}
+
+func TestSimplifyOnlyEnglish(t *testing.T) {
+	band := string(models.AgeBandPreschool)
+	m := newStoryTestModel(testutil.NewMockAIClient(hardPage), ModeChat, WithChoices(false), withUser(models.User{ID: "user", AgeBand: &band}), WithSimplify(true))
+	for _, language := range []string{"en", "fr"} {
+		m.currentUser.Language = &language
+		cmd := m.simplifyCompletion("tell me a story", ai.StoryPage{Text: hardPage})
+		if (cmd != nil) != (language == "en") {
+			t.Errorf("language %q: simplified = %v, want only English pages simplified", language, cmd != nil)
+		}
+	}
+
+	m, msg := send(t, m, "tell me a story")
+	m, _ = stream(t, m, msg)
+	if len(m.conversationHistory) != 2 {
+		t.Errorf("history = %q, want the French page shown as written", m.conversationHistory)
+	}
+}
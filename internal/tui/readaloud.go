@@ -0,0 +1,205 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kbrakke/illustrated-primer/internal/models"
+	"github.com/kbrakke/illustrated-primer/internal/narration"
+)
+
+// cueTickInterval is how often the highlighted sentence is updated.
+const cueTickInterval = 200 * time.Millisecond
+
+type readAloudStartedMsg struct {
+	id       int
+	playback narration.Playback
+	cues     []narration.Cue
+	err      error
+}
+
+type readAloudEndedMsg struct {
+	id  int
+	err error
+}
+
+type readAloudTickMsg struct {
+	id int
+}
+
+// startReading reads the page at index aloud, stopping whatever was playing.
+// Pages without audio are narrated first when a narrator is configured.
+func (m *Model) startReading(index int) tea.Cmd {
+	m.stopPlayback()
+	m.reading = true
+	m.readIndex = index
+
+	page := m.pages[index]
+	switch {
+	case m.player == nil:
+		m.statusMessage = "No audio player available for reading aloud"
+		return nil
+	case page.AudioPath != nil:
+		m.statusMessage = fmt.Sprintf("Reading page %d", page.PageNum)
+		return m.playPage(m.readID, page)
+	case m.narrator != nil:
+		m.statusMessage = fmt.Sprintf("Preparing narration for page %d...", page.PageNum)
+		return m.narratePage(page)
+	default:
+		m.statusMessage = fmt.Sprintf("Page %d has no narration", page.PageNum)
+		return nil
+	}
+}
+
+// stopPlayback stops the current audio, if any. Messages from it that are
+// still in flight are ignored because the playback ID changes.
+func (m *Model) stopPlayback() {
+	if m.playback != nil {
+		if err := m.playback.Stop(); err != nil {
+			m.logger.Warn("failed to stop playback", "error", err)
+		}
+	}
+	m.playback = nil
+	m.readPaused = false
+	m.cues = nil
+	m.cueIndex = -1
+	m.readID++
+}
+
+// stopReading leaves read-aloud mode.
+func (m *Model) stopReading() {
+	m.stopPlayback()
+	m.reading = false
+}
+
+// togglePause pauses or resumes the current page.
+func (m *Model) togglePause() {
+	if m.playback == nil {
+		return
+	}
+
+	var err error
+	if m.readPaused {
+		err = m.playback.Resume()
+	} else {
+		err = m.playback.Pause()
+	}
+	if err != nil {
+		m.statusMessage = fmt.Sprintf("Playback error: %v", err)
+		m.logger.Error("failed to toggle playback", "error", err)
+		return
+	}
+
+	m.readPaused = !m.readPaused
+	if m.readPaused {
+		m.statusMessage = "Paused"
+	} else {
+		m.statusMessage = fmt.Sprintf("Reading page %d", m.pages[m.readIndex].PageNum)
+	}
+}
+
+// playPage starts playing a page's narration and loads its sentence timing.
+func (m Model) playPage(id int, page models.Page) tea.Cmd {
+	player := m.player
+	path := *page.AudioPath
+	return func() tea.Msg {
+		playback, err := player.Play(path)
+		if err != nil {
+			return readAloudStartedMsg{id: id, err: err}
+		}
+		return readAloudStartedMsg{
+			id:       id,
+			playback: playback,
+			cues:     narration.LoadCues(path, narration.Text(page)),
+		}
+	}
+}
+
+// waitForPlayback reports when the audio finishes or is stopped.
+func waitForPlayback(id int, playback narration.Playback) tea.Cmd {
+	return func() tea.Msg {
+		return readAloudEndedMsg{id: id, err: playback.Wait()}
+	}
+}
+
+// readAloudTick schedules the next highlight update.
+func readAloudTick(id int) tea.Cmd {
+	return tea.Tick(cueTickInterval, func(time.Time) tea.Msg {
+		return readAloudTickMsg{id: id}
+	})
+}
+
+// updateReadAloud handles playback messages.
+func (m Model) updateReadAloud(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case readAloudStartedMsg:
+		if msg.id != m.readID {
+			// The reader moved on while the player was starting.
+			if msg.playback != nil {
+				_ = msg.playback.Stop()
+			}
+			return m, nil
+		}
+		if msg.err != nil {
+			m.statusMessage = fmt.Sprintf("Playback error: %v", msg.err)
+			m.logger.Error("failed to start playback", "error", msg.err)
+			return m, nil
+		}
+
+		m.playback = msg.playback
+		m.cues = msg.cues
+		m.cueIndex = -1
+		cmds := []tea.Cmd{waitForPlayback(msg.id, msg.playback)}
+		if len(m.cues) > 0 {
+			cmds = append(cmds, readAloudTick(msg.id))
+		}
+		return m, tea.Batch(cmds...)
+
+	case readAloudEndedMsg:
+		if msg.id != m.readID || errors.Is(msg.err, narration.ErrStopped) {
+			return m, nil
+		}
+		m.playback = nil
+		if msg.err != nil {
+			m.statusMessage = fmt.Sprintf("Playback error: %v", msg.err)
+			m.logger.Error("playback failed", "error", msg.err)
+			return m, nil
+		}
+
+		// Auto-advance to the next page.
+		if m.readIndex+1 < len(m.pages) {
+			return m, m.startReading(m.readIndex + 1)
+		}
+		m.stopReading()
+		m.statusMessage = "The end"
+		return m, nil
+
+	case readAloudTickMsg:
+		if msg.id != m.readID || m.playback == nil {
+			return m, nil
+		}
+		m.cueIndex = narration.CueAt(m.cues, m.playback.Elapsed())
+		return m, readAloudTick(msg.id)
+	}
+
+	return m, nil
+}
+
+// highlightSentences renders the page's narration text with the sentence
+// being spoken highlighted. Each word is styled separately so the highlight
+// survives wrapping.
+func highlightSentences(cues []narration.Cue, current, width int, s Styles) string {
+	var words []string
+	for i, cue := range cues {
+		for _, word := range strings.Fields(cue.Text) {
+			if i == current {
+				word = s.Highlight.Render(word)
+			}
+			words = append(words, word)
+		}
+	}
+	return wrapText(strings.Join(words, " "), width)
+}
@@ -0,0 +1,154 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kbrakke/illustrated-primer/internal/narration"
+)
+
+// fakePlayer records what it was asked to play.
+type fakePlayer struct {
+	played []string
+	last   *fakePlayback
+}
+
+func (p *fakePlayer) Play(path string) (narration.Playback, error) {
+	p.played = append(p.played, path)
+	p.last = &fakePlayback{done: make(chan struct{})}
+	return p.last, nil
+}
+
+type fakePlayback struct {
+	paused  bool
+	stopped bool
+	done    chan struct{}
+}
+
+func (pb *fakePlayback) Pause() error  { pb.paused = true; return nil }
+func (pb *fakePlayback) Resume() error { pb.paused = false; return nil }
+func (pb *fakePlayback) Stop() error   { pb.stopped = true; return nil }
+func (pb *fakePlayback) Wait() error {
+	<-pb.done
+	if pb.stopped {
+		return narration.ErrStopped
+	}
+	return nil
+}
+func (pb *fakePlayback) Elapsed() time.Duration { return 1500 * time.Millisecond }
+
+// run feeds a command's message back into the model, like the BubbleTea runtime.
+func run(t *testing.T, m Model, cmd tea.Cmd) Model {
+	t.Helper()
+	if cmd == nil {
+		t.Fatal("expected a command")
+	}
+	updated, _ := m.Update(cmd())
+	return updated.(Model)
+}
+
+func press(m Model, binding string) (Model, tea.Cmd) {
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(binding)})
+	return updated.(Model), cmd
+}
+
+func TestReadAloud(t *testing.T) {
+	player := &fakePlayer{}
+	m := newStoryTestModel(nil, ModeStoryView, WithPlayer(player), WithGraphics(GraphicsNone), withPages("One. Two.", "Three."))
+	for i := range m.pages {
+		path := "page" + string(rune('1'+i)) + ".wav"
+		m.pages[i].AudioPath = &path
+	}
+
+	m, cmd := press(m, "p")
+	if !m.reading {
+		t.Fatal("play should start read-aloud mode")
+	}
+	m = run(t, m, cmd)
+	if len(player.played) != 1 || player.played[0] != "page1.wav" {
+		t.Fatalf("played %v, want page1.wav", player.played)
+	}
+	first := player.last
+
+	// Pause and resume
+	m, _ = press(m, "p")
+	if !m.readPaused || !first.paused {
+		t.Error("second press should pause")
+	}
+	m, _ = press(m, "p")
+	if m.readPaused || first.paused {
+		t.Error("third press should resume")
+	}
+
+	t.Run("auto-advances when a page ends", func(t *testing.T) {
+		updated, cmd := m.Update(readAloudEndedMsg{id: m.readID})
+		next := run(t, updated.(Model), cmd)
+		if next.readIndex != 1 || player.played[len(player.played)-1] != "page2.wav" {
+			t.Errorf("expected to advance to page 2, at %d playing %v", next.readIndex, player.played)
+		}
+
+		updated, _ = next.Update(readAloudEndedMsg{id: next.readID})
+		if last := updated.(Model); last.reading || last.statusMessage != "The end" {
+			t.Error("reading should stop after the last page")
+		}
+	})
+
+	t.Run("skip stops the current page", func(t *testing.T) {
+		skipped, cmd := press(m, "l")
+		if !first.stopped {
+			t.Error("skipping should stop the playing page")
+		}
+		if skipped.readIndex != 1 || cmd == nil {
+			t.Error("skipping should start the next page")
+		}
+
+		// A late end message from the skipped page must not advance again.
+		updated, _ := skipped.Update(readAloudEndedMsg{id: m.readID})
+		if updated.(Model).readIndex != 1 {
+			t.Error("stale playback message changed the page")
+		}
+	})
+
+	t.Run("escape stops reading before leaving", func(t *testing.T) {
+		stopped, _ := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+		if got := stopped.(Model); got.reading || got.mode != ModeStoryView {
+			t.Error("esc should stop reading and stay on the story")
+		}
+	})
+}
+
+func TestReadAloudHighlight(t *testing.T) {
+	m := newStoryTestModel(nil, ModeStoryView, WithPlayer(&fakePlayer{}), WithGraphics(GraphicsNone), withPages("One. Two.", "Three."))
+	for i := range m.pages {
+		path := "page" + string(rune('1'+i)) + ".wav"
+		m.pages[i].AudioPath = &path
+	}
+	m, cmd := press(m, "p")
+	m = run(t, m, cmd)
+
+	m.cues = []narration.Cue{{Text: "One.", Start: 0}, {Text: "Two.", Start: time.Second}}
+	updated, _ := m.Update(readAloudTickMsg{id: m.readID})
+	m = updated.(Model)
+	if m.cueIndex != 1 {
+		t.Fatalf("cueIndex = %d, want 1 at 1.5s", m.cueIndex)
+	}
+
+	view := renderReadAloud(m)
+	if !strings.Contains(view, m.styles.Highlight.Render("Two.")) {
+		t.Errorf("current sentence not highlighted in %q", view)
+	}
+}
+
+func TestReadAloudWithoutPlayer(t *testing.T) {
+	m := newStoryTestModel(nil, ModeStoryView, WithPlayer(nil), WithGraphics(GraphicsNone), withPages("One. Two.", "Three."))
+	for i := range m.pages {
+		path := "page" + string(rune('1'+i)) + ".wav"
+		m.pages[i].AudioPath = &path
+	}
+	m, cmd := press(m, "p")
+	if cmd != nil || !strings.Contains(m.statusMessage, "No audio player") {
+		t.Errorf("expected a message about the missing player, got %q", m.statusMessage)
+	}
+}
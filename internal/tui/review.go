@@ -0,0 +1,251 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kbrakke/illustrated-primer/internal/models"
+)
+
+const (
+	// reviewSession is the most words practised in one go.
+	reviewSession = 10
+	// reviewPerPage is the most due words woven into a new page.
+	reviewPerPage = 2
+)
+
+// reviewState is a practice session of due words. The session is finished
+// when index reaches the number of words.
+type reviewState struct {
+	words    []models.VocabularyEntry
+	index    int  // word being practised
+	revealed bool // the definition is showing and the word can be graded
+	recalls  []models.Recall
+}
+
+// word returns the word being practised.
+func (r *reviewState) word() models.VocabularyEntry {
+	return r.words[r.index]
+}
+
+// finished reports whether every word has been graded.
+func (r *reviewState) finished() bool {
+	return r.index >= len(r.words)
+}
+
+// remembered counts the words recalled in the session.
+func (r *reviewState) remembered() int {
+	n := 0
+	for _, recall := range r.recalls {
+		if recall.Remembered() {
+			n++
+		}
+	}
+	return n
+}
+
+type dueWordsLoadedMsg struct {
+	userID  string
+	entries []models.VocabularyEntry
+	err     error
+}
+
+type wordReviewedMsg struct {
+	word string
+	err  error
+}
+
+// loadDueWords loads the current child's words that are due for review.
+func (m Model) loadDueWords() tea.Cmd {
+	if m.currentUser == nil {
+		return nil
+	}
+	userID, band := m.currentUser.ID, m.band()
+	return func() tea.Msg {
+		entries, err := m.db.ListDueWords(context.Background(), userID, band, time.Now(), reviewSession)
+		return dueWordsLoadedMsg{userID: userID, entries: entries, err: err}
+	}
+}
+
+// wordsToWeave picks due words for the next page, skipping those already
+// woven into a page, and marks them woven.
+func (m *Model) wordsToWeave() []string {
+	var words []string
+	for _, entry := range m.dueWords {
+		if len(words) == reviewPerPage {
+			break
+		}
+		if !m.woven[entry.Word.Word] {
+			m.woven[entry.Word.Word] = true
+			words = append(words, entry.Word.Word)
+		}
+	}
+	return words
+}
+
+// startReview switches to practising the due words, once they are loaded.
+func (m *Model) startReview() tea.Cmd {
+	m.mode = ModeReview
+	m.review = nil
+	m.statusMessage = ""
+	return m.loadDueWords()
+}
+
+// saveWordReview stores a word's new schedule and how well it was recalled.
+func (m Model) saveWordReview(word models.Word, recall models.Recall) tea.Cmd {
+	review := models.NewWordReview(word, recall)
+	return func() tea.Msg {
+		return wordReviewedMsg{word: word.Word, err: m.db.SaveWordReview(context.Background(), &word, review)}
+	}
+}
+
+// updateReview handles review messages.
+func (m Model) updateReview(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case dueWordsLoadedMsg:
+		if m.currentUser == nil || m.currentUser.ID != msg.userID {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.logger.Error("failed to load due words", "error", msg.err)
+			if m.mode == ModeReview {
+				m.mode = ModeStoryList
+				m.statusMessage = fmt.Sprintf("Error loading words: %v", msg.err)
+			}
+			return m, nil
+		}
+		m.dueWords = msg.entries
+		for _, entry := range msg.entries {
+			if entry.Definition != "" {
+				m.definitions[entry.Word.Word] = entry.Definition
+			}
+		}
+
+		if m.mode != ModeReview || m.review != nil {
+			return m, nil
+		}
+		if len(msg.entries) == 0 {
+			m.mode = ModeStoryList
+			m.statusMessage = "No words to practise right now. Come back tomorrow!"
+			return m, nil
+		}
+		m.review = &reviewState{words: msg.entries}
+
+	case wordReviewedMsg:
+		if msg.err != nil {
+			m.statusMessage = fmt.Sprintf("Error saving practice: %v", msg.err)
+			m.logger.Error("failed to save word review", "word", msg.word, "error", msg.err)
+		}
+	}
+	return m, nil
+}
+
+// handleReviewKeys moves through a practice session: show the word, reveal
+// its meaning, then grade how well it was remembered.
+func (m Model) handleReviewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if key.Matches(msg, m.keys.Back) {
+		m.mode = ModeStoryList
+		m.review = nil
+		return m, nil
+	}
+	if m.review == nil {
+		return m, nil
+	}
+
+	r := m.review
+	if r.finished() {
+		if key.Matches(msg, m.keys.Select) {
+			m.mode = ModeStoryList
+			m.review = nil
+		}
+		return m, nil
+	}
+
+	if !r.revealed {
+		if key.Matches(msg, m.keys.Select) {
+			r.revealed = true
+			return m, m.defineWord(r.word().Word.Word, "")
+		}
+		return m, nil
+	}
+
+	var recall models.Recall
+	switch {
+	case key.Matches(msg, m.keys.RecallForgot):
+		recall = models.RecallForgot
+	case key.Matches(msg, m.keys.RecallHard):
+		recall = models.RecallHard
+	case key.Matches(msg, m.keys.RecallGood):
+		recall = models.RecallGood
+	case key.Matches(msg, m.keys.RecallEasy):
+		recall = models.RecallEasy
+	default:
+		return m, nil
+	}
+
+	word := r.word().Word
+	word.Schedule(recall, time.Now())
+	r.recalls = append(r.recalls, recall)
+	r.index++
+	r.revealed = false
+	m.removeDueWord(word.Word)
+	if r.finished() {
+		m.statusMessage = fmt.Sprintf("Practice finished: %d of %d remembered", r.remembered(), len(r.words))
+	}
+	return m, m.saveWordReview(word, recall)
+}
+
+// removeDueWord drops a reviewed word from the due words.
+func (m *Model) removeDueWord(word string) {
+	for i, entry := range m.dueWords {
+		if entry.Word.Word == word {
+			m.dueWords = append(m.dueWords[:i:i], m.dueWords[i+1:]...)
+			return
+		}
+	}
+}
+
+// renderReview shows the word being practised, its meaning once revealed,
+// and a summary at the end.
+func renderReview(m Model) string {
+	var b strings.Builder
+	b.WriteString(m.styles.Header.Render("Word Practice"))
+	b.WriteString("\n\n")
+
+	r := m.review
+	switch {
+	case r == nil:
+		b.WriteString(m.styles.Spinner.Render(m.spinner.View()))
+		b.WriteString(m.styles.Normal.Render(" Finding words to practise..."))
+		b.WriteString("\n")
+
+	case r.finished():
+		b.WriteString(m.styles.Highlight.Render(fmt.Sprintf("You remembered %d of %d words!", r.remembered(), len(r.words))))
+		b.WriteString("\n")
+		b.WriteString(m.styles.Normal.Render("The tricky ones will come back soon, in your stories too."))
+		b.WriteString("\n")
+
+	default:
+		word := r.word().Word.Word
+		b.WriteString(m.styles.PageNum.Render(fmt.Sprintf("Word %d of %d", r.index+1, len(r.words))))
+		b.WriteString("\n\n")
+		b.WriteString(m.styles.Highlight.Render(" " + word + " "))
+		b.WriteString("\n\n")
+		if !r.revealed {
+			b.WriteString(m.styles.Normal.Render(fmt.Sprintf("Do you remember what it means? Press %s to check.", m.keys.Select.Help().Key)))
+		} else {
+			b.WriteString(m.styles.Normal.Render(wrapText(m.definitionText(word), m.width-10)))
+			b.WriteString("\n\n")
+			b.WriteString(m.styles.Normal.Render("How well did you remember it?"))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(renderHelp(m))
+	return b.String()
+}
@@ -0,0 +1,120 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kbrakke/illustrated-primer/internal/models"
+	"github.com/kbrakke/illustrated-primer/testutil"
+)
+
+func dueEntries(words ...string) []models.VocabularyEntry {
+	entries := make([]models.VocabularyEntry, len(words))
+	for i, word := range words {
+		entries[i] = models.VocabularyEntry{Word: models.Word{UserID: "user", Word: word, Ease: models.DefaultEase}}
+	}
+	return entries
+}
+
+func TestReview(t *testing.T) {
+	m := newStoryTestModel(nil, ModeStoryList, withUser(models.User{ID: "user"}))
+	m.dueWords = dueEntries("enormous", "iridescent")
+	if view := renderStoryList(m); !strings.Contains(view, "2 words to practise (p)") {
+		t.Errorf("due words not mentioned in the story list:\n%s", view)
+	}
+
+	m, cmd := press(m, "p")
+	if m.mode != ModeReview || m.review != nil || cmd == nil {
+		t.Fatalf("mode = %v, want practice while the words load", m.mode)
+	}
+	entries := dueEntries("enormous", "iridescent")
+	entries[0].Definition = "Very, very big."
+	updated, _ := m.Update(dueWordsLoadedMsg{userID: "user", entries: entries})
+	m = updated.(Model)
+	if m.review == nil || len(m.review.words) != 2 {
+		t.Fatalf("review = %+v", m.review)
+	}
+
+	view := renderReview(m)
+	if !strings.Contains(view, "Word 1 of 2") || !strings.Contains(view, "enormous") || strings.Contains(view, "Very, very big.") {
+		t.Errorf("the meaning should be hidden until asked for:\n%s", view)
+	}
+	m, _ = press(m, "3")
+	if m.review.index != 0 {
+		t.Fatal("a word was graded before its meaning was shown")
+	}
+
+	m, _ = enter(m)
+	if view := renderReview(m); !strings.Contains(view, "Very, very big.") || !strings.Contains(view, "How well did you remember it?") {
+		t.Errorf("meaning not revealed:\n%s", view)
+	}
+	m, cmd = press(m, "3")
+	if m.review.index != 1 || cmd == nil {
+		t.Fatalf("remembering a word should save it and move on; index %d", m.review.index)
+	}
+	if len(m.dueWords) != 1 || m.dueWords[0].Word.Word != "iridescent" {
+		t.Errorf("dueWords = %+v, want the reviewed word gone", m.dueWords)
+	}
+
+	m, cmd = enter(m)
+	if cmd == nil || !m.defining["iridescent"] {
+		t.Error("revealing a word without a meaning should look it up")
+	}
+	m, _ = press(m, "1")
+	if !m.review.finished() {
+		t.Fatal("practice should be finished")
+	}
+	if got := m.review.recalls; len(got) != 2 || got[0] != models.RecallGood || got[1] != models.RecallForgot {
+		t.Errorf("recalls = %v", got)
+	}
+	if view := renderReview(m); !strings.Contains(view, "You remembered 1 of 2 words!") {
+		t.Errorf("summary missing:\n%s", view)
+	}
+
+	m, _ = enter(m)
+	if m.mode != ModeStoryList || m.review != nil {
+		t.Errorf("mode = %v, want the story list after practice", m.mode)
+	}
+}
+
+func TestReviewNothingDue(t *testing.T) {
+	m := newStoryTestModel(nil, ModeStoryList, withUser(models.User{ID: "user"}))
+
+	m, _ = press(m, "p")
+	updated, _ := m.Update(dueWordsLoadedMsg{userID: "user"})
+	if m = updated.(Model); m.mode != ModeStoryList || m.statusMessage == "" {
+		t.Errorf("mode = %v, status %q; want the story list with an explanation", m.mode, m.statusMessage)
+	}
+}
+
+func TestReviewStopped(t *testing.T) {
+	m := newStoryTestModel(nil, ModeStoryList, withUser(models.User{ID: "user"}))
+	m, _ = press(m, "p")
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	updated, _ = m.Update(dueWordsLoadedMsg{userID: "user", entries: dueEntries("enormous")})
+	if m = updated.(Model); m.mode != ModeStoryList || m.review != nil {
+		t.Errorf("a stopped practice was resumed")
+	}
+	if len(m.dueWords) != 1 {
+		t.Errorf("dueWords = %v, want them kept for the story list", m.dueWords)
+	}
+}
+
+func TestWeaveDueWords(t *testing.T) {
+	m := newStoryTestModel(testutil.NewMockAIClient("Once upon a time..."), ModeChat)
+	m.dueWords = dueEntries("enormous", "iridescent", "magnificent")
+
+	m, _ = send(t, m, "tell me a story")
+	if got := strings.Join(m.weaving, ","); got != "enormous,iridescent" {
+		t.Errorf("weaving = %s, want the two most overdue words", got)
+	}
+
+	m.isLoading = false
+	m, _ = send(t, m, "what happens next?")
+	if got := strings.Join(m.weaving, ","); got != "magnificent" {
+		t.Errorf("weaving = %s, want only the word not yet used", got)
+	}
+}
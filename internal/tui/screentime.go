@@ -0,0 +1,330 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kbrakke/illustrated-primer/internal/models"
+)
+
+const (
+	// wrapUpWarning is how long before screen time ends that the story is
+	// brought to a resting place.
+	wrapUpWarning = 2 * time.Minute
+	// overrideTime is how much longer the parent PIN lets a child carry on.
+	overrideTime = 15 * time.Minute
+	// screenTickInterval is how often the timer counts down.
+	screenTickInterval = time.Second
+	// touchInterval is how often an open session is marked as still going.
+	touchInterval = time.Minute
+)
+
+// wrapUpPrompt is the child's turn that asks for the last page of the day.
+const wrapUpPrompt = "It's nearly time to stop. Can we finish the story for today?"
+
+// screenState is the current child's session and how long it may last.
+type screenState struct {
+	session    *models.ScreenSession // nil while starting, and once ended
+	limits     models.ScreenTime
+	override   bool      // carrying on with the parent PIN
+	deadline   time.Time // zero without a limit
+	now        time.Time // as of the last tick
+	touched    time.Time // when the session was last marked as going
+	wrappingUp bool      // the story is being brought to a resting place
+	locked     string    // why the screen is locked, empty while it isn't
+	resume     AppMode   // where an override goes back to
+}
+
+type screenSessionStartedMsg struct {
+	userID  string
+	session *models.ScreenSession // nil when there is no time left
+	used    time.Duration         // today, before this session
+	now     time.Time
+	err     error
+}
+
+type screenTickMsg struct {
+	id  string // the session the tick belongs to
+	now time.Time
+}
+
+// startScreenTime works out how much of the child's time is left today and,
+// if there is any, starts a session. Time granted with the parent PIN
+// ignores the limits.
+func (m Model) startScreenTime(user models.User, override bool) tea.Cmd {
+	limits := user.ScreenTime()
+	return func() tea.Msg {
+		ctx := context.Background()
+		now := time.Now()
+
+		var used time.Duration
+		if limits.Limited() && !override {
+			var err error
+			used, err = m.db.ScreenTimeUsed(ctx, user.ID, models.StartOfDay(now))
+			if err != nil {
+				return screenSessionStartedMsg{userID: user.ID, err: err}
+			}
+			if deadline := limits.Deadline(now, used); !deadline.IsZero() && !deadline.After(now) {
+				return screenSessionStartedMsg{userID: user.ID, used: used, now: now}
+			}
+		}
+
+		session := models.NewScreenSession(user.ID, override)
+		if err := m.db.StartScreenSession(ctx, session); err != nil {
+			return screenSessionStartedMsg{userID: user.ID, err: err}
+		}
+		return screenSessionStartedMsg{userID: user.ID, session: session, used: used, now: now}
+	}
+}
+
+// screenTick schedules the next countdown of a session's timer.
+func screenTick(id string) tea.Cmd {
+	return tea.Tick(screenTickInterval, func(now time.Time) tea.Msg {
+		return screenTickMsg{id: id, now: now}
+	})
+}
+
+// touchScreenSession marks a session as still going.
+func (m Model) touchScreenSession(id string, at time.Time) tea.Cmd {
+	return func() tea.Msg {
+		if err := m.db.TouchScreenSession(context.Background(), id, at); err != nil {
+			m.logger.Error("failed to touch screen session", "session_id", id, "error", err)
+		}
+		return nil
+	}
+}
+
+// endScreenSession records why the child's session ended. It does nothing
+// if there is no open session.
+func (m *Model) endScreenSession(reason string) tea.Cmd {
+	if m.screen == nil || m.screen.session == nil {
+		return nil
+	}
+	id := m.screen.session.ID
+	m.screen.session = nil
+	database, logger := m.db, m.logger
+	return func() tea.Msg {
+		if err := database.EndScreenSession(context.Background(), id, reason, time.Now()); err != nil {
+			logger.Error("failed to end screen session", "session_id", id, "reason", reason, "error", err)
+		}
+		return nil
+	}
+}
+
+// leaveChild ends the child's session and goes back to choosing a user.
+func (m *Model) leaveChild() tea.Cmd {
+	cmd := m.endScreenSession(models.SessionEndedLeft)
+	m.screen = nil
+	m.mode = ModeUserSelection
+	m.selectedIndex = 0
+	m.currentUser = nil
+	m.textInput.SetValue("")
+	m.textInput.EchoMode = textinput.EchoNormal
+	m.textInput.Blur()
+	m.applyTheme(DefaultThemeName)
+	return cmd
+}
+
+// updateScreenTime handles session messages and counts down the timer,
+// wrapping up the story when time is nearly up and locking the screen once
+// it is.
+func (m Model) updateScreenTime(msg tea.Msg) (Model, tea.Cmd) {
+	s := m.screen
+	switch msg := msg.(type) {
+	case screenSessionStartedMsg:
+		if s == nil || m.currentUser == nil || m.currentUser.ID != msg.userID {
+			// The child left before the session started.
+			return m, nil
+		}
+		if msg.err != nil {
+			m.statusMessage = fmt.Sprintf("Error starting screen time: %v", msg.err)
+			m.logger.Error("failed to start screen session", "user_id", msg.userID, "error", msg.err)
+			return m, nil
+		}
+		s.now, s.touched = msg.now, msg.now
+		if s.override {
+			s.deadline = msg.now.Add(overrideTime)
+		} else {
+			s.deadline = s.limits.Deadline(msg.now, msg.used)
+		}
+		if msg.session == nil {
+			return m, m.lockScreen()
+		}
+		s.session = msg.session
+		return m, screenTick(s.session.ID)
+
+	case screenTickMsg:
+		if s == nil || s.session == nil || s.session.ID != msg.id {
+			return m, nil
+		}
+		s.now = msg.now
+		cmds := []tea.Cmd{screenTick(msg.id)}
+		if msg.now.Sub(s.touched) >= touchInterval {
+			s.touched = msg.now
+			cmds = append(cmds, m.touchScreenSession(msg.id, msg.now))
+		}
+		if s.deadline.IsZero() {
+			return m, tea.Batch(cmds...)
+		}
+
+		left := s.deadline.Sub(msg.now)
+		if left <= wrapUpWarning && !s.wrappingUp {
+			s.wrappingUp = true
+			cmds = append(cmds, m.wrapUp())
+		}
+		// A page being written is finished first, so the story isn't cut
+		// off mid-sentence.
+		if left <= 0 && !m.isLoading {
+			return m, m.lockScreen()
+		}
+		return m, tea.Batch(cmds...)
+	}
+	return m, nil
+}
+
+// wrapUp asks for a last page that brings the story to a resting place,
+// if the child is in the middle of one.
+func (m *Model) wrapUp() tea.Cmd {
+	if m.mode != ModeChat || m.isLoading || m.currentStory == nil {
+		m.statusMessage = "It's nearly time to stop!"
+		return nil
+	}
+	cmd := m.beginTurn(wrapUpPrompt)
+	m.statusMessage = "It's nearly time to stop, so the story is finding a place to rest..."
+	return cmd
+}
+
+// lockScreen ends the session and shows the lock screen, which the parent
+// PIN can lift for a while.
+func (m *Model) lockScreen() tea.Cmd {
+	s := m.screen
+	s.locked = models.SessionEndedLimit
+	if !s.override && !s.limits.Allowed(s.now) {
+		s.locked = models.SessionEndedQuietHours
+	}
+	cmd := m.endScreenSession(s.locked)
+
+	m.stopReading()
+	m.cancelRecording()
+	m.composer.Blur()
+	if m.mode != ModeLocked {
+		s.resume = m.mode
+	}
+	s.wrappingUp = false
+	m.mode = ModeLocked
+	m.statusMessage = ""
+	if m.canUnlock(m.guardians(m.currentUser)) {
+		m.textInput.SetValue("")
+		m.textInput.EchoMode = textinput.EchoPassword
+		m.textInput.Focus()
+	}
+	return cmd
+}
+
+// handleLockedKeys takes the parent PIN on the lock screen. The child can
+// only go back to choosing a user.
+func (m Model) handleLockedKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	s := m.screen
+	if s == nil || key.Matches(msg, m.keys.Back) {
+		return m, m.leaveChild()
+	}
+	if !m.textInput.Focused() {
+		return m, nil
+	}
+	if !key.Matches(msg, m.keys.Select) {
+		var cmd tea.Cmd
+		m.textInput, cmd = m.textInput.Update(msg)
+		return m, cmd
+	}
+
+	if m.pinLockedOut() {
+		m.textInput.SetValue("")
+		return m, nil
+	}
+	if _, err := m.checkPIN(m.guardians(m.currentUser), m.textInput.Value()); err != nil {
+		m.textInput.SetValue("")
+		if m.wrongPIN() {
+			cmd := m.leaveChild()
+			m.pinLockedOut()
+			return m, cmd
+		}
+		m.statusMessage = "That PIN isn't right. Try again:"
+		return m, nil
+	}
+	m.pinAttempts = 0
+
+	s.locked = ""
+	s.override = true
+	m.textInput.SetValue("")
+	m.textInput.EchoMode = textinput.EchoNormal
+	m.textInput.Blur()
+	m.mode = s.resume
+	if m.mode == ModeChat {
+		m.composer.Focus()
+	}
+	m.statusMessage = fmt.Sprintf("%d more minutes.", int(overrideTime.Minutes()))
+	return m, m.startScreenTime(*m.currentUser, true)
+}
+
+// renderLocked shows the lock screen.
+func renderLocked(m Model) string {
+	var b strings.Builder
+	b.WriteString(m.styles.Header.Render("Time for a Break"))
+	b.WriteString("\n\n")
+
+	name := "friend"
+	if m.currentUser != nil {
+		name = m.currentUser.DisplayName()
+	}
+	message := fmt.Sprintf("That's all the stories for today, %s. See you tomorrow!", name)
+	if s := m.screen; s != nil && s.locked == models.SessionEndedQuietHours {
+		message = fmt.Sprintf("It's quiet time now, %s. Stories will be back at %s.", name, s.limits.From)
+	}
+	b.WriteString(m.styles.Normal.Render(wrapText(message, m.width-10)))
+	b.WriteString("\n")
+
+	if m.textInput.Focused() {
+		b.WriteString("\n")
+		b.WriteString(m.styles.Help.UnsetMarginTop().Render(fmt.Sprintf("A grown-up can enter the parent PIN for %d more minutes:", int(overrideTime.Minutes()))))
+		b.WriteString("\n")
+		b.WriteString(m.textInput.View())
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(renderHelp(m))
+	return b.String()
+}
+
+// screenTimer shows how long the child has left, or nothing without a limit.
+func screenTimer(m Model) string {
+	s := m.screen
+	if s == nil || s.deadline.IsZero() || s.now.IsZero() || s.locked != "" {
+		return ""
+	}
+	left := s.deadline.Sub(s.now).Round(time.Second)
+	if left < 0 {
+		left = 0
+	}
+	return fmt.Sprintf("⏱ %d:%02d left", int(left.Minutes()), int(left.Seconds())%60)
+}
+
+// screenTimeLine describes a child's screen-time limits for parents.
+func screenTimeLine(limits models.ScreenTime) string {
+	var parts []string
+	if limits.DailyMinutes > 0 {
+		parts = append(parts, fmt.Sprintf("%d minutes a day", limits.DailyMinutes))
+	}
+	if limits.From != "" {
+		parts = append(parts, fmt.Sprintf("between %s and %s", limits.From, limits.Until))
+	}
+	if len(parts) == 0 {
+		return "no limits"
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,166 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kbrakke/illustrated-primer/internal/models"
+	"github.com/kbrakke/illustrated-primer/testutil"
+)
+
+var screenNow = time.Date(2026, 3, 1, 18, 0, 0, 0, time.Local)
+
+// startSession delivers a started session to the model, with used minutes
+// already spent today.
+func startSession(t *testing.T, m Model, used time.Duration) Model {
+	t.Helper()
+	session := &models.ScreenSession{ID: "session", UserID: "user"}
+	updated, cmd := m.Update(screenSessionStartedMsg{userID: "user", session: session, used: used, now: screenNow})
+	if cmd == nil {
+		t.Fatal("a started session should start the timer")
+	}
+	return updated.(Model)
+}
+
+func tick(m Model, now time.Time) (Model, tea.Cmd) {
+	updated, cmd := m.Update(screenTickMsg{id: "session", now: now})
+	return updated.(Model), cmd
+}
+
+func TestScreenTimeWrapUpAndLock(t *testing.T) {
+	limits := models.ScreenTime{DailyMinutes: 30}
+	child := models.User{ID: "user"}
+	child.SetScreenTime(limits)
+	m := newStoryTestModel(testutil.NewMockAIClient("The fox curled up by the door, ready to dream."), ModeChat, withUser(child), WithParentPIN("2468"))
+	m.screen = &screenState{limits: limits}
+	m = startSession(t, m, 20*time.Minute)
+
+	if got := RenderView(m); !strings.Contains(got, "⏱ 10:00 left") {
+		t.Errorf("timer not shown:\n%s", got)
+	}
+
+	m, _ = tick(m, screenNow.Add(7*time.Minute))
+	if m.isLoading || m.screen.wrappingUp {
+		t.Fatal("the story shouldn't wrap up with three minutes left")
+	}
+
+	m, cmd := tick(m, screenNow.Add(8*time.Minute+30*time.Second))
+	if !m.screen.wrappingUp || !m.isLoading || m.inputBuffer != wrapUpPrompt || cmd == nil {
+		t.Fatalf("two minutes before the end the story should wrap up; status %q", m.statusMessage)
+	}
+	if got := RenderView(m); !strings.Contains(got, "⏱ 1:30 left") {
+		t.Errorf("timer not counting down:\n%s", got)
+	}
+	started := m.sendMessage(m.inputBuffer)()
+	if client := m.aiClient.(*testutil.MockAIClient); client.LastCall().Message != wrapUpPrompt {
+		t.Errorf("sent %q, want the wrap-up prompt", client.LastCall().Message)
+	}
+
+	// Time runs out while the last page is written; it is finished first.
+	m, _ = tick(m, screenNow.Add(11*time.Minute))
+	if m.mode == ModeLocked {
+		t.Fatal("the screen locked before the last page was finished")
+	}
+	for msg := started; ; msg = waitForChunk(m.stream)() {
+		updated, _ := m.Update(msg)
+		m = updated.(Model)
+		if _, done := msg.(aiDoneMsg); done {
+			break
+		}
+	}
+	if m.isLoading {
+		t.Fatal("the wrap-up page should be finished")
+	}
+
+	m, cmd = tick(m, screenNow.Add(11*time.Minute+time.Second))
+	if m.mode != ModeLocked || m.screen.locked != models.SessionEndedLimit {
+		t.Fatalf("mode = %v, want locked once time is up", m.mode)
+	}
+	if cmd == nil || m.screen.session != nil {
+		t.Error("locking should end the session")
+	}
+	view := RenderView(m)
+	if !strings.Contains(view, "See you tomorrow!") || !strings.Contains(view, "parent PIN for 15 more minutes") {
+		t.Errorf("lock screen:\n%s", view)
+	}
+	if strings.Contains(view, "left") {
+		t.Errorf("the timer should be gone once locked:\n%s", view)
+	}
+
+	// Keys go to the PIN; the child can't get back to the story.
+	m, _ = press(m, "q")
+	m, _ = enter(m)
+	if m.mode != ModeLocked || m.pinAttempts != 1 {
+		t.Fatalf("a wrong PIN should keep the screen locked; attempts %d", m.pinAttempts)
+	}
+	m, _ = press(m, "2468")
+	m, cmd = enter(m)
+	if m.mode != ModeChat || !m.screen.override || cmd == nil {
+		t.Fatalf("mode = %v, want back in the chat with more time", m.mode)
+	}
+
+	updated, _ := m.Update(screenSessionStartedMsg{userID: "user", session: &models.ScreenSession{ID: "override", Override: true}, now: screenNow.Add(12 * time.Minute)})
+	m = updated.(Model)
+	if want := screenNow.Add(12*time.Minute + overrideTime); !m.screen.deadline.Equal(want) {
+		t.Errorf("deadline = %v, want %v", m.screen.deadline, want)
+	}
+}
+
+func TestScreenTimeQuietHours(t *testing.T) {
+	limits := models.ScreenTime{From: "07:00", Until: "17:30"}
+	child := models.User{ID: "user"}
+	child.SetScreenTime(limits)
+	m := newStoryTestModel(testutil.NewMockAIClient("The fox curled up by the door, ready to dream."), ModeChat, withUser(child))
+	m.screen = &screenState{limits: limits}
+	m.mode = ModeStoryList
+
+	updated, _ := m.Update(screenSessionStartedMsg{userID: "user", now: screenNow})
+	m = updated.(Model)
+	if m.mode != ModeLocked || m.screen.locked != models.SessionEndedQuietHours {
+		t.Fatalf("mode = %v, want locked in quiet hours", m.mode)
+	}
+	view := renderLocked(m)
+	if !strings.Contains(view, "Stories will be back at 07:00.") {
+		t.Errorf("lock screen:\n%s", view)
+	}
+	if strings.Contains(view, "parent PIN") || m.textInput.Focused() {
+		t.Errorf("there's no override without a parent PIN:\n%s", view)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	if m.mode != ModeUserSelection || m.currentUser != nil || m.screen != nil {
+		t.Errorf("mode = %v, want back to choosing a user", m.mode)
+	}
+}
+
+func TestScreenTimeWithoutLimits(t *testing.T) {
+	limits := models.ScreenTime{}
+	child := models.User{ID: "user"}
+	child.SetScreenTime(limits)
+	m := newStoryTestModel(testutil.NewMockAIClient("The fox curled up by the door, ready to dream."), ModeChat, withUser(child))
+	m.screen = &screenState{limits: limits}
+	m = startSession(t, m, 0)
+	if !m.screen.deadline.IsZero() {
+		t.Fatalf("deadline = %v, want none", m.screen.deadline)
+	}
+
+	m, cmd := tick(m, screenNow.Add(3*time.Hour))
+	if m.mode != ModeChat || cmd == nil {
+		t.Fatal("without limits the session should carry on")
+	}
+	if !m.screen.touched.Equal(screenNow.Add(3 * time.Hour)) {
+		t.Error("an open session should be marked as still going")
+	}
+	if got := RenderView(m); strings.Contains(got, "⏱") {
+		t.Errorf("no timer without a limit:\n%s", got)
+	}
+
+	// Ticks of an ended session are dropped.
+	m.screen.session = nil
+	if _, cmd := tick(m, screenNow.Add(4*time.Hour)); cmd != nil {
+		t.Error("an ended session shouldn't keep ticking")
+	}
+}
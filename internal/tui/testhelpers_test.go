@@ -0,0 +1,60 @@
+package tui
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/kbrakke/illustrated-primer/internal/ai"
+	"github.com/kbrakke/illustrated-primer/internal/models"
+)
+
+// newTestModel returns a model with no database that discards its log.
+func newTestModel(client ai.Client, opts ...Option) Model {
+	return New(nil, client, slog.New(slog.NewTextHandler(io.Discard, nil)), opts...)
+}
+
+// newStoryTestModel returns a model with the "Fox" story open in mode. In
+// the chat, the composer has focus, ready for a prompt.
+func newStoryTestModel(client ai.Client, mode AppMode, opts ...Option) Model {
+	m := newTestModel(client, opts...)
+	m.mode = mode
+	m.currentStory = &models.Story{ID: "story", Title: "Fox"}
+	if mode == ModeChat {
+		m.composer.Focus()
+	}
+	return m
+}
+
+// withUser makes user the current user.
+func withUser(user models.User) Option {
+	return func(m *Model) {
+		m.currentUser = &user
+	}
+}
+
+// withUsers sets the users to choose from.
+func withUsers(users ...models.User) Option {
+	return func(m *Model) {
+		m.users = users
+	}
+}
+
+// withPages gives the story a page for each text, in order.
+func withPages(texts ...string) Option {
+	return func(m *Model) {
+		for i, text := range texts {
+			m.pages = append(m.pages, *models.NewPage("story", int64(i+1), "prompt", text))
+		}
+	}
+}
+
+// withRuleModeration moderates with the built-in rules and default policy.
+func withRuleModeration(t *testing.T) Option {
+	t.Helper()
+	rules, err := ai.NewRuleModerator(ai.DefaultRules())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return WithModeration(&ai.Guard{Moderators: []ai.Moderator{rules}})
+}
@@ -0,0 +1,224 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// DefaultThemeName is the theme used when a user has not chosen one.
+const DefaultThemeName = "default"
+
+// Theme describes the colors and spacing used to render the TUI.
+// Colors are lipgloss color strings: ANSI 256 numbers ("205") or hex ("#000000").
+// An empty color leaves the terminal default in place.
+type Theme struct {
+	Name       string `json:"name"`
+	Primary    string `json:"primary"`    // titles and spinner
+	Accent     string `json:"accent"`     // headers, page numbers, AI text
+	Background string `json:"background"` // header and selection background
+	Selected   string `json:"selected"`   // selected list item text
+	Text       string `json:"text"`       // body text
+	Muted      string `json:"muted"`      // status bar and help
+	User       string `json:"user"`       // child's messages
+	Border     string `json:"border"`     // input border
+
+	// Spacing adds blank lines between list items and extra padding, for
+	// readers who need more room than a terminal font size change gives them.
+	Spacing int `json:"spacing"`
+}
+
+// BuiltinThemes returns the themes that ship with the application.
+func BuiltinThemes() []Theme {
+	return []Theme{
+		{
+			Name:       DefaultThemeName,
+			Primary:    "205",
+			Accent:     "39",
+			Background: "236",
+			Selected:   "170",
+			Text:       "252",
+			Muted:      "241",
+			User:       "82",
+			Border:     "62",
+		},
+		{
+			Name:       "high-contrast",
+			Primary:    "#FFFF00",
+			Accent:     "#00FFFF",
+			Background: "#000000",
+			Selected:   "#FFFFFF",
+			Text:       "#FFFFFF",
+			Muted:      "#C0C0C0",
+			User:       "#00FF00",
+			Border:     "#FFFFFF",
+		},
+		{
+			// Pure black on white with minimal grays, per the e-ink design notes.
+			// Selection is shown with reverse video rather than a background color.
+			Name:   "e-ink",
+			Text:   "#000000",
+			Muted:  "#606060",
+			User:   "#000000",
+			Border: "#000000",
+		},
+		{
+			Name:       "large-print",
+			Primary:    "205",
+			Accent:     "39",
+			Background: "236",
+			Selected:   "170",
+			Text:       "255",
+			Muted:      "250",
+			User:       "82",
+			Border:     "62",
+			Spacing:    1,
+		},
+	}
+}
+
+// LoadThemes reads every *.json file in dir as a Theme. A theme whose name
+// matches a built-in replaces it.
+func LoadThemes(dir string) ([]Theme, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("list theme files: %w", err)
+	}
+	sort.Strings(paths)
+
+	var themes []Theme
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read theme file: %w", err)
+		}
+
+		var theme Theme
+		if err := json.Unmarshal(data, &theme); err != nil {
+			return nil, fmt.Errorf("parse theme file %s: %w", filepath.Base(path), err)
+		}
+		if theme.Name == "" {
+			theme.Name = strings.TrimSuffix(filepath.Base(path), ".json")
+		}
+		themes = append(themes, theme)
+	}
+
+	return themes, nil
+}
+
+// ForProfile adapts the theme to the terminal's color profile. lipgloss
+// already maps colors down to 256 or 16 colors; on terminals without color
+// support (or with NO_COLOR set) all colors are dropped so the theme falls
+// back to bold and reverse video.
+func (t Theme) ForProfile(profile termenv.Profile) Theme {
+	if profile != termenv.Ascii {
+		return t
+	}
+	return Theme{Name: t.Name, Spacing: t.Spacing}
+}
+
+// Styles holds the lipgloss styles derived from a Theme.
+type Styles struct {
+	Title       lipgloss.Style
+	Header      lipgloss.Style
+	Selected    lipgloss.Style
+	Normal      lipgloss.Style
+	Status      lipgloss.Style
+	Help        lipgloss.Style
+	UserMessage lipgloss.Style
+	AIMessage   lipgloss.Style
+	PageNum     lipgloss.Style
+	Input       lipgloss.Style
+	Spinner     lipgloss.Style
+	Highlight   lipgloss.Style // sentence being read aloud
+	Word        lipgloss.Style // vocabulary word with a definition to look up
+
+	// Markdown
+	Heading lipgloss.Style
+	Code    lipgloss.Style
+	Quote   lipgloss.Style
+}
+
+// NewStyles builds the styles for a theme.
+func NewStyles(t Theme) Styles {
+	fg := func(s lipgloss.Style, color string) lipgloss.Style {
+		if color == "" {
+			return s
+		}
+		return s.Foreground(lipgloss.Color(color))
+	}
+	bg := func(s lipgloss.Style, color string) lipgloss.Style {
+		if color == "" {
+			return s
+		}
+		return s.Background(lipgloss.Color(color))
+	}
+
+	selected := bg(fg(lipgloss.NewStyle().Bold(true).Padding(0, 1+t.Spacing), t.Selected), t.Background)
+	if t.Background == "" {
+		selected = selected.Reverse(true)
+	}
+
+	header := bg(fg(lipgloss.NewStyle().Bold(true).Padding(0, 2).MarginBottom(1), t.Accent), t.Background)
+	if t.Background == "" {
+		header = header.Underline(true)
+	}
+
+	input := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(0, 1)
+	if t.Border != "" {
+		input = input.BorderForeground(lipgloss.Color(t.Border))
+	}
+
+	highlight := bg(fg(lipgloss.NewStyle().Bold(true), t.Selected), t.Background)
+	if t.Background == "" {
+		highlight = highlight.Reverse(true)
+	}
+
+	code := fg(lipgloss.NewStyle(), t.Accent)
+	if t.Accent == "" {
+		code = code.Reverse(true)
+	}
+
+	return Styles{
+		Title:       fg(lipgloss.NewStyle().Bold(true).MarginBottom(1), t.Primary),
+		Header:      header,
+		Selected:    selected.MarginBottom(t.Spacing),
+		Normal:      fg(lipgloss.NewStyle().Bold(t.Spacing > 0).Padding(0, 1+t.Spacing), t.Text).MarginBottom(t.Spacing),
+		Status:      fg(lipgloss.NewStyle().MarginTop(1), t.Muted),
+		Help:        fg(lipgloss.NewStyle().MarginTop(1), t.Muted),
+		UserMessage: fg(lipgloss.NewStyle().Bold(true), t.User),
+		AIMessage:   fg(lipgloss.NewStyle().Bold(t.Spacing > 0), t.Accent),
+		PageNum:     fg(lipgloss.NewStyle().Bold(true), t.Accent),
+		Input:       input,
+		Spinner:     fg(lipgloss.NewStyle(), t.Primary),
+		Highlight:   highlight,
+		Word:        fg(lipgloss.NewStyle().Underline(true), t.Accent),
+		Heading:     fg(lipgloss.NewStyle().Bold(true).Underline(true), t.Primary),
+		Code:        code,
+		Quote:       fg(lipgloss.NewStyle().Italic(true), t.Muted),
+	}
+}
+
+// helpStyles returns bubbles/help styles matching the theme.
+func helpStyles(t Theme) help.Styles {
+	styles := help.New().Styles
+	if t.Text != "" {
+		styles.ShortKey = styles.ShortKey.Foreground(lipgloss.Color(t.Text))
+		styles.FullKey = styles.FullKey.Foreground(lipgloss.Color(t.Text))
+	}
+	if t.Muted != "" {
+		styles.ShortDesc = styles.ShortDesc.Foreground(lipgloss.Color(t.Muted))
+		styles.FullDesc = styles.FullDesc.Foreground(lipgloss.Color(t.Muted))
+		styles.ShortSeparator = styles.ShortSeparator.Foreground(lipgloss.Color(t.Muted))
+		styles.FullSeparator = styles.FullSeparator.Foreground(lipgloss.Color(t.Muted))
+		styles.Ellipsis = styles.Ellipsis.Foreground(lipgloss.Color(t.Muted))
+	}
+	return styles
+}
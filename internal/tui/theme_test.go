@@ -0,0 +1,85 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/muesli/termenv"
+)
+
+func TestBuiltinThemes(t *testing.T) {
+	names := make(map[string]bool)
+	for _, theme := range BuiltinThemes() {
+		if theme.Name == "" {
+			t.Error("expected non-empty theme name")
+		}
+		if names[theme.Name] {
+			t.Errorf("duplicate theme name %q", theme.Name)
+		}
+		names[theme.Name] = true
+	}
+
+	for _, want := range []string{DefaultThemeName, "high-contrast", "e-ink", "large-print"} {
+		if !names[want] {
+			t.Errorf("missing built-in theme %q", want)
+		}
+	}
+}
+
+func TestThemeForProfile(t *testing.T) {
+	theme := BuiltinThemes()[0]
+
+	if got := theme.ForProfile(termenv.ANSI256); got != theme {
+		t.Errorf("ForProfile(ANSI256) changed the theme: %+v", got)
+	}
+
+	mono := theme.ForProfile(termenv.Ascii)
+	if mono.Name != theme.Name {
+		t.Errorf("Name = %q, want %q", mono.Name, theme.Name)
+	}
+	if mono.Text != "" || mono.Background != "" || mono.Accent != "" {
+		t.Errorf("expected colors to be dropped, got %+v", mono)
+	}
+}
+
+func TestLoadThemes(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"sepia.json": `{"name": "sepia", "text": "#5b4636", "background": "#f4ecd8"}`,
+		"night.json": `{"text": "#cccccc"}`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("write theme: %v", err)
+		}
+	}
+
+	themes, err := LoadThemes(dir)
+	if err != nil {
+		t.Fatalf("LoadThemes failed: %v", err)
+	}
+	if len(themes) != 2 {
+		t.Fatalf("got %d themes, want 2", len(themes))
+	}
+	if themes[0].Name != "night" {
+		t.Errorf("Name = %q, want name derived from file %q", themes[0].Name, "night")
+	}
+	if themes[1].Text != "#5b4636" {
+		t.Errorf("Text = %q, want %q", themes[1].Text, "#5b4636")
+	}
+}
+
+func TestWithThemesReplacesBuiltin(t *testing.T) {
+	m := Model{themes: BuiltinThemes()}
+	WithThemes([]Theme{{Name: "e-ink", Text: "#111111"}, {Name: "sepia"}})(&m)
+
+	if len(m.themes) != len(BuiltinThemes())+1 {
+		t.Errorf("got %d themes, want %d", len(m.themes), len(BuiltinThemes())+1)
+	}
+	for _, theme := range m.themes {
+		if theme.Name == "e-ink" && theme.Text != "#111111" {
+			t.Errorf("e-ink Text = %q, want %q", theme.Text, "#111111")
+		}
+	}
+}
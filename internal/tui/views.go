@@ -7,58 +7,6 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
-// Styles
-var (
-	titleStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("205")).
-			MarginBottom(1)
-
-	headerStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("39")).
-			Background(lipgloss.Color("236")).
-			Padding(0, 2).
-			MarginBottom(1)
-
-	selectedStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("170")).
-			Background(lipgloss.Color("236")).
-			Bold(true).
-			Padding(0, 1)
-
-	normalStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("252")).
-			Padding(0, 1)
-
-	statusStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("241")).
-			MarginTop(1)
-
-	helpStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("241")).
-			MarginTop(1)
-
-	userMessageStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("82")).
-				Bold(true)
-
-	aiMessageStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("39"))
-
-	pageNumStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("39")).
-			Bold(true)
-
-	inputStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("62")).
-			Padding(0, 1)
-
-	spinnerStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("205"))
-)
-
 // RenderView renders the appropriate view based on the current mode.
 func RenderView(m Model) string {
 	var content string
@@ -72,10 +20,24 @@ func RenderView(m Model) string {
 		content = renderStoryView(m)
 	case ModeChat:
 		content = renderChat(m)
+	case ModeQuiz:
+		content = renderQuiz(m)
+	case ModeWords:
+		content = renderWords(m)
+	case ModeReview:
+		content = renderReview(m)
+	case ModeParent:
+		content = renderParent(m)
+	case ModeLocked:
+		content = renderLocked(m)
 	}
 
-	// Add status bar
-	status := statusStyle.Render(m.statusMessage)
+	// Add status bar, with the screen-time timer when there is a limit
+	status := m.statusMessage
+	if timer := screenTimer(m); timer != "" {
+		status = strings.TrimSpace(timer + "  " + status)
+	}
+	status = m.styles.Status.Render(status)
 
 	return lipgloss.JoinVertical(lipgloss.Left, content, status)
 }
@@ -83,28 +45,36 @@ func RenderView(m Model) string {
 func renderUserSelection(m Model) string {
 	var b strings.Builder
 
-	b.WriteString(headerStyle.Render("Illustrated Primer - Select User"))
+	b.WriteString(m.styles.Header.Render("Illustrated Primer - Select User"))
 	b.WriteString("\n\n")
 
 	if len(m.users) == 0 {
-		b.WriteString(normalStyle.Render("No users found. Run with --seed to load sample data."))
+		b.WriteString(m.styles.Normal.Render("No users found. Run with --seed to load sample data."))
 	} else {
-		for i, user := range m.users {
+		for i, user := range m.selectableUsers() {
 			name := user.DisplayName()
 			email := user.DisplayEmail()
 			line := fmt.Sprintf("%s <%s>", name, email)
+			if user.IsGuardian() {
+				line += " (grown-up)"
+			}
 
 			if i == m.selectedIndex {
-				b.WriteString(selectedStyle.Render("▸ " + line))
+				b.WriteString(m.styles.Selected.Render("▸ " + line))
 			} else {
-				b.WriteString(normalStyle.Render("  " + line))
+				b.WriteString(m.styles.Normal.Render("  " + line))
 			}
 			b.WriteString("\n")
 		}
+		if m.switching != nil {
+			b.WriteString("\n")
+			b.WriteString(m.textInput.View())
+			b.WriteString("\n")
+		}
 	}
 
 	b.WriteString("\n")
-	b.WriteString(helpStyle.Render("↑/↓: navigate • enter: select • q: quit"))
+	b.WriteString(renderHelp(m))
 
 	return b.String()
 }
@@ -117,37 +87,81 @@ func renderStoryList(m Model) string {
 		userName = m.currentUser.DisplayName()
 	}
 
-	b.WriteString(headerStyle.Render(fmt.Sprintf("Stories - %s", userName)))
+	b.WriteString(m.styles.Header.Render(fmt.Sprintf("Stories - %s", userName)))
+	b.WriteString("\n")
+	meta := fmt.Sprintf("Sorted by %s", m.storySort)
+	if m.storyFilter != "" {
+		meta += fmt.Sprintf(" • Filter: %q", m.storyFilter)
+	}
+	if n := len(m.dueWords); n > 0 {
+		meta += fmt.Sprintf(" • %d words to practise (%s)", n, m.keys.Practice.Help().Key)
+	}
+	b.WriteString(m.styles.Help.Render(meta))
 	b.WriteString("\n\n")
 
-	if m.textInput.Focused() {
-		b.WriteString(normalStyle.Render("New Story Title:"))
+	if m.storyInput != storyInputNone {
+		b.WriteString(m.styles.Normal.Render(storyInputLabel(m.storyInput)))
 		b.WriteString("\n")
-		b.WriteString(inputStyle.Render(m.textInput.View()))
+		b.WriteString(m.styles.Input.Render(m.textInput.View()))
 		b.WriteString("\n\n")
 	}
+	if m.picker != nil {
+		b.WriteString(renderObjectivePicker(m))
+	}
 
+	visible := m.visibleStories()
 	if len(m.stories) == 0 {
-		b.WriteString(normalStyle.Render("No stories yet. Press 'n' to create one."))
+		b.WriteString(m.styles.Normal.Render(fmt.Sprintf("No stories yet. Press '%s' to create one.", m.keys.NewStory.Help().Key)))
+	} else if len(visible) == 0 {
+		b.WriteString(m.styles.Normal.Render("No stories match the filter."))
 	} else {
-		for i, story := range m.stories {
+		for i, story := range visible {
 			line := fmt.Sprintf("%s - %s", story.Title, story.PageCountDisplay())
 
 			if i == m.selectedIndex {
-				b.WriteString(selectedStyle.Render("▸ " + line))
+				b.WriteString(m.styles.Selected.Render("▸ " + line))
+				if story.Summary != "" {
+					b.WriteString("\n")
+					b.WriteString(m.styles.Help.UnsetMarginTop().Render("    " + story.Summary))
+				}
 			} else {
-				b.WriteString(normalStyle.Render("  " + line))
+				b.WriteString(m.styles.Normal.Render("  " + line))
 			}
 			b.WriteString("\n")
 		}
 	}
 
 	b.WriteString("\n")
-	b.WriteString(helpStyle.Render("↑/↓: navigate • enter: select • n: new story • esc: back • q: quit"))
+	b.WriteString(renderHelp(m))
 
 	return b.String()
 }
 
+// renderHelp renders the key help for the current mode.
+func renderHelp(m Model) string {
+	return m.styles.Help.Render(m.help.View(m.keys.HelpFor(m.mode)))
+}
+
+// storyInputLabel returns the prompt shown above the story list text input.
+func storyInputLabel(kind storyInputKind) string {
+	switch kind {
+	case storyInputRename:
+		return "Rename Story:"
+	case storyInputSummary:
+		return "Story Summary:"
+	case storyInputArtStyle:
+		return "Art Style:"
+	case storyInputPromptTemplate:
+		return "Prompt Template:"
+	case storyInputFilter:
+		return "Filter:"
+	case storyInputGuardianPIN:
+		return "Grown-ups Only:"
+	default:
+		return "New Story Title:"
+	}
+}
+
 func renderStoryView(m Model) string {
 	var b strings.Builder
 
@@ -156,31 +170,77 @@ func renderStoryView(m Model) string {
 		title = m.currentStory.Title
 	}
 
-	b.WriteString(headerStyle.Render(fmt.Sprintf("Story: %s", title)))
+	b.WriteString(m.styles.Header.Render(fmt.Sprintf("Story: %s", title)))
 	b.WriteString("\n\n")
 
-	if len(m.pages) == 0 {
-		b.WriteString(normalStyle.Render("No pages yet. Press Enter to start the story."))
+	if m.reading && m.readIndex < len(m.pages) {
+		b.WriteString(renderReadAloud(m))
+	} else if len(m.pages) == 0 {
+		b.WriteString(m.styles.Normal.Render(fmt.Sprintf("No pages yet. Press %s to start the story.", m.keys.StartChat.Help().Key)))
 	} else {
+		known := make(map[string]bool)
+		for _, word := range m.glossaryWords() {
+			known[word] = true
+		}
 		for _, page := range m.pages {
 			// Page header
-			b.WriteString(pageNumStyle.Render(fmt.Sprintf("--- Page %d ---", page.PageNum)))
+			b.WriteString(m.styles.PageNum.Render(fmt.Sprintf("--- Page %d ---", page.PageNum)))
 			b.WriteString("\n\n")
 
+			// Illustration above the page text
+			if art := m.renderIllustration(page); art != "" {
+				b.WriteString(art)
+				b.WriteString("\n\n")
+			}
+
 			// User prompt
-			b.WriteString(userMessageStyle.Render("You: "))
+			b.WriteString(m.styles.UserMessage.Render("You: "))
 			b.WriteString(wrapText(page.Prompt, m.width-10))
 			b.WriteString("\n\n")
 
 			// AI completion
-			b.WriteString(aiMessageStyle.Render("AI: "))
-			b.WriteString(wrapText(page.Completion, m.width-10))
+			b.WriteString(m.styles.AIMessage.Render("AI: "))
+			b.WriteString(highlightWords(m.renderCompletion(page.Completion, m.width-10), known, m.styles.Word))
 			b.WriteString("\n\n")
 		}
+		if m.glossary {
+			b.WriteString(renderGlossary(m))
+		}
 	}
 
 	b.WriteString("\n")
-	b.WriteString(helpStyle.Render("enter: start chat • esc: back"))
+	b.WriteString(renderHelp(m))
+
+	return b.String()
+}
+
+// renderReadAloud shows only the page being read aloud, with the sentence
+// being spoken highlighted when timing is available.
+func renderReadAloud(m Model) string {
+	var b strings.Builder
+	page := m.pages[m.readIndex]
+
+	state := "▶"
+	switch {
+	case m.playback == nil:
+		state = m.spinner.View()
+	case m.readPaused:
+		state = "❚❚"
+	}
+	b.WriteString(m.styles.PageNum.Render(fmt.Sprintf("%s Page %d of %d", state, m.readIndex+1, len(m.pages))))
+	b.WriteString("\n\n")
+
+	if art := m.renderIllustration(page); art != "" {
+		b.WriteString(art)
+		b.WriteString("\n\n")
+	}
+
+	if len(m.cues) > 0 {
+		b.WriteString(highlightSentences(m.cues, m.cueIndex, m.width-10, m.styles))
+	} else {
+		b.WriteString(m.renderCompletion(page.Completion, m.width-10))
+	}
+	b.WriteString("\n\n")
 
 	return b.String()
 }
@@ -193,90 +253,64 @@ func renderChat(m Model) string {
 		title = m.currentStory.Title
 	}
 
-	b.WriteString(headerStyle.Render(fmt.Sprintf("Chat: %s", title)))
+	b.WriteString(m.styles.Header.Render(fmt.Sprintf("Chat: %s", title)))
 	b.WriteString("\n\n")
 
 	// Render conversation history
 	for i := 0; i < len(m.conversationHistory); i += 2 {
 		// User message
-		b.WriteString(userMessageStyle.Render("You: "))
+		b.WriteString(m.styles.UserMessage.Render("You: "))
 		if i < len(m.conversationHistory) {
 			b.WriteString(wrapText(m.conversationHistory[i], m.width-10))
 		}
 		b.WriteString("\n\n")
 
 		// AI message
-		b.WriteString(aiMessageStyle.Render("AI: "))
+		b.WriteString(m.styles.AIMessage.Render("AI: "))
 		if i+1 < len(m.conversationHistory) {
-			b.WriteString(wrapText(m.conversationHistory[i+1], m.width-10))
+			b.WriteString(m.renderCompletion(m.conversationHistory[i+1], m.width-10))
 		}
 		b.WriteString("\n\n")
 	}
 
+	if !m.isLoading {
+		b.WriteString(renderChoices(m))
+	}
+
 	// Show pending message if loading
 	if m.isLoading && m.inputBuffer != "" {
-		b.WriteString(userMessageStyle.Render("You: "))
+		b.WriteString(m.styles.UserMessage.Render("You: "))
 		b.WriteString(wrapText(m.inputBuffer, m.width-10))
 		b.WriteString("\n\n")
 
-		b.WriteString(aiMessageStyle.Render("AI: "))
-		if m.streamingResponse != "" {
-			b.WriteString(wrapText(m.streamingResponse, m.width-10))
+		b.WriteString(m.styles.AIMessage.Render("AI: "))
+		if m.streamRendered != "" {
+			b.WriteString(m.streamRendered)
 		}
-		b.WriteString(spinnerStyle.Render(m.spinner.View()))
+		b.WriteString(m.styles.Spinner.Render(m.spinner.View()))
 		b.WriteString("\n\n")
 	}
 
 	// Input area
 	b.WriteString("\n")
 	if m.isLoading {
-		b.WriteString(normalStyle.Render("Waiting for response..."))
+		b.WriteString(m.styles.Normal.Render("Waiting for response..."))
 	} else {
-		b.WriteString(inputStyle.Render(m.textInput.View()))
+		b.WriteString(m.styles.Input.Render(m.composer.View()))
+		b.WriteString("\n")
+		switch {
+		case m.listening:
+			b.WriteString(m.styles.Highlight.Render("● Listening..."))
+		case m.transcribing:
+			b.WriteString(m.styles.Spinner.Render(m.spinner.View()))
+			b.WriteString(m.styles.Normal.Render(" Writing down what you said..."))
+		default:
+			b.WriteString(m.styles.Status.UnsetMarginTop().Render(composerCounter(m.composer.Value())))
+		}
 	}
 
 	b.WriteString("\n\n")
-	b.WriteString(helpStyle.Render("enter: send • esc: back"))
+	b.WriteString(renderHelp(m))
 
 	return b.String()
 }
-
-// wrapText wraps text to the specified width.
-func wrapText(text string, width int) string {
-	if width <= 0 {
-		width = 80
-	}
-
-	var result strings.Builder
-	lines := strings.Split(text, "\n")
-
-	for i, line := range lines {
-		if i > 0 {
-			result.WriteString("\n")
-		}
-
-		words := strings.Fields(line)
-		currentLineLen := 0
-
-		for j, word := range words {
-			wordLen := len(word)
-
-			if currentLineLen+wordLen+1 > width && currentLineLen > 0 {
-				result.WriteString("\n")
-				currentLineLen = 0
-			}
-
-			if currentLineLen > 0 {
-				result.WriteString(" ")
-				currentLineLen++
-			}
-
-			result.WriteString(word)
-			currentLineLen += wordLen
-
-			_ = j // avoid unused variable warning
-		}
-	}
-
-	return result.String()
-}
@@ -0,0 +1,351 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/kbrakke/illustrated-primer/internal/ai"
+	"github.com/kbrakke/illustrated-primer/internal/db"
+	"github.com/kbrakke/illustrated-primer/internal/models"
+	"github.com/kbrakke/illustrated-primer/internal/vocab"
+)
+
+type vocabularyLoadedMsg struct {
+	userID  string
+	entries []models.VocabularyEntry
+	err     error
+}
+
+type wordDefinedMsg struct {
+	word    string
+	text    string     // the definition as written, before moderation
+	verdict ai.Verdict // zero for cached definitions and without moderation
+	err     error
+}
+
+// band returns the current child's age band, used to pick words and pitch
+// their definitions.
+func (m Model) band() models.AgeBand {
+	if m.currentUser == nil {
+		return ""
+	}
+	return m.currentUser.Band(time.Now())
+}
+
+// language returns the current child's language, or "" if it isn't set.
+func (m Model) language() string {
+	if m.currentUser == nil || m.currentUser.Language == nil {
+		return ""
+	}
+	return *m.currentUser.Language
+}
+
+// storyWords returns the challenging words in text for the current child,
+// or none if their stories aren't in a language words can be picked from.
+func (m Model) storyWords(text string) []string {
+	if !vocab.Supports(m.language()) {
+		return nil
+	}
+	return vocab.Extract(text, m.band())
+}
+
+// loadVocabulary loads the current child's words.
+func (m Model) loadVocabulary() tea.Cmd {
+	if m.currentUser == nil {
+		return nil
+	}
+	userID, band := m.currentUser.ID, m.band()
+	return func() tea.Msg {
+		entries, err := m.db.ListVocabulary(context.Background(), userID, band)
+		return vocabularyLoadedMsg{userID: userID, entries: entries, err: err}
+	}
+}
+
+// glossaryWords returns the challenging words in the current story, in the
+// order they first appear.
+func (m Model) glossaryWords() []string {
+	var words []string
+	seen := make(map[string]bool)
+	for _, page := range m.pages {
+		for _, word := range m.storyWords(page.Completion) {
+			if !seen[word] {
+				seen[word] = true
+				words = append(words, word)
+			}
+		}
+	}
+	return words
+}
+
+// sentenceFor returns the first sentence in the current story using word.
+func (m Model) sentenceFor(word string) string {
+	for _, page := range m.pages {
+		if sentence := vocab.Sentence(page.Completion, word); sentence != "" {
+			return sentence
+		}
+	}
+	return ""
+}
+
+// defineWord looks up a word's definition for the child's age band, asking
+// the model and caching its answer if it hasn't been defined before. It
+// returns nil if the definition is known or already being written.
+func (m *Model) defineWord(word, sentence string) tea.Cmd {
+	if _, ok := m.definitions[word]; ok || m.defining[word] {
+		return nil
+	}
+	m.defining[word] = true
+
+	client, guard := m.aiClient, m.guard
+	pc := ai.NewPromptContext(m.currentUser, m.currentStory, time.Now())
+	return func() tea.Msg {
+		ctx := context.Background()
+		cached, err := m.db.GetDefinition(ctx, word, pc.Band)
+		if err == nil {
+			return wordDefinedMsg{word: word, text: cached.Text, verdict: ai.Verdict{Action: ai.ActionAllow, Text: cached.Text}}
+		}
+		if !errors.Is(err, db.ErrDefinitionNotFound) {
+			return wordDefinedMsg{word: word, err: err}
+		}
+
+		text, tokens, err := ai.DefineWord(ctx, client, pc, word, sentence)
+		m.recordUsage(ctx, models.UsageDefinition, tokens)
+		if err != nil {
+			return wordDefinedMsg{word: word, err: err}
+		}
+		msg := wordDefinedMsg{word: word, text: text, verdict: ai.Verdict{Action: ai.ActionAllow, Text: text}}
+		if guard != nil {
+			msg.verdict, msg.err = guard.Check(ctx, ai.SourceCompletion, text)
+			if msg.err != nil || msg.verdict.Action == ai.ActionBlock {
+				return msg
+			}
+		}
+		msg.err = m.db.SaveDefinition(ctx, models.NewDefinition(word, pc.Band, msg.verdict.Text))
+		return msg
+	}
+}
+
+// updateVocabulary handles vocabulary messages.
+func (m Model) updateVocabulary(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case vocabularyLoadedMsg:
+		if m.currentUser == nil || m.currentUser.ID != msg.userID {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.statusMessage = fmt.Sprintf("Error loading words: %v", msg.err)
+			m.logger.Error("failed to load vocabulary", "error", msg.err)
+			return m, nil
+		}
+		m.vocabulary = msg.entries
+		for _, entry := range msg.entries {
+			if entry.Definition != "" {
+				m.definitions[entry.Word.Word] = entry.Definition
+			}
+		}
+		m.wordIndex = min(m.wordIndex, max(len(m.vocabulary)-1, 0))
+		if m.mode == ModeWords {
+			return m, m.defineSelectedWord()
+		}
+
+	case wordDefinedMsg:
+		delete(m.defining, msg.word)
+		if msg.err != nil {
+			m.statusMessage = fmt.Sprintf("Couldn't explain %q: %v", msg.word, msg.err)
+			m.logger.Error("failed to define word", "word", msg.word, "error", msg.err)
+			return m, nil
+		}
+
+		record := m.recordModeration(ai.SourceCompletion, msg.text, msg.verdict)
+		if msg.verdict.Action == ai.ActionBlock {
+			m.logger.Warn("definition blocked", "word", msg.word)
+			return m, record
+		}
+		m.definitions[msg.word] = msg.verdict.Text
+		return m, record
+	}
+	return m, nil
+}
+
+// toggleGlossary shows or hides the definitions of the highlighted words in
+// the story view, looking up the ones not yet known.
+func (m *Model) toggleGlossary() tea.Cmd {
+	m.glossary = !m.glossary
+	if !m.glossary {
+		return nil
+	}
+
+	words := m.glossaryWords()
+	if len(words) == 0 {
+		m.glossary = false
+		m.statusMessage = "No tricky words in this story yet."
+		return nil
+	}
+	cmds := []tea.Cmd{m.spinner.Tick}
+	for _, word := range words {
+		cmds = append(cmds, m.defineWord(word, m.sentenceFor(word)))
+	}
+	return tea.Batch(cmds...)
+}
+
+// defineSelectedWord looks up the definition of the word selected in "my
+// words" if it isn't known yet.
+func (m *Model) defineSelectedWord() tea.Cmd {
+	if m.wordIndex >= len(m.vocabulary) {
+		return nil
+	}
+	return m.defineWord(m.vocabulary[m.wordIndex].Word.Word, "")
+}
+
+// handleWordsKeys moves through the child's word list.
+func (m Model) handleWordsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		if m.wordIndex > 0 {
+			m.wordIndex--
+			return m, m.defineSelectedWord()
+		}
+	case key.Matches(msg, m.keys.Down):
+		if m.wordIndex < len(m.vocabulary)-1 {
+			m.wordIndex++
+			return m, m.defineSelectedWord()
+		}
+	case key.Matches(msg, m.keys.Back):
+		m.mode = ModeStoryList
+		m.statusMessage = ""
+	}
+	return m, nil
+}
+
+// renderGlossary lists the highlighted words in the story with their
+// definitions.
+func renderGlossary(m Model) string {
+	var b strings.Builder
+	b.WriteString(m.styles.PageNum.Render("Words in this story"))
+	b.WriteString("\n\n")
+	for _, word := range m.glossaryWords() {
+		b.WriteString(m.styles.Normal.Render(m.styles.Word.Render(word) + ": " + m.definitionText(word)))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// definitionText returns a word's definition, or what is happening to it.
+func (m Model) definitionText(word string) string {
+	switch {
+	case m.definitions[word] != "":
+		return m.definitions[word]
+	case m.defining[word]:
+		return m.spinner.View() + " looking it up..."
+	default:
+		return "no definition yet"
+	}
+}
+
+// renderWords shows the child's word list, with the definition of the
+// selected word and where it was first seen.
+func renderWords(m Model) string {
+	var b strings.Builder
+
+	userName := ""
+	if m.currentUser != nil {
+		userName = m.currentUser.DisplayName()
+	}
+	b.WriteString(m.styles.Header.Render(fmt.Sprintf("My Words - %s", userName)))
+	b.WriteString("\n\n")
+
+	if len(m.vocabulary) == 0 {
+		b.WriteString(m.styles.Normal.Render("No words yet. New words from your stories will appear here."))
+		b.WriteString("\n")
+	}
+	for i, entry := range m.vocabulary {
+		line := entry.Word.Word
+		if i != m.wordIndex {
+			b.WriteString(m.styles.Normal.Render("  " + line))
+			b.WriteString("\n")
+			continue
+		}
+
+		b.WriteString(m.styles.Selected.Render("▸ " + line))
+		b.WriteString("\n")
+		b.WriteString(m.styles.Normal.Render("    " + wrapText(m.definitionText(line), m.width-14)))
+		b.WriteString("\n")
+		b.WriteString(m.styles.Help.UnsetMarginTop().Render("    " + wordSeen(entry)))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(renderHelp(m))
+	return b.String()
+}
+
+// wordSeen describes where a word was first seen and how often since.
+func wordSeen(entry models.VocabularyEntry) string {
+	times := "once"
+	if entry.TimesSeen > 1 {
+		times = fmt.Sprintf("%d times", entry.TimesSeen)
+	}
+	if entry.StoryTitle == "" {
+		return fmt.Sprintf("Seen %s", times)
+	}
+	return fmt.Sprintf("First seen in %q, page %d • seen %s", entry.StoryTitle, entry.PageNum, times)
+}
+
+// highlightWords styles the words of rendered text that are in words,
+// leaving ANSI escape sequences already in the text untouched.
+func highlightWords(rendered string, words map[string]bool, style lipgloss.Style) string {
+	if len(words) == 0 {
+		return rendered
+	}
+
+	var b strings.Builder
+	for s := rendered; s != ""; {
+		if n := escapeLen(s); n > 0 {
+			b.WriteString(s[:n])
+			s = s[n:]
+			continue
+		}
+		n := wordLen(s)
+		if n == 0 {
+			_, size := utf8.DecodeRuneInString(s)
+			b.WriteString(s[:size])
+			s = s[size:]
+			continue
+		}
+		if word := s[:n]; words[vocab.Normalize(word)] {
+			b.WriteString(style.Render(word))
+		} else {
+			b.WriteString(word)
+		}
+		s = s[n:]
+	}
+	return b.String()
+}
+
+// wordLen returns the length of the word at the start of s: letters, with
+// inner apostrophes and hyphens.
+func wordLen(s string) int {
+	n := 0
+	for n < len(s) {
+		r, size := utf8.DecodeRuneInString(s[n:])
+		if !unicode.IsLetter(r) {
+			if (r == '\'' || r == '’' || r == '-') && n > 0 {
+				if next, _ := utf8.DecodeRuneInString(s[n+size:]); unicode.IsLetter(next) {
+					n += size
+					continue
+				}
+			}
+			break
+		}
+		n += size
+	}
+	return n
+}
@@ -0,0 +1,149 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/kbrakke/illustrated-primer/internal/ai"
+	"github.com/kbrakke/illustrated-primer/internal/models"
+	"github.com/kbrakke/illustrated-primer/testutil"
+)
+
+func TestHighlightWords(t *testing.T) {
+	upper := lipgloss.NewStyle().Transform(strings.ToUpper)
+	known := map[string]bool{"caterpillar": true, "cocoon": true}
+
+	got := highlightWords("\x1b[38;5;39mThe caterpillar's\x1b[0m caterpillar-ish cocoon.", known, upper)
+	if want := "\x1b[38;5;39mThe CATERPILLAR'S\x1b[0m caterpillar-ish COCOON."; got != want {
+		t.Errorf("highlightWords() = %q, want %q", got, want)
+	}
+	if got := highlightWords("plain", nil, upper); got != "plain" {
+		t.Errorf("highlightWords() without words = %q", got)
+	}
+}
+
+func TestGlossary(t *testing.T) {
+	m := newStoryTestModel(testutil.NewMockAIClient(""), ModeStoryView, withUser(models.User{ID: "user"}),
+		withPages("The fox found an enormous caterpillar.", "The caterpillar was magnificent."))
+	m.definitions["enormous"] = "Very, very big."
+
+	if view := renderStoryView(m); strings.Contains(view, "Words in this story") {
+		t.Fatalf("glossary shown before it was asked for:\n%s", view)
+	}
+
+	m, cmd := press(m, "g")
+	if !m.glossary || cmd == nil {
+		t.Fatalf("glossary = %v; want it open and missing definitions looked up", m.glossary)
+	}
+	if m.defining["enormous"] || !m.defining["caterpillar"] || !m.defining["magnificent"] {
+		t.Errorf("defining = %v, want only the unknown words", m.defining)
+	}
+	if words := strings.Join(m.glossaryWords(), ","); words != "caterpillar,enormous,magnificent" {
+		t.Errorf("glossaryWords() = %s", words)
+	}
+	if sentence := m.sentenceFor("magnificent"); sentence != "The caterpillar was magnificent." {
+		t.Errorf("sentenceFor() = %q", sentence)
+	}
+
+	updated, _ := m.Update(wordDefinedMsg{word: "caterpillar", text: "A baby butterfly.",
+		verdict: ai.Verdict{Action: ai.ActionAllow, Text: "A baby butterfly."}})
+	m = updated.(Model)
+	view := renderStoryView(m)
+	for _, want := range []string{"Words in this story", "caterpillar: A baby butterfly.", "enormous: Very, very big.", "magnificent:", "looking it up"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("glossary missing %q:\n%s", want, view)
+		}
+	}
+
+	m, _ = press(m, "g")
+	if m.glossary {
+		t.Error("g should close the glossary")
+	}
+}
+
+func TestGlossaryModerated(t *testing.T) {
+	m := newStoryTestModel(testutil.NewMockAIClient(""), ModeStoryView, withUser(models.User{ID: "user"}),
+		withPages("The fox found an enormous caterpillar.", "The caterpillar was magnificent."))
+	m, _ = press(m, "g")
+
+	updated, cmd := m.Update(wordDefinedMsg{word: "caterpillar", text: "Email fox@example.com",
+		verdict: ai.Verdict{Action: ai.ActionBlock}})
+	m = updated.(Model)
+	if _, ok := m.definitions["caterpillar"]; ok || m.defining["caterpillar"] {
+		t.Errorf("blocked definition kept: %q", m.definitions["caterpillar"])
+	}
+	if cmd == nil {
+		t.Error("blocked definition should be recorded for parents")
+	}
+}
+
+func TestGlossaryWithoutWords(t *testing.T) {
+	m := newStoryTestModel(testutil.NewMockAIClient(""), ModeStoryView, withUser(models.User{ID: "user"}), withPages("The fox found a door. The owl knocked."))
+	m, cmd := press(m, "g")
+	if m.glossary || cmd != nil || m.statusMessage == "" {
+		t.Errorf("glossary = %v, status %q; want it closed with an explanation", m.glossary, m.statusMessage)
+	}
+}
+
+func TestGlossaryOnlyEnglish(t *testing.T) {
+	m := newStoryTestModel(testutil.NewMockAIClient(""), ModeStoryView, withUser(models.User{ID: "user"}),
+		withPages("The fox found an enormous caterpillar.", "The caterpillar was magnificent."))
+	japanese := "ja"
+	m.currentUser.Language = &japanese
+	m.pages = []models.Page{*models.NewPage("story", 1, "おはなしして", "むかしむかし、あるところに小さなきつねがすんでいました。")}
+	if words := m.glossaryWords(); len(words) != 0 {
+		t.Errorf("glossaryWords() = %q, want none for a Japanese page", words)
+	}
+	if words := m.storyWords(m.pages[0].Completion); words != nil {
+		t.Errorf("storyWords() = %q, want nothing recorded for a Japanese page", words)
+	}
+}
+
+func TestMyWords(t *testing.T) {
+	m := newTestModel(testutil.NewMockAIClient(""), withUser(models.User{ID: "user"}))
+	m.mode = ModeStoryList
+
+	m, cmd := press(m, "m")
+	if m.mode != ModeWords || cmd == nil {
+		t.Fatalf("mode = %v, want my words while they load", m.mode)
+	}
+
+	storyID := "story"
+	entries := []models.VocabularyEntry{
+		{Word: models.Word{UserID: "user", Word: "caterpillar", FirstStoryID: &storyID, TimesSeen: 3},
+			StoryTitle: "Fox", PageNum: 1, Definition: "A baby butterfly."},
+		{Word: models.Word{UserID: "user", Word: "enormous", TimesSeen: 1}},
+	}
+	updated, cmd := m.Update(vocabularyLoadedMsg{userID: "user", entries: entries})
+	m = updated.(Model)
+	if len(m.vocabulary) != 2 || m.definitions["caterpillar"] != "A baby butterfly." {
+		t.Fatalf("vocabulary = %+v, definitions %v", m.vocabulary, m.definitions)
+	}
+	if cmd != nil {
+		t.Error("a selected word with a definition needs no lookup")
+	}
+	view := renderWords(m)
+	for _, want := range []string{"My Words", "▸ caterpillar", "A baby butterfly.", `First seen in "Fox", page 1 • seen 3 times`, "enormous"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("word list missing %q:\n%s", want, view)
+		}
+	}
+
+	m, cmd = press(m, "j")
+	if m.wordIndex != 1 || cmd == nil || !m.defining["enormous"] {
+		t.Errorf("moving to an undefined word should look it up; index %d", m.wordIndex)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m = updated.(Model); m.mode != ModeStoryList {
+		t.Errorf("mode = %v, want the story list", m.mode)
+	}
+
+	// Words loaded for another child are ignored.
+	updated, _ = m.Update(vocabularyLoadedMsg{userID: "other", entries: entries[:1]})
+	if m = updated.(Model); len(m.vocabulary) != 2 {
+		t.Errorf("vocabulary replaced by another child's words")
+	}
+}
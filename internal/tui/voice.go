@@ -0,0 +1,151 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kbrakke/illustrated-primer/internal/voice"
+)
+
+const (
+	// maxRecording stops a recording that was never stopped by hand, so a
+	// forgotten microphone doesn't record forever.
+	maxRecording = time.Minute
+
+	// transcriptionTimeout bounds turning a recording into text.
+	transcriptionTimeout = time.Minute
+)
+
+type recordingStartedMsg struct {
+	id        int
+	recording *voice.Recording
+	err       error
+}
+
+type recordingTimeoutMsg struct {
+	id int
+}
+
+type transcribedMsg struct {
+	text string
+	err  error
+}
+
+// toggleRecording starts recording the child's prompt, or stops the
+// recording in progress and transcribes it.
+func (m *Model) toggleRecording() tea.Cmd {
+	switch {
+	case m.recorder == nil || m.transcriber == nil:
+		m.statusMessage = "Voice input is not set up"
+		return nil
+	case m.transcribing:
+		return nil
+	case m.listening:
+		if m.recording == nil {
+			// Still starting; the next press will stop it.
+			return nil
+		}
+		return m.finishRecording()
+	}
+
+	m.listening = true
+	m.recordID++
+	m.statusMessage = "Listening..."
+	id, recorder := m.recordID, m.recorder
+	return func() tea.Msg {
+		rec, err := recorder.Start()
+		return recordingStartedMsg{id: id, recording: rec, err: err}
+	}
+}
+
+// finishRecording stops the recording and transcribes it in the background.
+func (m *Model) finishRecording() tea.Cmd {
+	rec, transcriber := m.recording, m.transcriber
+	m.recording = nil
+	m.listening = false
+	m.transcribing = true
+	m.recordID++
+	m.statusMessage = "Writing down what you said..."
+
+	return tea.Batch(m.spinner.Tick, func() tea.Msg {
+		path, err := rec.Stop()
+		if err != nil {
+			return transcribedMsg{err: err}
+		}
+		defer os.Remove(path)
+
+		ctx, cancel := context.WithTimeout(context.Background(), transcriptionTimeout)
+		defer cancel()
+
+		text, err := transcriber.Transcribe(ctx, path)
+		return transcribedMsg{text: text, err: err}
+	})
+}
+
+// cancelRecording discards the recording in progress.
+func (m *Model) cancelRecording() {
+	if m.recording != nil {
+		m.recording.Cancel()
+	}
+	m.recording = nil
+	m.listening = false
+	m.recordID++
+}
+
+// updateVoice handles recording and transcription messages.
+func (m Model) updateVoice(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case recordingStartedMsg:
+		if msg.id != m.recordID {
+			// Cancelled while the capture program was starting.
+			if msg.recording != nil {
+				msg.recording.Cancel()
+			}
+			return m, nil
+		}
+		if msg.err != nil {
+			m.listening = false
+			m.statusMessage = fmt.Sprintf("Recording failed: %v", msg.err)
+			m.logger.Error("failed to start recording", "error", msg.err)
+			return m, nil
+		}
+
+		m.recording = msg.recording
+		m.statusMessage = fmt.Sprintf("Listening... press %s when you're done", m.keys.Record.Help().Key)
+		id := msg.id
+		return m, tea.Tick(maxRecording, func(time.Time) tea.Msg {
+			return recordingTimeoutMsg{id: id}
+		})
+
+	case recordingTimeoutMsg:
+		if msg.id != m.recordID || m.recording == nil {
+			return m, nil
+		}
+		return m, m.finishRecording()
+
+	case transcribedMsg:
+		m.transcribing = false
+		if msg.err != nil {
+			m.statusMessage = fmt.Sprintf("Couldn't understand the recording: %v", msg.err)
+			m.logger.Error("failed to transcribe recording", "error", msg.err)
+			return m, nil
+		}
+		if msg.text == "" {
+			m.statusMessage = "Didn't hear anything, try again"
+			return m, nil
+		}
+
+		// The transcript goes into the composer rather than straight to the
+		// AI, so it can be checked and corrected before sending.
+		if value := m.composer.Value(); value != "" && value[len(value)-1] != ' ' && value[len(value)-1] != '\n' {
+			m.composer.InsertString(" ")
+		}
+		m.composer.InsertString(msg.text)
+		m.statusMessage = fmt.Sprintf("Check your message, then press %s to send", m.keys.Send.Help().Key)
+	}
+
+	return m, nil
+}
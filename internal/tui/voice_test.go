@@ -0,0 +1,157 @@
+package tui
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kbrakke/illustrated-primer/internal/voice"
+)
+
+type fakeTranscriber struct {
+	text  string
+	heard []string
+}
+
+func (t *fakeTranscriber) Transcribe(_ context.Context, path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	t.heard = append(t.heard, string(data))
+	return t.text, nil
+}
+
+func pressRecord(m Model) (Model, tea.Cmd) {
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+	return updated.(Model), cmd
+}
+
+func TestVoiceInput(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	// Stands in for arecord: writes some audio, then waits to be interrupted.
+	recorder := &voice.Recorder{Name: "sh", Args: []string{"-c", `printf RIFF > "$0"; exec sleep 10`, voice.OutputPlaceholder}}
+	transcriber := &fakeTranscriber{text: "a dragon who is afraid of the dark"}
+	m := newStoryTestModel(nil, ModeChat, WithVoiceInput(recorder, transcriber))
+	m.composer.SetValue("Tell me about")
+
+	m, cmd := pressRecord(m)
+	if !m.listening {
+		t.Fatal("first press should start listening")
+	}
+	m = run(t, m, cmd)
+	if m.recording == nil {
+		t.Fatalf("recording did not start: %s", m.statusMessage)
+	}
+
+	// Typing is held while listening.
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	if got := updated.(Model).composer.Value(); got != "Tell me about" {
+		t.Errorf("composer changed while listening: %q", got)
+	}
+
+	// Give the capture program time to write before it is stopped.
+	waitForFile(t, m.recording)
+
+	m, cmd = pressRecord(m)
+	if m.listening || !m.transcribing {
+		t.Fatal("second press should stop listening and transcribe")
+	}
+	msg := findMsg[transcribedMsg](t, cmd)
+	updated, _ = m.Update(msg)
+	m = updated.(Model)
+
+	if len(transcriber.heard) != 1 || transcriber.heard[0] != "RIFF" {
+		t.Errorf("transcriber heard %q", transcriber.heard)
+	}
+	if got := m.composer.Value(); got != "Tell me about a dragon who is afraid of the dark" {
+		t.Errorf("composer = %q", got)
+	}
+	if m.isLoading {
+		t.Error("the transcript should wait in the composer, not be sent")
+	}
+}
+
+func TestVoiceInputCancel(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	recorder := &voice.Recorder{Name: "sh", Args: []string{"-c", "exec sleep 10"}}
+	m := newStoryTestModel(nil, ModeChat, WithVoiceInput(recorder, &fakeTranscriber{}))
+
+	m, cmd := pressRecord(m)
+	m = run(t, m, cmd)
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	if m.listening || m.recording != nil || m.mode != ModeChat {
+		t.Error("esc should cancel the recording and stay in the chat")
+	}
+}
+
+func TestVoiceInputNotConfigured(t *testing.T) {
+	m := newStoryTestModel(nil, ModeChat)
+	m, cmd := pressRecord(m)
+	if cmd != nil || m.listening || !strings.Contains(m.statusMessage, "not set up") {
+		t.Errorf("expected a message about missing voice input, got %q", m.statusMessage)
+	}
+}
+
+func TestTranscriptEmpty(t *testing.T) {
+	m := newStoryTestModel(nil, ModeChat)
+	m.transcribing = true
+	updated, _ := m.Update(transcribedMsg{})
+	m = updated.(Model)
+	if m.transcribing || m.composer.Value() != "" {
+		t.Error("an empty transcript should leave the composer alone")
+	}
+}
+
+// waitForFile waits until the capture program has written to its file.
+func waitForFile(t *testing.T, rec *voice.Recording) {
+	t.Helper()
+	for i := 0; i < 200; i++ {
+		if info, err := os.Stat(rec.Path()); err == nil && info.Size() > 0 {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("capture program never wrote audio")
+}
+
+// collectMsgs runs cmd and any commands batched with it, returning their messages.
+func collectMsgs(cmd tea.Cmd) []tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+	msg := cmd()
+	if batch, ok := msg.(tea.BatchMsg); ok {
+		var msgs []tea.Msg
+		for _, c := range batch {
+			msgs = append(msgs, collectMsgs(c)...)
+		}
+		return msgs
+	}
+	return []tea.Msg{msg}
+}
+
+// findMsg runs cmd, unpacking batches, and returns the first message of type T.
+func findMsg[T tea.Msg](t *testing.T, cmd tea.Cmd) T {
+	t.Helper()
+	for _, msg := range collectMsgs(cmd) {
+		if found, ok := msg.(T); ok {
+			return found
+		}
+	}
+	var zero T
+	t.Fatalf("no %T produced", zero)
+	return zero
+}
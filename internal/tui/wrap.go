@@ -0,0 +1,203 @@
+package tui
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/rivo/uniseg"
+)
+
+// tabWidth is the number of columns a tab in leading indentation expands to.
+const tabWidth = 4
+
+// segment is an indivisible piece of a word: either one grapheme cluster or
+// one ANSI escape sequence (which has no width).
+type segment struct {
+	text   string
+	width  int
+	letter bool // a narrow letter or digit, between which breaks get a hyphen
+}
+
+// segments splits s into grapheme clusters and ANSI escape sequences.
+func segments(s string) []segment {
+	var segs []segment
+	state := -1
+	for s != "" {
+		if n := escapeLen(s); n > 0 {
+			segs = append(segs, segment{text: s[:n]})
+			s = s[n:]
+			state = -1
+			continue
+		}
+
+		var cluster string
+		var width int
+		cluster, s, width, state = uniseg.FirstGraphemeClusterInString(s, state)
+		r, _ := utf8.DecodeRuneInString(cluster)
+		segs = append(segs, segment{
+			text:   cluster,
+			width:  width,
+			letter: width == 1 && (unicode.IsLetter(r) || unicode.IsDigit(r)),
+		})
+	}
+	return segs
+}
+
+// escapeLen returns the length of the ANSI escape sequence at the start of s,
+// or 0 if s does not start with one. Unterminated sequences run to the end
+// of s so they are never split.
+func escapeLen(s string) int {
+	if len(s) < 2 || s[0] != '\x1b' {
+		return 0
+	}
+
+	switch s[1] {
+	case '[': // CSI: parameters, then a final byte in 0x40–0x7E
+		for i := 2; i < len(s); i++ {
+			if s[i] >= 0x40 && s[i] <= 0x7e {
+				return i + 1
+			}
+		}
+		return len(s)
+	case ']', 'P', '_', '^', 'X': // OSC, DCS, APC, PM, SOS: terminated by BEL or ST (ESC \)
+		for i := 2; i < len(s); i++ {
+			if s[i] == '\a' {
+				return i + 1
+			}
+			if s[i] == '\x1b' && i+1 < len(s) && s[i+1] == '\\' {
+				return i + 2
+			}
+		}
+		return len(s)
+	default: // two-character sequence; don't split a multibyte rune after ESC
+		_, size := utf8.DecodeRuneInString(s[1:])
+		return 1 + size
+	}
+}
+
+// displayWidth returns the number of terminal columns s occupies, ignoring
+// ANSI escape sequences.
+func displayWidth(s string) int {
+	width := 0
+	for _, seg := range segments(s) {
+		width += seg.width
+	}
+	return width
+}
+
+// wrapText wraps text to the specified display width. Widths are measured in
+// terminal columns per grapheme cluster, so accented letters, CJK characters
+// and emoji wrap correctly, and ANSI escape sequences are never split or
+// counted. Leading indentation of each line is kept on its continuation
+// lines. Words longer than a line are broken, with a hyphen between letters.
+func wrapText(text string, width int) string {
+	if width <= 0 {
+		width = 80
+	}
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		body := strings.TrimLeft(line, " \t")
+		if body == "" {
+			lines[i] = ""
+			continue
+		}
+		indent := strings.ReplaceAll(line[:len(line)-len(body)], "\t", strings.Repeat(" ", tabWidth))
+		lines[i] = wrapLine(body, width, indent, indent)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// wrapLine wraps a single line of words. The first output line starts with
+// prefix and continuation lines with indent.
+func wrapLine(line string, width int, prefix, indent string) string {
+	// Always leave room for at least one wide character and a hyphen.
+	if minWidth := max(displayWidth(prefix), displayWidth(indent)) + 2; width < minWidth {
+		width = minWidth
+	}
+
+	var b strings.Builder
+	b.WriteString(prefix)
+	col := displayWidth(prefix)
+	start := col
+
+	newline := func() {
+		b.WriteString("\n")
+		b.WriteString(indent)
+		col = displayWidth(indent)
+		start = col
+	}
+
+	words := strings.FieldsFunc(line, func(r rune) bool { return r == ' ' || r == '\t' })
+	for _, word := range words {
+		segs := segments(word)
+		wordWidth := 0
+		for _, seg := range segs {
+			wordWidth += seg.width
+		}
+
+		if col > start && col+1+wordWidth > width {
+			newline()
+		} else if col > start {
+			b.WriteString(" ")
+			col++
+		}
+
+		// Break words that don't fit on a line of their own.
+		for col+wordWidth > width {
+			head, rest, hyphen := breakSegments(segs, width-col)
+			for _, seg := range head {
+				b.WriteString(seg.text)
+				col += seg.width
+				wordWidth -= seg.width
+			}
+			if hyphen {
+				b.WriteString("-")
+			}
+			segs = rest
+			newline()
+		}
+
+		for _, seg := range segs {
+			b.WriteString(seg.text)
+		}
+		col += wordWidth
+	}
+
+	return b.String()
+}
+
+// breakSegments splits segs so that the head fits in avail columns. When the
+// break falls between two narrow letters, one column is reserved for a hyphen.
+// The head always contains at least one visible segment so wrapping progresses.
+func breakSegments(segs []segment, avail int) (head, rest []segment, hyphen bool) {
+	used := 0
+	i := 0
+	for i < len(segs) && used+segs[i].width <= avail {
+		used += segs[i].width
+		i++
+	}
+
+	// Back off to make room for a hyphen between letters.
+	if i > 0 && i < len(segs) && segs[i].letter && segs[i-1].letter {
+		if used+1 > avail {
+			i--
+			used -= segs[i].width
+		}
+		hyphen = i > 0 && segs[i-1].letter
+	}
+
+	// Guarantee progress with at least one visible segment.
+	if used == 0 {
+		for i < len(segs) && segs[i].width == 0 {
+			i++
+		}
+		if i < len(segs) {
+			i++
+		}
+		hyphen = false
+	}
+
+	return segs[:i], segs[i:], hyphen
+}
@@ -0,0 +1,176 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"unicode"
+	"unicode/utf8"
+)
+
+func TestWrapText(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		width    int
+		expected string
+	}{
+		{
+			name:     "fits on one line",
+			text:     "Once upon a time",
+			width:    20,
+			expected: "Once upon a time",
+		},
+		{
+			name:     "breaks between words",
+			text:     "Once upon a time there was a fox",
+			width:    10,
+			expected: "Once upon\na time\nthere was\na fox",
+		},
+		{
+			name:     "accented letters count one column",
+			text:     "niña corazón árbol",
+			width:    11,
+			expected: "niña\ncorazón\nárbol",
+		},
+		{
+			name:     "combining marks stay with their letter",
+			text:     "café café",
+			width:    5,
+			expected: "café\ncafé",
+		},
+		{
+			name:     "wide CJK characters break without hyphen",
+			text:     "むかしむかしあるところに",
+			width:    10,
+			expected: "むかしむか\nしあるとこ\nろに",
+		},
+		{
+			name:     "emoji count two columns",
+			text:     "🦊🦊 🦊",
+			width:    4,
+			expected: "🦊🦊\n🦊",
+		},
+		{
+			name:     "overlong word is hyphenated",
+			text:     "supercalifragilistic",
+			width:    8,
+			expected: "superca-\nlifragi-\nlistic",
+		},
+		{
+			name:     "indentation preserved on continuation lines",
+			text:     "  one two three",
+			width:    9,
+			expected: "  one two\n  three",
+		},
+		{
+			name:     "tabs in indentation expand",
+			text:     "\tab cd",
+			width:    7,
+			expected: "    ab\n    cd",
+		},
+		{
+			name:     "newlines and blank lines preserved",
+			text:     "one\n\ntwo",
+			width:    10,
+			expected: "one\n\ntwo",
+		},
+		{
+			name:     "escape sequences are not counted",
+			text:     "\x1b[1mbold\x1b[0m word",
+			width:    9,
+			expected: "\x1b[1mbold\x1b[0m word",
+		},
+		{
+			name:     "zero width defaults to 80",
+			text:     "short",
+			width:    0,
+			expected: "short",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := wrapText(tt.text, tt.width)
+			if got != tt.expected {
+				t.Errorf("wrapText(%q, %d) = %q, want %q", tt.text, tt.width, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDisplayWidth(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected int
+	}{
+		{text: "abc", expected: 3},
+		{text: "ñandú", expected: 5},
+		{text: "日本", expected: 4},
+		{text: "👩‍👩‍👧", expected: 2},
+		{text: "\x1b[38;5;205mpink\x1b[0m", expected: 4},
+		{text: "\x1b]8;;https://example.com\x1b\\link\x1b]8;;\x1b\\", expected: 4},
+	}
+
+	for _, tt := range tests {
+		if got := displayWidth(tt.text); got != tt.expected {
+			t.Errorf("displayWidth(%q) = %d, want %d", tt.text, got, tt.expected)
+		}
+	}
+}
+
+func FuzzWrapText(f *testing.F) {
+	seeds := []string{
+		"Once upon a time there was a fox",
+		"niña corazón árbol",
+		"むかしむかしあるところに",
+		"🦊🦊 👩‍👩‍👧 🇯🇵",
+		"  indented\tline\nnext",
+		"\x1b[1mbold\x1b[0m and \x1b[3",
+		"supercalifragilisticexpialidocious",
+	}
+	for _, seed := range seeds {
+		f.Add(seed, 10)
+	}
+
+	f.Fuzz(func(t *testing.T, text string, width int) {
+		if !utf8.ValidString(text) || width < 1 || width > 200 {
+			t.Skip()
+		}
+
+		got := wrapText(text, width)
+
+		if !utf8.ValidString(got) {
+			t.Fatalf("wrapText produced invalid UTF-8: %q", got)
+		}
+
+		// No content is lost or reordered; only whitespace and hyphens are added.
+		strip := func(s string) string {
+			return strings.Map(func(r rune) rune {
+				if unicode.IsSpace(r) || r == '-' {
+					return -1
+				}
+				return r
+			}, s)
+		}
+		if strip(got) != strip(text) {
+			t.Fatalf("content changed: wrapText(%q, %d) = %q", text, width, got)
+		}
+
+		// Every line fits, allowing for indentation wider than the width.
+		inputLines := strings.Split(text, "\n")
+		outputLines := strings.Split(got, "\n")
+		for _, line := range outputLines {
+			limit := width
+			indent := len(line) - len(strings.TrimLeft(line, " "))
+			if indent+2 > limit {
+				limit = indent + 2
+			}
+			if w := displayWidth(line); w > limit {
+				t.Fatalf("line %q is %d columns, limit %d (input %q)", line, w, limit, text)
+			}
+		}
+		if len(outputLines) < len(inputLines) {
+			t.Fatalf("wrapText dropped lines: %d -> %d", len(inputLines), len(outputLines))
+		}
+	})
+}
@@ -0,0 +1,238 @@
+// Package vocab finds the words in a story that are new or challenging for
+// a child, so they can be explained and practised.
+package vocab
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/kbrakke/illustrated-primer/internal/models"
+)
+
+// threshold is how long a word must be to count as challenging for an age
+// band: at least minSyllables syllables, or minLetters letters.
+type threshold struct {
+	minSyllables int
+	minLetters   int
+}
+
+var thresholds = map[models.AgeBand]threshold{
+	models.AgeBandToddler:     {minSyllables: 3, minLetters: 8},
+	models.AgeBandPreschool:   {minSyllables: 3, minLetters: 8},
+	models.AgeBandEarlyReader: {minSyllables: 3, minLetters: 9},
+	models.AgeBandReader:      {minSyllables: 4, minLetters: 11},
+}
+
+// defaultThreshold is used when the child's age band is unknown.
+var defaultThreshold = threshold{minSyllables: 3, minLetters: 9}
+
+// common words are long but familiar to young children, and never picked.
+var common = map[string]bool{
+	"another": true, "anything": true, "anyone": true, "anywhere": true,
+	"beautiful": true, "everybody": true, "everyone": true, "everything": true,
+	"everywhere": true, "family": true, "favorite": true, "favourite": true,
+	"grandfather": true, "grandmother": true, "important": true, "remember": true,
+	"somebody": true, "something": true, "sometimes": true, "somewhere": true,
+	"together": true, "tomorrow": true, "underneath": true, "understand": true,
+	"wonderful": true, "yesterday": true, "animal": true, "animals": true,
+	"banana": true, "bananas": true, "butterfly": true, "butterflies": true,
+	"elephant": true, "elephants": true, "however": true, "whatever": true,
+}
+
+// Supports reports whether words can be picked from text in language, an
+// ISO 639-1 code. Words are split at spaces, and syllables and the common
+// words are English, which is also what an unset language means.
+func Supports(language string) bool {
+	base, _, _ := strings.Cut(language, "-")
+	return base == "" || strings.EqualFold(base, "en")
+}
+
+// Extract returns the challenging words in text for a child in the given
+// age band, lower-cased and sorted, without repeats. Names, recognised by a
+// capital letter in the middle of a sentence, are never picked.
+func Extract(text string, band models.AgeBand) []string {
+	t, ok := thresholds[band]
+	if !ok {
+		t = defaultThreshold
+	}
+
+	names := Names(text)
+	candidates := make(map[string]bool)
+	for _, word := range Tokens(text) {
+		if names[word] || common[word] {
+			continue
+		}
+		if Syllables(word) >= t.minSyllables || len([]rune(word)) >= t.minLetters {
+			candidates[word] = true
+		}
+	}
+
+	var words []string
+	for word := range candidates {
+		words = append(words, word)
+	}
+	sort.Strings(words)
+	return words
+}
+
+// Tokens returns every word of text in order, normalized.
+func Tokens(text string) []string {
+	var words []string
+	for _, tok := range tokenize(text) {
+		words = append(words, Normalize(tok.text))
+	}
+	return words
+}
+
+// Names returns the normalized words used as names in text: those with a
+// capital letter in the middle of a sentence.
+func Names(text string) map[string]bool {
+	names := make(map[string]bool)
+	for _, tok := range tokenize(text) {
+		if tok.capitalized && !tok.sentenceStart {
+			names[Normalize(tok.text)] = true
+		}
+	}
+	return names
+}
+
+// Words returns the words of text in the order they first appear,
+// normalized and without repeats.
+func Words(text string) []string {
+	var words []string
+	seen := make(map[string]bool)
+	for _, word := range Tokens(text) {
+		if !seen[word] {
+			seen[word] = true
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+// Normalize lower-cases a word and drops a possessive "'s", so that each
+// word is stored once.
+func Normalize(word string) string {
+	word = strings.ToLower(word)
+	return strings.TrimSuffix(strings.TrimSuffix(word, "'s"), "’s")
+}
+
+// Sentence returns the first sentence of text that uses word, or "".
+func Sentence(text, word string) string {
+	for _, sentence := range Sentences(text) {
+		for _, token := range Tokens(sentence) {
+			if token == word {
+				return sentence
+			}
+		}
+	}
+	return ""
+}
+
+// Sentences splits text after sentence-ending punctuation and line breaks.
+func Sentences(text string) []string {
+	var out []string
+	start := 0
+	for i, r := range text {
+		if r == '.' || r == '!' || r == '?' || r == '\n' {
+			if sentence := strings.TrimSpace(text[start : i+1]); sentence != "" {
+				out = append(out, sentence)
+			}
+			start = i + 1
+		}
+	}
+	if sentence := strings.TrimSpace(text[start:]); sentence != "" {
+		out = append(out, sentence)
+	}
+	return out
+}
+
+// token is a word in a text.
+type token struct {
+	text          string
+	capitalized   bool
+	sentenceStart bool
+}
+
+// tokenize splits text into words: runs of letters, with inner apostrophes
+// and hyphens. Words containing digits are skipped.
+func tokenize(text string) []token {
+	var tokens []token
+	runes := []rune(text)
+	sentenceStart := true
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if r == '.' || r == '!' || r == '?' || r == '\n' {
+				sentenceStart = true
+			}
+			i++
+			continue
+		}
+
+		start, digits := i, false
+		for i < len(runes) {
+			c := runes[i]
+			inner := (c == '\'' || c == '’' || c == '-') && i+1 < len(runes) && unicode.IsLetter(runes[i+1]) && i > start
+			if !unicode.IsLetter(c) && !unicode.IsDigit(c) && !inner {
+				break
+			}
+			digits = digits || unicode.IsDigit(c)
+			i++
+		}
+		if !digits {
+			tokens = append(tokens, token{
+				text:          string(runes[start:i]),
+				capitalized:   unicode.IsUpper(runes[start]),
+				sentenceStart: sentenceStart,
+			})
+		}
+		sentenceStart = false
+	}
+	return tokens
+}
+
+// Syllables estimates the number of syllables in an English word by
+// counting groups of vowels, with the usual adjustments for a silent final
+// "e" and for "-le", "-ed" and "-es" endings. Every word has at least one.
+func Syllables(word string) int {
+	word = strings.ToLower(word)
+	count := 0
+	prevVowel := false
+	for _, r := range word {
+		vowel := strings.ContainsRune("aeiouy", r)
+		if vowel && !prevVowel {
+			count++
+		}
+		prevVowel = vowel
+	}
+
+	switch {
+	case strings.HasSuffix(word, "le") && len(word) > 2 && !strings.ContainsRune("aeiouy", rune(word[len(word)-3])):
+		// "little", "table": the final "le" is a syllable of its own.
+	case strings.HasSuffix(word, "e") && !strings.HasSuffix(word, "ee") && count > 1:
+		count-- // silent e: "stone", "make"
+	case strings.HasSuffix(word, "ed") && count > 1 && !strings.HasSuffix(word, "ted") && !strings.HasSuffix(word, "ded"):
+		count-- // "jumped", "played"
+	case strings.HasSuffix(word, "es") && count > 1 && !hasSibilantStem(word):
+		count-- // "stones", "makes"
+	}
+
+	if count < 1 {
+		count = 1
+	}
+	return count
+}
+
+// hasSibilantStem reports whether a word ending in "es" pronounces it as a
+// syllable, as in "boxes" or "wishes".
+func hasSibilantStem(word string) bool {
+	stem := strings.TrimSuffix(word, "es")
+	for _, suffix := range []string{"s", "x", "z", "ch", "sh", "g", "c"} {
+		if strings.HasSuffix(stem, suffix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package vocab
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kbrakke/illustrated-primer/internal/models"
+)
+
+func TestSyllables(t *testing.T) {
+	tests := map[string]int{
+		"cat":            1,
+		"stone":          1,
+		"jumped":         1,
+		"stones":         1,
+		"boxes":          2,
+		"little":         2,
+		"garden":         2,
+		"wanted":         2,
+		"tree":           1,
+		"adventure":      3,
+		"photosynthesis": 5,
+		"magnificent":    4,
+		"the":            1,
+		"rhythm":         1,
+	}
+	for word, want := range tests {
+		if got := Syllables(word); got != want {
+			t.Errorf("Syllables(%q) = %d, want %d", word, got, want)
+		}
+	}
+}
+
+func TestExtract(t *testing.T) {
+	text := `Nellodee found an enormous caterpillar in the garden. "Remarkable!" said Nellodee.
+Everything was wonderful. The caterpillar's cocoon would become a butterfly. It's 10 o'clock.
+It was a magnificent, iridescent morning.`
+
+	tests := []struct {
+		band models.AgeBand
+		want []string
+	}{
+		{models.AgeBandToddler, []string{"caterpillar", "enormous", "iridescent", "magnificent", "remarkable"}},
+		{models.AgeBandReader, []string{"caterpillar", "iridescent", "magnificent", "remarkable"}},
+		{"", []string{"caterpillar", "enormous", "iridescent", "magnificent", "remarkable"}},
+	}
+	for _, tt := range tests {
+		got := Extract(text, tt.band)
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("Extract(%q) = %v, want %v", tt.band, got, tt.want)
+		}
+	}
+}
+
+func TestSupports(t *testing.T) {
+	for _, language := range []string{"", "en", "en-US"} {
+		if !Supports(language) {
+			t.Errorf("Supports(%q) = false, want true", language)
+		}
+	}
+	for _, language := range []string{"ja", "zh", "fr"} {
+		if Supports(language) {
+			t.Errorf("Supports(%q) = true, want false", language)
+		}
+	}
+}
+
+func TestExtractSkipsNames(t *testing.T) {
+	// A name at the start of a sentence is recognised from its other uses.
+	text := "Bartholomew smiled. Then Bartholomew waved."
+	if got := Extract(text, models.AgeBandToddler); len(got) != 0 {
+		t.Errorf("Extract() = %v, want no words", got)
+	}
+}
+
+func TestWordsAndSentence(t *testing.T) {
+	text := "The fox's den was cozy. The Fox slept!\nThen the fox woke up."
+
+	if got := strings.Join(Words(text), ","); got != "the,fox,den,was,cozy,slept,then,woke,up" {
+		t.Errorf("Words() = %s", got)
+	}
+	if got := Sentence(text, "slept"); got != "The Fox slept!" {
+		t.Errorf("Sentence(slept) = %q", got)
+	}
+	if got := Sentence(text, "woke"); got != "Then the fox woke up." {
+		t.Errorf("Sentence(woke) = %q", got)
+	}
+	if got := Sentence(text, "badger"); got != "" {
+		t.Errorf("Sentence(badger) = %q, want none", got)
+	}
+}
@@ -0,0 +1,147 @@
+// Package voice turns a child's spoken prompt into text.
+package voice
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// ErrNoRecorder is returned by DetectRecorder when no known capture program is installed.
+var ErrNoRecorder = errors.New("no audio capture program found")
+
+// OutputPlaceholder in a Recorder's arguments is replaced by the path of the
+// WAV file to record into. Without it the path is appended as the last argument.
+const OutputPlaceholder = "{output}"
+
+// stopGrace is how long a capture program gets to finish writing its file
+// after being interrupted before it is killed.
+const stopGrace = 2 * time.Second
+
+// Recorder captures audio from the microphone by running a capture command,
+// such as arecord or sox, that records until it is interrupted.
+type Recorder struct {
+	Name string
+	Args []string
+}
+
+// knownRecorders are tried in order by DetectRecorder. All record 16 kHz
+// mono WAV, which is what speech recognisers expect.
+var knownRecorders = []Recorder{
+	{Name: "arecord", Args: []string{"-q", "-f", "S16_LE", "-r", "16000", "-c", "1", OutputPlaceholder}},
+	{Name: "rec", Args: []string{"-q", "-r", "16000", "-c", "1", "-b", "16", OutputPlaceholder}},
+}
+
+// DetectRecorder returns the first known capture program found by lookPath
+// (normally exec.LookPath).
+func DetectRecorder(lookPath func(string) (string, error)) (*Recorder, error) {
+	for _, r := range knownRecorders {
+		if _, err := lookPath(r.Name); err == nil {
+			recorder := r
+			return &recorder, nil
+		}
+	}
+	return nil, ErrNoRecorder
+}
+
+// ParseRecorder builds a Recorder from a command line such as
+// "arecord -f cd {output}".
+func ParseRecorder(command string) (*Recorder, error) {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("empty capture command")
+	}
+	return &Recorder{Name: fields[0], Args: fields[1:]}, nil
+}
+
+// Recording is a capture in progress.
+type Recording struct {
+	cmd  *exec.Cmd
+	path string
+	done chan struct{}
+	err  error // set before done is closed
+}
+
+// Start begins recording into a new temporary WAV file.
+func (r *Recorder) Start() (*Recording, error) {
+	f, err := os.CreateTemp("", "primer-voice-*.wav")
+	if err != nil {
+		return nil, fmt.Errorf("create recording file: %w", err)
+	}
+	f.Close()
+
+	args := make([]string, 0, len(r.Args)+1)
+	placed := false
+	for _, arg := range r.Args {
+		if strings.Contains(arg, OutputPlaceholder) {
+			arg = strings.ReplaceAll(arg, OutputPlaceholder, f.Name())
+			placed = true
+		}
+		args = append(args, arg)
+	}
+	if !placed {
+		args = append(args, f.Name())
+	}
+
+	cmd := exec.Command(r.Name, args...)
+	if err := cmd.Start(); err != nil {
+		os.Remove(f.Name())
+		return nil, fmt.Errorf("start %s: %w", r.Name, err)
+	}
+
+	rec := &Recording{cmd: cmd, path: f.Name(), done: make(chan struct{})}
+	go func() {
+		rec.err = cmd.Wait()
+		close(rec.done)
+	}()
+	return rec, nil
+}
+
+// Path returns the file being recorded into.
+func (rec *Recording) Path() string {
+	return rec.path
+}
+
+// Stop ends the recording and returns the path of the audio file. The
+// capture program is interrupted so it can finish the file's header, and
+// killed if it doesn't exit promptly. The caller owns the file.
+func (rec *Recording) Stop() (string, error) {
+	select {
+	case <-rec.done:
+		// The program exited by itself, which means it failed.
+		if rec.err != nil {
+			os.Remove(rec.path)
+			return "", fmt.Errorf("capture exited: %w", rec.err)
+		}
+		return rec.path, nil
+	default:
+	}
+
+	if err := interruptProcess(rec.cmd.Process); err != nil {
+		_ = rec.cmd.Process.Kill()
+	}
+
+	select {
+	case <-rec.done:
+	case <-time.After(stopGrace):
+		_ = rec.cmd.Process.Kill()
+		<-rec.done
+	}
+
+	info, err := os.Stat(rec.path)
+	if err != nil || info.Size() == 0 {
+		os.Remove(rec.path)
+		return "", fmt.Errorf("nothing was recorded")
+	}
+	return rec.path, nil
+}
+
+// Cancel stops the recording and discards the audio.
+func (rec *Recording) Cancel() {
+	_ = rec.cmd.Process.Kill()
+	<-rec.done
+	os.Remove(rec.path)
+}
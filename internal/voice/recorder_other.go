@@ -0,0 +1,12 @@
+//go:build !unix
+
+package voice
+
+import (
+	"errors"
+	"os"
+)
+
+// Interrupting another process isn't supported on Windows; Stop falls back
+// to killing the capture program.
+func interruptProcess(p *os.Process) error { return errors.New("interrupt not supported") }
@@ -0,0 +1,7 @@
+//go:build unix
+
+package voice
+
+import "os"
+
+func interruptProcess(p *os.Process) error { return p.Signal(os.Interrupt) }
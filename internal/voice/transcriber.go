@@ -0,0 +1,194 @@
+package voice
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const (
+	// DefaultTranscriptionModel is the OpenAI speech-to-text model.
+	DefaultTranscriptionModel = "gpt-4o-mini-transcribe"
+
+	transcriptionsAPIURL = "https://api.openai.com/v1/audio/transcriptions"
+)
+
+// Transcriber turns recorded speech into text.
+type Transcriber interface {
+	Transcribe(ctx context.Context, audioPath string) (string, error)
+}
+
+// OpenAITranscriber implements Transcriber using the OpenAI transcription API.
+type OpenAITranscriber struct {
+	apiKey     string
+	orgID      string
+	model      string
+	language   string
+	httpClient *http.Client
+	logger     *slog.Logger
+}
+
+// OpenAIOption is a function that configures an OpenAITranscriber.
+type OpenAIOption func(*OpenAITranscriber)
+
+// WithModel sets the transcription model to use.
+func WithModel(model string) OpenAIOption {
+	return func(t *OpenAITranscriber) {
+		t.model = model
+	}
+}
+
+// WithLanguage sets the spoken language as an ISO-639-1 code, such as "en".
+// Young children's speech is hard to recognise, and knowing the language
+// up front improves accuracy.
+func WithLanguage(language string) OpenAIOption {
+	return func(t *OpenAITranscriber) {
+		t.language = language
+	}
+}
+
+// WithOrgID sets the organization ID for project-scoped keys.
+func WithOrgID(orgID string) OpenAIOption {
+	return func(t *OpenAITranscriber) {
+		t.orgID = orgID
+	}
+}
+
+// WithLogger sets a custom logger.
+func WithLogger(logger *slog.Logger) OpenAIOption {
+	return func(t *OpenAITranscriber) {
+		t.logger = logger
+	}
+}
+
+// WithHTTPClient sets a custom HTTP client.
+func WithHTTPClient(client *http.Client) OpenAIOption {
+	return func(t *OpenAITranscriber) {
+		t.httpClient = client
+	}
+}
+
+// NewOpenAITranscriber creates a transcriber with the given API key and options.
+func NewOpenAITranscriber(apiKey string, opts ...OpenAIOption) *OpenAITranscriber {
+	t := &OpenAITranscriber{
+		apiKey:     apiKey,
+		model:      DefaultTranscriptionModel,
+		httpClient: http.DefaultClient,
+		logger:     slog.Default(),
+	}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	return t
+}
+
+// Transcribe uploads the audio file and returns its transcript.
+func (t *OpenAITranscriber) Transcribe(ctx context.Context, audioPath string) (string, error) {
+	audio, err := os.ReadFile(audioPath)
+	if err != nil {
+		return "", fmt.Errorf("read audio: %w", err)
+	}
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	fields := map[string]string{"model": t.model, "response_format": "json"}
+	if t.language != "" {
+		fields["language"] = t.language
+	}
+	for name, value := range fields {
+		if err := w.WriteField(name, value); err != nil {
+			return "", fmt.Errorf("build request: %w", err)
+		}
+	}
+	part, err := w.CreateFormFile("file", filepath.Base(audioPath))
+	if err != nil {
+		return "", fmt.Errorf("build request: %w", err)
+	}
+	if _, err := part.Write(audio); err != nil {
+		return "", fmt.Errorf("build request: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return "", fmt.Errorf("build request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", transcriptionsAPIURL, &body)
+	if err != nil {
+		return "", fmt.Errorf("create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer "+t.apiKey)
+	if t.orgID != "" {
+		req.Header.Set("OpenAI-Organization", t.orgID)
+	}
+
+	t.logger.Debug("requesting transcription", "model", t.model, "bytes", len(audio))
+
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(respBody))
+	}
+
+	var result struct {
+		Text string `json:"text"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("decode response: %w", err)
+	}
+	return strings.TrimSpace(result.Text), nil
+}
+
+// WhisperCppTranscriber implements Transcriber with a local whisper.cpp
+// binary, so speech never leaves the machine.
+type WhisperCppTranscriber struct {
+	Binary   string // whisper.cpp CLI, "whisper-cli" in current releases
+	Model    string // path to a ggml model file
+	Language string // ISO-639-1 code, or "" for automatic detection
+}
+
+// NewWhisperCppTranscriber creates a transcriber using whisper-cli and the given model.
+func NewWhisperCppTranscriber(model string) *WhisperCppTranscriber {
+	return &WhisperCppTranscriber{Binary: "whisper-cli", Model: model}
+}
+
+// nonSpeech matches whisper's annotations for sounds, such as [BLANK_AUDIO]
+// or (laughs), which shouldn't end up in the prompt.
+var nonSpeech = regexp.MustCompile(`\[[^\]]*\]|\([^)]*\)`)
+
+// Transcribe runs whisper.cpp on the audio file and returns its transcript.
+func (t *WhisperCppTranscriber) Transcribe(ctx context.Context, audioPath string) (string, error) {
+	args := []string{"-m", t.Model, "-f", audioPath, "--no-timestamps", "--no-prints"}
+	if t.Language != "" {
+		args = append(args, "-l", t.Language)
+	}
+
+	cmd := exec.CommandContext(ctx, t.Binary, args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("run %s: %w: %s", t.Binary, err, strings.TrimSpace(stderr.String()))
+	}
+
+	text := nonSpeech.ReplaceAllString(stdout.String(), "")
+	return strings.Join(strings.Fields(text), " "), nil
+}
@@ -0,0 +1,145 @@
+package voice
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecorder(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	t.Run("records until stopped", func(t *testing.T) {
+		// Write some audio, then wait to be interrupted like arecord does.
+		r := &Recorder{Name: "sh", Args: []string{"-c", `printf RIFF > "$0"; exec sleep 10`, OutputPlaceholder}}
+		rec, err := r.Start()
+		if err != nil {
+			t.Fatalf("Start() error = %v", err)
+		}
+
+		path, err := waitForAudio(t, rec)
+		if err != nil {
+			t.Fatalf("Stop() error = %v", err)
+		}
+		defer os.Remove(path)
+		if data, _ := os.ReadFile(path); string(data) != "RIFF" {
+			t.Errorf("recorded %q", data)
+		}
+	})
+
+	t.Run("empty recording", func(t *testing.T) {
+		rec, err := (&Recorder{Name: "sh", Args: []string{"-c", "exec sleep 10"}}).Start()
+		if err != nil {
+			t.Fatalf("Start() error = %v", err)
+		}
+		if _, err := rec.Stop(); err == nil {
+			t.Error("expected an error when nothing was recorded")
+		}
+		if _, err := os.Stat(rec.path); !os.IsNotExist(err) {
+			t.Error("empty recording file should be removed")
+		}
+	})
+
+	t.Run("cancel removes the file", func(t *testing.T) {
+		rec, err := (&Recorder{Name: "sh", Args: []string{"-c", "exec sleep 10"}}).Start()
+		if err != nil {
+			t.Fatalf("Start() error = %v", err)
+		}
+		rec.Cancel()
+		if _, err := os.Stat(rec.path); !os.IsNotExist(err) {
+			t.Error("cancelled recording file should be removed")
+		}
+	})
+}
+
+// waitForAudio stops the recording once the capture program has written to its file.
+func waitForAudio(t *testing.T, rec *Recording) (string, error) {
+	t.Helper()
+	for i := 0; i < 200; i++ {
+		if info, err := os.Stat(rec.Path()); err == nil && info.Size() > 0 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return rec.Stop()
+}
+
+func TestDetectRecorder(t *testing.T) {
+	lookPath := func(name string) (string, error) {
+		if name == "rec" {
+			return "/usr/bin/rec", nil
+		}
+		return "", exec.ErrNotFound
+	}
+	if r, err := DetectRecorder(lookPath); err != nil || r.Name != "rec" {
+		t.Errorf("DetectRecorder() = %v, %v; want rec", r, err)
+	}
+	if _, err := DetectRecorder(func(string) (string, error) { return "", exec.ErrNotFound }); !errors.Is(err, ErrNoRecorder) {
+		t.Errorf("expected ErrNoRecorder, got %v", err)
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestOpenAITranscriber(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prompt.wav")
+	if err := os.WriteFile(path, []byte("RIFF audio"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	fields := map[string]string{}
+	client := &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		_, params, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+		mr := multipart.NewReader(req.Body, params["boundary"])
+		for {
+			part, err := mr.NextPart()
+			if err != nil {
+				break
+			}
+			data, _ := io.ReadAll(part)
+			fields[part.FormName()] = string(data)
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(`{"text":" Tell me about dinosaurs. "}`))}, nil
+	})}
+
+	tr := NewOpenAITranscriber("key", WithHTTPClient(client), WithLanguage("en"))
+	text, err := tr.Transcribe(context.Background(), path)
+	if err != nil || text != "Tell me about dinosaurs." {
+		t.Fatalf("Transcribe() = %q, %v", text, err)
+	}
+	if fields["file"] != "RIFF audio" || fields["language"] != "en" || fields["model"] != DefaultTranscriptionModel {
+		t.Errorf("unexpected form %v", fields)
+	}
+}
+
+func TestWhisperCppTranscriber(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	// A stand-in for whisper-cli that prints a transcript with annotations.
+	script := filepath.Join(t.TempDir(), "whisper-cli")
+	err := os.WriteFile(script, []byte("#!/bin/sh\necho ' [BLANK_AUDIO]'\necho ' Can we go to (laughs) space?'\n"), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tr := &WhisperCppTranscriber{Binary: script, Model: "ggml-base.en.bin"}
+	text, err := tr.Transcribe(context.Background(), "prompt.wav")
+	if err != nil || text != "Can we go to space?" {
+		t.Errorf("Transcribe() = %q, %v", text, err)
+	}
+}
@@ -0,0 +1,4 @@
+-- Illustrated Primer Database Schema
+-- PostgreSQL Migration 002: Per-user TUI theme
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS theme TEXT;
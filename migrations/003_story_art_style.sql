@@ -0,0 +1,4 @@
+-- Illustrated Primer Database Schema
+-- PostgreSQL Migration 003: Per-story illustration art style
+
+ALTER TABLE stories ADD COLUMN IF NOT EXISTS art_style TEXT NOT NULL DEFAULT '';
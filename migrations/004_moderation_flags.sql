@@ -0,0 +1,19 @@
+-- Illustrated Primer Database Schema
+-- PostgreSQL Migration 004: Content moderation flags for parent review
+
+CREATE TABLE IF NOT EXISTS moderation_flags (
+    id TEXT PRIMARY KEY NOT NULL,
+    user_id TEXT NOT NULL,
+    story_id TEXT,
+    source TEXT NOT NULL,
+    action TEXT NOT NULL,
+    categories TEXT[] NOT NULL DEFAULT '{}',
+    original_text TEXT NOT NULL,
+    final_text TEXT NOT NULL DEFAULT '',
+    reviewed_at BIGINT,
+    created_at BIGINT NOT NULL DEFAULT (EXTRACT(EPOCH FROM NOW())::BIGINT),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (story_id) REFERENCES stories(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_moderation_flags_user_created ON moderation_flags(user_id, created_at);
@@ -0,0 +1,8 @@
+-- Illustrated Primer Database Schema
+-- PostgreSQL Migration 005: Child profile for age-adaptive prompts
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS birthdate TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS age_band TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS reading_level TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS interests TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS language TEXT;
@@ -0,0 +1,5 @@
+-- Illustrated Primer Database Schema
+-- PostgreSQL Migration 006: Prompt template versions pinned by stories and recorded on pages
+
+ALTER TABLE stories ADD COLUMN IF NOT EXISTS prompt_template TEXT NOT NULL DEFAULT '';
+ALTER TABLE pages ADD COLUMN IF NOT EXISTS prompt_template TEXT NOT NULL DEFAULT '';
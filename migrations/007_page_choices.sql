@@ -0,0 +1,4 @@
+-- Illustrated Primer Database Schema
+-- PostgreSQL Migration 007: Suggested next actions offered with each page
+
+ALTER TABLE pages ADD COLUMN IF NOT EXISTS choices TEXT[] NOT NULL DEFAULT '{}';
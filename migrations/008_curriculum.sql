@@ -0,0 +1,40 @@
+-- Illustrated Primer Database Schema
+-- PostgreSQL Migration 008: Curriculum subjects and learning objectives for stories
+
+CREATE TABLE IF NOT EXISTS subjects (
+    id TEXT PRIMARY KEY NOT NULL,
+    name TEXT NOT NULL UNIQUE,
+    description TEXT NOT NULL DEFAULT '',
+    created_at BIGINT NOT NULL DEFAULT (EXTRACT(EPOCH FROM NOW())::BIGINT)
+);
+
+CREATE TABLE IF NOT EXISTS objectives (
+    id TEXT PRIMARY KEY NOT NULL,
+    subject_id TEXT NOT NULL,
+    title TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_at BIGINT NOT NULL DEFAULT (EXTRACT(EPOCH FROM NOW())::BIGINT),
+    FOREIGN KEY (subject_id) REFERENCES subjects(id) ON DELETE CASCADE,
+    UNIQUE(subject_id, title)
+);
+
+-- The objectives a story was created to teach
+CREATE TABLE IF NOT EXISTS story_objectives (
+    story_id TEXT NOT NULL,
+    objective_id TEXT NOT NULL,
+    PRIMARY KEY (story_id, objective_id),
+    FOREIGN KEY (story_id) REFERENCES stories(id) ON DELETE CASCADE,
+    FOREIGN KEY (objective_id) REFERENCES objectives(id) ON DELETE CASCADE
+);
+
+-- The objectives each page worked on
+CREATE TABLE IF NOT EXISTS page_objectives (
+    page_id TEXT NOT NULL,
+    objective_id TEXT NOT NULL,
+    PRIMARY KEY (page_id, objective_id),
+    FOREIGN KEY (page_id) REFERENCES pages(id) ON DELETE CASCADE,
+    FOREIGN KEY (objective_id) REFERENCES objectives(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_objectives_subject_id ON objectives(subject_id);
+CREATE INDEX IF NOT EXISTS idx_page_objectives_objective_id ON page_objectives(objective_id);
@@ -0,0 +1,28 @@
+-- Illustrated Primer Database Schema
+-- PostgreSQL Migration 009: Comprehension quiz attempts and answers
+
+CREATE TABLE IF NOT EXISTS quiz_attempts (
+    id TEXT PRIMARY KEY NOT NULL,
+    user_id TEXT NOT NULL,
+    story_id TEXT,
+    score INTEGER NOT NULL DEFAULT 0,
+    total INTEGER NOT NULL DEFAULT 0,
+    created_at BIGINT NOT NULL DEFAULT (EXTRACT(EPOCH FROM NOW())::BIGINT),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (story_id) REFERENCES stories(id) ON DELETE SET NULL
+);
+
+-- The questions of each attempt as they were asked, and the options chosen
+CREATE TABLE IF NOT EXISTS quiz_answers (
+    attempt_id TEXT NOT NULL,
+    position INTEGER NOT NULL,
+    question TEXT NOT NULL,
+    kind TEXT NOT NULL DEFAULT 'text',
+    options TEXT[] NOT NULL DEFAULT '{}',
+    answer INTEGER NOT NULL,
+    chosen INTEGER NOT NULL,
+    PRIMARY KEY (attempt_id, position),
+    FOREIGN KEY (attempt_id) REFERENCES quiz_attempts(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_quiz_attempts_user_created ON quiz_attempts(user_id, created_at);
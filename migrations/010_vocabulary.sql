@@ -0,0 +1,26 @@
+-- Illustrated Primer Database Schema
+-- PostgreSQL Migration 010: Vocabulary per child and cached word definitions
+
+-- The challenging words each child has met, and where they first saw them
+CREATE TABLE IF NOT EXISTS vocabulary (
+    user_id TEXT NOT NULL,
+    word TEXT NOT NULL,
+    first_story_id TEXT,
+    first_page_id TEXT,
+    times_seen INTEGER NOT NULL DEFAULT 1,
+    created_at BIGINT NOT NULL DEFAULT (EXTRACT(EPOCH FROM NOW())::BIGINT),
+    updated_at BIGINT NOT NULL DEFAULT (EXTRACT(EPOCH FROM NOW())::BIGINT),
+    PRIMARY KEY (user_id, word),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (first_story_id) REFERENCES stories(id) ON DELETE SET NULL,
+    FOREIGN KEY (first_page_id) REFERENCES pages(id) ON DELETE SET NULL
+);
+
+-- Definitions are generated once per word and age band
+CREATE TABLE IF NOT EXISTS definitions (
+    word TEXT NOT NULL,
+    age_band TEXT NOT NULL DEFAULT '',
+    definition TEXT NOT NULL,
+    created_at BIGINT NOT NULL DEFAULT (EXTRACT(EPOCH FROM NOW())::BIGINT),
+    PRIMARY KEY (word, age_band)
+);
@@ -0,0 +1,24 @@
+-- Illustrated Primer Database Schema
+-- PostgreSQL Migration 011: Spaced-repetition review of vocabulary
+
+-- SM-2 review schedule of each word
+ALTER TABLE vocabulary ADD COLUMN IF NOT EXISTS ease DOUBLE PRECISION NOT NULL DEFAULT 2.5;
+ALTER TABLE vocabulary ADD COLUMN IF NOT EXISTS interval_days BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE vocabulary ADD COLUMN IF NOT EXISTS repetitions BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE vocabulary ADD COLUMN IF NOT EXISTS due_at BIGINT NOT NULL DEFAULT (EXTRACT(EPOCH FROM NOW())::BIGINT);
+ALTER TABLE vocabulary ADD COLUMN IF NOT EXISTS reviewed_at BIGINT;
+
+CREATE INDEX IF NOT EXISTS idx_vocabulary_user_due ON vocabulary(user_id, due_at);
+
+-- How well each word was remembered at each review
+CREATE TABLE IF NOT EXISTS word_reviews (
+    id TEXT PRIMARY KEY NOT NULL,
+    user_id TEXT NOT NULL,
+    word TEXT NOT NULL,
+    recall INTEGER NOT NULL,
+    interval_days BIGINT NOT NULL,
+    created_at BIGINT NOT NULL DEFAULT (EXTRACT(EPOCH FROM NOW())::BIGINT),
+    FOREIGN KEY (user_id, word) REFERENCES vocabulary(user_id, word) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_word_reviews_user_created ON word_reviews(user_id, created_at);
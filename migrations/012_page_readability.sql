@@ -0,0 +1,8 @@
+-- Illustrated Primer Database Schema
+-- PostgreSQL Migration 012: Readability scores of pages
+
+-- How hard each page is to read; NULL for pages saved before scoring
+ALTER TABLE pages ADD COLUMN IF NOT EXISTS grade_level DOUBLE PRECISION;
+ALTER TABLE pages ADD COLUMN IF NOT EXISTS reading_ease DOUBLE PRECISION;
+ALTER TABLE pages ADD COLUMN IF NOT EXISTS words_per_sentence DOUBLE PRECISION;
+ALTER TABLE pages ADD COLUMN IF NOT EXISTS rare_word_ratio DOUBLE PRECISION;
@@ -0,0 +1,19 @@
+-- Illustrated Primer Database Schema
+-- PostgreSQL Migration 014: Screen-time limits and sessions per child
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS daily_minutes BIGINT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS allowed_from TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS allowed_until TEXT;
+
+CREATE TABLE IF NOT EXISTS screen_sessions (
+    id TEXT PRIMARY KEY NOT NULL,
+    user_id TEXT NOT NULL,
+    override BOOLEAN NOT NULL DEFAULT FALSE,
+    end_reason TEXT NOT NULL DEFAULT '',
+    started_at BIGINT NOT NULL,
+    last_seen_at BIGINT NOT NULL,
+    ended_at BIGINT,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_screen_sessions_user_started ON screen_sessions(user_id, started_at);
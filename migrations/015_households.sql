@@ -0,0 +1,15 @@
+-- Illustrated Primer Database Schema
+-- PostgreSQL Migration 015: Households with guardian and child roles
+
+CREATE TABLE IF NOT EXISTS households (
+    id TEXT PRIMARY KEY NOT NULL,
+    name TEXT NOT NULL,
+    created_at BIGINT NOT NULL DEFAULT (EXTRACT(EPOCH FROM NOW())::BIGINT),
+    updated_at BIGINT NOT NULL DEFAULT (EXTRACT(EPOCH FROM NOW())::BIGINT)
+);
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS household_id TEXT REFERENCES households(id) ON DELETE SET NULL;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role TEXT NOT NULL DEFAULT 'child';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS pin_hash TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_users_household_id ON users(household_id);
@@ -0,0 +1,6 @@
+-- Illustrated Primer Database Schema
+-- PostgreSQL Migration 016: Content preferences per child
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS story_themes TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS banned_topics TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS sensitivities TEXT[] NOT NULL DEFAULT '{}';
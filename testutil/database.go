@@ -86,7 +86,7 @@ func (td *TestDatabase) Clean(t *testing.T) {
 	ctx := context.Background()
 
 	// Delete in reverse order of foreign key dependencies
-	tables := []string{"pages", "stories", "users"}
+	tables := []string{"verification_tokens", "sessions", "accounts", "screen_sessions", "token_usage", "word_reviews", "definitions", "vocabulary", "quiz_answers", "quiz_attempts", "moderation_flags", "page_objectives", "story_objectives", "pages", "stories", "objectives", "subjects", "users", "households"}
 	for _, table := range tables {
 		_, err := td.Pool.Exec(ctx, "DELETE FROM "+table)
 		if err != nil {
@@ -2,17 +2,21 @@ package testutil
 
 import (
 	"context"
+
+	"github.com/kbrakke/illustrated-primer/internal/ai"
 )
 
 // MockCall records a call to the mock AI client.
 type MockCall struct {
 	Message string
 	History []string
+	Options ai.RequestOptions
 }
 
 // MockAIClient is a mock implementation of the AI client for testing.
 type MockAIClient struct {
 	Response string
+	Tokens   ai.Tokens // reported for every call
 	Err      error
 	Calls    []MockCall
 }
@@ -26,19 +30,21 @@ func NewMockAIClient(response string) *MockAIClient {
 }
 
 // GenerateResponse returns the configured mock response.
-func (m *MockAIClient) GenerateResponse(ctx context.Context, message string, history []string) (string, error) {
+func (m *MockAIClient) GenerateResponse(ctx context.Context, message string, history []string, opts ai.RequestOptions) (string, ai.Tokens, error) {
 	m.Calls = append(m.Calls, MockCall{
 		Message: message,
 		History: history,
+		Options: opts,
 	})
-	return m.Response, m.Err
+	return m.Response, m.Tokens, m.Err
 }
 
 // GenerateResponseStream returns the configured mock response via a channel.
-func (m *MockAIClient) GenerateResponseStream(ctx context.Context, message string, history []string) (<-chan string, error) {
+func (m *MockAIClient) GenerateResponseStream(ctx context.Context, message string, history []string, opts ai.RequestOptions) (*ai.Stream, error) {
 	m.Calls = append(m.Calls, MockCall{
 		Message: message,
 		History: history,
+		Options: opts,
 	})
 
 	if m.Err != nil {
@@ -46,12 +52,13 @@ func (m *MockAIClient) GenerateResponseStream(ctx context.Context, message strin
 	}
 
 	ch := make(chan string, 1)
+	tokens := m.Tokens
 	go func() {
 		defer close(ch)
 		ch <- m.Response
 	}()
 
-	return ch, nil
+	return ai.NewStream(ch, &tokens), nil
 }
 
 // CallCount returns the number of times the client was called.